@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net"
 	"net/http"
@@ -11,17 +12,25 @@ import (
 	"github.com/opentracing/opentracing-go/ext"
 	"gitlab.ozon.dev/cranky4/tg-bot/internal/api"
 	"gitlab.ozon.dev/cranky4/tg-bot/internal/config"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/model"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/service/expense_exporter"
 	"gitlab.ozon.dev/cranky4/tg-bot/internal/service/expense_reporter"
 	"gitlab.ozon.dev/cranky4/tg-bot/internal/service/logger"
 	servicemessages "gitlab.ozon.dev/cranky4/tg-bot/internal/service/messages"
 	"gitlab.ozon.dev/cranky4/tg-bot/internal/utils/tracer"
 	pkg_api "gitlab.ozon.dev/cranky4/tg-bot/pkg/reporter_v1"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
+// maxMessageSize файл выгрузки или импорта приходит одним сообщением, Telegram принимает от бота файлы до 50 МБ
+const maxMessageSize = 50 << 20
+
 type server struct {
 	pkg_api.UnimplementedReporterV1Server
 	messagesService *servicemessages.Model
@@ -41,8 +50,34 @@ func (s *server) SendReport(ctx context.Context, request *pkg_api.SendReportRequ
 	defer span.Finish()
 
 	report := expense_reporter.ExpenseReport{
-		Rows:   request.GetRows(),
-		UserID: request.GetUserId(),
+		UserID:                request.GetUserId(),
+		ChatID:                request.GetChatId(),
+		Period:                model.ExpensePeriod(request.GetPeriod()),
+		Range:                 model.NewDateRange(request.GetRangeFrom().AsTime(), request.GetRangeTo().AsTime()),
+		TotalIncome:           request.GetTotalIncome(),
+		TotalExpenses:         request.GetTotalExpenses(),
+		Balance:               request.GetBalance(),
+		Account:               request.GetAccount(),
+		BudgetID:              request.GetBudgetId(),
+		Members:               request.GetMembers(),
+		PreviousRange:         model.NewDateRange(request.GetPreviousFrom().AsTime(), request.GetPreviousTo().AsTime()),
+		PreviousTotalExpenses: request.GetPreviousTotalExpenses(),
+	}
+	for _, row := range request.GetRows() {
+		report.Rows = append(report.Rows, expense_reporter.ReportRow{
+			Category:   row.GetCategory(),
+			Amount:     row.GetAmount(),
+			Share:      row.GetShare(),
+			Previous:   row.GetPrevious(),
+			Limit:      row.GetLimit(),
+			LimitUsage: row.GetLimitUsage(),
+		})
+	}
+	for _, point := range request.GetTimeline() {
+		report.Timeline = append(report.Timeline, expense_reporter.TimelinePoint{
+			From:   point.GetFrom().AsTime(),
+			Amount: point.GetAmount(),
+		})
 	}
 
 	err = s.messagesService.SendReport(ctx, &report)
@@ -53,6 +88,123 @@ func (s *server) SendReport(ctx context.Context, request *pkg_api.SendReportRequ
 	return &emptypb.Empty{}, nil
 }
 
+func (s *server) SendExport(ctx context.Context, request *pkg_api.SendExportRequest) (*emptypb.Empty, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "GRPCServer_SendExport")
+
+	md, ok := metadata.FromIncomingContext(ctx)
+	var err error
+	if ok {
+		span, ctx, err = extractTraceFromMeta(ctx, span, md)
+		if err != nil {
+			return nil, err
+		}
+	}
+	defer span.Finish()
+
+	export := expense_exporter.ExpenseExport{
+		UserID:   request.GetUserId(),
+		ChatID:   request.GetChatId(),
+		BudgetID: request.GetBudgetId(),
+		Format:   model.ExportFormat(request.GetFormat()),
+		Range:    model.NewDateRange(request.GetRangeFrom().AsTime(), request.GetRangeTo().AsTime()),
+		Count:    int(request.GetCount()),
+		File: model.File{
+			Name:     request.GetFileName(),
+			MimeType: request.GetMimeType(),
+			Data:     request.GetData(),
+		},
+	}
+
+	err = s.messagesService.SendExport(ctx, &export)
+	if err != nil {
+		return nil, err
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *server) SendTrend(ctx context.Context, request *pkg_api.SendTrendRequest) (*emptypb.Empty, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "GRPCServer_SendTrend")
+
+	md, ok := metadata.FromIncomingContext(ctx)
+	var err error
+	if ok {
+		span, ctx, err = extractTraceFromMeta(ctx, span, md)
+		if err != nil {
+			return nil, err
+		}
+	}
+	defer span.Finish()
+
+	trend := expense_reporter.TrendReport{
+		UserID:     request.GetUserId(),
+		ChatID:     request.GetChatId(),
+		BudgetID:   request.GetBudgetId(),
+		Range:      model.NewDateRange(request.GetRangeFrom().AsTime(), request.GetRangeTo().AsTime()),
+		Bucket:     model.TrendBucket(request.GetBucket()),
+		ByCategory: request.GetByCategory(),
+	}
+	for _, point := range request.GetPoints() {
+		trend.Points = append(trend.Points, expense_reporter.TrendReportPoint{
+			From:     point.GetFrom().AsTime(),
+			Category: point.GetCategory(),
+			Amount:   point.GetAmount(),
+		})
+	}
+
+	err = s.messagesService.SendTrend(ctx, &trend)
+	if err != nil {
+		return nil, err
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *server) ImportExpenses(ctx context.Context, request *pkg_api.ImportExpensesRequest) (*pkg_api.ImportExpensesResponse, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "GRPCServer_ImportExpenses")
+	defer span.Finish()
+
+	result, err := s.messagesService.ImportExpenses(
+		ctx,
+		request.GetToken(),
+		request.GetChatId(),
+		request.GetData(),
+		request.GetMapping(),
+		request.GetDryRun(),
+	)
+	if errors.Is(err, servicemessages.ErrInvalidImportToken) {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	response := &pkg_api.ImportExpensesResponse{
+		Rows:       int64(result.Rows),
+		Added:      int64(result.Added),
+		Duplicates: int64(result.Duplicates),
+		DryRun:     result.DryRun,
+	}
+	for _, rowErr := range result.Errors {
+		response.Errors = append(response.Errors, &pkg_api.ImportRowError{
+			Row:     int64(rowErr.Row),
+			Message: rowErr.Message,
+		})
+	}
+	for _, row := range result.Preview {
+		response.Preview = append(response.Preview, &pkg_api.ImportPreviewRow{
+			Row:       int64(row.Row),
+			Datetime:  timestamppb.New(row.Datetime),
+			Amount:    row.Amount,
+			Currency:  row.Currency,
+			Category:  row.Category,
+			Duplicate: row.Duplicate,
+		})
+	}
+
+	return response, nil
+}
+
 func initGRPСServer(grpcConf config.GRPCConf, messagesService *servicemessages.Model) error {
 	grpcPort := fmt.Sprintf(":%d", grpcConf.Port)
 
@@ -62,6 +214,7 @@ func initGRPСServer(grpcConf config.GRPCConf, messagesService *servicemessages.
 	}
 
 	s := grpc.NewServer(
+		grpc.MaxRecvMsgSize(maxMessageSize),
 		grpc.InTapHandle(api.CountRequestsInterceptor),
 		grpc.ChainUnaryInterceptor(api.LogInterceptor, api.TracingInterceptor),
 	)
@@ -0,0 +1,18 @@
+package main
+
+import (
+	"errors"
+
+	blobstorage "gitlab.ozon.dev/cranky4/tg-bot/internal/clients/blob_storage"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/clients/blob_storage/local"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/config"
+)
+
+func initBlobStorage(conf config.BlobStorageConf) (blobstorage.BlobStorage, error) {
+	switch conf.Adapter {
+	case "local":
+		return local.NewLocalStorage(conf)
+	}
+
+	return nil, errors.New("Невалидный адаптер хранилища файлов")
+}
@@ -13,11 +13,15 @@ import (
 	"gitlab.ozon.dev/cranky4/tg-bot/internal/clients/tg"
 	"gitlab.ozon.dev/cranky4/tg-bot/internal/config"
 	serviceconverter "gitlab.ozon.dev/cranky4/tg-bot/internal/service/converter"
+	expenseattachments "gitlab.ozon.dev/cranky4/tg-bot/internal/service/expense_attachments"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/service/expense_importer"
 	"gitlab.ozon.dev/cranky4/tg-bot/internal/service/expense_processor"
+	limitnotifier "gitlab.ozon.dev/cranky4/tg-bot/internal/service/limit_notifier"
 	"gitlab.ozon.dev/cranky4/tg-bot/internal/service/logger"
 	servicelogger "gitlab.ozon.dev/cranky4/tg-bot/internal/service/logger"
 	servicemessages "gitlab.ozon.dev/cranky4/tg-bot/internal/service/messages"
 	"gitlab.ozon.dev/cranky4/tg-bot/internal/service/metrics"
+	recurringexpenses "gitlab.ozon.dev/cranky4/tg-bot/internal/service/recurring_expenses"
 	reportrequester "gitlab.ozon.dev/cranky4/tg-bot/internal/service/report_requester"
 )
 
@@ -38,7 +42,10 @@ func main() {
 	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
 	defer cancel()
 
-	repo := initRepo(*config)
+	categoryRulesRepo := initCategoryRulesRepo(*config)
+	splitsRepo := initSplitsRepo(*config)
+	repo := initRepo(*config, categoryRulesRepo, splitsRepo)
+	settingsRepo := initSettingsRepo(*config)
 
 	// Загружаем курс валют
 	go func(ctx context.Context) {
@@ -84,14 +91,57 @@ func main() {
 		logger.Fatal(fmt.Sprintf("broker message init failed: %s", err))
 	}
 
-	messagesService := servicemessages.New(
-		tgClient,
-		converter.GetAvailableCurrencies(),
-		expense_processor.NewProcessor(repo, converter, cache),
-		reportrequester.NewReportRequester(broker, config.MessageBroker.Queue, metrics.MessageBrokerMessagesProducesTotalCounter),
-		metrics.TotalRequestCounter,
-		metrics.ResponseTimeSummary,
+	// Хранилище чеков
+	blobStorage, err := initBlobStorage(config.BlobStorage)
+	if err != nil {
+		logger.Fatal(fmt.Sprintf("blob storage init failed: %s", err))
+	}
+
+	attachmentsRepo := initAttachmentsRepo(*config)
+
+	expenseProcessor := expense_processor.NewProcessor(
+		repo,
+		initIncomesRepo(*config),
+		splitsRepo,
+		categoryRulesRepo,
+		settingsRepo,
+		attachmentsRepo,
+		blobStorage,
+		converter,
+		cache,
+		limitnotifier.NewLimitNotifier(tgClient),
+	)
+
+	// Регулярные траты
+	recurringExpenses := recurringexpenses.NewRecurringExpenses(
+		initRecurringExpensesRepo(*config),
+		settingsRepo,
+		expenseProcessor,
+		config.Scheduler.Interval,
+		config.Scheduler.BatchSize,
 	)
+	go func(ctx context.Context) {
+		if err := recurringExpenses.Start(ctx); err != nil {
+			logger.Error("recurring expenses err", servicelogger.LogDataItem{Key: "error", Value: err.Error()})
+		}
+	}(ctx)
+
+	messagesService := servicemessages.New(servicemessages.Deps{
+		TgClient:             tgClient,
+		Currencies:           converter.GetAvailableCurrencies(),
+		ExpenseProcessor:     expenseProcessor,
+		ReportRequester:      reportrequester.NewReportRequester(broker, config.MessageBroker.Queue, metrics.MessageBrokerMessagesProducesTotalCounter),
+		SettingsRepo:         settingsRepo,
+		SubscriptionsRepo:    initReportSubscriptionsRepo(*config),
+		BudgetsRepo:          initBudgetsRepo(*config),
+		RecurringExpenses:    recurringExpenses,
+		ExpenseAttachments:   expenseattachments.NewExpenseAttachments(attachmentsRepo, blobStorage),
+		ExpenseImporter:      expense_importer.NewImporter(expenseProcessor, settingsRepo, converter),
+		ImportTokensRepo:     initImportTokensRepo(*config),
+		DialogCache:          cache,
+		TotalRequestsCounter: metrics.TotalRequestCounter,
+		ResponseTimeSummary:  metrics.ResponseTimeSummary,
+	})
 
 	// GRPC
 	go func() {
@@ -9,13 +9,13 @@ import (
 	sqlrepo "gitlab.ozon.dev/cranky4/tg-bot/internal/repository/sql"
 )
 
-func initRepo(conf config.Config) repo.ExpensesRepository {
+func initRepo(conf config.Config, rulesRepo repo.CategoryRulesRepository, splitsRepo repo.SplitsRepository) repo.ExpensesRepository {
 	var repo repo.ExpensesRepository
 	var err error
 
 	switch conf.Storage.Mode {
 	case "memory":
-		repo = memoryrepo.NewRepository()
+		repo = memoryrepo.NewLinkedRepository(rulesRepo, splitsRepo)
 	case "sql":
 		repo, err = sqlrepo.NewRepository(conf.Database)
 		if err != nil {
@@ -27,3 +27,174 @@ func initRepo(conf config.Config) repo.ExpensesRepository {
 
 	return repo
 }
+
+func initSettingsRepo(conf config.Config) repo.UserSettingsRepository {
+	var repo repo.UserSettingsRepository
+	var err error
+
+	switch conf.Storage.Mode {
+	case "memory":
+		repo = memoryrepo.NewSettingsRepository()
+	case "sql":
+		repo, err = sqlrepo.NewSettingsRepository(conf.Database)
+		if err != nil {
+			log.Fatalf("cannot connect to db %s", err.Error())
+		}
+	default:
+		log.Fatalf("unknown repo mode %s", conf.Storage)
+	}
+
+	return repo
+}
+
+func initCategoryRulesRepo(conf config.Config) repo.CategoryRulesRepository {
+	var repo repo.CategoryRulesRepository
+	var err error
+
+	switch conf.Storage.Mode {
+	case "memory":
+		repo = memoryrepo.NewCategoryRulesRepository()
+	case "sql":
+		repo, err = sqlrepo.NewCategoryRulesRepository(conf.Database)
+		if err != nil {
+			log.Fatalf("cannot connect to db %s", err.Error())
+		}
+	default:
+		log.Fatalf("unknown repo mode %s", conf.Storage)
+	}
+
+	return repo
+}
+
+func initReportSubscriptionsRepo(conf config.Config) repo.ReportSubscriptionsRepository {
+	var repo repo.ReportSubscriptionsRepository
+	var err error
+
+	switch conf.Storage.Mode {
+	case "memory":
+		repo = memoryrepo.NewReportSubscriptionsRepository()
+	case "sql":
+		repo, err = sqlrepo.NewReportSubscriptionsRepository(conf.Database)
+		if err != nil {
+			log.Fatalf("cannot connect to db %s", err.Error())
+		}
+	default:
+		log.Fatalf("unknown repo mode %s", conf.Storage)
+	}
+
+	return repo
+}
+
+func initRecurringExpensesRepo(conf config.Config) repo.RecurringExpensesRepository {
+	var repo repo.RecurringExpensesRepository
+	var err error
+
+	switch conf.Storage.Mode {
+	case "memory":
+		repo = memoryrepo.NewRecurringExpensesRepository()
+	case "sql":
+		repo, err = sqlrepo.NewRecurringExpensesRepository(conf.Database)
+		if err != nil {
+			log.Fatalf("cannot connect to db %s", err.Error())
+		}
+	default:
+		log.Fatalf("unknown repo mode %s", conf.Storage)
+	}
+
+	return repo
+}
+
+func initIncomesRepo(conf config.Config) repo.IncomesRepository {
+	var repo repo.IncomesRepository
+	var err error
+
+	switch conf.Storage.Mode {
+	case "memory":
+		repo = memoryrepo.NewIncomesRepository()
+	case "sql":
+		repo, err = sqlrepo.NewIncomesRepository(conf.Database)
+		if err != nil {
+			log.Fatalf("cannot connect to db %s", err.Error())
+		}
+	default:
+		log.Fatalf("unknown repo mode %s", conf.Storage)
+	}
+
+	return repo
+}
+
+func initSplitsRepo(conf config.Config) repo.SplitsRepository {
+	var repo repo.SplitsRepository
+	var err error
+
+	switch conf.Storage.Mode {
+	case "memory":
+		repo = memoryrepo.NewSplitsRepository()
+	case "sql":
+		repo, err = sqlrepo.NewSplitsRepository(conf.Database)
+		if err != nil {
+			log.Fatalf("cannot connect to db %s", err.Error())
+		}
+	default:
+		log.Fatalf("unknown repo mode %s", conf.Storage)
+	}
+
+	return repo
+}
+
+func initAttachmentsRepo(conf config.Config) repo.AttachmentsRepository {
+	var repo repo.AttachmentsRepository
+	var err error
+
+	switch conf.Storage.Mode {
+	case "memory":
+		repo = memoryrepo.NewAttachmentsRepository()
+	case "sql":
+		repo, err = sqlrepo.NewAttachmentsRepository(conf.Database)
+		if err != nil {
+			log.Fatalf("cannot connect to db %s", err.Error())
+		}
+	default:
+		log.Fatalf("unknown repo mode %s", conf.Storage)
+	}
+
+	return repo
+}
+
+func initImportTokensRepo(conf config.Config) repo.ImportTokensRepository {
+	var repo repo.ImportTokensRepository
+	var err error
+
+	switch conf.Storage.Mode {
+	case "memory":
+		repo = memoryrepo.NewImportTokensRepository()
+	case "sql":
+		repo, err = sqlrepo.NewImportTokensRepository(conf.Database)
+		if err != nil {
+			log.Fatalf("cannot connect to db %s", err.Error())
+		}
+	default:
+		log.Fatalf("unknown repo mode %s", conf.Storage)
+	}
+
+	return repo
+}
+
+func initBudgetsRepo(conf config.Config) repo.BudgetsRepository {
+	var repo repo.BudgetsRepository
+	var err error
+
+	switch conf.Storage.Mode {
+	case "memory":
+		repo = memoryrepo.NewBudgetsRepository()
+	case "sql":
+		repo, err = sqlrepo.NewBudgetsRepository(conf.Database)
+		if err != nil {
+			log.Fatalf("cannot connect to db %s", err.Error())
+		}
+	default:
+		log.Fatalf("unknown repo mode %s", conf.Storage)
+	}
+
+	return repo
+}
@@ -11,6 +11,7 @@ import (
 	"gitlab.ozon.dev/cranky4/tg-bot/internal/clients/exchangerate"
 	"gitlab.ozon.dev/cranky4/tg-bot/internal/config"
 	serviceconverter "gitlab.ozon.dev/cranky4/tg-bot/internal/service/converter"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/service/expense_exporter"
 	"gitlab.ozon.dev/cranky4/tg-bot/internal/service/expense_reporter"
 	"gitlab.ozon.dev/cranky4/tg-bot/internal/service/logger"
 	"gitlab.ozon.dev/cranky4/tg-bot/internal/service/metrics"
@@ -42,7 +43,7 @@ func main() {
 	}
 
 	converter := serviceconverter.NewConverter(exchangerate.NewGetter())
-	expenseReporter := expense_reporter.NewReporter(repo, converter, cache)
+	expenseReporter := expense_reporter.NewReporter(repo, initIncomesRepo(*config), converter, cache)
 	reportSender := reportsender.NewReportSender(config.GRPC)
 
 	// Метрики
@@ -65,6 +66,7 @@ func main() {
 		broker,
 		config.MessageBroker.Queue,
 		expenseReporter,
+		expense_exporter.NewExporter(repo, converter),
 		reportSender,
 		metrics.MessageBrokerMessagesConsumedTotalCounter,
 	)
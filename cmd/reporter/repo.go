@@ -27,3 +27,22 @@ func initRepo(conf config.Config) repo.ExpensesRepository {
 
 	return repo
 }
+
+func initIncomesRepo(conf config.Config) repo.IncomesRepository {
+	var repo repo.IncomesRepository
+	var err error
+
+	switch conf.Storage.Mode {
+	case "memory":
+		repo = memoryrepo.NewIncomesRepository()
+	case "sql":
+		repo, err = sqlrepo.NewIncomesRepository(conf.Database)
+		if err != nil {
+			log.Fatalf("cannot connect to db %s", err.Error())
+		}
+	default:
+		log.Fatalf("unknown repo mode %s", conf.Storage)
+	}
+
+	return repo
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os/signal"
+	"syscall"
+
+	// init pgsql.
+	_ "github.com/jackc/pgx/stdlib"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/config"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/service/logger"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/service/metrics"
+	reportrequester "gitlab.ozon.dev/cranky4/tg-bot/internal/service/report_requester"
+	reportscheduler "gitlab.ozon.dev/cranky4/tg-bot/internal/service/report_scheduler"
+)
+
+const (
+	startSchedulerInfoMsg = "планировщик отчетов по подпискам запущен"
+	stopSchedulerInfoMsg  = "планировщик отчетов по подпискам остановлен"
+)
+
+func main() {
+	config, err := config.New()
+	if err != nil {
+		log.Fatal("config init failed:", err)
+	}
+	logger.SetLevel(config.Logger.Level)
+
+	broker, err := initMessageBroker(config.MessageBroker)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+
+	settingsRepo := initSettingsRepo(*config)
+	subscriptionsRepo := initReportSubscriptionsRepo(*config)
+	budgetsRepo := initBudgetsRepo(*config)
+
+	// Метрики
+	go func() {
+		err = startMetricsHTTPServer(config.Metrics.URL, config.SchedulerMetrics.Port)
+		if err != nil {
+			logger.Error("Error while tracer flush", logger.LogDataItem{Key: "error", Value: err.Error()})
+		}
+	}()
+
+	// Трейсы
+	initTraces()
+	defer func() {
+		if err = flushTraces(); err != nil {
+			logger.Error("traces flush err", logger.LogDataItem{Key: "error", Value: err.Error()})
+		}
+	}()
+
+	scheduler := reportscheduler.NewReportScheduler(
+		subscriptionsRepo,
+		settingsRepo,
+		budgetsRepo,
+		reportrequester.NewReportRequester(broker, config.MessageBroker.Queue, metrics.MessageBrokerMessagesProducesTotalCounter),
+		config.Scheduler.Interval,
+		config.Scheduler.BatchSize,
+	)
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+	defer cancel()
+
+	logger.Info(startSchedulerInfoMsg)
+
+	if err := scheduler.Start(ctx); err != nil {
+		logger.Error(err.Error())
+	}
+
+	logger.Info(stopSchedulerInfoMsg)
+}
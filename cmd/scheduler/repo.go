@@ -0,0 +1,67 @@
+package main
+
+import (
+	"log"
+
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/config"
+	repo "gitlab.ozon.dev/cranky4/tg-bot/internal/repository"
+	memoryrepo "gitlab.ozon.dev/cranky4/tg-bot/internal/repository/memory"
+	sqlrepo "gitlab.ozon.dev/cranky4/tg-bot/internal/repository/sql"
+)
+
+func initSettingsRepo(conf config.Config) repo.UserSettingsRepository {
+	var repo repo.UserSettingsRepository
+	var err error
+
+	switch conf.Storage.Mode {
+	case "memory":
+		repo = memoryrepo.NewSettingsRepository()
+	case "sql":
+		repo, err = sqlrepo.NewSettingsRepository(conf.Database)
+		if err != nil {
+			log.Fatalf("cannot connect to db %s", err.Error())
+		}
+	default:
+		log.Fatalf("unknown repo mode %s", conf.Storage)
+	}
+
+	return repo
+}
+
+func initReportSubscriptionsRepo(conf config.Config) repo.ReportSubscriptionsRepository {
+	var repo repo.ReportSubscriptionsRepository
+	var err error
+
+	switch conf.Storage.Mode {
+	case "memory":
+		repo = memoryrepo.NewReportSubscriptionsRepository()
+	case "sql":
+		repo, err = sqlrepo.NewReportSubscriptionsRepository(conf.Database)
+		if err != nil {
+			log.Fatalf("cannot connect to db %s", err.Error())
+		}
+	default:
+		log.Fatalf("unknown repo mode %s", conf.Storage)
+	}
+
+	return repo
+}
+
+func initBudgetsRepo(conf config.Config) repo.BudgetsRepository {
+	var repo repo.BudgetsRepository
+	var err error
+
+	switch conf.Storage.Mode {
+	case "memory":
+		repo = memoryrepo.NewBudgetsRepository()
+	case "sql":
+		repo, err = sqlrepo.NewBudgetsRepository(conf.Database)
+		if err != nil {
+			log.Fatalf("cannot connect to db %s", err.Error())
+		}
+	default:
+		log.Fatalf("unknown repo mode %s", conf.Storage)
+	}
+
+	return repo
+}
@@ -9,8 +9,12 @@ import (
 	"github.com/google/uuid"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/config"
 	"gitlab.ozon.dev/cranky4/tg-bot/internal/model"
 	expenses_sql_repo "gitlab.ozon.dev/cranky4/tg-bot/internal/repository/sql"
+	serviceconverter "gitlab.ozon.dev/cranky4/tg-bot/internal/service/converter"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/service/expense_exporter"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/service/expense_processor"
 
 	// init pgsql.
 	_ "github.com/jackc/pgx/stdlib"
@@ -66,7 +70,7 @@ var _ = Describe("Testing SQL queries", Ordered, func() {
 		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 		defer cancel()
 
-		res, err := db.ExecContext(ctx, expenses_sql_repo.ExpenseCategoryInsertSQL, category.ID, category.Name)
+		res, err := db.ExecContext(ctx, expenses_sql_repo.ExpenseCategoryInsertSQL, category.ID, category.Name, userId)
 
 		Expect(err).To(BeNil())
 		rows, err := res.RowsAffected()
@@ -78,7 +82,7 @@ var _ = Describe("Testing SQL queries", Ordered, func() {
 		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 		defer cancel()
 
-		row := db.QueryRowContext(ctx, expenses_sql_repo.ExpenseCategorySearchSQL, category.Name)
+		row := db.QueryRowContext(ctx, expenses_sql_repo.ExpenseCategorySearchSQL, userId, category.Name)
 		Expect(row.Err()).To(BeNil())
 		var id, name string
 
@@ -93,7 +97,7 @@ var _ = Describe("Testing SQL queries", Ordered, func() {
 		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 		defer cancel()
 
-		res, err := db.ExecContext(ctx, expenses_sql_repo.ExpensesInsertSQL, expense1.ID, expense1.Amount, expense1.Datetime, expense1.CategoryID, expense1.UserId)
+		res, err := db.ExecContext(ctx, expenses_sql_repo.ExpensesInsertSQL, expense1.ID, expense1.Amount, expense1.Datetime, expense1.CategoryID, expense1.UserId, expense1.AccountID, expense1.AuthorId)
 
 		Expect(err).To(BeNil())
 		rows, err := res.RowsAffected()
@@ -103,7 +107,7 @@ var _ = Describe("Testing SQL queries", Ordered, func() {
 		ctx, cancel = context.WithTimeout(context.Background(), 3*time.Second)
 		defer cancel()
 
-		res, err = db.ExecContext(ctx, expenses_sql_repo.ExpensesInsertSQL, expense2.ID, expense2.Amount, expense2.Datetime, expense2.CategoryID, expense2.UserId)
+		res, err = db.ExecContext(ctx, expenses_sql_repo.ExpensesInsertSQL, expense2.ID, expense2.Amount, expense2.Datetime, expense2.CategoryID, expense2.UserId, expense2.AccountID, expense2.AuthorId)
 
 		Expect(err).To(BeNil())
 		rows, err = res.RowsAffected()
@@ -115,7 +119,7 @@ var _ = Describe("Testing SQL queries", Ordered, func() {
 		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 		defer cancel()
 
-		row := db.QueryRowContext(ctx, expenses_sql_repo.ExpensesSelectCountSQL, time.Now().AddDate(0, 0, -1), userId)
+		row := db.QueryRowContext(ctx, expenses_sql_repo.ExpensesSelectCountSQL, time.Now().AddDate(0, 0, -1), time.Now().AddDate(0, 0, 1), userId)
 		Expect(row.Err()).To(BeNil())
 		var count int
 
@@ -129,7 +133,7 @@ var _ = Describe("Testing SQL queries", Ordered, func() {
 		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 		defer cancel()
 
-		rows, err := db.QueryContext(ctx, expenses_sql_repo.ExpensesSelectSQL, time.Now().AddDate(0, 0, -1), userId)
+		rows, err := db.QueryContext(ctx, expenses_sql_repo.ExpensesSelectSQL, time.Now().AddDate(0, 0, -1), time.Now().AddDate(0, 0, 1), userId)
 
 		Expect(err).To(BeNil())
 		Expect(rows.Err()).To(BeNil())
@@ -139,12 +143,12 @@ var _ = Describe("Testing SQL queries", Ordered, func() {
 			Expect(err).To(BeNil())
 		}()
 
-		var id, categoryName, categoryId string
-		var amount, user int64
+		var id, categoryName, categoryId, accountId, accountName string
+		var amount, user, author int64
 		var datetime time.Time
 
 		Expect(rows.Next()).To(BeTrue())
-		err = rows.Scan(&id, &amount, &datetime, &categoryId, &categoryName, &user)
+		err = rows.Scan(&id, &amount, &datetime, &categoryId, &categoryName, &user, &accountId, &accountName, &author)
 		Expect(err).To(BeNil())
 
 		Expect(expense2.ID).To(Equal(id))
@@ -153,9 +157,10 @@ var _ = Describe("Testing SQL queries", Ordered, func() {
 		Expect(expense2.CategoryID).To(Equal(categoryId))
 		Expect(expense2.Category).To(Equal(categoryName))
 		Expect(expense2.UserId).To(Equal(user))
+		Expect(author).To(Equal(user))
 
 		Expect(rows.Next()).To(BeTrue())
-		err = rows.Scan(&id, &amount, &datetime, &categoryId, &categoryName, &user)
+		err = rows.Scan(&id, &amount, &datetime, &categoryId, &categoryName, &user, &accountId, &accountName, &author)
 		Expect(err).To(BeNil())
 
 		Expect(expense1.ID).To(Equal(id))
@@ -168,11 +173,93 @@ var _ = Describe("Testing SQL queries", Ordered, func() {
 		Expect(rows.Next()).To(BeFalse())
 	})
 
+	It("get top categories", func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+
+		rows, err := db.QueryContext(ctx, expenses_sql_repo.TopCategoriesSQL, userId, 5)
+
+		Expect(err).To(BeNil())
+		Expect(rows.Err()).To(BeNil())
+
+		defer func() {
+			err = rows.Close()
+			Expect(err).To(BeNil())
+		}()
+
+		var name string
+		Expect(rows.Next()).To(BeTrue())
+		err = rows.Scan(&name)
+		Expect(err).To(BeNil())
+		Expect(category.Name).To(Equal(name))
+
+		Expect(rows.Next()).To(BeFalse())
+	})
+
+	It("group expenses by buckets", func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+
+		rows, err := db.QueryContext(
+			ctx,
+			expenses_sql_repo.TrendSelectSQL,
+			time.Now().AddDate(0, 0, -1),
+			time.Now().AddDate(0, 0, 1),
+			userId,
+			string(model.WeekTrendBucket),
+			6, // неделя с воскресенья
+			true,
+		)
+
+		Expect(err).To(BeNil())
+		Expect(rows.Err()).To(BeNil())
+
+		defer func() {
+			err = rows.Close()
+			Expect(err).To(BeNil())
+		}()
+
+		var from time.Time
+		var categoryName string
+		var amount int64
+
+		Expect(rows.Next()).To(BeTrue())
+		err = rows.Scan(&from, &categoryName, &amount)
+		Expect(err).To(BeNil())
+
+		weekStart := model.StartOfWeek(expense1.Datetime, time.Sunday)
+		Expect(from.Format(dateFormat)).To(Equal(weekStart.Format(dateFormat)))
+		Expect(category.Name).To(Equal(categoryName))
+		Expect(expense1.Amount + expense2.Amount).To(Equal(amount))
+
+		Expect(rows.Next()).To(BeFalse())
+	})
+
+	It("store supergroup ledger", func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+
+		supergroupId := int64(-1001234567890)
+		expenseID, err := uuid.NewUUID()
+		Expect(err).To(BeNil())
+
+		_, err = db.ExecContext(ctx, expenses_sql_repo.ExpensesInsertSQL, expenseID.String(), 5000, time.Now(), category.ID, supergroupId, "", userId)
+		Expect(err).To(BeNil())
+		_, err = db.ExecContext(ctx, expenses_sql_repo.UpsertTotalLimitSQL, 30000, supergroupId, "month")
+		Expect(err).To(BeNil())
+
+		res, err := db.ExecContext(ctx, expenses_sql_repo.ExpensesDeleteSQL, expenseID.String(), supergroupId)
+		Expect(err).To(BeNil())
+		rows, err := res.RowsAffected()
+		Expect(err).To(BeNil())
+		Expect(int64(1)).To(Equal(rows))
+	})
+
 	It("upsert limit", func() {
 		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 		defer cancel()
 
-		res, err := db.ExecContext(ctx, expenses_sql_repo.UpsertLimitSQL, category.ID, 15000, userId)
+		res, err := db.ExecContext(ctx, expenses_sql_repo.UpsertLimitSQL, category.ID, 15000, userId, "month")
 
 		Expect(err).To(BeNil())
 		rows, err := res.RowsAffected()
@@ -180,19 +267,670 @@ var _ = Describe("Testing SQL queries", Ordered, func() {
 		Expect(int64(1)).To(Equal(rows))
 	})
 
-	It("select free limit", func() {
+	It("upsert total limit", func() {
 		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 		defer cancel()
 
-		row := db.QueryRowContext(ctx, expenses_sql_repo.FreeLimitSQL, category.ID, userId)
+		res, err := db.ExecContext(ctx, expenses_sql_repo.UpsertTotalLimitSQL, 30000, userId, "week")
+
+		Expect(err).To(BeNil())
+		rows, err := res.RowsAffected()
+		Expect(err).To(BeNil())
+		Expect(int64(1)).To(Equal(rows))
+	})
+
+	It("select limits", func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+
+		rows, err := db.QueryContext(ctx, expenses_sql_repo.LimitsSelectSQL, userId, category.Name)
+		Expect(err).To(BeNil())
+		defer rows.Close() //nolint:errcheck
+
+		count := 0
+		for rows.Next() {
+			count++
+		}
+		Expect(2).To(Equal(count))
+	})
+
+	It("update limit thresholds", func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+
+		res, err := db.ExecContext(ctx, expenses_sql_repo.LimitThresholdsUpdateSQL, "80,100", userId, "month", "category", category.Name)
+
+		Expect(err).To(BeNil())
+		rows, err := res.RowsAffected()
+		Expect(err).To(BeNil())
+		Expect(int64(1)).To(Equal(rows))
+	})
+
+	It("insert limit notification once", func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+
+		periodStart := model.StartOfMonth(time.Now())
+		for i, expected := range []int64{1, 0} {
+			res, err := db.ExecContext(ctx, expenses_sql_repo.LimitNotificationInsertSQL, userId, "total", "week", "", periodStart, 80)
+
+			Expect(err).To(BeNil(), "attempt %d", i)
+			rows, err := res.RowsAffected()
+			Expect(err).To(BeNil())
+			Expect(expected).To(Equal(rows))
+		}
+	})
+
+	It("select spent", func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+
+		from, to := time.Now().AddDate(0, 0, -1), time.Now().AddDate(0, 0, 1)
+
+		var spent int64
+		err := db.QueryRowContext(ctx, expenses_sql_repo.CategorySpentSQL, category.ID, userId, from, to).Scan(&spent)
+		Expect(err).To(BeNil())
+		Expect(int64(20000)).To(Equal(spent))
+
+		err = db.QueryRowContext(ctx, expenses_sql_repo.TotalSpentSQL, userId, from, to).Scan(&spent)
+		Expect(err).To(BeNil())
+		Expect(int64(20000)).To(Equal(spent))
+	})
+
+	It("list categories", func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+
+		rows, err := db.QueryContext(ctx, expenses_sql_repo.ExpenseCategoriesListSQL, userId)
+
+		Expect(err).To(BeNil())
+		Expect(rows.Err()).To(BeNil())
+
+		defer func() {
+			err = rows.Close()
+			Expect(err).To(BeNil())
+		}()
+
+		var id, name string
+		Expect(rows.Next()).To(BeTrue())
+		err = rows.Scan(&id, &name)
+		Expect(err).To(BeNil())
+		Expect(category.ID).To(Equal(id))
+		Expect(category.Name).To(Equal(name))
+
+		Expect(rows.Next()).To(BeFalse())
+	})
 
+	It("count category expenses", func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+
+		row := db.QueryRowContext(ctx, expenses_sql_repo.ExpensesCountCategorySQL, category.ID, userId)
 		Expect(row.Err()).To(BeNil())
-		var limit sql.NullInt64
 
-		err := row.Scan(&limit)
+		var count int
+		err := row.Scan(&count)
 		Expect(err).To(BeNil())
+		Expect(2).To(Equal(count))
+	})
 
-		Expect(true).To(Equal(limit.Valid))
-		Expect(int64(-5000)).To(Equal(limit.Int64))
+	It("rename category", func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+
+		res, err := db.ExecContext(ctx, expenses_sql_repo.ExpenseCategoryRenameSQL, category.Name, category.ID, userId)
+
+		Expect(err).To(BeNil())
+		rows, err := res.RowsAffected()
+		Expect(err).To(BeNil())
+		Expect(int64(1)).To(Equal(rows))
+	})
+
+	It("update expense", func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+
+		res, err := db.ExecContext(ctx, expenses_sql_repo.ExpensesUpdateSQL, 20000, expense2.Datetime, expense2.CategoryID, expense2.ID, expense2.UserId)
+
+		Expect(err).To(BeNil())
+		rows, err := res.RowsAffected()
+		Expect(err).To(BeNil())
+		Expect(int64(1)).To(Equal(rows))
+	})
+
+	It("delete expense", func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+
+		res, err := db.ExecContext(ctx, expenses_sql_repo.ExpensesDeleteSQL, expense2.ID, expense2.UserId)
+
+		Expect(err).To(BeNil())
+		rows, err := res.RowsAffected()
+		Expect(err).To(BeNil())
+		Expect(int64(1)).To(Equal(rows))
+	})
+
+	It("upsert category rule", func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+
+		_, err := db.ExecContext(ctx, expenses_sql_repo.CategoryRuleUpsertSQL, userId, "alias", "еда", "Продукты")
+		Expect(err).To(BeNil())
+
+		_, err = db.ExecContext(ctx, expenses_sql_repo.CategoryRuleUpsertSQL, userId, "alias", "ЕДА", category.Name)
+		Expect(err).To(BeNil())
+	})
+
+	It("select category rules", func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+
+		var ruleType, pattern, ruleCategory string
+		var ruleUserId int64
+		err := db.QueryRowContext(ctx, expenses_sql_repo.CategoryRulesSelectSQL, userId).
+			Scan(&ruleType, &pattern, &ruleCategory, &ruleUserId)
+
+		Expect(err).To(BeNil())
+		Expect("ЕДА").To(Equal(pattern))
+		Expect(category.Name).To(Equal(ruleCategory))
+	})
+
+	It("move category rules to renamed category", func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+
+		_, err := db.ExecContext(ctx, expenses_sql_repo.CategoryRulesMoveCategorySQL, "Квартира", userId, "дом")
+		Expect(err).To(BeNil())
+
+		var ruleCategory string
+		err = db.QueryRowContext(ctx, expenses_sql_repo.CategoryRulesSelectSQL, userId).
+			Scan(new(string), new(string), &ruleCategory, new(int64))
+		Expect(err).To(BeNil())
+		Expect("Квартира").To(Equal(ruleCategory))
+
+		_, err = db.ExecContext(ctx, expenses_sql_repo.CategoryRulesMoveCategorySQL, category.Name, userId, "Квартира")
+		Expect(err).To(BeNil())
+	})
+
+	It("delete category rule", func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+
+		res, err := db.ExecContext(ctx, expenses_sql_repo.CategoryRuleDeleteSQL, userId, "еда")
+
+		Expect(err).To(BeNil())
+		rows, err := res.RowsAffected()
+		Expect(err).To(BeNil())
+		Expect(int64(1)).To(Equal(rows))
+	})
+
+	It("upsert report subscription", func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+
+		nextRunAt := time.Date(2022, 11, 7, 6, 0, 0, 0, time.UTC)
+		_, err := db.ExecContext(ctx, expenses_sql_repo.ReportSubscriptionUpsertSQL, userId, "weekly", 1, 1, 9, 0, nextRunAt)
+		Expect(err).To(BeNil())
+	})
+
+	It("select due report subscriptions", func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+
+		var subscriptionUserId int64
+		var period string
+		var weekday, day, hour, minute int
+		var nextRunAt time.Time
+		err := db.QueryRowContext(ctx, expenses_sql_repo.DueReportSubscriptionsSelectSQL, time.Date(2022, 11, 7, 6, 0, 0, 0, time.UTC), 10).
+			Scan(&subscriptionUserId, &period, &weekday, &day, &hour, &minute, &nextRunAt)
+
+		Expect(err).To(BeNil())
+		Expect(userId).To(Equal(subscriptionUserId))
+		Expect("weekly").To(Equal(period))
+	})
+
+	It("reschedule report subscription once", func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+
+		prevRunAt := time.Date(2022, 11, 7, 6, 0, 0, 0, time.UTC)
+		nextRunAt := prevRunAt.AddDate(0, 0, 7)
+
+		res, err := db.ExecContext(ctx, expenses_sql_repo.ReportSubscriptionRescheduleSQL, userId, "weekly", prevRunAt, nextRunAt)
+		Expect(err).To(BeNil())
+		rows, err := res.RowsAffected()
+		Expect(err).To(BeNil())
+		Expect(int64(1)).To(Equal(rows))
+
+		res, err = db.ExecContext(ctx, expenses_sql_repo.ReportSubscriptionRescheduleSQL, userId, "weekly", prevRunAt, nextRunAt)
+		Expect(err).To(BeNil())
+		rows, err = res.RowsAffected()
+		Expect(err).To(BeNil())
+		Expect(int64(0)).To(Equal(rows))
+	})
+
+	It("delete report subscription", func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+
+		res, err := db.ExecContext(ctx, expenses_sql_repo.ReportSubscriptionDeleteSQL, userId, "weekly")
+
+		Expect(err).To(BeNil())
+		rows, err := res.RowsAffected()
+		Expect(err).To(BeNil())
+		Expect(int64(1)).To(Equal(rows))
+	})
+
+	recurringID, er := uuid.NewUUID()
+	if er != nil {
+		Fail(er.Error())
+	}
+	recurringRunAt := time.Date(2022, 11, 5, 0, 0, 0, 0, time.UTC)
+
+	It("insert recurring expense", func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+
+		_, err := db.ExecContext(
+			ctx,
+			expenses_sql_repo.RecurringExpenseInsertSQL,
+			recurringID.String(), userId, 2500000, "RUB", "Аренда", "monthly", 0, 5, 0, 0, recurringRunAt,
+		)
+		Expect(err).To(BeNil())
+	})
+
+	It("select due recurring expenses", func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+
+		rows, err := db.QueryContext(ctx, expenses_sql_repo.DueRecurringExpensesSelectSQL, recurringRunAt, 10)
+		Expect(err).To(BeNil())
+		defer rows.Close() //nolint:errcheck
+
+		Expect(rows.Next()).To(BeTrue())
+	})
+
+	It("reschedule recurring expense once", func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+
+		nextRunAt := recurringRunAt.AddDate(0, 1, 0)
+
+		res, err := db.ExecContext(ctx, expenses_sql_repo.RecurringExpenseRescheduleSQL, recurringID.String(), recurringRunAt, nextRunAt)
+		Expect(err).To(BeNil())
+		rows, err := res.RowsAffected()
+		Expect(err).To(BeNil())
+		Expect(int64(1)).To(Equal(rows))
+
+		res, err = db.ExecContext(ctx, expenses_sql_repo.RecurringExpenseRescheduleSQL, recurringID.String(), recurringRunAt, nextRunAt)
+		Expect(err).To(BeNil())
+		rows, err = res.RowsAffected()
+		Expect(err).To(BeNil())
+		Expect(int64(0)).To(Equal(rows))
+	})
+
+	It("pause and resume recurring expense", func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+
+		_, err := db.ExecContext(ctx, expenses_sql_repo.RecurringExpensePauseSQL, recurringID.String(), userId)
+		Expect(err).To(BeNil())
+
+		var paused bool
+		err = db.QueryRowContext(ctx, expenses_sql_repo.RecurringExpensesSelectSQL, userId).Scan(
+			new(string), new(int64), new(int64), new(string), new(string), new(string),
+			new(int), new(int), new(int), new(int), new(time.Time), &paused,
+		)
+		Expect(err).To(BeNil())
+		Expect(paused).To(BeTrue())
+
+		res, err := db.ExecContext(ctx, expenses_sql_repo.RecurringExpenseResumeSQL, recurringID.String(), userId, recurringRunAt.AddDate(0, 2, 0))
+		Expect(err).To(BeNil())
+		rows, err := res.RowsAffected()
+		Expect(err).To(BeNil())
+		Expect(int64(1)).To(Equal(rows))
+	})
+
+	incomeID, er := uuid.NewUUID()
+	if er != nil {
+		Fail(er.Error())
+	}
+	incomeDate := time.Date(2022, 10, 5, 0, 0, 0, 0, time.UTC)
+
+	It("insert income", func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+
+		_, err := db.ExecContext(ctx, expenses_sql_repo.IncomeInsertSQL, incomeID.String(), userId, 15000000, "Зарплата", incomeDate)
+		Expect(err).To(BeNil())
+	})
+
+	It("select incomes", func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+
+		var amount int64
+		err := db.QueryRowContext(ctx, expenses_sql_repo.IncomesSelectSQL, incomeDate, incomeDate.AddDate(0, 0, 1), userId).Scan(
+			new(string), new(int64), &amount, new(string), new(time.Time),
+		)
+		Expect(err).To(BeNil())
+		Expect(int64(15000000)).To(Equal(amount))
+	})
+
+	accountFromID, er := uuid.NewUUID()
+	if er != nil {
+		Fail(er.Error())
+	}
+	accountToID, er := uuid.NewUUID()
+	if er != nil {
+		Fail(er.Error())
+	}
+
+	It("insert accounts and transfer", func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+
+		_, err := db.ExecContext(ctx, expenses_sql_repo.AccountInsertSQL, accountFromID.String(), "Карта", "RUB", 1500000, userId)
+		Expect(err).To(BeNil())
+		_, err = db.ExecContext(ctx, expenses_sql_repo.AccountInsertSQL, accountToID.String(), "Накопления", "RUB", 0, userId)
+		Expect(err).To(BeNil())
+
+		transferID, err := uuid.NewUUID()
+		Expect(err).To(BeNil())
+		_, err = db.ExecContext(
+			ctx,
+			expenses_sql_repo.TransferInsertSQL,
+			transferID.String(), accountFromID.String(), accountToID.String(), 500000, time.Now(), userId,
+		)
+		Expect(err).To(BeNil())
+	})
+
+	It("search account ignoring case", func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+
+		var id string
+		err := db.QueryRowContext(ctx, expenses_sql_repo.AccountSearchSQL, userId, "карта").Scan(&id, new(string), new(string), new(int64))
+		Expect(err).To(BeNil())
+		Expect(accountFromID.String()).To(Equal(id))
+	})
+
+	It("select account balances", func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+
+		rows, err := db.QueryContext(ctx, expenses_sql_repo.AccountBalancesSQL, userId)
+		Expect(err).To(BeNil())
+		defer rows.Close() //nolint:errcheck
+
+		balances := make(map[string]int64)
+		for rows.Next() {
+			var id string
+			var balance int64
+			Expect(rows.Scan(&id, new(string), new(string), new(int64), &balance)).To(BeNil())
+			balances[id] = balance
+		}
+
+		Expect(int64(1000000)).To(Equal(balances[accountFromID.String()]))
+		Expect(int64(500000)).To(Equal(balances[accountToID.String()]))
+	})
+
+	var budgetId int64
+
+	It("insert budget with owner", func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+
+		err := db.QueryRowContext(ctx, expenses_sql_repo.BudgetInsertSQL, "Семья", "ABCD1234", userId).Scan(&budgetId)
+		Expect(err).To(BeNil())
+		Expect(model.IsSharedBudget(budgetId)).To(BeTrue())
+
+		_, err = db.ExecContext(ctx, expenses_sql_repo.BudgetMemberUpsertSQL, budgetId, userId, "alice", "owner")
+		Expect(err).To(BeNil())
+	})
+
+	It("select active budget member", func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+
+		_, err := db.ExecContext(ctx, expenses_sql_repo.ActiveBudgetUpsertSQL, userId, budgetId)
+		Expect(err).To(BeNil())
+
+		var activeBudgetId int64
+		var role string
+		err = db.QueryRowContext(ctx, expenses_sql_repo.ActiveMemberSelectSQL, userId).Scan(&activeBudgetId, new(int64), new(string), &role)
+		Expect(err).To(BeNil())
+		Expect(budgetId).To(Equal(activeBudgetId))
+		Expect("owner").To(Equal(role))
+
+		_, err = db.ExecContext(ctx, expenses_sql_repo.ActiveBudgetDeleteSQL, userId)
+		Expect(err).To(BeNil())
+	})
+
+	It("select debt balances of split expense", func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+
+		_, err := db.ExecContext(ctx, expenses_sql_repo.ExpenseShareInsertSQL, expense1.ID, budgetId, userId, userId, 5000)
+		Expect(err).To(BeNil())
+		_, err = db.ExecContext(ctx, expenses_sql_repo.ExpenseShareInsertSQL, expense1.ID, budgetId, userId, userId+1, 5000)
+		Expect(err).To(BeNil())
+
+		settlementID, err := uuid.NewUUID()
+		Expect(err).To(BeNil())
+		_, err = db.ExecContext(ctx, expenses_sql_repo.SettlementInsertSQL, settlementID.String(), budgetId, userId+1, userId, 2000, time.Now())
+		Expect(err).To(BeNil())
+
+		rows, err := db.QueryContext(ctx, expenses_sql_repo.DebtBalancesSelectSQL, budgetId)
+		Expect(err).To(BeNil())
+		defer rows.Close() //nolint:errcheck
+
+		balances := make(map[int64]int64)
+		for rows.Next() {
+			var user, balance int64
+			Expect(rows.Scan(&user, &balance)).To(BeNil())
+			balances[user] = balance
+		}
+
+		Expect(int64(3000)).To(Equal(balances[userId]))
+		Expect(int64(-3000)).To(Equal(balances[userId+1]))
+	})
+
+	It("upsert and select expense attachment", func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+
+		_, err := db.ExecContext(ctx, expenses_sql_repo.AttachmentUpsertSQL, expense1.ID, userId, "100/receipt.jpg", "receipt.jpg", "image/jpeg", 7)
+		Expect(err).To(BeNil())
+		_, err = db.ExecContext(ctx, expenses_sql_repo.AttachmentUpsertSQL, expense1.ID, userId, "100/receipt.pdf", "receipt.pdf", "application/pdf", 3)
+		Expect(err).To(BeNil())
+
+		var attachment model.Attachment
+		err = db.QueryRowContext(ctx, expenses_sql_repo.AttachmentSelectSQL, expense1.ID, userId).Scan(
+			&attachment.ExpenseID,
+			&attachment.UserId,
+			&attachment.Key,
+			&attachment.FileName,
+			&attachment.MimeType,
+			&attachment.Size,
+			&attachment.CreatedAt,
+		)
+		Expect(err).To(BeNil())
+		Expect("100/receipt.pdf").To(Equal(attachment.Key))
+		Expect(int64(3)).To(Equal(attachment.Size))
+
+		err = db.QueryRowContext(ctx, expenses_sql_repo.AttachmentSelectSQL, expense1.ID, userId+1).Scan(&attachment.ExpenseID)
+		Expect(err).To(Equal(sql.ErrNoRows))
+	})
+
+	It("replace import token", func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+
+		_, err := db.ExecContext(ctx, expenses_sql_repo.ImportTokenUpsertSQL, userId, "first-hash")
+		Expect(err).To(BeNil())
+		_, err = db.ExecContext(ctx, expenses_sql_repo.ImportTokenUpsertSQL, userId, "second-hash")
+		Expect(err).To(BeNil())
+
+		err = db.QueryRowContext(ctx, expenses_sql_repo.ImportTokenUserSelectSQL, "first-hash").Scan(new(int64))
+		Expect(err).To(Equal(sql.ErrNoRows))
+
+		var tokenUserId int64
+		err = db.QueryRowContext(ctx, expenses_sql_repo.ImportTokenUserSelectSQL, "second-hash").Scan(&tokenUserId)
+		Expect(err).To(BeNil())
+		Expect(tokenUserId).To(Equal(userId))
+	})
+
+	It("export expense at stored wall clock", func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+
+		repository, err := expenses_sql_repo.NewRepository(config.DatabaseConf{Dsn: dsn})
+		Expect(err).To(BeNil())
+
+		// трата пользователя из Москвы сразу после полуночи, диапазон приходит из брокера со смещением пользователя
+		ledgerId := userId + 2
+		moscow := time.FixedZone("", 3*60*60)
+		err = repository.Add(ctx, model.Expense{
+			ID:       uuid.NewString(),
+			Amount:   35000,
+			Category: "Кофе",
+			Datetime: time.Date(2022, 11, 20, 0, 30, 0, 0, moscow),
+			UserId:   ledgerId,
+			AuthorId: ledgerId,
+		})
+		Expect(err).To(BeNil())
+
+		exporter := expense_exporter.NewExporter(repository, serviceconverter.NewConverter(nil))
+		dateRange := model.NewDateRange(time.Date(2022, 11, 20, 0, 0, 0, 0, moscow), time.Date(2022, 11, 21, 0, 0, 0, 0, moscow))
+		export, err := exporter.Export(ctx, model.CSVExportFormat, dateRange, "RUB", ledgerId)
+		Expect(err).To(BeNil())
+
+		Expect(export.Count).To(Equal(1))
+		Expect(string(export.File.Data)).To(ContainSubstring("2022-11-20 00:30:00,Кофе,350.00,RUB"))
+	})
+
+	It("find imported duplicate before midnight", func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+
+		conf := config.DatabaseConf{Dsn: dsn}
+		repository, err := expenses_sql_repo.NewRepository(conf)
+		Expect(err).To(BeNil())
+		rulesRepository, err := expenses_sql_repo.NewCategoryRulesRepository(conf)
+		Expect(err).To(BeNil())
+
+		ledgerId := userId + 3
+		moscow := time.FixedZone("", 3*60*60)
+		datetime := time.Date(2022, 11, 20, 23, 30, 0, 0, moscow)
+		err = repository.AddBatch(ctx, []model.Expense{
+			{ID: uuid.NewString(), Amount: 35000, Category: "Такси", Datetime: datetime, UserId: ledgerId, AuthorId: ledgerId},
+		})
+		Expect(err).To(BeNil())
+
+		processor := expense_processor.NewProcessor(
+			repository, nil, nil, rulesRepository, nil, nil, nil, serviceconverter.NewConverter(nil), nil, nil,
+		)
+		results, err := processor.ImportExpenses(ctx, []expense_processor.ImportItem{
+			{Amount: 350, Currency: "RUB", Category: "Такси", Datetime: datetime},
+			{Amount: 350, Currency: "RUB", Category: "Такси", Datetime: datetime.Add(time.Hour)},
+		}, ledgerId, ledgerId, true)
+		Expect(err).To(BeNil())
+		Expect(results).To(Equal([]expense_processor.ImportItemResult{
+			{Category: "Такси", Duplicate: true},
+			{Category: "Такси"},
+		}))
+	})
+
+	It("add recurring occurrence once", func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+
+		repository, err := expenses_sql_repo.NewRepository(config.DatabaseConf{Dsn: dsn})
+		Expect(err).To(BeNil())
+
+		ledgerId := userId + 4
+		recurringId := uuid.NewString()
+		occurrence := time.Date(2022, 11, 7, 9, 0, 0, 0, time.UTC)
+		for i, expected := range []bool{true, false} {
+			added, err := repository.AddOccurrence(ctx, model.Expense{
+				Amount:   59900,
+				Category: "Подписки",
+				Datetime: occurrence,
+				UserId:   ledgerId,
+				AuthorId: ledgerId,
+			}, recurringId, occurrence)
+			Expect(err).To(BeNil())
+			Expect(added).To(Equal(expected), "попытка %d", i)
+		}
+
+		expenses, err := repository.GetExpenses(ctx, model.NewDateRange(occurrence, occurrence.Add(time.Hour)), ledgerId)
+		Expect(err).To(BeNil())
+		Expect(expenses).To(HaveLen(1))
+	})
+
+	It("delete category rules with category", func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+
+		conf := config.DatabaseConf{Dsn: dsn}
+		repository, err := expenses_sql_repo.NewRepository(conf)
+		Expect(err).To(BeNil())
+		rulesRepository, err := expenses_sql_repo.NewCategoryRulesRepository(conf)
+		Expect(err).To(BeNil())
+
+		ledgerId := userId + 5
+		Expect(repository.SetLimit(ctx, model.ExpenseLimit{
+			Scope: model.CategoryLimitScope, Period: model.Week, Category: "Такси", Amount: 50000, UserId: ledgerId,
+		})).To(BeNil())
+		Expect(rulesRepository.SaveRule(ctx, model.CategoryRule{
+			Type: model.KeywordCategoryRule, Pattern: "uber", Category: "такси", UserId: ledgerId,
+		})).To(BeNil())
+
+		found, err := repository.DeleteCategory(ctx, ledgerId, "Такси")
+		Expect(err).To(BeNil())
+		Expect(found).To(BeTrue())
+
+		rules, err := rulesRepository.GetRules(ctx, ledgerId)
+		Expect(err).To(BeNil())
+		Expect(rules).To(BeEmpty())
+	})
+
+	It("keep expense amount when shares fail", func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+
+		conf := config.DatabaseConf{Dsn: dsn}
+		repository, err := expenses_sql_repo.NewRepository(conf)
+		Expect(err).To(BeNil())
+		splitsRepository, err := expenses_sql_repo.NewSplitsRepository(conf)
+		Expect(err).To(BeNil())
+
+		ledgerId := userId + 6
+		datetime := time.Date(2022, 11, 21, 12, 0, 0, 0, time.UTC)
+		ex := model.Expense{ID: uuid.NewString(), Amount: 10000, Category: "Кафе", Datetime: datetime, UserId: ledgerId, AuthorId: ledgerId}
+		Expect(repository.Add(ctx, ex)).To(BeNil())
+		Expect(splitsRepository.AddShares(ctx, []model.ExpenseShare{
+			{ExpenseID: ex.ID, BudgetID: ledgerId, PayerID: 200, UserID: 200, Amount: 5000},
+			{ExpenseID: ex.ID, BudgetID: ledgerId, PayerID: 200, UserID: 300, Amount: 5000},
+		})).To(BeNil())
+
+		// одинаковые участники нарушают ключ долей, трата должна остаться прежней
+		ex.Amount = 12000
+		_, err = repository.Update(ctx, ex, []model.ExpenseShare{
+			{ExpenseID: ex.ID, BudgetID: ledgerId, PayerID: 200, UserID: 200, Amount: 6000},
+			{ExpenseID: ex.ID, BudgetID: ledgerId, PayerID: 200, UserID: 200, Amount: 6000},
+		})
+		Expect(err).NotTo(BeNil())
+
+		expenses, err := repository.GetExpenses(ctx, model.NewDateRange(datetime, datetime.Add(time.Hour)), ledgerId)
+		Expect(err).To(BeNil())
+		Expect(expenses).To(HaveLen(1))
+		Expect(expenses[0].Amount).To(Equal(int64(10000)))
+
+		shares, err := splitsRepository.GetShares(ctx, ex.ID)
+		Expect(err).To(BeNil())
+		Expect(shares).To(HaveLen(2))
+		Expect(shares[0].Amount).To(Equal(int64(5000)))
 	})
 })
@@ -0,0 +1,17 @@
+package blobstorage
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrBlobNotFound файла с таким ключом в хранилище нет
+var ErrBlobNotFound = errors.New("файл не найден в хранилище")
+
+// BlobStorage хранилище файлов по ключу: чеков, документов
+type BlobStorage interface {
+	Put(ctx context.Context, key string, data []byte) error
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Delete удаляет файл, удаление отсутствующего файла не считается ошибкой
+	Delete(ctx context.Context, key string) error
+}
@@ -0,0 +1,113 @@
+package local
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	blobstorage "gitlab.ozon.dev/cranky4/tg-bot/internal/clients/blob_storage"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/config"
+)
+
+const (
+	dirPerm  = 0o750
+	filePerm = 0o640
+
+	invalidKeyErrMsg = "недопустимый ключ файла"
+	putErrMsg        = "ошибка записи файла"
+	getErrMsg        = "ошибка чтения файла"
+	deleteErrMsg     = "ошибка удаления файла"
+)
+
+// localStorage хранит файлы в каталоге на диске, ключ - относительный путь внутри каталога
+type localStorage struct {
+	dir string
+}
+
+func NewLocalStorage(conf config.BlobStorageConf) (blobstorage.BlobStorage, error) {
+	dir, err := filepath.Abs(conf.Dir)
+	if err != nil {
+		return nil, errors.Wrap(err, "filepath.Abs")
+	}
+
+	if err = os.MkdirAll(dir, dirPerm); err != nil {
+		return nil, errors.Wrap(err, "os.MkdirAll")
+	}
+
+	return &localStorage{dir: dir}, nil
+}
+
+func (s *localStorage) Put(ctx context.Context, key string, data []byte) error {
+	span, _ := opentracing.StartSpanFromContext(ctx, "LocalStorage_Put")
+	defer span.Finish()
+
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(path), dirPerm); err != nil {
+		return errors.Wrap(err, putErrMsg)
+	}
+
+	// пишем во временный файл, чтобы при сбое не оставить обрезанный
+	tmp := path + ".tmp"
+	if err = os.WriteFile(tmp, data, filePerm); err != nil {
+		return errors.Wrap(err, putErrMsg)
+	}
+
+	if err = os.Rename(tmp, path); err != nil {
+		return errors.Wrap(err, putErrMsg)
+	}
+
+	return nil
+}
+
+func (s *localStorage) Get(ctx context.Context, key string) ([]byte, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "LocalStorage_Get")
+	defer span.Finish()
+
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, blobstorage.ErrBlobNotFound
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, getErrMsg)
+	}
+
+	return data, nil
+}
+
+func (s *localStorage) Delete(ctx context.Context, key string) error {
+	span, _ := opentracing.StartSpanFromContext(ctx, "LocalStorage_Delete")
+	defer span.Finish()
+
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err = os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return errors.Wrap(err, deleteErrMsg)
+	}
+
+	return nil
+}
+
+// path путь к файлу, ключ не должен выводить за пределы каталога хранилища
+func (s *localStorage) path(key string) (string, error) {
+	path := filepath.Join(s.dir, filepath.FromSlash(key))
+	if key == "" || !strings.HasPrefix(path, s.dir+string(filepath.Separator)) {
+		return "", errors.New(invalidKeyErrMsg)
+	}
+
+	return path, nil
+}
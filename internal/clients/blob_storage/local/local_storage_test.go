@@ -0,0 +1,56 @@
+package local
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	blobstorage "gitlab.ozon.dev/cranky4/tg-bot/internal/clients/blob_storage"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/config"
+)
+
+func TestLocalStorageShouldReturnSavedFile(t *testing.T) {
+	ctx := context.Background()
+	storage, err := NewLocalStorage(config.BlobStorageConf{Dir: t.TempDir()})
+	assert.NoError(t, err)
+
+	err = storage.Put(ctx, "-100/receipt.jpg", []byte("receipt"))
+	assert.NoError(t, err)
+
+	data, err := storage.Get(ctx, "-100/receipt.jpg")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("receipt"), data)
+
+	_, err = storage.Get(ctx, "-100/unknown.jpg")
+	assert.ErrorIs(t, err, blobstorage.ErrBlobNotFound)
+}
+
+func TestLocalStorageShouldDeleteFile(t *testing.T) {
+	ctx := context.Background()
+	storage, err := NewLocalStorage(config.BlobStorageConf{Dir: t.TempDir()})
+	assert.NoError(t, err)
+
+	err = storage.Put(ctx, "-100/receipt.jpg", []byte("receipt"))
+	assert.NoError(t, err)
+
+	assert.NoError(t, storage.Delete(ctx, "-100/receipt.jpg"))
+
+	_, err = storage.Get(ctx, "-100/receipt.jpg")
+	assert.ErrorIs(t, err, blobstorage.ErrBlobNotFound)
+
+	// повторное удаление не ошибка
+	assert.NoError(t, storage.Delete(ctx, "-100/receipt.jpg"))
+	assert.Error(t, storage.Delete(ctx, "../receipt.jpg"))
+}
+
+func TestLocalStorageShouldRejectKeysOutsideDir(t *testing.T) {
+	ctx := context.Background()
+	storage, err := NewLocalStorage(config.BlobStorageConf{Dir: t.TempDir()})
+	assert.NoError(t, err)
+
+	assert.Error(t, storage.Put(ctx, "../receipt.jpg", []byte("receipt")))
+	assert.Error(t, storage.Put(ctx, "", []byte("receipt")))
+
+	_, err = storage.Get(ctx, "../../etc/passwd")
+	assert.Error(t, err)
+}
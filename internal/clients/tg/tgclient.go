@@ -2,22 +2,39 @@ package tg
 
 import (
 	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"github.com/pkg/errors"
 	"gitlab.ozon.dev/cranky4/tg-bot/internal/config"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/model"
 	"gitlab.ozon.dev/cranky4/tg-bot/internal/service/logger"
 	servicemessages "gitlab.ozon.dev/cranky4/tg-bot/internal/service/messages"
 )
 
+const (
+	// maxDownloadSize Bot API отдает боту файлы не больше 20 МБ
+	maxDownloadSize = 20 << 20
+	downloadTimeout = time.Minute
+)
+
 type TgClient interface {
 	SendMessage(text string, userID int64, buttons []string) error
+	SendInlineKeyboard(text string, userID int64, buttons []model.InlineButton) error
+	SendDocument(caption string, userID int64, file model.File) error
+	SendPhoto(caption string, userID int64, file model.File) error
+	DownloadFile(fileID string) ([]byte, error)
 	ListenUpdates(ctx context.Context, msgModel *servicemessages.Model)
 	Stop()
 }
 
 type client struct {
-	api *tgbotapi.BotAPI
+	api        *tgbotapi.BotAPI
+	httpClient *http.Client
 }
 
 func New(tokenGetter config.TokenGetter) (TgClient, error) {
@@ -26,7 +43,7 @@ func New(tokenGetter config.TokenGetter) (TgClient, error) {
 		return nil, errors.Wrap(err, "NewBotAPI")
 	}
 
-	return &client{api: api}, nil
+	return &client{api: api, httpClient: &http.Client{Timeout: downloadTimeout}}, nil
 }
 
 func (c *client) SendMessage(text string, userID int64, buttons []string) error {
@@ -49,6 +66,70 @@ func (c *client) SendMessage(text string, userID int64, buttons []string) error
 	return nil
 }
 
+func (c *client) SendInlineKeyboard(text string, userID int64, buttons []model.InlineButton) error {
+	msg := tgbotapi.NewMessage(userID, text)
+
+	rows := make([][]tgbotapi.InlineKeyboardButton, 0, len(buttons))
+	for _, b := range buttons {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(b.Text, b.Data)))
+	}
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(rows...)
+
+	if _, err := c.api.Send(msg); err != nil {
+		return errors.Wrap(err, "client.Send")
+	}
+	return nil
+}
+
+func (c *client) SendDocument(caption string, userID int64, file model.File) error {
+	msg := tgbotapi.NewDocument(userID, tgbotapi.FileBytes{Name: file.Name, Bytes: file.Data})
+	msg.Caption = caption
+
+	if _, err := c.api.Send(msg); err != nil {
+		return errors.Wrap(err, "client.Send")
+	}
+	return nil
+}
+
+func (c *client) SendPhoto(caption string, userID int64, file model.File) error {
+	msg := tgbotapi.NewPhoto(userID, tgbotapi.FileBytes{Name: file.Name, Bytes: file.Data})
+	msg.Caption = caption
+
+	if _, err := c.api.Send(msg); err != nil {
+		return errors.Wrap(err, "client.Send")
+	}
+	return nil
+}
+
+func (c *client) DownloadFile(fileID string) ([]byte, error) {
+	url, err := c.api.GetFileDirectURL(fileID)
+	if err != nil {
+		return nil, errors.Wrap(err, "client.GetFileDirectURL")
+	}
+
+	resp, err := c.httpClient.Get(url) //nolint:noctx
+	if err != nil {
+		// в ссылке токен бота, в лог она попасть не должна
+		return nil, errors.New("ошибка скачивания файла")
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("ошибка скачивания файла: %s", resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxDownloadSize+1))
+	if err != nil {
+		return nil, errors.Wrap(err, "io.ReadAll")
+	}
+
+	if len(data) > maxDownloadSize {
+		return nil, errors.New("файл больше 20 МБ")
+	}
+
+	return data, nil
+}
+
 func (c *client) ListenUpdates(ctx context.Context, msgModel *servicemessages.Model) {
 	u := tgbotapi.NewUpdate(0)
 	u.Timeout = 5
@@ -58,20 +139,129 @@ func (c *client) ListenUpdates(ctx context.Context, msgModel *servicemessages.Mo
 	logger.Info("listening for messages")
 
 	for update := range updates {
-		if update.Message != nil {
-			err := msgModel.IncomingMessage(ctx, servicemessages.Message{
-				Text:             update.Message.Text,
-				UserID:           update.Message.From.ID,
-				Command:          update.Message.Command(),
-				CommandArguments: update.Message.CommandArguments(),
-			})
-			if err != nil {
-				logger.Error(err.Error())
-			}
+		if update.Message != nil && update.Message.From != nil {
+			c.handleMessage(ctx, update.Message, msgModel)
+		}
+
+		if update.CallbackQuery != nil {
+			c.handleCallback(ctx, update.CallbackQuery, msgModel)
 		}
 	}
 }
 
+func (c *client) handleMessage(ctx context.Context, message *tgbotapi.Message, msgModel *servicemessages.Model) {
+	msg := servicemessages.Message{
+		Text:             message.Text,
+		UserID:           message.From.ID,
+		ChatID:           message.Chat.ID,
+		UserName:         userName(message.From),
+		BotName:          c.api.Self.UserName,
+		Command:          message.Command(),
+		CommandArguments: message.CommandArguments(),
+	}
+	commandWithAt := message.CommandWithAt()
+
+	// у фото и документа текст в подписи, команда тоже: /addExpense 350;Кафе
+	if attachment := messageAttachment(message); attachment != nil {
+		msg.Attachment = attachment
+		msg.Text = message.Caption
+		commandWithAt, msg.CommandArguments = captionCommand(message.Caption)
+		msg.Command, _, _ = strings.Cut(commandWithAt, "@")
+	}
+
+	if c.isCommandForOtherBot(commandWithAt) {
+		return
+	}
+
+	if err := msgModel.IncomingMessage(ctx, msg); err != nil {
+		logger.Error(err.Error())
+	}
+}
+
+// messageAttachment фото в лучшем качестве или документ сообщения, nil - файла нет
+func messageAttachment(message *tgbotapi.Message) *servicemessages.Attachment {
+	if message.Document != nil {
+		return &servicemessages.Attachment{
+			FileID:   message.Document.FileID,
+			FileName: message.Document.FileName,
+			MimeType: message.Document.MimeType,
+		}
+	}
+
+	if len(message.Photo) > 0 {
+		// размеры фото идут по возрастанию
+		photo := message.Photo[len(message.Photo)-1]
+		return &servicemessages.Attachment{
+			FileID:   photo.FileID,
+			FileName: fmt.Sprintf("photo_%s.jpg", photo.FileUniqueID),
+			MimeType: "image/jpeg",
+		}
+	}
+
+	return nil
+}
+
+// captionCommand команда с именем бота и ее аргументы из подписи, для подписи без команды - пустая команда
+func captionCommand(caption string) (string, string) {
+	if !strings.HasPrefix(caption, "/") {
+		return "", ""
+	}
+
+	command, args, _ := strings.Cut(caption[1:], " ")
+	return command, strings.TrimSpace(args)
+}
+
+func (c *client) handleCallback(ctx context.Context, query *tgbotapi.CallbackQuery, msgModel *servicemessages.Model) {
+	// Убирает кнопки, чтобы повторное нажатие не создало дубль
+	if query.Message != nil {
+		edit := tgbotapi.NewEditMessageReplyMarkup(
+			query.Message.Chat.ID,
+			query.Message.MessageID,
+			tgbotapi.InlineKeyboardMarkup{InlineKeyboard: [][]tgbotapi.InlineKeyboardButton{}},
+		)
+		if _, err := c.api.Request(edit); err != nil {
+			logger.Error(errors.Wrap(err, "client.Request").Error())
+		}
+	}
+
+	callback := servicemessages.Callback{
+		Data:     query.Data,
+		UserID:   query.From.ID,
+		UserName: userName(query.From),
+	}
+	if query.Message != nil {
+		callback.ChatID = query.Message.Chat.ID
+	}
+
+	err := msgModel.IncomingCallback(ctx, callback)
+	if err != nil {
+		logger.Error(err.Error())
+	}
+
+	if _, err = c.api.Request(tgbotapi.NewCallback(query.ID, "")); err != nil {
+		logger.Error(errors.Wrap(err, "client.Request").Error())
+	}
+}
+
+// isCommandForOtherBot команда в групповом чате адресована другому боту: /addExpense@otherbot
+func (c *client) isCommandForOtherBot(command string) bool {
+	at := strings.Index(command, "@")
+	if at < 0 {
+		return false
+	}
+
+	return !strings.EqualFold(command[at+1:], c.api.Self.UserName)
+}
+
+// userName ник пользователя, либо имя, если ника нет
+func userName(user *tgbotapi.User) string {
+	if user.UserName != "" {
+		return user.UserName
+	}
+
+	return user.FirstName
+}
+
 func (c *client) Stop() {
 	c.api.StopReceivingUpdates()
 }
@@ -2,6 +2,7 @@ package config
 
 import (
 	"os"
+	"time"
 
 	"github.com/pkg/errors"
 	"gopkg.in/yaml.v3"
@@ -12,17 +13,20 @@ const (
 )
 
 type Config struct {
-	Token           string            `yaml:"token"`
-	Storage         StorageConf       `yaml:"storage"`
-	Database        DatabaseConf      `yaml:"database"`
-	Logger          LoggerConf        `yaml:"logger"`
-	Metrics         MetricsConf       `yaml:"metrics"`
-	ReporterMetrics MetricsConf       `yaml:"reporter_metrics"`
-	Cache           CacheConf         `yaml:"cache"`
-	Redis           RedisConf         `yaml:"redis"`
-	MessageBroker   MessageBrokerConf `yaml:"message_broker"`
-	GRPC            GRPCConf          `yaml:"grpc"`
-	HTTP            HTTPConf          `yaml:"http"`
+	Token            string            `yaml:"token"`
+	Storage          StorageConf       `yaml:"storage"`
+	Database         DatabaseConf      `yaml:"database"`
+	Logger           LoggerConf        `yaml:"logger"`
+	Metrics          MetricsConf       `yaml:"metrics"`
+	ReporterMetrics  MetricsConf       `yaml:"reporter_metrics"`
+	SchedulerMetrics MetricsConf       `yaml:"scheduler_metrics"`
+	Cache            CacheConf         `yaml:"cache"`
+	Redis            RedisConf         `yaml:"redis"`
+	MessageBroker    MessageBrokerConf `yaml:"message_broker"`
+	GRPC             GRPCConf          `yaml:"grpc"`
+	HTTP             HTTPConf          `yaml:"http"`
+	Scheduler        SchedulerConf     `yaml:"scheduler"`
+	BlobStorage      BlobStorageConf   `yaml:"blob_storage"`
 }
 
 type TokenGetter interface {
@@ -72,6 +76,16 @@ type HTTPConf struct {
 	Port int `yaml:"port"`
 }
 
+type SchedulerConf struct {
+	Interval  time.Duration `yaml:"interval"`
+	BatchSize int           `yaml:"batch_size"`
+}
+
+type BlobStorageConf struct {
+	Adapter string `yaml:"adapter"`
+	Dir     string `yaml:"dir"`
+}
+
 func New() (*Config, error) {
 	c := &Config{}
 
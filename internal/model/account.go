@@ -0,0 +1,28 @@
+package model
+
+import "time"
+
+// Account счет пользователя: карта, наличные, накопления
+type Account struct {
+	ID             string
+	Name           string
+	Currency       string // валюта отображения баланса
+	OpeningBalance int64  // копейки в рублях
+	UserId         int64
+}
+
+// AccountBalance остаток на счете: начальный баланс с учетом переводов и трат
+type AccountBalance struct {
+	Account Account
+	Balance int64 // копейки в рублях
+}
+
+// Transfer перевод между счетами пользователя
+type Transfer struct {
+	ID          string
+	FromAccount string
+	ToAccount   string
+	Amount      int64 // копейки в рублях
+	Datetime    time.Time
+	UserId      int64
+}
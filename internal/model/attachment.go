@@ -0,0 +1,21 @@
+package model
+
+import "time"
+
+// Attachment файл, прикрепленный к трате: фото чека или документ
+type Attachment struct {
+	ExpenseID string
+	UserId    int64  // ИД пользователя или общего бюджета
+	Key       string // ключ файла в хранилище
+	FileName  string
+	MimeType  string
+	Size      int64 // байты
+	CreatedAt time.Time
+}
+
+// File содержимое файла с именем и типом
+type File struct {
+	Name     string
+	MimeType string
+	Data     []byte
+}
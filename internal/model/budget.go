@@ -0,0 +1,73 @@
+package model
+
+import "strings"
+
+// BudgetRole роль участника общего бюджета
+type BudgetRole string
+
+const (
+	OwnerBudgetRole  BudgetRole = "owner"  // управляет участниками
+	EditorBudgetRole BudgetRole = "editor" // добавляет и меняет траты, лимиты, категории
+	ViewerBudgetRole BudgetRole = "viewer" // только смотрит отчеты
+)
+
+// budgetRoles роли, которые можно назначить участнику
+var budgetRoles = map[string]BudgetRole{
+	string(OwnerBudgetRole):  OwnerBudgetRole,
+	string(EditorBudgetRole): EditorBudgetRole,
+	string(ViewerBudgetRole): ViewerBudgetRole,
+}
+
+// ParseBudgetRole возвращает роль по названию
+func ParseBudgetRole(name string) (BudgetRole, bool) {
+	role, ok := budgetRoles[strings.ToLower(strings.Trim(name, " "))]
+
+	return role, ok
+}
+
+// CanEdit участник может менять данные бюджета
+func (r BudgetRole) CanEdit() bool {
+	return r == OwnerBudgetRole || r == EditorBudgetRole
+}
+
+// CanManage участник может управлять участниками бюджета
+func (r BudgetRole) CanManage() bool {
+	return r == OwnerBudgetRole
+}
+
+// Budget общий бюджет нескольких пользователей.
+// ИД общего бюджета отрицательный, чтобы не пересекаться с ИД пользователей Telegram:
+// траты, лимиты и категории бюджета хранятся с ИД бюджета вместо ИД пользователя
+type Budget struct {
+	ID       int64
+	Name     string
+	JoinCode string // код приглашения
+	OwnerId  int64
+}
+
+// BudgetMember участник общего бюджета или группового чата, в котором ведется бюджет.
+// У группового чата нет Budget, BudgetID участника - ИД чата
+type BudgetMember struct {
+	BudgetID int64
+	UserID   int64
+	Name     string // имя пользователя в Telegram на момент вступления
+	Role     BudgetRole
+}
+
+// FirstBudgetID ИД первого общего бюджета, следующие идут вниз. ИД чатов Telegram по модулю меньше 2^52,
+// поэтому общий бюджет не совпадает с ИД группового чата
+const FirstBudgetID int64 = -(1<<53 + 1)
+
+// IsSharedBudget ledgerId - ИД общего бюджета или группового чата Telegram, а не пользователя
+func IsSharedBudget(ledgerId int64) bool {
+	return ledgerId < 0
+}
+
+// PersonalBudgetMember личный бюджет пользователя, в котором он владелец
+func PersonalBudgetMember(userID int64) BudgetMember {
+	return BudgetMember{
+		BudgetID: userID,
+		UserID:   userID,
+		Role:     OwnerBudgetRole,
+	}
+}
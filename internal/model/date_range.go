@@ -0,0 +1,107 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+const dateRangeFormat = "2006-01-02"
+
+// DateRange диапазон дат [From, To)
+type DateRange struct {
+	From time.Time
+	To   time.Time
+}
+
+func NewDateRange(from, to time.Time) DateRange {
+	return DateRange{From: from, To: to}
+}
+
+func (r DateRange) IsEmpty() bool {
+	return r.From.IsZero() && r.To.IsZero()
+}
+
+func (r DateRange) Contains(t time.Time) bool {
+	return !t.Before(r.From) && t.Before(r.To)
+}
+
+// In возвращает диапазон в часовом поясе loc
+func (r DateRange) In(loc *time.Location) DateRange {
+	return DateRange{From: r.From.In(loc), To: r.To.In(loc)}
+}
+
+// String возвращает диапазон с включенной последней датой, например 2022-09-01..2022-09-30
+func (r DateRange) String() string {
+	return fmt.Sprintf("%s..%s", r.From.Format(dateRangeFormat), r.To.Add(-time.Nanosecond).Format(dateRangeFormat))
+}
+
+// Previous возвращает предыдущий диапазон той же длины: для целых календарных месяцев - те же месяцы раньше,
+// иначе - диапазон той же продолжительности, заканчивающийся в начале текущего
+func (r DateRange) Previous() DateRange {
+	if r.IsEmpty() || !r.From.Before(r.To) {
+		return r
+	}
+
+	// границы могут прийти в UTC, месяцы считаем в поясе, где начало диапазона - полночь
+	zone := midnightZone(r.From)
+	from, to := r.From.In(zone), r.To.In(zone)
+	if from.Equal(StartOfMonth(from)) && to.Equal(StartOfMonth(to)) {
+		months := (to.Year()-from.Year())*12 + int(to.Month()-from.Month())
+		return NewDateRange(from.AddDate(0, -months, 0).In(r.From.Location()), r.From)
+	}
+
+	return NewDateRange(r.From.Add(-r.To.Sub(r.From)), r.From)
+}
+
+// midnightZone возвращает пояс со смещением от -12 до +12 часов, в котором t приходится на полночь
+func midnightZone(t time.Time) *time.Location {
+	const day = 24 * 60 * 60
+
+	hour, minute, sec := t.UTC().Clock()
+	offset := -(hour*60*60 + minute*60 + sec)
+	if offset < -day/2 {
+		offset += day
+	}
+
+	return time.FixedZone("", offset)
+}
+
+// WallClock возвращает время с теми же показаниями часов в поясе loc. Время трат хранится в базе без пояса,
+// как его видел пользователь, и читается с меткой UTC, поэтому переводить его в пояс пользователя через In нельзя
+func WallClock(t time.Time, loc *time.Location) time.Time {
+	year, month, day := t.Date()
+	hour, minute, sec := t.Clock()
+
+	return time.Date(year, month, day, hour, minute, sec, t.Nanosecond(), loc)
+}
+
+// StartOfDay возвращает начало дня
+func StartOfDay(t time.Time) time.Time {
+	year, month, day := t.Date()
+
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
+
+// StartOfWeek возвращает начало недели, начинающейся с weekStart
+func StartOfWeek(t time.Time, weekStart time.Weekday) time.Time {
+	daysSinceWeekStart := (int(t.Weekday()) - int(weekStart) + 7) % 7
+
+	return StartOfDay(t).AddDate(0, 0, -daysSinceWeekStart)
+}
+
+// StartOfMonth возвращает начало месяца
+func StartOfMonth(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+}
+
+// StartOfQuarter возвращает начало квартала
+func StartOfQuarter(t time.Time) time.Time {
+	month := (t.Month()-1)/3*3 + 1
+
+	return time.Date(t.Year(), month, 1, 0, 0, 0, 0, t.Location())
+}
+
+// StartOfYear возвращает начало года
+func StartOfYear(t time.Time) time.Time {
+	return time.Date(t.Year(), time.January, 1, 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,39 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPreviousShouldShiftCalendarMonthsInUserZone(t *testing.T) {
+	moscow := time.FixedZone("MSK", 3*60*60)
+	from := time.Date(2022, 10, 1, 0, 0, 0, 0, moscow)
+	// диапазон пришел в UTC: 2022-09-30 21:00..2022-10-31 21:00
+	month := NewDateRange(from, from.AddDate(0, 1, 0)).In(time.UTC)
+
+	previous := month.Previous()
+	assert.Equal(t, time.Date(2022, 9, 1, 0, 0, 0, 0, moscow).Unix(), previous.From.Unix())
+	assert.Equal(t, month.From, previous.To)
+
+	year := NewDateRange(time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+	assert.Equal(t, "2021-01-01..2021-12-31", year.Previous().String())
+}
+
+func TestPreviousShouldShiftRollingRangeByDuration(t *testing.T) {
+	week := NewDateRange(time.Date(2022, 10, 12, 0, 0, 0, 0, time.UTC), time.Date(2022, 10, 20, 0, 0, 0, 0, time.UTC))
+	assert.Equal(t, "2022-10-04..2022-10-11", week.Previous().String())
+
+	assert.True(t, DateRange{}.Previous().IsEmpty())
+}
+
+func TestWallClockShouldKeepClockReadings(t *testing.T) {
+	moscow := time.FixedZone("MSK", 3*60*60)
+	// так трата в 12:00 по Москве читается из базы
+	stored := time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	datetime := WallClock(stored, moscow)
+	assert.Equal(t, time.Date(2022, 10, 1, 12, 0, 0, 0, moscow), datetime)
+	assert.Equal(t, datetime, WallClock(datetime, moscow))
+}
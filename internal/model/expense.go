@@ -1,33 +1,52 @@
 package model
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 type Expense struct {
 	ID         string
 	Amount     int64 // копейки
 	Category   string
 	CategoryID string
+	Account    string // пусто - трата без счета
+	AccountID  string
 	Datetime   time.Time
-	UserId     int64
+	UserId     int64 // ИД пользователя или общего бюджета
+	AuthorId   int64 // кто добавил трату
 }
 
 type ExpenseCategory struct {
-	ID   string
-	Name string
+	ID     string
+	Name   string
+	UserId int64
 }
 
 type ExpensePeriod int64
 
+// PeriodMode режим вычисления границ периодов
+type PeriodMode string
+
 const (
 	Week ExpensePeriod = iota
 	Month
 	Year
+	Custom  // произвольный диапазон дат
+	Quarter // используется для лимитов
 )
 
 const (
-	weekly  = "Недельный"
-	monthly = "Месячный"
-	annual  = "Годовой"
+	RollingPeriodMode  PeriodMode = "rolling"  // неделя, месяц, год назад от текущего дня
+	CalendarPeriodMode PeriodMode = "calendar" // текущие календарные неделя, месяц, год
+)
+
+const (
+	weekly    = "Недельный"
+	monthly   = "Месячный"
+	quarterly = "Квартальный"
+	annual    = "Годовой"
+	custom    = "Произвольный"
 )
 
 func (p *ExpensePeriod) String() string {
@@ -38,8 +57,12 @@ func (p *ExpensePeriod) String() string {
 		return weekly
 	case Month:
 		return monthly
+	case Quarter:
+		return quarterly
 	case Year:
 		return annual
+	case Custom:
+		return custom
 	}
 }
 
@@ -51,7 +74,66 @@ func (p *ExpensePeriod) GetStart(time time.Time) time.Time {
 		return time.AddDate(0, 0, -7)
 	case Month:
 		return time.AddDate(0, -1, 0)
+	case Quarter:
+		return time.AddDate(0, -3, 0)
 	case Year:
 		return time.AddDate(-1, 0, 0)
 	}
 }
+
+// GetRange возвращает диапазон периода от его начала до конца текущего дня
+func (p *ExpensePeriod) GetRange(now time.Time) DateRange {
+	return NewDateRange(StartOfDay(p.GetStart(now)), StartOfDay(now).AddDate(0, 0, 1))
+}
+
+// GetPeriodRange возвращает диапазон периода в зависимости от режима, неделя в календарном режиме начинается с weekStart
+func (p *ExpensePeriod) GetPeriodRange(now time.Time, mode PeriodMode, weekStart time.Weekday) DateRange {
+	if mode != CalendarPeriodMode {
+		return p.GetRange(now)
+	}
+
+	switch *p {
+	default:
+		start := StartOfWeek(now, weekStart)
+		return NewDateRange(start, start.AddDate(0, 0, 7))
+	case Month:
+		start := StartOfMonth(now)
+		return NewDateRange(start, start.AddDate(0, 1, 0))
+	case Quarter:
+		start := StartOfQuarter(now)
+		return NewDateRange(start, start.AddDate(0, 3, 0))
+	case Year:
+		start := StartOfYear(now)
+		return NewDateRange(start, start.AddDate(1, 0, 0))
+	}
+}
+
+// CategoryRuleType тип правила выбора категории
+type CategoryRuleType string
+
+const (
+	AliasCategoryRule   CategoryRuleType = "alias"   // введенная категория целиком совпадает с шаблоном
+	KeywordCategoryRule CategoryRuleType = "keyword" // введенный текст содержит шаблон
+)
+
+// CategoryRule правило пользователя, заменяющее введенную категорию на Category
+type CategoryRule struct {
+	Type     CategoryRuleType
+	Pattern  string
+	Category string
+	UserId   int64
+}
+
+// Matches проверяет, подходит ли правило для введенного текста категории
+func (r CategoryRule) Matches(text string) bool {
+	text, pattern := strings.ToLower(strings.Trim(text, " ")), strings.ToLower(r.Pattern)
+
+	switch r.Type {
+	case AliasCategoryRule:
+		return text == pattern
+	case KeywordCategoryRule:
+		return strings.Contains(text, pattern)
+	}
+
+	return false
+}
@@ -46,3 +46,42 @@ func TestExpensePeriodYearShouldReturnCorrectStartDate(t *testing.T) {
 
 	assert.Equal(t, exp, start)
 }
+
+func TestExpensePeriodShouldReturnRangeUntilEndOfDay(t *testing.T) {
+	p := Week
+
+	now, err := time.Parse("2006-01-02 15:04:05", "2022-10-01 13:20:00")
+	assert.NoError(t, err)
+
+	r := p.GetRange(now)
+
+	assert.Equal(t, "2022-09-24..2022-10-01", r.String())
+	assert.True(t, r.Contains(now))
+	assert.False(t, r.Contains(now.AddDate(0, 0, 1)))
+}
+
+func TestExpensePeriodShouldReturnCalendarRange(t *testing.T) {
+	now, err := time.Parse("2006-01-02 15:04:05", "2022-10-13 15:00:00")
+	assert.NoError(t, err)
+
+	week := Week
+	assert.Equal(t, "2022-10-10..2022-10-16", week.GetPeriodRange(now, CalendarPeriodMode, time.Monday).String())
+	assert.Equal(t, "2022-10-09..2022-10-15", week.GetPeriodRange(now, CalendarPeriodMode, time.Sunday).String())
+
+	month := Month
+	assert.Equal(t, "2022-10-01..2022-10-31", month.GetPeriodRange(now, CalendarPeriodMode, time.Monday).String())
+
+	quarter := Quarter
+	assert.Equal(t, "2022-10-01..2022-12-31", quarter.GetPeriodRange(now, CalendarPeriodMode, time.Monday).String())
+
+	year := Year
+	assert.Equal(t, "2022-01-01..2022-12-31", year.GetPeriodRange(now, CalendarPeriodMode, time.Monday).String())
+}
+
+func TestExpensePeriodShouldReturnRollingRange(t *testing.T) {
+	now, err := time.Parse("2006-01-02 15:04:05", "2022-10-13 15:00:00")
+	assert.NoError(t, err)
+
+	month := Month
+	assert.Equal(t, month.GetRange(now), month.GetPeriodRange(now, RollingPeriodMode, time.Monday))
+}
@@ -0,0 +1,30 @@
+package model
+
+import "strings"
+
+// ExportFormat формат файла выгрузки трат
+type ExportFormat string
+
+const (
+	CSVExportFormat  ExportFormat = "csv"
+	XLSXExportFormat ExportFormat = "xlsx"
+)
+
+// ParseExportFormat возвращает формат выгрузки по названию без учета регистра
+func ParseExportFormat(name string) (ExportFormat, bool) {
+	switch format := ExportFormat(strings.ToLower(strings.Trim(name, " "))); format {
+	case CSVExportFormat, XLSXExportFormat:
+		return format, true
+	}
+
+	return "", false
+}
+
+// MimeType тип содержимого файла выгрузки
+func (f ExportFormat) MimeType() string {
+	if f == XLSXExportFormat {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+
+	return "text/csv"
+}
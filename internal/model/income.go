@@ -0,0 +1,12 @@
+package model
+
+import "time"
+
+// Income доход пользователя
+type Income struct {
+	ID       string
+	Amount   int64 // копейки
+	Source   string
+	Datetime time.Time
+	UserId   int64
+}
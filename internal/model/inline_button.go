@@ -0,0 +1,7 @@
+package model
+
+// InlineButton кнопка под сообщением, Data возвращается боту при нажатии
+type InlineButton struct {
+	Text string
+	Data string
+}
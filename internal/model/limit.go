@@ -0,0 +1,131 @@
+package model
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// LimitScope область действия лимита
+type LimitScope string
+
+const (
+	CategoryLimitScope LimitScope = "category" // траты одной категории
+	TotalLimitScope    LimitScope = "total"    // траты всех категорий
+)
+
+// limitPeriodNames периоды, на которые можно установить лимит
+var limitPeriodNames = map[ExpensePeriod]string{
+	Week:    "week",
+	Month:   "month",
+	Quarter: "quarter",
+	Year:    "year",
+}
+
+// limitPeriodOrder порядок периодов лимитов от короткого к длинному
+var limitPeriodOrder = map[ExpensePeriod]int{
+	Week:    0,
+	Month:   1,
+	Quarter: 2,
+	Year:    3,
+}
+
+// ExpenseLimit лимит трат пользователя на период
+type ExpenseLimit struct {
+	Scope      LimitScope
+	Period     ExpensePeriod
+	Category   string // пусто для общего лимита
+	CategoryID string
+	Amount     int64 // копейки
+	Thresholds []int // пороги уведомлений в процентах от лимита
+	UserId     int64
+}
+
+// LimitAlert уведомление о превышении порога лимита, суммы в валюте пользователя
+type LimitAlert struct {
+	UserID    int64
+	Scope     LimitScope
+	Period    ExpensePeriod
+	Category  string
+	Threshold int
+	Amount    float64
+	Free      float64
+	Currency  string
+}
+
+const (
+	thresholdsSeparator = ","
+	maxThreshold        = 1000
+)
+
+// DefaultLimitThresholds пороги уведомлений нового лимита
+func DefaultLimitThresholds() []int {
+	return []int{50, 80, 100}
+}
+
+// FormatThresholds возвращает пороги для хранения: 50,80,100
+func FormatThresholds(thresholds []int) string {
+	parts := make([]string, 0, len(thresholds))
+	for _, t := range thresholds {
+		parts = append(parts, strconv.Itoa(t))
+	}
+
+	return strings.Join(parts, thresholdsSeparator)
+}
+
+// ParseThresholds разбирает пороги вида 50,80,100 и возвращает их по возрастанию без повторов.
+// Пустая строка означает, что уведомления отключены
+func ParseThresholds(value string) ([]int, bool) {
+	thresholds := make([]int, 0)
+	if strings.Trim(value, " ") == "" {
+		return thresholds, true
+	}
+
+	seen := make(map[int]struct{})
+	for _, part := range strings.Split(value, thresholdsSeparator) {
+		t, err := strconv.Atoi(strings.Trim(strings.TrimSuffix(strings.Trim(part, " "), "%"), " "))
+		if err != nil || t <= 0 || t > maxThreshold {
+			return nil, false
+		}
+
+		if _, ok := seen[t]; ok {
+			continue
+		}
+		seen[t] = struct{}{}
+		thresholds = append(thresholds, t)
+	}
+
+	sort.Ints(thresholds)
+
+	return thresholds, true
+}
+
+// ParseLimitPeriod возвращает период лимита по названию: week, month, quarter, year
+func ParseLimitPeriod(name string) (ExpensePeriod, bool) {
+	for period, periodName := range limitPeriodNames {
+		if periodName == name {
+			return period, true
+		}
+	}
+
+	return Month, false
+}
+
+// LimitPeriodName возвращает название периода лимита для хранения
+func LimitPeriodName(period ExpensePeriod) string {
+	if name, ok := limitPeriodNames[period]; ok {
+		return name
+	}
+
+	return limitPeriodNames[Month]
+}
+
+// SortLimits сортирует лимиты: сначала лимиты категории, затем общие, внутри - от короткого периода к длинному
+func SortLimits(limits []ExpenseLimit) {
+	sort.SliceStable(limits, func(i, j int) bool {
+		if limits[i].Scope != limits[j].Scope {
+			return limits[i].Scope == CategoryLimitScope
+		}
+		return limitPeriodOrder[limits[i].Period] < limitPeriodOrder[limits[j].Period]
+	})
+}
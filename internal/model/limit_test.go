@@ -0,0 +1,42 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseThresholdsShouldSortAndDeduplicate(t *testing.T) {
+	thresholds, ok := ParseThresholds(" 100, 50%,80,50 ")
+	assert.True(t, ok)
+	assert.Equal(t, []int{50, 80, 100}, thresholds)
+	assert.Equal(t, "50,80,100", FormatThresholds(thresholds))
+
+	thresholds, ok = ParseThresholds("")
+	assert.True(t, ok)
+	assert.Len(t, thresholds, 0)
+
+	_, ok = ParseThresholds("50,0")
+	assert.False(t, ok)
+
+	_, ok = ParseThresholds("половина")
+	assert.False(t, ok)
+}
+
+func TestSortLimitsShouldPutCategoryLimitsFirst(t *testing.T) {
+	limits := []ExpenseLimit{
+		{Scope: TotalLimitScope, Period: Year},
+		{Scope: CategoryLimitScope, Period: Quarter},
+		{Scope: TotalLimitScope, Period: Week},
+		{Scope: CategoryLimitScope, Period: Month},
+	}
+
+	SortLimits(limits)
+
+	assert.Equal(t, []ExpenseLimit{
+		{Scope: CategoryLimitScope, Period: Month},
+		{Scope: CategoryLimitScope, Period: Quarter},
+		{Scope: TotalLimitScope, Period: Week},
+		{Scope: TotalLimitScope, Period: Year},
+	}, limits)
+}
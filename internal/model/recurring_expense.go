@@ -0,0 +1,15 @@
+package model
+
+import "time"
+
+// RecurringExpense регулярная трата, которая добавляется по расписанию
+type RecurringExpense struct {
+	ID        string
+	UserId    int64
+	Amount    int64  // копейки в валюте Currency
+	Currency  string // валюта пересчитывается в рубли при добавлении каждой траты
+	Category  string
+	Schedule  Schedule
+	NextRunAt time.Time // время следующей траты, пропущенные траты добавляются по очереди
+	Paused    bool
+}
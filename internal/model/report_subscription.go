@@ -0,0 +1,27 @@
+package model
+
+import "time"
+
+// ReportSubscription подписка пользователя на регулярный отчет
+type ReportSubscription struct {
+	UserId    int64
+	Schedule  Schedule
+	NextRunAt time.Time
+}
+
+// ReportRange возвращает диапазон отчета, отправляемого в now: в календарном режиме
+// последний завершенный период, иначе неделя или месяц назад
+func (s ReportSubscription) ReportRange(now time.Time, settings UserSettings) DateRange {
+	period := s.Schedule.Period
+	if settings.PeriodMode != CalendarPeriodMode {
+		return period.GetRange(now)
+	}
+
+	if period == Month {
+		end := StartOfMonth(now)
+		return NewDateRange(end.AddDate(0, -1, 0), end)
+	}
+
+	end := StartOfWeek(now, settings.WeekStart)
+	return NewDateRange(end.AddDate(0, 0, -7), end)
+}
@@ -0,0 +1,97 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const scheduleTimeFormat = "15:04"
+
+// schedulePeriodNames периоды повторения расписания
+var schedulePeriodNames = map[ExpensePeriod]string{
+	Week:  "weekly",
+	Month: "monthly",
+}
+
+// Schedule еженедельное или ежемесячное расписание, время задается в часовом поясе пользователя
+type Schedule struct {
+	Period  ExpensePeriod // Week или Month
+	Weekday time.Weekday  // день недели для недельного расписания
+	Day     int           // день месяца для месячного расписания, в коротких месяцах - последний день
+	Hour    int
+	Minute  int
+}
+
+// ParseSchedulePeriod возвращает период расписания по названию: weekly, monthly
+func ParseSchedulePeriod(value string) (ExpensePeriod, bool) {
+	value = strings.ToLower(strings.Trim(value, " "))
+
+	for period, name := range schedulePeriodNames {
+		if name == value {
+			return period, true
+		}
+	}
+
+	return Week, false
+}
+
+// SchedulePeriodName возвращает название периода расписания
+func SchedulePeriodName(period ExpensePeriod) string {
+	return schedulePeriodNames[period]
+}
+
+// ParseScheduleTime разбирает время по расписанию 09:00
+func ParseScheduleTime(value string) (hour, minute int, ok bool) {
+	t, err := time.Parse(scheduleTimeFormat, strings.Trim(value, " "))
+	if err != nil {
+		return 0, 0, false
+	}
+
+	return t.Hour(), t.Minute(), true
+}
+
+// String возвращает расписание в формате ввода: weekly monday 09:00, monthly 1 09:00
+func (s Schedule) String() string {
+	at := fmt.Sprintf("%02d:%02d", s.Hour, s.Minute)
+
+	if s.Period == Month {
+		return fmt.Sprintf("%s %d %s", SchedulePeriodName(s.Period), s.Day, at)
+	}
+
+	return fmt.Sprintf("%s %s %s", SchedulePeriodName(s.Period), strings.ToLower(s.Weekday.String()), at)
+}
+
+// NextRun возвращает ближайшее время по расписанию строго после after в часовом поясе loc
+func (s Schedule) NextRun(after time.Time, loc *time.Location) time.Time {
+	local := after.In(loc)
+
+	if s.Period == Month {
+		next := s.monthRun(local.Year(), local.Month(), loc)
+		if !next.After(after) {
+			next = s.monthRun(local.Year(), local.Month()+1, loc)
+		}
+
+		return next
+	}
+
+	daysUntil := (int(s.Weekday) - int(local.Weekday()) + 7) % 7
+	next := time.Date(local.Year(), local.Month(), local.Day()+daysUntil, s.Hour, s.Minute, 0, 0, loc)
+	if !next.After(after) {
+		next = time.Date(local.Year(), local.Month(), local.Day()+daysUntil+7, s.Hour, s.Minute, 0, 0, loc)
+	}
+
+	return next
+}
+
+// monthRun возвращает время по расписанию в месяце, день ограничен длиной месяца
+func (s Schedule) monthRun(year int, month time.Month, loc *time.Location) time.Time {
+	lastDay := time.Date(year, month+1, 0, 0, 0, 0, 0, loc).Day()
+
+	day := s.Day
+	if day > lastDay {
+		day = lastDay
+	}
+
+	return time.Date(year, month, day, s.Hour, s.Minute, 0, 0, loc)
+}
@@ -0,0 +1,43 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNextRunShouldUseUserTimezone(t *testing.T) {
+	loc := time.FixedZone("UTC+3", 3*60*60)
+	schedule := Schedule{Period: Week, Weekday: time.Monday, Hour: 9}
+
+	// понедельник 05:00 UTC - 08:00 у пользователя, отчет еще сегодня
+	now := time.Date(2022, 11, 7, 5, 0, 0, 0, time.UTC)
+	assert.True(t, time.Date(2022, 11, 7, 6, 0, 0, 0, time.UTC).Equal(schedule.NextRun(now, loc)))
+
+	// ровно во время отправки следующий отчет через неделю
+	now = time.Date(2022, 11, 7, 6, 0, 0, 0, time.UTC)
+	assert.True(t, time.Date(2022, 11, 14, 6, 0, 0, 0, time.UTC).Equal(schedule.NextRun(now, loc)))
+}
+
+func TestNextRunShouldClampDayToMonthLength(t *testing.T) {
+	schedule := Schedule{Period: Month, Day: 31, Hour: 9, Minute: 30}
+
+	now := time.Date(2022, 1, 31, 10, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2022, 2, 28, 9, 30, 0, 0, time.UTC), schedule.NextRun(now, time.UTC))
+
+	now = time.Date(2022, 2, 28, 9, 30, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2022, 3, 31, 9, 30, 0, 0, time.UTC), schedule.NextRun(now, time.UTC))
+	assert.Equal(t, "monthly 31 09:30", schedule.String())
+}
+
+func TestReportRangeShouldReturnPreviousCalendarPeriod(t *testing.T) {
+	now := time.Date(2022, 11, 7, 9, 0, 0, 0, time.UTC)
+	settings := UserSettings{PeriodMode: CalendarPeriodMode, WeekStart: time.Monday}
+
+	weekly := ReportSubscription{Schedule: Schedule{Period: Week}}
+	assert.Equal(t, "2022-10-31..2022-11-06", weekly.ReportRange(now, settings).String())
+
+	monthly := ReportSubscription{Schedule: Schedule{Period: Month}}
+	assert.Equal(t, "2022-10-01..2022-10-31", monthly.ReportRange(now, settings).String())
+}
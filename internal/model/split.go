@@ -0,0 +1,108 @@
+package model
+
+import (
+	"sort"
+	"time"
+)
+
+// ExpenseShare доля участника в трате общего бюджета, которую оплатил PayerID
+type ExpenseShare struct {
+	ExpenseID string
+	BudgetID  int64
+	PayerID   int64
+	UserID    int64
+	Amount    int64 // копейки в рублях
+}
+
+// Settlement возврат долга: участник FromUserID отдал ToUserID сумму Amount
+type Settlement struct {
+	ID         string
+	BudgetID   int64
+	FromUserID int64
+	ToUserID   int64
+	Amount     int64 // копейки в рублях
+	Datetime   time.Time
+}
+
+// Debt перевод, который закрывает долг FromUserID перед ToUserID
+type Debt struct {
+	FromUserID int64
+	ToUserID   int64
+	Amount     int64 // копейки в рублях
+}
+
+// SplitAmount делит сумму поровну между участниками,
+// остаток от деления по копейке достается первым участникам
+func SplitAmount(amount int64, userIds []int64) map[int64]int64 {
+	shares := make(map[int64]int64, len(userIds))
+	if len(userIds) == 0 {
+		return shares
+	}
+
+	share := amount / int64(len(userIds))
+	remainder := amount - share*int64(len(userIds))
+	for i, userId := range userIds {
+		shares[userId] = share
+		if int64(i) < remainder {
+			shares[userId]++
+		}
+	}
+
+	return shares
+}
+
+type debtBalance struct {
+	userId int64
+	amount int64
+}
+
+// SettleDebts возвращает переводы, которые обнуляют балансы участников.
+// balances - [участник]баланс: положительный - участнику должны, отрицательный - участник должен.
+// Крупнейший должник отдает крупнейшему кредитору, пока долги не закончатся:
+// переводов не больше, чем участников с ненулевым балансом, минус один
+func SettleDebts(balances map[int64]int64) []Debt {
+	creditors := make([]debtBalance, 0)
+	debtors := make([]debtBalance, 0)
+	for userId, amount := range balances {
+		switch {
+		case amount > 0:
+			creditors = append(creditors, debtBalance{userId: userId, amount: amount})
+		case amount < 0:
+			debtors = append(debtors, debtBalance{userId: userId, amount: -amount})
+		}
+	}
+
+	sortBalances(creditors)
+	sortBalances(debtors)
+
+	debts := make([]Debt, 0)
+	for c, d := 0, 0; c < len(creditors) && d < len(debtors); {
+		amount := creditors[c].amount
+		if debtors[d].amount < amount {
+			amount = debtors[d].amount
+		}
+
+		debts = append(debts, Debt{FromUserID: debtors[d].userId, ToUserID: creditors[c].userId, Amount: amount})
+
+		creditors[c].amount -= amount
+		debtors[d].amount -= amount
+		if creditors[c].amount == 0 {
+			c++
+		}
+		if debtors[d].amount == 0 {
+			d++
+		}
+	}
+
+	return debts
+}
+
+// sortBalances по убыванию суммы, при равных суммах - по ИД участника
+func sortBalances(balances []debtBalance) {
+	sort.Slice(balances, func(i, j int) bool {
+		if balances[i].amount == balances[j].amount {
+			return balances[i].userId < balances[j].userId
+		}
+		return balances[i].amount > balances[j].amount
+	})
+}
@@ -0,0 +1,30 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitAmountShouldGiveRemainderToFirstParticipants(t *testing.T) {
+	assert.Equal(t, map[int64]int64{1: 334, 2: 333, 3: 333}, SplitAmount(1000, []int64{1, 2, 3}))
+	assert.Equal(t, map[int64]int64{}, SplitAmount(1000, nil))
+}
+
+func TestSettleDebtsShouldReturnMinimalTransfers(t *testing.T) {
+	// alice заплатила 3000 за троих, bob - 600 за троих
+	debts := SettleDebts(map[int64]int64{
+		1: 2000 - 200,
+		2: -1000 + 400,
+		3: -1000 - 200,
+	})
+
+	assert.Equal(t, []Debt{
+		{FromUserID: 3, ToUserID: 1, Amount: 1200},
+		{FromUserID: 2, ToUserID: 1, Amount: 600},
+	}, debts)
+}
+
+func TestSettleDebtsWithoutDebtsShouldReturnEmptyList(t *testing.T) {
+	assert.Len(t, SettleDebts(map[int64]int64{1: 0, 2: 0}), 0)
+}
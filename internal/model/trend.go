@@ -0,0 +1,75 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+// TrendBucket интервал, по которым группируются траты в отчете о динамике
+type TrendBucket string
+
+const (
+	DayTrendBucket   TrendBucket = "day"
+	WeekTrendBucket  TrendBucket = "week"
+	MonthTrendBucket TrendBucket = "month"
+)
+
+// ParseTrendBucket возвращает интервал по названию без учета регистра: day, week, month
+func ParseTrendBucket(name string) (TrendBucket, bool) {
+	switch bucket := TrendBucket(strings.ToLower(strings.Trim(name, " "))); bucket {
+	case DayTrendBucket, WeekTrendBucket, MonthTrendBucket:
+		return bucket, true
+	}
+
+	return "", false
+}
+
+// Start возвращает начало интервала, в который попадает t, неделя начинается с weekStart
+func (b TrendBucket) Start(t time.Time, weekStart time.Weekday) time.Time {
+	switch b {
+	case WeekTrendBucket:
+		return StartOfWeek(t, weekStart)
+	case MonthTrendBucket:
+		return StartOfMonth(t)
+	default:
+		return StartOfDay(t)
+	}
+}
+
+// Next возвращает начало следующего интервала
+func (b TrendBucket) Next(start time.Time) time.Time {
+	switch b {
+	case WeekTrendBucket:
+		return start.AddDate(0, 0, 7)
+	case MonthTrendBucket:
+		return start.AddDate(0, 1, 0)
+	default:
+		return start.AddDate(0, 0, 1)
+	}
+}
+
+// Buckets возвращает начала интервалов, пересекающихся с диапазоном, в часовом поясе диапазона
+func (b TrendBucket) Buckets(dateRange DateRange, weekStart time.Weekday) []time.Time {
+	buckets := make([]time.Time, 0)
+	for start := b.Start(dateRange.From, weekStart); start.Before(dateRange.To); start = b.Next(start) {
+		buckets = append(buckets, start)
+	}
+
+	return buckets
+}
+
+// TrendQuery параметры группировки трат по интервалам
+type TrendQuery struct {
+	Range      DateRange
+	Bucket     TrendBucket
+	ByCategory bool // группировать внутри интервала по категориям
+	Location   *time.Location
+	WeekStart  time.Weekday
+}
+
+// TrendPoint сумма трат за интервал, для группировки без категорий Category пустая
+type TrendPoint struct {
+	From     time.Time
+	Category string
+	Amount   int64 // копейки
+}
@@ -0,0 +1,32 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrendBucketsShouldCoverRange(t *testing.T) {
+	// суббота 2022-10-01 .. понедельник 2022-10-17
+	dateRange := NewDateRange(time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC), time.Date(2022, 10, 18, 0, 0, 0, 0, time.UTC))
+
+	assert.Len(t, DayTrendBucket.Buckets(dateRange, time.Monday), 17)
+	assert.Equal(t, []time.Time{
+		time.Date(2022, 9, 26, 0, 0, 0, 0, time.UTC),
+		time.Date(2022, 10, 3, 0, 0, 0, 0, time.UTC),
+		time.Date(2022, 10, 10, 0, 0, 0, 0, time.UTC),
+		time.Date(2022, 10, 17, 0, 0, 0, 0, time.UTC),
+	}, WeekTrendBucket.Buckets(dateRange, time.Monday))
+	assert.Equal(t, time.Date(2022, 10, 16, 0, 0, 0, 0, time.UTC), WeekTrendBucket.Start(dateRange.To, time.Sunday))
+	assert.Equal(t, []time.Time{time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC)}, MonthTrendBucket.Buckets(dateRange, time.Monday))
+}
+
+func TestParseTrendBucket(t *testing.T) {
+	bucket, ok := ParseTrendBucket(" Week ")
+	assert.True(t, ok)
+	assert.Equal(t, WeekTrendBucket, bucket)
+
+	_, ok = ParseTrendBucket("hour")
+	assert.False(t, ok)
+}
@@ -0,0 +1,40 @@
+package model
+
+import "time"
+
+type UserSettings struct {
+	UserID     int64
+	Currency   string
+	PeriodMode PeriodMode
+	WeekStart  time.Weekday
+	Timezone   string
+}
+
+// DefaultUserSettings настройки пользователя, который их не менял
+func DefaultUserSettings(userID int64) UserSettings {
+	return UserSettings{
+		UserID:     userID,
+		Currency:   "RUB",
+		PeriodMode: RollingPeriodMode,
+		WeekStart:  time.Monday,
+	}
+}
+
+// Location возвращает часовой пояс пользователя, по-умолчанию часовой пояс сервера
+func (s UserSettings) Location() *time.Location {
+	return LoadLocation(s.Timezone)
+}
+
+// LoadLocation возвращает часовой пояс по названию, для пустого или неизвестного названия - часовой пояс сервера
+func LoadLocation(name string) *time.Location {
+	if name == "" {
+		return time.Local
+	}
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return time.Local
+	}
+
+	return loc
+}
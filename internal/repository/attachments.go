@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/model"
+)
+
+type AttachmentsRepository interface {
+	// SaveAttachment прикрепляет файл к трате, прежний файл траты заменяется
+	SaveAttachment(ctx context.Context, attachment model.Attachment) error
+	GetAttachment(ctx context.Context, expenseId string, userId int64) (model.Attachment, bool, error)
+}
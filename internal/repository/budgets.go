@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"context"
+
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/model"
+)
+
+type BudgetsRepository interface {
+	// AddBudget создает бюджет с владельцем owner, ИД бюджета выдает хранилище
+	AddBudget(ctx context.Context, budget model.Budget, owner model.BudgetMember) (model.Budget, error)
+	GetBudget(ctx context.Context, budgetId int64) (model.Budget, bool, error)
+	GetBudgetByCode(ctx context.Context, joinCode string) (model.Budget, bool, error)
+	// GetUserBudgets возвращает общие бюджеты, в которых состоит пользователь
+	GetUserBudgets(ctx context.Context, userId int64) ([]model.Budget, error)
+	// SaveMember добавляет участника или меняет его роль
+	SaveMember(ctx context.Context, member model.BudgetMember) error
+	GetMembers(ctx context.Context, budgetId int64) ([]model.BudgetMember, error)
+	DeleteMember(ctx context.Context, budgetId, userId int64) (bool, error)
+	SetActiveBudget(ctx context.Context, userId, budgetId int64) error
+	// GetActiveMember возвращает участие пользователя в активном бюджете,
+	// found = false, если активен личный бюджет или пользователь больше не участник
+	GetActiveMember(ctx context.Context, userId int64) (model.BudgetMember, bool, error)
+}
@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/model"
+)
+
+type CategoryRulesRepository interface {
+	SaveRule(ctx context.Context, rule model.CategoryRule) error
+	GetRules(ctx context.Context, userId int64) ([]model.CategoryRule, error)
+	DeleteRule(ctx context.Context, userId int64, pattern string) (bool, error)
+}
@@ -0,0 +1,11 @@
+package repository
+
+import "github.com/pkg/errors"
+
+var (
+	ErrCategoryExists      = errors.New("категория с таким названием уже существует")
+	ErrCategoryHasExpenses = errors.New("в категории есть траты, объедините ее с другой категорией: /mergeCategories")
+	ErrAccountExists       = errors.New("счет с таким названием уже существует")
+	ErrAccountNotFound     = errors.New("счет не найден, список счетов: /balances")
+	ErrBudgetNotFound      = errors.New("бюджет не найден, список бюджетов: /budgets")
+)
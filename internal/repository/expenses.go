@@ -2,13 +2,36 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"gitlab.ozon.dev/cranky4/tg-bot/internal/model"
 )
 
 type ExpensesRepository interface {
 	Add(ctx context.Context, expense model.Expense) error
-	GetExpenses(ctx context.Context, period model.ExpensePeriod, userId int64) ([]*model.Expense, error)
-	SetLimit(ctx context.Context, category string, userId, amount int64) error
-	GetFreeLimit(ctx context.Context, category string, userId int64) (int64, bool, error)
+	// AddBatch сохраняет траты без счета одной транзакцией, недостающие категории создаются
+	AddBatch(ctx context.Context, expenses []model.Expense) error
+	// AddOccurrence сохраняет трату регулярной траты recurringId за время occurrenceAt вместе с отметкой об этом времени.
+	// Если трата за это время уже сохранена, возвращает false и ничего не меняет
+	AddOccurrence(ctx context.Context, expense model.Expense, recurringId string, occurrenceAt time.Time) (bool, error)
+	// Update изменяет трату и заменяет доли разделенной траты shares в той же транзакции, для пустого shares доли
+	// не меняются
+	Update(ctx context.Context, expense model.Expense, shares []model.ExpenseShare) (bool, error)
+	Delete(ctx context.Context, id string, userId int64) (bool, error)
+	GetExpenses(ctx context.Context, dateRange model.DateRange, userId int64) ([]*model.Expense, error)
+	// GetTrend возвращает суммы трат по интервалам, упорядоченные по началу интервала и убыванию суммы
+	GetTrend(ctx context.Context, query model.TrendQuery, userId int64) ([]model.TrendPoint, error)
+	SetLimit(ctx context.Context, limit model.ExpenseLimit) error
+	GetLimits(ctx context.Context, category string, userId int64) ([]model.ExpenseLimit, error)
+	GetFreeLimit(ctx context.Context, limit model.ExpenseLimit, dateRange model.DateRange) (int64, error)
+	SetLimitThresholds(ctx context.Context, limit model.ExpenseLimit) (bool, error)
+	SaveLimitNotification(ctx context.Context, limit model.ExpenseLimit, periodStart time.Time, threshold int) (bool, error)
+	GetTopCategories(ctx context.Context, userId int64, limit int) ([]string, error)
+	GetCategories(ctx context.Context, userId int64) ([]model.ExpenseCategory, error)
+	RenameCategory(ctx context.Context, userId int64, name, newName string) (bool, error)
+	MergeCategories(ctx context.Context, userId int64, from, to string) (bool, error)
+	DeleteCategory(ctx context.Context, userId int64, name string) (bool, error)
+	AddAccount(ctx context.Context, account model.Account) (model.Account, error)
+	AddTransfer(ctx context.Context, transfer model.Transfer) error
+	GetAccountBalances(ctx context.Context, userId int64) ([]model.AccountBalance, error)
 }
@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+)
+
+// ImportTokensRepository токены пользователей для импорта трат по HTTP, хранятся только хеши токенов
+type ImportTokensRepository interface {
+	// SaveToken выдает пользователю токен, прежний токен перестает действовать
+	SaveToken(ctx context.Context, userId int64, tokenHash string) error
+	// GetTokenUser возвращает владельца токена
+	GetTokenUser(ctx context.Context, tokenHash string) (int64, bool, error)
+}
@@ -0,0 +1,12 @@
+package repository
+
+import (
+	"context"
+
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/model"
+)
+
+type IncomesRepository interface {
+	AddIncome(ctx context.Context, income model.Income) (model.Income, error)
+	GetIncomes(ctx context.Context, dateRange model.DateRange, userId int64) ([]model.Income, error)
+}
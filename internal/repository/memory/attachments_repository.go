@@ -0,0 +1,51 @@
+package expenses_memory_repo
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/model"
+	repo "gitlab.ozon.dev/cranky4/tg-bot/internal/repository"
+)
+
+type attachmentsRepository struct {
+	mu          *sync.RWMutex
+	attachments map[string]model.Attachment // [ИД траты]файл
+}
+
+func NewAttachmentsRepository() repo.AttachmentsRepository {
+	return &attachmentsRepository{
+		mu:          &sync.RWMutex{},
+		attachments: make(map[string]model.Attachment),
+	}
+}
+
+func (r *attachmentsRepository) SaveAttachment(ctx context.Context, attachment model.Attachment) error {
+	span, _ := opentracing.StartSpanFromContext(ctx, "SaveAttachment")
+	defer span.Finish()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	attachment.CreatedAt = time.Now()
+	r.attachments[attachment.ExpenseID] = attachment
+
+	return nil
+}
+
+func (r *attachmentsRepository) GetAttachment(ctx context.Context, expenseId string, userId int64) (model.Attachment, bool, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "GetAttachment")
+	defer span.Finish()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	attachment, ok := r.attachments[expenseId]
+	if !ok || attachment.UserId != userId {
+		return model.Attachment{}, false, nil
+	}
+
+	return attachment, true, nil
+}
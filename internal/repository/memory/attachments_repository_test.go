@@ -0,0 +1,40 @@
+package expenses_memory_repo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/model"
+)
+
+func TestAttachmentShouldBeAvailableOnlyInItsBudget(t *testing.T) {
+	ctx := context.Background()
+	storage := NewAttachmentsRepository()
+
+	err := storage.SaveAttachment(ctx, model.Attachment{
+		ExpenseID: "expense", UserId: 100, Key: "100/expense.jpg", FileName: "receipt.jpg", MimeType: "image/jpeg", Size: 7,
+	})
+	assert.NoError(t, err)
+
+	attachment, found, err := storage.GetAttachment(ctx, "expense", 100)
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "100/expense.jpg", attachment.Key)
+	assert.Equal(t, "receipt.jpg", attachment.FileName)
+
+	_, found, err = storage.GetAttachment(ctx, "expense", 200)
+	assert.NoError(t, err)
+	assert.False(t, found)
+
+	// повторный файл заменяет прежний
+	err = storage.SaveAttachment(ctx, model.Attachment{
+		ExpenseID: "expense", UserId: 100, Key: "100/expense.pdf", FileName: "receipt.pdf", MimeType: "application/pdf", Size: 3,
+	})
+	assert.NoError(t, err)
+
+	attachment, found, err = storage.GetAttachment(ctx, "expense", 100)
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "receipt.pdf", attachment.FileName)
+}
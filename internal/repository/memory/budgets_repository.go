@@ -0,0 +1,189 @@
+package expenses_memory_repo
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/opentracing/opentracing-go"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/model"
+	repo "gitlab.ozon.dev/cranky4/tg-bot/internal/repository"
+)
+
+type memberKey struct {
+	budgetId int64
+	userId   int64
+}
+
+type budgetsRepository struct {
+	mu      *sync.RWMutex
+	lastId  int64
+	budgets map[int64]model.Budget
+	members map[memberKey]model.BudgetMember
+	active  map[int64]int64 // [пользователь]бюджет
+}
+
+func NewBudgetsRepository() repo.BudgetsRepository {
+	return &budgetsRepository{
+		mu:      &sync.RWMutex{},
+		lastId:  model.FirstBudgetID + 1,
+		budgets: make(map[int64]model.Budget),
+		members: make(map[memberKey]model.BudgetMember),
+		active:  make(map[int64]int64),
+	}
+}
+
+func (r *budgetsRepository) AddBudget(ctx context.Context, budget model.Budget, owner model.BudgetMember) (model.Budget, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "AddBudget")
+	defer span.Finish()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.lastId--
+	budget.ID = r.lastId
+	r.budgets[budget.ID] = budget
+
+	owner.BudgetID = budget.ID
+	r.members[memberKey{budgetId: budget.ID, userId: owner.UserID}] = owner
+
+	return budget, nil
+}
+
+func (r *budgetsRepository) GetBudget(ctx context.Context, budgetId int64) (model.Budget, bool, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "GetBudget")
+	defer span.Finish()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	budget, ok := r.budgets[budgetId]
+
+	return budget, ok, nil
+}
+
+func (r *budgetsRepository) GetBudgetByCode(ctx context.Context, joinCode string) (model.Budget, bool, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "GetBudgetByCode")
+	defer span.Finish()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, budget := range r.budgets {
+		if strings.EqualFold(budget.JoinCode, joinCode) {
+			return budget, true, nil
+		}
+	}
+
+	return model.Budget{}, false, nil
+}
+
+func (r *budgetsRepository) GetUserBudgets(ctx context.Context, userId int64) ([]model.Budget, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "GetUserBudgets")
+	defer span.Finish()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	budgets := make([]model.Budget, 0)
+	for key := range r.members {
+		// участники групповых чатов не состоят в бюджетах
+		budget, ok := r.budgets[key.budgetId]
+		if key.userId == userId && ok {
+			budgets = append(budgets, budget)
+		}
+	}
+
+	// в порядке создания
+	sort.Slice(budgets, func(i, j int) bool {
+		return budgets[i].ID > budgets[j].ID
+	})
+
+	return budgets, nil
+}
+
+func (r *budgetsRepository) SaveMember(ctx context.Context, member model.BudgetMember) error {
+	span, _ := opentracing.StartSpanFromContext(ctx, "SaveMember")
+	defer span.Finish()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.members[memberKey{budgetId: member.BudgetID, userId: member.UserID}] = member
+
+	return nil
+}
+
+func (r *budgetsRepository) GetMembers(ctx context.Context, budgetId int64) ([]model.BudgetMember, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "GetMembers")
+	defer span.Finish()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	members := make([]model.BudgetMember, 0)
+	for key, member := range r.members {
+		if key.budgetId == budgetId {
+			members = append(members, member)
+		}
+	}
+
+	sort.Slice(members, func(i, j int) bool {
+		return members[i].UserID < members[j].UserID
+	})
+
+	return members, nil
+}
+
+func (r *budgetsRepository) DeleteMember(ctx context.Context, budgetId, userId int64) (bool, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "DeleteMember")
+	defer span.Finish()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := memberKey{budgetId: budgetId, userId: userId}
+	if _, ok := r.members[key]; !ok {
+		return false, nil
+	}
+
+	delete(r.members, key)
+
+	return true, nil
+}
+
+func (r *budgetsRepository) SetActiveBudget(ctx context.Context, userId, budgetId int64) error {
+	span, _ := opentracing.StartSpanFromContext(ctx, "SetActiveBudget")
+	defer span.Finish()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// личный бюджет активен по-умолчанию
+	if !model.IsSharedBudget(budgetId) {
+		delete(r.active, userId)
+		return nil
+	}
+
+	r.active[userId] = budgetId
+
+	return nil
+}
+
+func (r *budgetsRepository) GetActiveMember(ctx context.Context, userId int64) (model.BudgetMember, bool, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "GetActiveMember")
+	defer span.Finish()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	budgetId, ok := r.active[userId]
+	if !ok {
+		return model.BudgetMember{}, false, nil
+	}
+
+	member, ok := r.members[memberKey{budgetId: budgetId, userId: userId}]
+
+	return member, ok, nil
+}
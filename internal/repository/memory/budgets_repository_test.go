@@ -0,0 +1,69 @@
+package expenses_memory_repo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/model"
+)
+
+func TestActiveMemberShouldBeResetAfterLeavingBudget(t *testing.T) {
+	ctx := context.Background()
+	storage := NewBudgetsRepository()
+
+	budget, err := storage.AddBudget(
+		ctx,
+		model.Budget{Name: "Семья", JoinCode: "ABCD1234", OwnerId: 100},
+		model.BudgetMember{UserID: 100, Name: "alice", Role: model.OwnerBudgetRole},
+	)
+	assert.NoError(t, err)
+	assert.True(t, model.IsSharedBudget(budget.ID))
+
+	found, ok, err := storage.GetBudgetByCode(ctx, "abcd1234")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, budget, found)
+
+	member := model.BudgetMember{BudgetID: budget.ID, UserID: 200, Name: "bob", Role: model.EditorBudgetRole}
+	assert.NoError(t, storage.SaveMember(ctx, member))
+	assert.NoError(t, storage.SetActiveBudget(ctx, 200, budget.ID))
+
+	active, ok, err := storage.GetActiveMember(ctx, 200)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, member, active)
+
+	members, err := storage.GetMembers(ctx, budget.ID)
+	assert.NoError(t, err)
+	assert.Len(t, members, 2)
+
+	deleted, err := storage.DeleteMember(ctx, budget.ID, 200)
+	assert.NoError(t, err)
+	assert.True(t, deleted)
+
+	_, ok, err = storage.GetActiveMember(ctx, 200)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	budgets, err := storage.GetUserBudgets(ctx, 200)
+	assert.NoError(t, err)
+	assert.Len(t, budgets, 0)
+}
+
+func TestBudgetIdsShouldNotOverlapGroupChats(t *testing.T) {
+	ctx := context.Background()
+	storage := NewBudgetsRepository()
+
+	for _, expected := range []int64{model.FirstBudgetID, model.FirstBudgetID - 1} {
+		budget, err := storage.AddBudget(
+			ctx,
+			model.Budget{Name: "Семья", OwnerId: 100},
+			model.BudgetMember{UserID: 100, Role: model.OwnerBudgetRole},
+		)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, budget.ID)
+		// у супергрупп ИД вида -100xxxxxxxxxx, все ИД чатов по модулю меньше 2^52
+		assert.Less(t, budget.ID, int64(-(1 << 52)))
+	}
+}
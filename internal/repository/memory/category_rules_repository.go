@@ -0,0 +1,74 @@
+package expenses_memory_repo
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/opentracing/opentracing-go"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/model"
+	repo "gitlab.ozon.dev/cranky4/tg-bot/internal/repository"
+)
+
+type categoryRulesRepository struct {
+	mu    *sync.RWMutex
+	rules map[int64][]model.CategoryRule // правила пользователя в порядке добавления
+}
+
+func NewCategoryRulesRepository() repo.CategoryRulesRepository {
+	return &categoryRulesRepository{
+		mu:    &sync.RWMutex{},
+		rules: make(map[int64][]model.CategoryRule),
+	}
+}
+
+func (r *categoryRulesRepository) SaveRule(ctx context.Context, rule model.CategoryRule) error {
+	span, _ := opentracing.StartSpanFromContext(ctx, "SaveRule")
+	defer span.Finish()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rules := r.rules[rule.UserId]
+	for i := 0; i < len(rules); i++ {
+		if strings.EqualFold(rules[i].Pattern, rule.Pattern) {
+			rules[i] = rule
+			return nil
+		}
+	}
+
+	r.rules[rule.UserId] = append(rules, rule)
+
+	return nil
+}
+
+func (r *categoryRulesRepository) GetRules(ctx context.Context, userId int64) ([]model.CategoryRule, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "GetRules")
+	defer span.Finish()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	rules := make([]model.CategoryRule, len(r.rules[userId]))
+	copy(rules, r.rules[userId])
+
+	return rules, nil
+}
+
+func (r *categoryRulesRepository) DeleteRule(ctx context.Context, userId int64, pattern string) (bool, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "DeleteRule")
+	defer span.Finish()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rules := r.rules[userId]
+	for i := 0; i < len(rules); i++ {
+		if strings.EqualFold(rules[i].Pattern, pattern) {
+			r.rules[userId] = append(rules[:i], rules[i+1:]...)
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
@@ -0,0 +1,44 @@
+package expenses_memory_repo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/model"
+)
+
+func TestCategoryRulesShouldBeReplacedByPatternAndDeletedPerUser(t *testing.T) {
+	ctx := context.Background()
+	storage := NewCategoryRulesRepository()
+	userId := int64(100)
+
+	assert.NoError(t, storage.SaveRule(ctx, model.CategoryRule{
+		Type: model.AliasCategoryRule, Pattern: "еда", Category: "Еда", UserId: userId,
+	}))
+	assert.NoError(t, storage.SaveRule(ctx, model.CategoryRule{
+		Type: model.KeywordCategoryRule, Pattern: "старбакс", Category: "Кофе", UserId: userId,
+	}))
+	assert.NoError(t, storage.SaveRule(ctx, model.CategoryRule{
+		Type: model.AliasCategoryRule, Pattern: "ЕДА", Category: "Продукты", UserId: userId,
+	}))
+
+	rules, err := storage.GetRules(ctx, userId)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.CategoryRule{
+		{Type: model.AliasCategoryRule, Pattern: "ЕДА", Category: "Продукты", UserId: userId},
+		{Type: model.KeywordCategoryRule, Pattern: "старбакс", Category: "Кофе", UserId: userId},
+	}, rules)
+
+	found, err := storage.DeleteRule(ctx, 200, "еда")
+	assert.NoError(t, err)
+	assert.False(t, found)
+
+	found, err = storage.DeleteRule(ctx, userId, "еда")
+	assert.NoError(t, err)
+	assert.True(t, found)
+
+	rules, err = storage.GetRules(ctx, userId)
+	assert.NoError(t, err)
+	assert.Len(t, rules, 1)
+}
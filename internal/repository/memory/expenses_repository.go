@@ -2,85 +2,674 @@ package expenses_memory_repo
 
 import (
 	"context"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/opentracing/opentracing-go"
 	"gitlab.ozon.dev/cranky4/tg-bot/internal/model"
 	repo "gitlab.ozon.dev/cranky4/tg-bot/internal/repository"
 )
 
-type limit struct {
+// categoryKey ключ категории пользователя, название в нижнем регистре
+type categoryKey struct {
 	userId int64
-	amount int64
+	name   string
+}
+
+func newCategoryKey(userId int64, name string) categoryKey {
+	return categoryKey{userId: userId, name: strings.ToLower(strings.Trim(name, " "))}
+}
+
+// limitKey ключ лимита, у общего лимита название категории пустое
+type limitKey struct {
+	category categoryKey
+	scope    model.LimitScope
+	period   model.ExpensePeriod
+}
+
+func newLimitKey(limit model.ExpenseLimit) limitKey {
+	key := limitKey{category: categoryKey{userId: limit.UserId}, scope: limit.Scope, period: limit.Period}
+	if limit.Scope != model.TotalLimitScope {
+		key.category = newCategoryKey(limit.UserId, limit.Category)
+	}
+
+	return key
+}
+
+// limitValue сумма и пороги уведомлений лимита
+type limitValue struct {
+	amount     int64
+	thresholds []int
+}
+
+// notificationKey ключ отправленного уведомления о пороге лимита
+type notificationKey struct {
+	limit       limitKey
+	periodStart int64
+	threshold   int
+}
+
+// occurrenceKey ключ добавленной траты регулярной траты
+type occurrenceKey struct {
+	recurringId  string
+	occurrenceAt int64
 }
 
 type repository struct {
-	expenses []*model.Expense
-	limits   map[string]*limit
+	mu            *sync.RWMutex
+	expenses      []*model.Expense
+	occurrences   map[occurrenceKey]struct{}
+	categories    map[categoryKey]string // название категории в исходном регистре
+	limits        map[limitKey]limitValue
+	notifications map[notificationKey]struct{}
+	accounts      []model.Account // в порядке добавления
+	transfers     []model.Transfer
+	rules         repo.CategoryRulesRepository // правила удаляются вместе с категорией, может быть nil
+	splits        repo.SplitsRepository        // доли заменяются вместе с тратой, может быть nil
 }
 
 func NewRepository() repo.ExpensesRepository {
 	return &repository{
-		limits: make(map[string]*limit),
+		mu:            &sync.RWMutex{},
+		occurrences:   make(map[occurrenceKey]struct{}),
+		categories:    make(map[categoryKey]string),
+		limits:        make(map[limitKey]limitValue),
+		notifications: make(map[notificationKey]struct{}),
 	}
 }
 
+// NewLinkedRepository хранилище трат, которое, как таблицы одной базы, меняет вместе с тратами и категориями
+// правила из rules и доли из splits
+func NewLinkedRepository(rules repo.CategoryRulesRepository, splits repo.SplitsRepository) repo.ExpensesRepository {
+	r := NewRepository().(*repository)
+	r.rules = rules
+	r.splits = splits
+
+	return r
+}
+
 func (r *repository) Add(ctx context.Context, ex model.Expense) error {
 	span, _ := opentracing.StartSpanFromContext(ctx, "Add")
 	defer span.Finish()
 
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if ex.ID == "" {
+		ex.ID = uuid.NewString()
+	}
+	if ex.AuthorId == 0 {
+		ex.AuthorId = ex.UserId
+	}
+
+	if ex.Account != "" {
+		account, found := r.findAccount(ex.UserId, ex.Account)
+		if !found {
+			return repo.ErrAccountNotFound
+		}
+		ex.AccountID, ex.Account = account.ID, account.Name
+	}
+	ex.Category = r.ensureCategory(ex.UserId, ex.Category)
+
 	r.expenses = append(r.expenses, &ex)
 
 	return nil
 }
 
-func (r *repository) GetExpenses(ctx context.Context, p model.ExpensePeriod, userId int64) ([]*model.Expense, error) {
+func (r *repository) AddOccurrence(ctx context.Context, ex model.Expense, recurringId string, occurrenceAt time.Time) (bool, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "AddOccurrence")
+	defer span.Finish()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := occurrenceKey{recurringId: recurringId, occurrenceAt: occurrenceAt.UnixNano()}
+	if _, ok := r.occurrences[key]; ok {
+		return false, nil
+	}
+
+	if ex.ID == "" {
+		ex.ID = uuid.NewString()
+	}
+	if ex.AuthorId == 0 {
+		ex.AuthorId = ex.UserId
+	}
+	ex.Category = r.ensureCategory(ex.UserId, ex.Category)
+
+	r.expenses = append(r.expenses, &ex)
+	r.occurrences[key] = struct{}{}
+
+	return true, nil
+}
+
+func (r *repository) AddBatch(ctx context.Context, expenses []model.Expense) error {
+	span, _ := opentracing.StartSpanFromContext(ctx, "AddBatch")
+	defer span.Finish()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, ex := range expenses {
+		ex := ex
+		if ex.ID == "" {
+			ex.ID = uuid.NewString()
+		}
+		if ex.AuthorId == 0 {
+			ex.AuthorId = ex.UserId
+		}
+		ex.Category = r.ensureCategory(ex.UserId, ex.Category)
+
+		r.expenses = append(r.expenses, &ex)
+	}
+
+	return nil
+}
+
+func (r *repository) Update(ctx context.Context, ex model.Expense, shares []model.ExpenseShare) (bool, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "Update")
+	defer span.Finish()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := 0; i < len(r.expenses); i++ {
+		if r.expenses[i].ID == ex.ID && r.expenses[i].UserId == ex.UserId {
+			if len(shares) > 0 && r.splits != nil {
+				if err := r.splits.ReplaceShares(ctx, ex.ID, shares); err != nil {
+					return false, err
+				}
+			}
+
+			ex.Category = r.ensureCategory(ex.UserId, ex.Category)
+			ex.AccountID, ex.Account = r.expenses[i].AccountID, r.expenses[i].Account
+			ex.AuthorId = r.expenses[i].AuthorId
+			r.expenses[i] = &ex
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (r *repository) Delete(ctx context.Context, id string, userId int64) (bool, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "Delete")
+	defer span.Finish()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := 0; i < len(r.expenses); i++ {
+		if r.expenses[i].ID == id && r.expenses[i].UserId == userId {
+			r.expenses = append(r.expenses[:i], r.expenses[i+1:]...)
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (r *repository) GetExpenses(ctx context.Context, dateRange model.DateRange, userId int64) ([]*model.Expense, error) {
 	span, _ := opentracing.StartSpanFromContext(ctx, "GetExpenses")
 	defer span.Finish()
 
-	exps := make([]*model.Expense, 0, len(r.expenses))
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 
-	periodStart := p.GetStart(time.Now())
+	exps := make([]*model.Expense, 0, len(r.expenses))
+	dateRange = wallClockRange(dateRange)
 
+	// копии, переименование категории меняет сохраненные траты
 	for i := 0; i < len(r.expenses); i++ {
-		if r.expenses[i].Datetime.After(periodStart) {
-			exps = append(exps, r.expenses[i])
+		if r.expenses[i].UserId == userId && dateRange.Contains(model.WallClock(r.expenses[i].Datetime, time.UTC)) {
+			ex := *r.expenses[i]
+			exps = append(exps, &ex)
 		}
 	}
 
 	return exps, nil
 }
 
-func (r *repository) SetLimit(ctx context.Context, category string, userId, amount int64) error {
+func (r *repository) GetTrend(ctx context.Context, query model.TrendQuery, userId int64) ([]model.TrendPoint, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "GetTrend")
+	defer span.Finish()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	type trendKey struct {
+		from     time.Time
+		category string
+	}
+
+	// как и в базе, интервалы считаются по показаниям часов траты и границ периода, без перевода между поясами
+	dateRange := model.NewDateRange(model.WallClock(query.Range.From, query.Location), model.WallClock(query.Range.To, query.Location))
+
+	amounts := make(map[trendKey]int64)
+	for _, ex := range r.expenses {
+		datetime := model.WallClock(ex.Datetime, query.Location)
+		if ex.UserId != userId || !dateRange.Contains(datetime) {
+			continue
+		}
+
+		key := trendKey{from: query.Bucket.Start(datetime, query.WeekStart)}
+		if query.ByCategory {
+			key.category = ex.Category
+		}
+		amounts[key] += ex.Amount
+	}
+
+	points := make([]model.TrendPoint, 0, len(amounts))
+	for key, amount := range amounts {
+		points = append(points, model.TrendPoint{From: key.from, Category: key.category, Amount: amount})
+	}
+
+	sort.Slice(points, func(i, j int) bool {
+		if !points[i].From.Equal(points[j].From) {
+			return points[i].From.Before(points[j].From)
+		}
+		if points[i].Amount != points[j].Amount {
+			return points[i].Amount > points[j].Amount
+		}
+		return points[i].Category < points[j].Category
+	})
+
+	return points, nil
+}
+
+func (r *repository) SetLimit(ctx context.Context, limit model.ExpenseLimit) error {
 	span, _ := opentracing.StartSpanFromContext(ctx, "SetLimit")
 	defer span.Finish()
 
-	r.limits[strings.ToLower(category)] = &limit{amount: amount, userId: userId}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if limit.Scope != model.TotalLimitScope {
+		r.ensureCategory(limit.UserId, limit.Category)
+	}
+
+	key := newLimitKey(limit)
+	value, ex := r.limits[key]
+	if !ex {
+		value.thresholds = model.DefaultLimitThresholds()
+	}
+	value.amount = limit.Amount
+	r.limits[key] = value
 
 	return nil
 }
 
-func (r *repository) GetFreeLimit(ctx context.Context, category string, userId int64) (int64, bool, error) {
-	span, _ := opentracing.StartSpanFromContext(ctx, "GetFreeLimit")
+func (r *repository) GetLimits(ctx context.Context, category string, userId int64) ([]model.ExpenseLimit, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "GetLimits")
 	defer span.Finish()
 
-	loweredCategory := strings.ToLower(category)
-	if _, ex := r.limits[loweredCategory]; !ex {
-		return 0, false, nil
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	catKey := newCategoryKey(userId, category)
+
+	limits := make([]model.ExpenseLimit, 0)
+	for key, value := range r.limits {
+		if key.category.userId != userId || (key.scope != model.TotalLimitScope && key.category != catKey) {
+			continue
+		}
+
+		limits = append(limits, model.ExpenseLimit{
+			Scope:      key.scope,
+			Period:     key.period,
+			Category:   r.categories[key.category],
+			Amount:     value.amount,
+			Thresholds: value.thresholds,
+			UserId:     userId,
+		})
 	}
 
-	year, month, _ := time.Now().Date()
-	loc := time.Now().Location()
-	beginingOfMonth := time.Date(year, month, 0, 0, 0, 0, 0, loc)
+	return limits, nil
+}
+
+func (r *repository) GetFreeLimit(ctx context.Context, limit model.ExpenseLimit, dateRange model.DateRange) (int64, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "GetFreeLimit")
+	defer span.Finish()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	key := newLimitKey(limit)
+	dateRange = wallClockRange(dateRange)
 
 	var total int64
 	for i := 0; i < len(r.expenses); i++ {
-		if strings.ToLower(r.expenses[i].Category) == loweredCategory &&
-			r.expenses[i].Datetime.After(beginingOfMonth) {
-			total += r.expenses[i].Amount
+		ex := r.expenses[i]
+		if ex.UserId != limit.UserId || !dateRange.Contains(model.WallClock(ex.Datetime, time.UTC)) {
+			continue
+		}
+
+		if key.scope == model.TotalLimitScope || newCategoryKey(ex.UserId, ex.Category) == key.category {
+			total += ex.Amount
+		}
+	}
+
+	return limit.Amount - total, nil
+}
+
+func (r *repository) SetLimitThresholds(ctx context.Context, limit model.ExpenseLimit) (bool, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "SetLimitThresholds")
+	defer span.Finish()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := newLimitKey(limit)
+	value, ex := r.limits[key]
+	if !ex {
+		return false, nil
+	}
+
+	value.thresholds = limit.Thresholds
+	r.limits[key] = value
+
+	return true, nil
+}
+
+func (r *repository) SaveLimitNotification(ctx context.Context, limit model.ExpenseLimit, periodStart time.Time, threshold int) (bool, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "SaveLimitNotification")
+	defer span.Finish()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := notificationKey{limit: newLimitKey(limit), periodStart: periodStart.Unix(), threshold: threshold}
+	if _, ex := r.notifications[key]; ex {
+		return false, nil
+	}
+
+	r.notifications[key] = struct{}{}
+
+	return true, nil
+}
+
+func (r *repository) GetTopCategories(ctx context.Context, userId int64, limit int) ([]string, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "GetTopCategories")
+	defer span.Finish()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	counts := make(map[string]int)
+	for i := 0; i < len(r.expenses); i++ {
+		if r.expenses[i].UserId == userId {
+			counts[r.expenses[i].Category]++
+		}
+	}
+
+	categories := make([]string, 0, len(counts))
+	for category := range counts {
+		categories = append(categories, category)
+	}
+
+	sort.Slice(categories, func(i, j int) bool {
+		if counts[categories[i]] != counts[categories[j]] {
+			return counts[categories[i]] > counts[categories[j]]
+		}
+		return categories[i] < categories[j]
+	})
+
+	if len(categories) > limit {
+		categories = categories[:limit]
+	}
+
+	return categories, nil
+}
+
+func (r *repository) GetCategories(ctx context.Context, userId int64) ([]model.ExpenseCategory, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "GetCategories")
+	defer span.Finish()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	categories := make([]model.ExpenseCategory, 0)
+	for key, name := range r.categories {
+		if key.userId == userId {
+			categories = append(categories, model.ExpenseCategory{Name: name, UserId: userId})
+		}
+	}
+
+	sort.Slice(categories, func(i, j int) bool {
+		return categories[i].Name < categories[j].Name
+	})
+
+	return categories, nil
+}
+
+func (r *repository) RenameCategory(ctx context.Context, userId int64, name, newName string) (bool, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "RenameCategory")
+	defer span.Finish()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key, newKey := newCategoryKey(userId, name), newCategoryKey(userId, newName)
+	if _, ex := r.categories[key]; !ex {
+		return false, nil
+	}
+
+	// допускается смена регистра в названии той же категории
+	if _, ex := r.categories[newKey]; ex && newKey != key {
+		return false, repo.ErrCategoryExists
+	}
+
+	r.moveCategory(key, newKey, newName)
+
+	return true, nil
+}
+
+func (r *repository) MergeCategories(ctx context.Context, userId int64, from, to string) (bool, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "MergeCategories")
+	defer span.Finish()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	source, target := newCategoryKey(userId, from), newCategoryKey(userId, to)
+	if _, ex := r.categories[source]; !ex {
+		return false, nil
+	}
+
+	targetName, ex := r.categories[target]
+	if !ex {
+		return false, nil
+	}
+
+	if source != target {
+		r.moveCategory(source, target, targetName)
+	}
+
+	return true, nil
+}
+
+func (r *repository) DeleteCategory(ctx context.Context, userId int64, name string) (bool, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "DeleteCategory")
+	defer span.Finish()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := newCategoryKey(userId, name)
+	if _, ex := r.categories[key]; !ex {
+		return false, nil
+	}
+
+	for i := 0; i < len(r.expenses); i++ {
+		if newCategoryKey(r.expenses[i].UserId, r.expenses[i].Category) == key {
+			return false, repo.ErrCategoryHasExpenses
+		}
+	}
+
+	if err := r.deleteCategoryRules(ctx, key); err != nil {
+		return false, err
+	}
+
+	delete(r.categories, key)
+	for lk := range r.limits {
+		if lk.category == key {
+			delete(r.limits, lk)
+		}
+	}
+
+	return true, nil
+}
+
+func (r *repository) AddAccount(ctx context.Context, account model.Account) (model.Account, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "AddAccount")
+	defer span.Finish()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, found := r.findAccount(account.UserId, account.Name); found {
+		return model.Account{}, repo.ErrAccountExists
+	}
+
+	account.ID = uuid.NewString()
+	r.accounts = append(r.accounts, account)
+
+	return account, nil
+}
+
+func (r *repository) AddTransfer(ctx context.Context, transfer model.Transfer) error {
+	span, _ := opentracing.StartSpanFromContext(ctx, "AddTransfer")
+	defer span.Finish()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	from, fromFound := r.findAccount(transfer.UserId, transfer.FromAccount)
+	to, toFound := r.findAccount(transfer.UserId, transfer.ToAccount)
+	if !fromFound || !toFound {
+		return repo.ErrAccountNotFound
+	}
+
+	transfer.ID = uuid.NewString()
+	transfer.FromAccount, transfer.ToAccount = from.ID, to.ID
+	r.transfers = append(r.transfers, transfer)
+
+	return nil
+}
+
+func (r *repository) GetAccountBalances(ctx context.Context, userId int64) ([]model.AccountBalance, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "GetAccountBalances")
+	defer span.Finish()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	balances := make([]model.AccountBalance, 0)
+	for _, account := range r.accounts {
+		if account.UserId != userId {
+			continue
+		}
+
+		balance := account.OpeningBalance
+		for _, transfer := range r.transfers {
+			if transfer.ToAccount == account.ID {
+				balance += transfer.Amount
+			}
+			if transfer.FromAccount == account.ID {
+				balance -= transfer.Amount
+			}
+		}
+
+		for _, ex := range r.expenses {
+			if ex.AccountID == account.ID {
+				balance -= ex.Amount
+			}
+		}
+
+		balances = append(balances, model.AccountBalance{Account: account, Balance: balance})
+	}
+
+	return balances, nil
+}
+
+// findAccount ищет счет пользователя по названию без учета регистра
+func (r *repository) findAccount(userId int64, name string) (model.Account, bool) {
+	for _, account := range r.accounts {
+		if account.UserId == userId && strings.EqualFold(account.Name, strings.Trim(name, " ")) {
+			return account, true
+		}
+	}
+
+	return model.Account{}, false
+}
+
+// ensureCategory создает категорию пользователя, если ее нет, и возвращает ее название
+func (r *repository) ensureCategory(userId int64, name string) string {
+	key := newCategoryKey(userId, name)
+	if existing, ex := r.categories[key]; ex {
+		return existing
+	}
+
+	r.categories[key] = strings.Trim(name, " ")
+
+	return r.categories[key]
+}
+
+// moveCategory переносит траты и лимиты категории from в категорию to с названием name.
+// Лимит на период переносится, только если у категории to своего лимита на этот период нет
+// wallClockRange возвращает диапазон с теми же показаниями часов в UTC. Как и в базе, траты попадают в диапазон
+// по показаниям часов, без перевода между поясами
+func wallClockRange(dateRange model.DateRange) model.DateRange {
+	return model.NewDateRange(model.WallClock(dateRange.From, time.UTC), model.WallClock(dateRange.To, time.UTC))
+}
+
+// deleteCategoryRules удаляет правила, которые выбирают категорию key
+func (r *repository) deleteCategoryRules(ctx context.Context, key categoryKey) error {
+	if r.rules == nil {
+		return nil
+	}
+
+	rules, err := r.rules.GetRules(ctx, key.userId)
+	if err != nil {
+		return err
+	}
+
+	for _, rule := range rules {
+		if newCategoryKey(rule.UserId, rule.Category) != key {
+			continue
+		}
+
+		if _, err = r.rules.DeleteRule(ctx, key.userId, rule.Pattern); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *repository) moveCategory(from, to categoryKey, name string) {
+	for i := 0; i < len(r.expenses); i++ {
+		if newCategoryKey(r.expenses[i].UserId, r.expenses[i].Category) == from {
+			r.expenses[i].Category = name
+		}
+	}
+
+	if from != to {
+		for key, value := range r.limits {
+			if key.category != from {
+				continue
+			}
+
+			target := limitKey{category: to, scope: key.scope, period: key.period}
+			if _, targetEx := r.limits[target]; !targetEx {
+				r.limits[target] = value
+			}
+			delete(r.limits, key)
 		}
 	}
 
-	return r.limits[loweredCategory].amount - total, true, nil
+	delete(r.categories, from)
+	r.categories[to] = name
 }
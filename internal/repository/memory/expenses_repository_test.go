@@ -2,11 +2,13 @@ package expenses_memory_repo
 
 import (
 	"context"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"gitlab.ozon.dev/cranky4/tg-bot/internal/model"
+	repo "gitlab.ozon.dev/cranky4/tg-bot/internal/repository"
 )
 
 func TestStorageShouldAddExpensesToStorage(t *testing.T) {
@@ -14,7 +16,7 @@ func TestStorageShouldAddExpensesToStorage(t *testing.T) {
 	storage := NewRepository()
 	userId := int64(100)
 
-	exps, err := storage.GetExpenses(ctx, model.Week, userId)
+	exps, err := storage.GetExpenses(ctx, periodRange(model.Week), userId)
 	assert.Len(t, exps, 0)
 	assert.NoError(t, err)
 
@@ -31,7 +33,7 @@ func TestStorageShouldAddExpensesToStorage(t *testing.T) {
 	})
 	assert.NoError(t, err)
 
-	exps, err = storage.GetExpenses(ctx, model.Week, userId)
+	exps, err = storage.GetExpenses(ctx, periodRange(model.Week), userId)
 	assert.Len(t, exps, 1)
 	assert.NoError(t, err)
 
@@ -43,7 +45,7 @@ func TestStorageShouldAddExpensesToStorage(t *testing.T) {
 	})
 	assert.NoError(t, err)
 
-	exps, err = storage.GetExpenses(ctx, model.Month, userId)
+	exps, err = storage.GetExpenses(ctx, periodRange(model.Month), userId)
 	assert.Len(t, exps, 2)
 	assert.NoError(t, err)
 
@@ -59,20 +61,25 @@ func TestStorageShouldAddExpensesToStorage(t *testing.T) {
 		Amount:   12500,
 		Category: "Еще кофе в прошлом году",
 		Datetime: lastYear,
+		UserId:   userId,
 	})
 	assert.NoError(t, err)
 
-	exps, err = storage.GetExpenses(ctx, model.Month, userId)
+	exps, err = storage.GetExpenses(ctx, periodRange(model.Month), userId)
 	assert.Len(t, exps, 3)
 	assert.NoError(t, err)
 
-	exps, err = storage.GetExpenses(ctx, model.Year, userId)
+	exps, err = storage.GetExpenses(ctx, periodRange(model.Year), userId)
 	assert.Len(t, exps, 4)
 	assert.NoError(t, err)
 
-	exps, err = storage.GetExpenses(ctx, model.Week, userId)
+	exps, err = storage.GetExpenses(ctx, periodRange(model.Week), userId)
 	assert.Len(t, exps, 2)
 	assert.NoError(t, err)
+
+	exps, err = storage.GetExpenses(ctx, periodRange(model.Year), userId+1)
+	assert.Len(t, exps, 0)
+	assert.NoError(t, err)
 }
 
 func TestStorageShouldSetLimitAndReachedIt(t *testing.T) {
@@ -98,17 +105,30 @@ func TestStorageShouldSetLimitAndReachedIt(t *testing.T) {
 	})
 	assert.NoError(t, err)
 
-	freeLimit, isSet, err := repo.GetFreeLimit(ctx, category, userId)
+	period := model.Month
+	limitRange := period.GetRange(now)
+
+	limits, err := repo.GetLimits(ctx, category, userId)
+	assert.NoError(t, err)
+	assert.Len(t, limits, 0)
+
+	limit := model.ExpenseLimit{
+		Scope:    model.CategoryLimitScope,
+		Period:   model.Month,
+		Category: category,
+		Amount:   25000,
+		UserId:   userId,
+	}
+	err = repo.SetLimit(ctx, limit)
 	assert.NoError(t, err)
-	assert.False(t, isSet)
-	assert.Equal(t, int64(0), freeLimit)
 
-	err = repo.SetLimit(ctx, category, userId, 25000)
+	limits, err = repo.GetLimits(ctx, category, userId)
 	assert.NoError(t, err)
+	limit.Thresholds = model.DefaultLimitThresholds()
+	assert.Equal(t, []model.ExpenseLimit{limit}, limits)
 
-	freeLimit, isSet, err = repo.GetFreeLimit(ctx, category, userId)
+	freeLimit, err := repo.GetFreeLimit(ctx, limit, limitRange)
 	assert.NoError(t, err)
-	assert.True(t, isSet)
 	assert.Equal(t, int64(13000), freeLimit)
 
 	err = repo.Add(ctx, model.Expense{
@@ -119,9 +139,8 @@ func TestStorageShouldSetLimitAndReachedIt(t *testing.T) {
 	})
 	assert.NoError(t, err)
 
-	freeLimit, isSet, err = repo.GetFreeLimit(ctx, category, userId)
+	freeLimit, err = repo.GetFreeLimit(ctx, limit, limitRange)
 	assert.NoError(t, err)
-	assert.True(t, isSet)
 	assert.Equal(t, int64(1000), freeLimit)
 
 	err = repo.Add(ctx, model.Expense{
@@ -132,8 +151,484 @@ func TestStorageShouldSetLimitAndReachedIt(t *testing.T) {
 	})
 	assert.NoError(t, err)
 
-	freeLimit, isSet, err = repo.GetFreeLimit(ctx, category, userId)
+	freeLimit, err = repo.GetFreeLimit(ctx, limit, limitRange)
 	assert.NoError(t, err)
-	assert.True(t, isSet)
 	assert.Equal(t, int64(-11000), freeLimit)
 }
+
+func TestStorageShouldCountTotalLimitAcrossCategories(t *testing.T) {
+	repo := NewRepository()
+	now := time.Now()
+	ctx := context.Background()
+	userId := int64(100)
+
+	for _, ex := range []model.Expense{
+		{Amount: 1000, Category: "Кофе", Datetime: now, UserId: userId},
+		{Amount: 2000, Category: "Такси", Datetime: now, UserId: userId},
+		{Amount: 4000, Category: "Такси", Datetime: now, UserId: 200},
+	} {
+		assert.NoError(t, repo.Add(ctx, ex))
+	}
+
+	total := model.ExpenseLimit{Scope: model.TotalLimitScope, Period: model.Week, Amount: 5000, UserId: userId}
+	assert.NoError(t, repo.SetLimit(ctx, total))
+	assert.NoError(t, repo.SetLimit(ctx, model.ExpenseLimit{
+		Scope: model.CategoryLimitScope, Period: model.Year, Category: "Такси", Amount: 9000, UserId: userId,
+	}))
+
+	limits, err := repo.GetLimits(ctx, "Кофе", userId)
+	assert.NoError(t, err)
+	total.Thresholds = model.DefaultLimitThresholds()
+	assert.Equal(t, []model.ExpenseLimit{total}, limits)
+
+	limits, err = repo.GetLimits(ctx, "такси", userId)
+	assert.NoError(t, err)
+	assert.Len(t, limits, 2)
+
+	freeLimit, err := repo.GetFreeLimit(ctx, total, periodRange(model.Week))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2000), freeLimit)
+}
+
+func TestStorageShouldUpdateAndDeleteOnlyOwnExpenses(t *testing.T) {
+	ctx := context.Background()
+	repo := NewRepository()
+	userId := int64(100)
+
+	err := repo.Add(ctx, model.Expense{
+		Amount:   12000,
+		Category: "Кофе",
+		Datetime: time.Now(),
+		UserId:   userId,
+	})
+	assert.NoError(t, err)
+
+	exps, err := repo.GetExpenses(ctx, periodRange(model.Week), userId)
+	assert.NoError(t, err)
+	assert.Len(t, exps, 1)
+	assert.NotEmpty(t, exps[0].ID)
+
+	updated := *exps[0]
+	updated.Amount = 15000
+
+	found, err := repo.Update(ctx, model.Expense{ID: updated.ID, UserId: 200}, nil)
+	assert.NoError(t, err)
+	assert.False(t, found)
+
+	found, err = repo.Update(ctx, updated, nil)
+	assert.NoError(t, err)
+	assert.True(t, found)
+
+	exps, err = repo.GetExpenses(ctx, periodRange(model.Week), userId)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(15000), exps[0].Amount)
+
+	found, err = repo.Delete(ctx, updated.ID, 200)
+	assert.NoError(t, err)
+	assert.False(t, found)
+
+	found, err = repo.Delete(ctx, updated.ID, userId)
+	assert.NoError(t, err)
+	assert.True(t, found)
+
+	exps, err = repo.GetExpenses(ctx, periodRange(model.Week), userId)
+	assert.NoError(t, err)
+	assert.Len(t, exps, 0)
+}
+
+func periodRange(p model.ExpensePeriod) model.DateRange {
+	return p.GetRange(time.Now())
+}
+
+func TestAddBatchShouldSaveExpensesWithExistingCategories(t *testing.T) {
+	storage := NewRepository()
+	ctx := context.Background()
+	userId := int64(100)
+	now := time.Now()
+
+	assert.NoError(t, storage.Add(ctx, model.Expense{Amount: 100, Category: "Кофе", Datetime: now, UserId: userId}))
+
+	err := storage.AddBatch(ctx, []model.Expense{
+		{Amount: 200, Category: "кофе", Datetime: now, UserId: userId, AuthorId: 300},
+		{Amount: 300, Category: "Такси", Datetime: now, UserId: userId},
+	})
+	assert.NoError(t, err)
+
+	exps, err := storage.GetExpenses(ctx, periodRange(model.Week), userId)
+	assert.NoError(t, err)
+	assert.Len(t, exps, 3)
+	assert.Equal(t, "Кофе", exps[1].Category)
+	assert.Equal(t, int64(300), exps[1].AuthorId)
+	assert.NotEmpty(t, exps[2].ID)
+	assert.Equal(t, userId, exps[2].AuthorId)
+
+	categories, err := storage.GetCategories(ctx, userId)
+	assert.NoError(t, err)
+	assert.Len(t, categories, 2)
+}
+
+func TestAddOccurrenceShouldSaveExpenseOncePerOccurrence(t *testing.T) {
+	storage := NewRepository()
+	ctx := context.Background()
+	userId := int64(100)
+	now := time.Now()
+	recurringId := "1b9d6bcd-bbfd-4b2d-9b5d-ab8dfbbd4bed"
+
+	added, err := storage.AddOccurrence(ctx, model.Expense{Amount: 59900, Category: "Подписки", Datetime: now, UserId: userId}, recurringId, now)
+	assert.NoError(t, err)
+	assert.True(t, added)
+
+	// повторное добавление после сбоя, время в другом часовом поясе
+	added, err = storage.AddOccurrence(ctx, model.Expense{Amount: 59900, Category: "Подписки", Datetime: now, UserId: userId}, recurringId, now.UTC())
+	assert.NoError(t, err)
+	assert.False(t, added)
+
+	added, err = storage.AddOccurrence(ctx, model.Expense{Amount: 59900, Category: "Подписки", Datetime: now, UserId: userId}, recurringId, now.Add(time.Hour))
+	assert.NoError(t, err)
+	assert.True(t, added)
+
+	exps, err := storage.GetExpenses(ctx, periodRange(model.Week), userId)
+	assert.NoError(t, err)
+	assert.Len(t, exps, 2)
+}
+
+func TestGetTopCategoriesShouldReturnMostUsedUserCategories(t *testing.T) {
+	repo := NewRepository()
+	ctx := context.Background()
+	userId := int64(100)
+	now := time.Now()
+
+	for _, ex := range []model.Expense{
+		{Amount: 100, Category: "Дом", Datetime: now, UserId: userId},
+		{Amount: 100, Category: "Кофе", Datetime: now, UserId: userId},
+		{Amount: 100, Category: "Кофе", Datetime: now, UserId: userId},
+		{Amount: 100, Category: "Такси", Datetime: now, UserId: userId},
+		{Amount: 100, Category: "Чужая", Datetime: now, UserId: 200},
+	} {
+		assert.NoError(t, repo.Add(ctx, ex))
+	}
+
+	categories, err := repo.GetTopCategories(ctx, userId, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Кофе", "Дом"}, categories)
+}
+
+func TestCategoriesShouldBeRenamedMergedAndDeletedPerUser(t *testing.T) {
+	storage := NewRepository()
+	ctx := context.Background()
+	userId := int64(100)
+	otherUserId := int64(200)
+	now := time.Now()
+	period := model.Month
+	limitRange := period.GetRange(now)
+
+	for _, ex := range []model.Expense{
+		{Amount: 100, Category: "Еда", Datetime: now, UserId: userId},
+		{Amount: 200, Category: "еда", Datetime: now, UserId: userId},
+		{Amount: 300, Category: "Продукты", Datetime: now, UserId: userId},
+		{Amount: 400, Category: "Еда", Datetime: now, UserId: otherUserId},
+	} {
+		assert.NoError(t, storage.Add(ctx, ex))
+	}
+	assert.NoError(t, storage.SetLimit(ctx, model.ExpenseLimit{
+		Scope: model.CategoryLimitScope, Period: model.Month, Category: "Еда", Amount: 1000, UserId: userId,
+	}))
+
+	categories, err := storage.GetCategories(ctx, userId)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.ExpenseCategory{
+		{Name: "Еда", UserId: userId},
+		{Name: "Продукты", UserId: userId},
+	}, categories)
+
+	_, err = storage.RenameCategory(ctx, userId, "еда", "продукты")
+	assert.ErrorIs(t, err, repo.ErrCategoryExists)
+
+	found, err := storage.MergeCategories(ctx, userId, "Еда", "Продукты")
+	assert.NoError(t, err)
+	assert.True(t, found)
+
+	limits, err := storage.GetLimits(ctx, "Продукты", userId)
+	assert.NoError(t, err)
+	assert.Len(t, limits, 1)
+
+	freeLimit, err := storage.GetFreeLimit(ctx, limits[0], limitRange)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(400), freeLimit)
+
+	_, err = storage.DeleteCategory(ctx, userId, "Продукты")
+	assert.ErrorIs(t, err, repo.ErrCategoryHasExpenses)
+
+	found, err = storage.RenameCategory(ctx, userId, "Продукты", "Магазин")
+	assert.NoError(t, err)
+	assert.True(t, found)
+
+	assert.NoError(t, storage.SetLimit(ctx, model.ExpenseLimit{
+		Scope: model.CategoryLimitScope, Period: model.Week, Category: "Такси", Amount: 500, UserId: userId,
+	}))
+	found, err = storage.DeleteCategory(ctx, userId, "такси")
+	assert.NoError(t, err)
+	assert.True(t, found)
+
+	categories, err = storage.GetCategories(ctx, userId)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.ExpenseCategory{{Name: "Магазин", UserId: userId}}, categories)
+
+	categories, err = storage.GetCategories(ctx, otherUserId)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.ExpenseCategory{{Name: "Еда", UserId: otherUserId}}, categories)
+}
+
+func TestStorageShouldSaveLimitNotificationOncePerPeriod(t *testing.T) {
+	repo := NewRepository()
+	ctx := context.Background()
+	userId := int64(100)
+	periodStart := model.StartOfMonth(time.Now())
+
+	limit := model.ExpenseLimit{Scope: model.CategoryLimitScope, Period: model.Month, Category: "Кофе", Amount: 1000, UserId: userId}
+
+	found, err := repo.SetLimitThresholds(ctx, model.ExpenseLimit{
+		Scope: model.CategoryLimitScope, Period: model.Month, Category: "Кофе", Thresholds: []int{90}, UserId: userId,
+	})
+	assert.NoError(t, err)
+	assert.False(t, found)
+
+	assert.NoError(t, repo.SetLimit(ctx, limit))
+
+	limit.Thresholds = []int{90}
+	found, err = repo.SetLimitThresholds(ctx, limit)
+	assert.NoError(t, err)
+	assert.True(t, found)
+
+	assert.NoError(t, repo.SetLimit(ctx, model.ExpenseLimit{
+		Scope: model.CategoryLimitScope, Period: model.Month, Category: "кофе", Amount: 2000, UserId: userId,
+	}))
+
+	limits, err := repo.GetLimits(ctx, "Кофе", userId)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{90}, limits[0].Thresholds)
+	assert.Equal(t, int64(2000), limits[0].Amount)
+
+	saved, err := repo.SaveLimitNotification(ctx, limit, periodStart, 90)
+	assert.NoError(t, err)
+	assert.True(t, saved)
+
+	saved, err = repo.SaveLimitNotification(ctx, limit, periodStart, 90)
+	assert.NoError(t, err)
+	assert.False(t, saved)
+
+	saved, err = repo.SaveLimitNotification(ctx, limit, periodStart.AddDate(0, 1, 0), 90)
+	assert.NoError(t, err)
+	assert.True(t, saved)
+}
+
+func TestAccountBalancesShouldIncludeTransfersAndExpenses(t *testing.T) {
+	ctx := context.Background()
+	storage := NewRepository()
+	userId := int64(100)
+
+	card, err := storage.AddAccount(ctx, model.Account{Name: "Карта", Currency: "RUB", OpeningBalance: 1500000, UserId: userId})
+	assert.NoError(t, err)
+	savings, err := storage.AddAccount(ctx, model.Account{Name: "Накопления", Currency: "RUB", UserId: userId})
+	assert.NoError(t, err)
+
+	_, err = storage.AddAccount(ctx, model.Account{Name: "карта", Currency: "RUB", UserId: userId})
+	assert.ErrorIs(t, err, repo.ErrAccountExists)
+
+	assert.NoError(t, storage.AddTransfer(ctx, model.Transfer{
+		FromAccount: "карта", ToAccount: "Накопления", Amount: 500000, Datetime: time.Now(), UserId: userId,
+	}))
+	assert.ErrorIs(t, storage.AddTransfer(ctx, model.Transfer{
+		FromAccount: "Карта", ToAccount: "Наличные", Amount: 100, Datetime: time.Now(), UserId: userId,
+	}), repo.ErrAccountNotFound)
+
+	assert.NoError(t, storage.Add(ctx, model.Expense{
+		Amount: 35000, Category: "Кафе", Account: "КАРТА", Datetime: time.Now(), UserId: userId,
+	}))
+	assert.NoError(t, storage.Add(ctx, model.Expense{
+		Amount: 10000, Category: "Кафе", Datetime: time.Now(), UserId: userId,
+	}))
+	assert.ErrorIs(t, storage.Add(ctx, model.Expense{
+		Amount: 10000, Category: "Кафе", Account: "Карта", Datetime: time.Now(), UserId: 200,
+	}), repo.ErrAccountNotFound)
+
+	balances, err := storage.GetAccountBalances(ctx, userId)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.AccountBalance{
+		{Account: card, Balance: 965000},
+		{Account: savings, Balance: 500000},
+	}, balances)
+
+	exps, err := storage.GetExpenses(ctx, periodRange(model.Week), userId)
+	assert.NoError(t, err)
+	assert.Equal(t, "Карта", exps[0].Account)
+	assert.Equal(t, card.ID, exps[0].AccountID)
+}
+
+func TestStorageShouldGroupTrendByBucketsAndCategories(t *testing.T) {
+	ctx := context.Background()
+	storage := NewRepository()
+	userId := int64(100)
+
+	from := time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC)
+	for _, ex := range []model.Expense{
+		{Amount: 10000, Category: "Кафе", Datetime: from.Add(10 * time.Hour), UserId: userId},
+		{Amount: 20000, Category: "Дом", Datetime: from.Add(11 * time.Hour), UserId: userId},
+		{Amount: 5000, Category: "Кафе", Datetime: from.Add(23 * time.Hour), UserId: userId},
+		{Amount: 3000, Category: "Кафе", Datetime: from.Add(24*time.Hour + 30*time.Minute), UserId: userId},
+		{Amount: 7000, Category: "Кафе", Datetime: from.Add(12 * time.Hour), UserId: 200},
+	} {
+		assert.NoError(t, storage.Add(ctx, ex))
+	}
+
+	query := model.TrendQuery{
+		Range:     model.NewDateRange(from, from.AddDate(0, 0, 7)),
+		Bucket:    model.DayTrendBucket,
+		Location:  time.FixedZone("MSK", 3*60*60),
+		WeekStart: time.Monday,
+	}
+
+	// интервалы по показаниям часов, как в базе: трата в 23:00 остается в своих сутках
+	points, err := storage.GetTrend(ctx, query, userId)
+	assert.NoError(t, err)
+	assert.Len(t, points, 2)
+	assert.Equal(t, time.Date(2022, 10, 1, 0, 0, 0, 0, query.Location), points[0].From)
+	assert.Equal(t, int64(35000), points[0].Amount)
+	assert.Equal(t, "", points[0].Category)
+	assert.Equal(t, time.Date(2022, 10, 2, 0, 0, 0, 0, query.Location), points[1].From)
+	assert.Equal(t, int64(3000), points[1].Amount)
+
+	query.ByCategory = true
+	points, err = storage.GetTrend(ctx, query, userId)
+	assert.NoError(t, err)
+	assert.Len(t, points, 3)
+	assert.Equal(t, model.TrendPoint{From: time.Date(2022, 10, 1, 0, 0, 0, 0, query.Location), Category: "Дом", Amount: 20000}, points[0])
+	assert.Equal(t, model.TrendPoint{From: time.Date(2022, 10, 1, 0, 0, 0, 0, query.Location), Category: "Кафе", Amount: 15000}, points[1])
+}
+
+func TestStorageShouldBeSafeForConcurrentUse(t *testing.T) {
+	storage := NewRepository()
+	ctx := context.Background()
+	userId := int64(100)
+	now := time.Now()
+	limit := model.ExpenseLimit{Scope: model.CategoryLimitScope, Period: model.Week, Category: "Кофе", Amount: 100000, UserId: userId}
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			assert.NoError(t, storage.Add(ctx, model.Expense{Amount: 100, Category: "Кофе", Datetime: now, UserId: userId}))
+			assert.NoError(t, storage.SetLimit(ctx, limit))
+			_, err := storage.GetExpenses(ctx, periodRange(model.Week), userId)
+			assert.NoError(t, err)
+			_, err = storage.GetFreeLimit(ctx, limit, periodRange(model.Week))
+			assert.NoError(t, err)
+			_, err = storage.RenameCategory(ctx, userId, "Кофе", "кофе")
+			assert.NoError(t, err)
+			_, err = storage.GetCategories(ctx, userId)
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	exps, err := storage.GetExpenses(ctx, periodRange(model.Week), userId)
+	assert.NoError(t, err)
+	assert.Len(t, exps, 10)
+}
+
+func TestDeleteCategoryShouldDeleteItsRules(t *testing.T) {
+	ctx := context.Background()
+	userId := int64(100)
+	rules := NewCategoryRulesRepository()
+	storage := NewLinkedRepository(rules, nil)
+
+	assert.NoError(t, storage.Add(ctx, model.Expense{Amount: 100, Category: "Кофе", Datetime: time.Now(), UserId: userId}))
+	assert.NoError(t, storage.Add(ctx, model.Expense{Amount: 100, Category: "Такси", Datetime: time.Now(), UserId: 200}))
+	for _, rule := range []model.CategoryRule{
+		{Type: model.KeywordCategoryRule, Pattern: "uber", Category: "такси", UserId: userId},
+		{Type: model.AliasCategoryRule, Pattern: "кофейня", Category: "Кофе", UserId: userId},
+		{Type: model.KeywordCategoryRule, Pattern: "yandex", Category: "Такси", UserId: 200},
+	} {
+		assert.NoError(t, rules.SaveRule(ctx, rule))
+	}
+
+	// категория такси есть только у другого пользователя, у пользователя появляется через лимит
+	assert.NoError(t, storage.SetLimit(ctx, model.ExpenseLimit{
+		Scope: model.CategoryLimitScope, Period: model.Week, Category: "Такси", Amount: 500, UserId: userId,
+	}))
+	found, err := storage.DeleteCategory(ctx, userId, "Такси")
+	assert.NoError(t, err)
+	assert.True(t, found)
+
+	userRules, err := rules.GetRules(ctx, userId)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.CategoryRule{
+		{Type: model.AliasCategoryRule, Pattern: "кофейня", Category: "Кофе", UserId: userId},
+	}, userRules)
+
+	otherRules, err := rules.GetRules(ctx, 200)
+	assert.NoError(t, err)
+	assert.Len(t, otherRules, 1)
+}
+
+func TestRangeQueriesShouldCompareStoredWallClock(t *testing.T) {
+	storage := NewRepository()
+	ctx := context.Background()
+	userId := int64(100)
+	moscow := time.FixedZone("", 3*60*60)
+
+	// как из базы: показания часов пользователя с меткой UTC. По часам это 20.11 и 19.11,
+	// а по моменту времени наоборот - первая трата уже после полуночи по Москве, вторая - в пределах 20.11
+	assert.NoError(t, storage.Add(ctx, model.Expense{
+		Amount: 35000, Category: "Такси", Datetime: time.Date(2022, 11, 20, 23, 30, 0, 0, time.UTC), UserId: userId,
+	}))
+	assert.NoError(t, storage.Add(ctx, model.Expense{
+		Amount: 15000, Category: "Такси", Datetime: time.Date(2022, 11, 19, 22, 0, 0, 0, time.UTC), UserId: userId,
+	}))
+
+	day := model.NewDateRange(time.Date(2022, 11, 20, 0, 0, 0, 0, moscow), time.Date(2022, 11, 21, 0, 0, 0, 0, moscow))
+	exps, err := storage.GetExpenses(ctx, day, userId)
+	assert.NoError(t, err)
+	assert.Len(t, exps, 1)
+	assert.Equal(t, int64(35000), exps[0].Amount)
+
+	limit := model.ExpenseLimit{Scope: model.TotalLimitScope, Period: model.Week, Amount: 100000, UserId: userId}
+	free, err := storage.GetFreeLimit(ctx, limit, day)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(65000), free)
+}
+
+func TestUpdateShouldReplaceSharesWithExpense(t *testing.T) {
+	ctx := context.Background()
+	splits := NewSplitsRepository()
+	storage := NewLinkedRepository(nil, splits)
+	budgetId := int64(-1)
+	now := time.Now()
+
+	assert.NoError(t, storage.Add(ctx, model.Expense{ID: "1", Amount: 10000, Category: "Кафе", Datetime: now, UserId: budgetId}))
+	assert.NoError(t, splits.AddShares(ctx, []model.ExpenseShare{
+		{ExpenseID: "1", BudgetID: budgetId, PayerID: 200, UserID: 200, Amount: 5000},
+		{ExpenseID: "1", BudgetID: budgetId, PayerID: 200, UserID: 300, Amount: 5000},
+	}))
+	resplit := []model.ExpenseShare{
+		{ExpenseID: "1", BudgetID: budgetId, PayerID: 200, UserID: 200, Amount: 6000},
+		{ExpenseID: "1", BudgetID: budgetId, PayerID: 200, UserID: 300, Amount: 6000},
+	}
+
+	// чужая трата не меняется вместе с долями
+	found, err := storage.Update(ctx, model.Expense{ID: "1", Amount: 12000, Category: "Кафе", Datetime: now, UserId: 200}, resplit)
+	assert.NoError(t, err)
+	assert.False(t, found)
+	shares, err := splits.GetShares(ctx, "1")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5000), shares[0].Amount)
+
+	found, err = storage.Update(ctx, model.Expense{ID: "1", Amount: 12000, Category: "Кафе", Datetime: now, UserId: budgetId}, resplit)
+	assert.NoError(t, err)
+	assert.True(t, found)
+	shares, err = splits.GetShares(ctx, "1")
+	assert.NoError(t, err)
+	assert.Equal(t, resplit, shares)
+}
@@ -0,0 +1,49 @@
+package expenses_memory_repo
+
+import (
+	"context"
+	"sync"
+
+	"github.com/opentracing/opentracing-go"
+	repo "gitlab.ozon.dev/cranky4/tg-bot/internal/repository"
+)
+
+type importTokensRepository struct {
+	mu     *sync.RWMutex
+	tokens map[int64]string // [пользователь]хеш токена
+}
+
+func NewImportTokensRepository() repo.ImportTokensRepository {
+	return &importTokensRepository{
+		mu:     &sync.RWMutex{},
+		tokens: make(map[int64]string),
+	}
+}
+
+func (r *importTokensRepository) SaveToken(ctx context.Context, userId int64, tokenHash string) error {
+	span, _ := opentracing.StartSpanFromContext(ctx, "SaveToken")
+	defer span.Finish()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.tokens[userId] = tokenHash
+
+	return nil
+}
+
+func (r *importTokensRepository) GetTokenUser(ctx context.Context, tokenHash string) (int64, bool, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "GetTokenUser")
+	defer span.Finish()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for userId, hash := range r.tokens {
+		if hash == tokenHash {
+			return userId, true, nil
+		}
+	}
+
+	return 0, false, nil
+}
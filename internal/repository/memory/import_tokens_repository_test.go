@@ -0,0 +1,32 @@
+package expenses_memory_repo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewImportTokenShouldReplacePrevious(t *testing.T) {
+	ctx := context.Background()
+	repo := NewImportTokensRepository()
+
+	assert.NoError(t, repo.SaveToken(ctx, 100, "first"))
+	assert.NoError(t, repo.SaveToken(ctx, 200, "other"))
+
+	userId, found, err := repo.GetTokenUser(ctx, "first")
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, int64(100), userId)
+
+	assert.NoError(t, repo.SaveToken(ctx, 100, "second"))
+
+	_, found, err = repo.GetTokenUser(ctx, "first")
+	assert.NoError(t, err)
+	assert.False(t, found)
+
+	userId, found, err = repo.GetTokenUser(ctx, "second")
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, int64(100), userId)
+}
@@ -0,0 +1,53 @@
+package expenses_memory_repo
+
+import (
+	"context"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/opentracing/opentracing-go"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/model"
+	repo "gitlab.ozon.dev/cranky4/tg-bot/internal/repository"
+)
+
+type incomesRepository struct {
+	mu      *sync.RWMutex
+	incomes []model.Income
+}
+
+func NewIncomesRepository() repo.IncomesRepository {
+	return &incomesRepository{
+		mu:      &sync.RWMutex{},
+		incomes: make([]model.Income, 0),
+	}
+}
+
+func (r *incomesRepository) AddIncome(ctx context.Context, income model.Income) (model.Income, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "AddIncome")
+	defer span.Finish()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	income.ID = uuid.NewString()
+	r.incomes = append(r.incomes, income)
+
+	return income, nil
+}
+
+func (r *incomesRepository) GetIncomes(ctx context.Context, dateRange model.DateRange, userId int64) ([]model.Income, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "GetIncomes")
+	defer span.Finish()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	incomes := make([]model.Income, 0)
+	for _, income := range r.incomes {
+		if income.UserId == userId && dateRange.Contains(income.Datetime) {
+			incomes = append(incomes, income)
+		}
+	}
+
+	return incomes, nil
+}
@@ -0,0 +1,35 @@
+package expenses_memory_repo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/model"
+)
+
+func TestIncomesShouldBeFilteredByUserAndRange(t *testing.T) {
+	ctx := context.Background()
+	storage := NewIncomesRepository()
+	from := time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	salary, err := storage.AddIncome(ctx, model.Income{
+		Amount: 15000000, Source: "Зарплата", Datetime: from.AddDate(0, 0, 4), UserId: 100,
+	})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, salary.ID)
+
+	_, err = storage.AddIncome(ctx, model.Income{
+		Amount: 500000, Source: "Кэшбэк", Datetime: from.AddDate(0, 1, 0), UserId: 100,
+	})
+	assert.NoError(t, err)
+	_, err = storage.AddIncome(ctx, model.Income{
+		Amount: 100000, Source: "Зарплата", Datetime: from.AddDate(0, 0, 4), UserId: 200,
+	})
+	assert.NoError(t, err)
+
+	incomes, err := storage.GetIncomes(ctx, model.NewDateRange(from, from.AddDate(0, 1, 0)), 100)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.Income{salary}, incomes)
+}
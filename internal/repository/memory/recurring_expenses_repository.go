@@ -0,0 +1,133 @@
+package expenses_memory_repo
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/opentracing/opentracing-go"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/model"
+	repo "gitlab.ozon.dev/cranky4/tg-bot/internal/repository"
+)
+
+type recurringExpensesRepository struct {
+	mu        *sync.RWMutex
+	recurring []model.RecurringExpense // в порядке добавления
+}
+
+func NewRecurringExpensesRepository() repo.RecurringExpensesRepository {
+	return &recurringExpensesRepository{
+		mu:        &sync.RWMutex{},
+		recurring: make([]model.RecurringExpense, 0),
+	}
+}
+
+func (r *recurringExpensesRepository) AddRecurring(ctx context.Context, recurring model.RecurringExpense) (model.RecurringExpense, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "AddRecurring")
+	defer span.Finish()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	recurring.ID = uuid.NewString()
+	r.recurring = append(r.recurring, recurring)
+
+	return recurring, nil
+}
+
+func (r *recurringExpensesRepository) GetRecurring(ctx context.Context, userId int64) ([]model.RecurringExpense, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "GetRecurring")
+	defer span.Finish()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	recurring := make([]model.RecurringExpense, 0)
+	for _, rec := range r.recurring {
+		if rec.UserId == userId {
+			recurring = append(recurring, rec)
+		}
+	}
+
+	return recurring, nil
+}
+
+func (r *recurringExpensesRepository) SetPaused(
+	ctx context.Context,
+	userId int64,
+	id string,
+	paused bool,
+	nextRunAt time.Time,
+) (bool, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "SetPaused")
+	defer span.Finish()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := 0; i < len(r.recurring); i++ {
+		if r.recurring[i].ID == id && r.recurring[i].UserId == userId {
+			r.recurring[i].Paused = paused
+			if !paused {
+				r.recurring[i].NextRunAt = nextRunAt
+			}
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (r *recurringExpensesRepository) GetDueRecurring(ctx context.Context, now time.Time, limit int) ([]model.RecurringExpense, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "GetDueRecurring")
+	defer span.Finish()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	recurring := make([]model.RecurringExpense, 0)
+	for _, rec := range r.recurring {
+		if !rec.Paused && !rec.NextRunAt.After(now) {
+			recurring = append(recurring, rec)
+		}
+	}
+
+	sort.SliceStable(recurring, func(i, j int) bool {
+		return recurring[i].NextRunAt.Before(recurring[j].NextRunAt)
+	})
+
+	if len(recurring) > limit {
+		recurring = recurring[:limit]
+	}
+
+	return recurring, nil
+}
+
+func (r *recurringExpensesRepository) Reschedule(
+	ctx context.Context,
+	recurring model.RecurringExpense,
+	nextRunAt time.Time,
+) (bool, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "Reschedule")
+	defer span.Finish()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := 0; i < len(r.recurring); i++ {
+		if r.recurring[i].ID != recurring.ID {
+			continue
+		}
+
+		if r.recurring[i].Paused || !r.recurring[i].NextRunAt.Equal(recurring.NextRunAt) {
+			return false, nil
+		}
+
+		r.recurring[i].NextRunAt = nextRunAt
+		return true, nil
+	}
+
+	return false, nil
+}
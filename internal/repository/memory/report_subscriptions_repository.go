@@ -0,0 +1,127 @@
+package expenses_memory_repo
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/model"
+	repo "gitlab.ozon.dev/cranky4/tg-bot/internal/repository"
+)
+
+type subscriptionKey struct {
+	userId int64
+	period model.ExpensePeriod
+}
+
+type reportSubscriptionsRepository struct {
+	mu            *sync.RWMutex
+	subscriptions map[subscriptionKey]model.ReportSubscription
+}
+
+func NewReportSubscriptionsRepository() repo.ReportSubscriptionsRepository {
+	return &reportSubscriptionsRepository{
+		mu:            &sync.RWMutex{},
+		subscriptions: make(map[subscriptionKey]model.ReportSubscription),
+	}
+}
+
+func (r *reportSubscriptionsRepository) SaveSubscription(ctx context.Context, subscription model.ReportSubscription) error {
+	span, _ := opentracing.StartSpanFromContext(ctx, "SaveSubscription")
+	defer span.Finish()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.subscriptions[subscriptionKey{userId: subscription.UserId, period: subscription.Schedule.Period}] = subscription
+
+	return nil
+}
+
+func (r *reportSubscriptionsRepository) GetSubscriptions(ctx context.Context, userId int64) ([]model.ReportSubscription, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "GetSubscriptions")
+	defer span.Finish()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	subscriptions := make([]model.ReportSubscription, 0)
+	for key, subscription := range r.subscriptions {
+		if key.userId == userId {
+			subscriptions = append(subscriptions, subscription)
+		}
+	}
+
+	sort.Slice(subscriptions, func(i, j int) bool {
+		return subscriptions[i].Schedule.Period < subscriptions[j].Schedule.Period
+	})
+
+	return subscriptions, nil
+}
+
+func (r *reportSubscriptionsRepository) DeleteSubscription(ctx context.Context, userId int64, period model.ExpensePeriod) (bool, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "DeleteSubscription")
+	defer span.Finish()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := subscriptionKey{userId: userId, period: period}
+	if _, ok := r.subscriptions[key]; !ok {
+		return false, nil
+	}
+
+	delete(r.subscriptions, key)
+
+	return true, nil
+}
+
+func (r *reportSubscriptionsRepository) GetDueSubscriptions(ctx context.Context, now time.Time, limit int) ([]model.ReportSubscription, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "GetDueSubscriptions")
+	defer span.Finish()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	subscriptions := make([]model.ReportSubscription, 0)
+	for _, subscription := range r.subscriptions {
+		if !subscription.NextRunAt.After(now) {
+			subscriptions = append(subscriptions, subscription)
+		}
+	}
+
+	sort.Slice(subscriptions, func(i, j int) bool {
+		return subscriptions[i].NextRunAt.Before(subscriptions[j].NextRunAt)
+	})
+
+	if len(subscriptions) > limit {
+		subscriptions = subscriptions[:limit]
+	}
+
+	return subscriptions, nil
+}
+
+func (r *reportSubscriptionsRepository) Reschedule(
+	ctx context.Context,
+	subscription model.ReportSubscription,
+	nextRunAt time.Time,
+) (bool, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "Reschedule")
+	defer span.Finish()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := subscriptionKey{userId: subscription.UserId, period: subscription.Schedule.Period}
+	stored, ok := r.subscriptions[key]
+	if !ok || !stored.NextRunAt.Equal(subscription.NextRunAt) {
+		return false, nil
+	}
+
+	stored.NextRunAt = nextRunAt
+	r.subscriptions[key] = stored
+
+	return true, nil
+}
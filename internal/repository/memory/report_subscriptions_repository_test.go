@@ -0,0 +1,50 @@
+package expenses_memory_repo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/model"
+)
+
+func TestDueSubscriptionShouldBeRescheduledOnce(t *testing.T) {
+	ctx := context.Background()
+	storage := NewReportSubscriptionsRepository()
+	now := time.Date(2022, 11, 7, 9, 0, 0, 0, time.UTC)
+
+	due := model.ReportSubscription{
+		UserId: 100, Schedule: model.Schedule{Period: model.Week, Weekday: time.Monday, Hour: 9}, NextRunAt: now,
+	}
+	assert.NoError(t, storage.SaveSubscription(ctx, due))
+	assert.NoError(t, storage.SaveSubscription(ctx, model.ReportSubscription{
+		UserId: 100, Schedule: model.Schedule{Period: model.Month, Day: 1, Hour: 9}, NextRunAt: now.AddDate(0, 0, 24),
+	}))
+
+	subscriptions, err := storage.GetDueSubscriptions(ctx, now, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.ReportSubscription{due}, subscriptions)
+
+	rescheduled, err := storage.Reschedule(ctx, due, now.AddDate(0, 0, 7))
+	assert.NoError(t, err)
+	assert.True(t, rescheduled)
+
+	// вторая реплика с устаревшими данными не должна отправить отчет повторно
+	rescheduled, err = storage.Reschedule(ctx, due, now.AddDate(0, 0, 7))
+	assert.NoError(t, err)
+	assert.False(t, rescheduled)
+
+	subscriptions, err = storage.GetDueSubscriptions(ctx, now, 10)
+	assert.NoError(t, err)
+	assert.Len(t, subscriptions, 0)
+
+	found, err := storage.DeleteSubscription(ctx, 100, model.Week)
+	assert.NoError(t, err)
+	assert.True(t, found)
+
+	subscriptions, err = storage.GetSubscriptions(ctx, 100)
+	assert.NoError(t, err)
+	assert.Len(t, subscriptions, 1)
+	assert.Equal(t, model.Month, subscriptions[0].Schedule.Period)
+}
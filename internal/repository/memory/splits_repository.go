@@ -0,0 +1,131 @@
+package expenses_memory_repo
+
+import (
+	"context"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/opentracing/opentracing-go"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/model"
+	repo "gitlab.ozon.dev/cranky4/tg-bot/internal/repository"
+)
+
+type splitsRepository struct {
+	mu          *sync.RWMutex
+	shares      []model.ExpenseShare
+	settlements []model.Settlement
+}
+
+func NewSplitsRepository() repo.SplitsRepository {
+	return &splitsRepository{
+		mu:          &sync.RWMutex{},
+		shares:      make([]model.ExpenseShare, 0),
+		settlements: make([]model.Settlement, 0),
+	}
+}
+
+func (r *splitsRepository) AddShares(ctx context.Context, shares []model.ExpenseShare) error {
+	span, _ := opentracing.StartSpanFromContext(ctx, "AddShares")
+	defer span.Finish()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.shares = append(r.shares, shares...)
+
+	return nil
+}
+
+func (r *splitsRepository) GetShares(ctx context.Context, expenseId string) ([]model.ExpenseShare, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "GetShares")
+	defer span.Finish()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	shares := make([]model.ExpenseShare, 0)
+	for _, share := range r.shares {
+		if share.ExpenseID == expenseId {
+			shares = append(shares, share)
+		}
+	}
+
+	return shares, nil
+}
+
+func (r *splitsRepository) DeleteShares(ctx context.Context, expenseId string) error {
+	span, _ := opentracing.StartSpanFromContext(ctx, "DeleteShares")
+	defer span.Finish()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	shares := make([]model.ExpenseShare, 0, len(r.shares))
+	for _, share := range r.shares {
+		if share.ExpenseID != expenseId {
+			shares = append(shares, share)
+		}
+	}
+	r.shares = shares
+
+	return nil
+}
+
+func (r *splitsRepository) ReplaceShares(ctx context.Context, expenseId string, shares []model.ExpenseShare) error {
+	span, _ := opentracing.StartSpanFromContext(ctx, "ReplaceShares")
+	defer span.Finish()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	replaced := make([]model.ExpenseShare, 0, len(r.shares)+len(shares))
+	for _, share := range r.shares {
+		if share.ExpenseID != expenseId {
+			replaced = append(replaced, share)
+		}
+	}
+	r.shares = append(replaced, shares...)
+
+	return nil
+}
+
+func (r *splitsRepository) AddSettlement(ctx context.Context, settlement model.Settlement) (model.Settlement, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "AddSettlement")
+	defer span.Finish()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	settlement.ID = uuid.NewString()
+	r.settlements = append(r.settlements, settlement)
+
+	return settlement, nil
+}
+
+func (r *splitsRepository) GetBalances(ctx context.Context, budgetId int64) (map[int64]int64, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "GetBalances")
+	defer span.Finish()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	balances := make(map[int64]int64)
+	for _, share := range r.shares {
+		// свою долю плательщик никому не должен
+		if share.BudgetID != budgetId || share.PayerID == share.UserID {
+			continue
+		}
+		balances[share.PayerID] += share.Amount
+		balances[share.UserID] -= share.Amount
+	}
+
+	for _, settlement := range r.settlements {
+		if settlement.BudgetID != budgetId {
+			continue
+		}
+		balances[settlement.FromUserID] += settlement.Amount
+		balances[settlement.ToUserID] -= settlement.Amount
+	}
+
+	return balances, nil
+}
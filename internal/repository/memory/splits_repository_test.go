@@ -0,0 +1,59 @@
+package expenses_memory_repo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/model"
+)
+
+func TestBalancesShouldConsiderSharesAndSettlements(t *testing.T) {
+	ctx := context.Background()
+	storage := NewSplitsRepository()
+	budgetId := int64(-1)
+
+	assert.NoError(t, storage.AddShares(ctx, []model.ExpenseShare{
+		{ExpenseID: "1", BudgetID: budgetId, PayerID: 100, UserID: 100, Amount: 100000},
+		{ExpenseID: "1", BudgetID: budgetId, PayerID: 100, UserID: 200, Amount: 100000},
+		{ExpenseID: "1", BudgetID: budgetId, PayerID: 100, UserID: 300, Amount: 100000},
+		{ExpenseID: "2", BudgetID: -2, PayerID: 200, UserID: 100, Amount: 500},
+	}))
+
+	_, err := storage.AddSettlement(ctx, model.Settlement{BudgetID: budgetId, FromUserID: 200, ToUserID: 100, Amount: 40000})
+	assert.NoError(t, err)
+
+	balances, err := storage.GetBalances(ctx, budgetId)
+	assert.NoError(t, err)
+	assert.Equal(t, map[int64]int64{100: 160000, 200: -60000, 300: -100000}, balances)
+
+	assert.NoError(t, storage.DeleteShares(ctx, "1"))
+
+	shares, err := storage.GetShares(ctx, "1")
+	assert.NoError(t, err)
+	assert.Len(t, shares, 0)
+}
+
+func TestReplaceSharesShouldReplaceOnlyExpenseShares(t *testing.T) {
+	ctx := context.Background()
+	storage := NewSplitsRepository()
+
+	assert.NoError(t, storage.AddShares(ctx, []model.ExpenseShare{
+		{ExpenseID: "1", BudgetID: -1, PayerID: 100, UserID: 100, Amount: 5000},
+		{ExpenseID: "1", BudgetID: -1, PayerID: 100, UserID: 200, Amount: 5000},
+		{ExpenseID: "2", BudgetID: -1, PayerID: 200, UserID: 100, Amount: 500},
+	}))
+
+	assert.NoError(t, storage.ReplaceShares(ctx, "1", []model.ExpenseShare{
+		{ExpenseID: "1", BudgetID: -1, PayerID: 100, UserID: 100, Amount: 6000},
+		{ExpenseID: "1", BudgetID: -1, PayerID: 100, UserID: 200, Amount: 6000},
+	}))
+
+	shares, err := storage.GetShares(ctx, "1")
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{6000, 6000}, []int64{shares[0].Amount, shares[1].Amount})
+
+	shares, err = storage.GetShares(ctx, "2")
+	assert.NoError(t, err)
+	assert.Len(t, shares, 1)
+}
@@ -0,0 +1,46 @@
+package expenses_memory_repo
+
+import (
+	"context"
+	"sync"
+
+	"github.com/opentracing/opentracing-go"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/model"
+	repo "gitlab.ozon.dev/cranky4/tg-bot/internal/repository"
+)
+
+type settingsRepository struct {
+	mu       *sync.RWMutex
+	settings map[int64]model.UserSettings
+}
+
+func NewSettingsRepository() repo.UserSettingsRepository {
+	return &settingsRepository{
+		mu:       &sync.RWMutex{},
+		settings: make(map[int64]model.UserSettings),
+	}
+}
+
+func (r *settingsRepository) GetSettings(ctx context.Context, userId int64) (model.UserSettings, bool, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "GetSettings")
+	defer span.Finish()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	settings, ok := r.settings[userId]
+
+	return settings, ok, nil
+}
+
+func (r *settingsRepository) SaveSettings(ctx context.Context, settings model.UserSettings) error {
+	span, _ := opentracing.StartSpanFromContext(ctx, "SaveSettings")
+	defer span.Finish()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.settings[settings.UserID] = settings
+
+	return nil
+}
@@ -0,0 +1,34 @@
+package expenses_memory_repo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/model"
+)
+
+func TestSettingsShouldBeStoredPerUser(t *testing.T) {
+	ctx := context.Background()
+	repo := NewSettingsRepository()
+
+	_, found, err := repo.GetSettings(ctx, 100)
+	assert.NoError(t, err)
+	assert.False(t, found)
+
+	err = repo.SaveSettings(ctx, model.UserSettings{UserID: 100, Currency: "USD"})
+	assert.NoError(t, err)
+
+	err = repo.SaveSettings(ctx, model.UserSettings{UserID: 200, Currency: "EUR"})
+	assert.NoError(t, err)
+
+	settings, found, err := repo.GetSettings(ctx, 100)
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "USD", settings.Currency)
+
+	settings, found, err = repo.GetSettings(ctx, 200)
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "EUR", settings.Currency)
+}
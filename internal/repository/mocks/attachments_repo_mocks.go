@@ -0,0 +1,66 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/attachments.go
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	model "gitlab.ozon.dev/cranky4/tg-bot/internal/model"
+)
+
+// MockAttachmentsRepository is a mock of AttachmentsRepository interface.
+type MockAttachmentsRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAttachmentsRepositoryMockRecorder
+}
+
+// MockAttachmentsRepositoryMockRecorder is the mock recorder for MockAttachmentsRepository.
+type MockAttachmentsRepositoryMockRecorder struct {
+	mock *MockAttachmentsRepository
+}
+
+// NewMockAttachmentsRepository creates a new mock instance.
+func NewMockAttachmentsRepository(ctrl *gomock.Controller) *MockAttachmentsRepository {
+	mock := &MockAttachmentsRepository{ctrl: ctrl}
+	mock.recorder = &MockAttachmentsRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAttachmentsRepository) EXPECT() *MockAttachmentsRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetAttachment mocks base method.
+func (m *MockAttachmentsRepository) GetAttachment(ctx context.Context, expenseId string, userId int64) (model.Attachment, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAttachment", ctx, expenseId, userId)
+	ret0, _ := ret[0].(model.Attachment)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetAttachment indicates an expected call of GetAttachment.
+func (mr *MockAttachmentsRepositoryMockRecorder) GetAttachment(ctx, expenseId, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAttachment", reflect.TypeOf((*MockAttachmentsRepository)(nil).GetAttachment), ctx, expenseId, userId)
+}
+
+// SaveAttachment mocks base method.
+func (m *MockAttachmentsRepository) SaveAttachment(ctx context.Context, attachment model.Attachment) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveAttachment", ctx, attachment)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveAttachment indicates an expected call of SaveAttachment.
+func (mr *MockAttachmentsRepositoryMockRecorder) SaveAttachment(ctx, attachment interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveAttachment", reflect.TypeOf((*MockAttachmentsRepository)(nil).SaveAttachment), ctx, attachment)
+}
@@ -0,0 +1,172 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/budgets.go
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	model "gitlab.ozon.dev/cranky4/tg-bot/internal/model"
+)
+
+// MockBudgetsRepository is a mock of BudgetsRepository interface.
+type MockBudgetsRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockBudgetsRepositoryMockRecorder
+}
+
+// MockBudgetsRepositoryMockRecorder is the mock recorder for MockBudgetsRepository.
+type MockBudgetsRepositoryMockRecorder struct {
+	mock *MockBudgetsRepository
+}
+
+// NewMockBudgetsRepository creates a new mock instance.
+func NewMockBudgetsRepository(ctrl *gomock.Controller) *MockBudgetsRepository {
+	mock := &MockBudgetsRepository{ctrl: ctrl}
+	mock.recorder = &MockBudgetsRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBudgetsRepository) EXPECT() *MockBudgetsRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddBudget mocks base method.
+func (m *MockBudgetsRepository) AddBudget(ctx context.Context, budget model.Budget, owner model.BudgetMember) (model.Budget, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddBudget", ctx, budget, owner)
+	ret0, _ := ret[0].(model.Budget)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddBudget indicates an expected call of AddBudget.
+func (mr *MockBudgetsRepositoryMockRecorder) AddBudget(ctx, budget, owner interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddBudget", reflect.TypeOf((*MockBudgetsRepository)(nil).AddBudget), ctx, budget, owner)
+}
+
+// DeleteMember mocks base method.
+func (m *MockBudgetsRepository) DeleteMember(ctx context.Context, budgetId, userId int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteMember", ctx, budgetId, userId)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteMember indicates an expected call of DeleteMember.
+func (mr *MockBudgetsRepositoryMockRecorder) DeleteMember(ctx, budgetId, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMember", reflect.TypeOf((*MockBudgetsRepository)(nil).DeleteMember), ctx, budgetId, userId)
+}
+
+// GetActiveMember mocks base method.
+func (m *MockBudgetsRepository) GetActiveMember(ctx context.Context, userId int64) (model.BudgetMember, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActiveMember", ctx, userId)
+	ret0, _ := ret[0].(model.BudgetMember)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetActiveMember indicates an expected call of GetActiveMember.
+func (mr *MockBudgetsRepositoryMockRecorder) GetActiveMember(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveMember", reflect.TypeOf((*MockBudgetsRepository)(nil).GetActiveMember), ctx, userId)
+}
+
+// GetBudget mocks base method.
+func (m *MockBudgetsRepository) GetBudget(ctx context.Context, budgetId int64) (model.Budget, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBudget", ctx, budgetId)
+	ret0, _ := ret[0].(model.Budget)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetBudget indicates an expected call of GetBudget.
+func (mr *MockBudgetsRepositoryMockRecorder) GetBudget(ctx, budgetId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBudget", reflect.TypeOf((*MockBudgetsRepository)(nil).GetBudget), ctx, budgetId)
+}
+
+// GetBudgetByCode mocks base method.
+func (m *MockBudgetsRepository) GetBudgetByCode(ctx context.Context, joinCode string) (model.Budget, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBudgetByCode", ctx, joinCode)
+	ret0, _ := ret[0].(model.Budget)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetBudgetByCode indicates an expected call of GetBudgetByCode.
+func (mr *MockBudgetsRepositoryMockRecorder) GetBudgetByCode(ctx, joinCode interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBudgetByCode", reflect.TypeOf((*MockBudgetsRepository)(nil).GetBudgetByCode), ctx, joinCode)
+}
+
+// GetMembers mocks base method.
+func (m *MockBudgetsRepository) GetMembers(ctx context.Context, budgetId int64) ([]model.BudgetMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMembers", ctx, budgetId)
+	ret0, _ := ret[0].([]model.BudgetMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMembers indicates an expected call of GetMembers.
+func (mr *MockBudgetsRepositoryMockRecorder) GetMembers(ctx, budgetId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMembers", reflect.TypeOf((*MockBudgetsRepository)(nil).GetMembers), ctx, budgetId)
+}
+
+// GetUserBudgets mocks base method.
+func (m *MockBudgetsRepository) GetUserBudgets(ctx context.Context, userId int64) ([]model.Budget, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserBudgets", ctx, userId)
+	ret0, _ := ret[0].([]model.Budget)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserBudgets indicates an expected call of GetUserBudgets.
+func (mr *MockBudgetsRepositoryMockRecorder) GetUserBudgets(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserBudgets", reflect.TypeOf((*MockBudgetsRepository)(nil).GetUserBudgets), ctx, userId)
+}
+
+// SaveMember mocks base method.
+func (m *MockBudgetsRepository) SaveMember(ctx context.Context, member model.BudgetMember) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveMember", ctx, member)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveMember indicates an expected call of SaveMember.
+func (mr *MockBudgetsRepositoryMockRecorder) SaveMember(ctx, member interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveMember", reflect.TypeOf((*MockBudgetsRepository)(nil).SaveMember), ctx, member)
+}
+
+// SetActiveBudget mocks base method.
+func (m *MockBudgetsRepository) SetActiveBudget(ctx context.Context, userId, budgetId int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetActiveBudget", ctx, userId, budgetId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetActiveBudget indicates an expected call of SetActiveBudget.
+func (mr *MockBudgetsRepositoryMockRecorder) SetActiveBudget(ctx, userId, budgetId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetActiveBudget", reflect.TypeOf((*MockBudgetsRepository)(nil).SetActiveBudget), ctx, userId, budgetId)
+}
@@ -0,0 +1,80 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/category_rules.go
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	model "gitlab.ozon.dev/cranky4/tg-bot/internal/model"
+)
+
+// MockCategoryRulesRepository is a mock of CategoryRulesRepository interface.
+type MockCategoryRulesRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockCategoryRulesRepositoryMockRecorder
+}
+
+// MockCategoryRulesRepositoryMockRecorder is the mock recorder for MockCategoryRulesRepository.
+type MockCategoryRulesRepositoryMockRecorder struct {
+	mock *MockCategoryRulesRepository
+}
+
+// NewMockCategoryRulesRepository creates a new mock instance.
+func NewMockCategoryRulesRepository(ctrl *gomock.Controller) *MockCategoryRulesRepository {
+	mock := &MockCategoryRulesRepository{ctrl: ctrl}
+	mock.recorder = &MockCategoryRulesRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCategoryRulesRepository) EXPECT() *MockCategoryRulesRepositoryMockRecorder {
+	return m.recorder
+}
+
+// DeleteRule mocks base method.
+func (m *MockCategoryRulesRepository) DeleteRule(ctx context.Context, userId int64, pattern string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteRule", ctx, userId, pattern)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteRule indicates an expected call of DeleteRule.
+func (mr *MockCategoryRulesRepositoryMockRecorder) DeleteRule(ctx, userId, pattern interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRule", reflect.TypeOf((*MockCategoryRulesRepository)(nil).DeleteRule), ctx, userId, pattern)
+}
+
+// GetRules mocks base method.
+func (m *MockCategoryRulesRepository) GetRules(ctx context.Context, userId int64) ([]model.CategoryRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRules", ctx, userId)
+	ret0, _ := ret[0].([]model.CategoryRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRules indicates an expected call of GetRules.
+func (mr *MockCategoryRulesRepositoryMockRecorder) GetRules(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRules", reflect.TypeOf((*MockCategoryRulesRepository)(nil).GetRules), ctx, userId)
+}
+
+// SaveRule mocks base method.
+func (m *MockCategoryRulesRepository) SaveRule(ctx context.Context, rule model.CategoryRule) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveRule", ctx, rule)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveRule indicates an expected call of SaveRule.
+func (mr *MockCategoryRulesRepositoryMockRecorder) SaveRule(ctx, rule interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveRule", reflect.TypeOf((*MockCategoryRulesRepository)(nil).SaveRule), ctx, rule)
+}
@@ -7,6 +7,7 @@ package mock_repository
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	model "gitlab.ozon.dev/cranky4/tg-bot/internal/model"
@@ -49,47 +50,284 @@ func (mr *MockExpensesRepositoryMockRecorder) Add(ctx, expense interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockExpensesRepository)(nil).Add), ctx, expense)
 }
 
+// AddAccount mocks base method.
+func (m *MockExpensesRepository) AddAccount(ctx context.Context, account model.Account) (model.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddAccount", ctx, account)
+	ret0, _ := ret[0].(model.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddAccount indicates an expected call of AddAccount.
+func (mr *MockExpensesRepositoryMockRecorder) AddAccount(ctx, account interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAccount", reflect.TypeOf((*MockExpensesRepository)(nil).AddAccount), ctx, account)
+}
+
+// AddBatch mocks base method.
+func (m *MockExpensesRepository) AddBatch(ctx context.Context, expenses []model.Expense) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddBatch", ctx, expenses)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddBatch indicates an expected call of AddBatch.
+func (mr *MockExpensesRepositoryMockRecorder) AddBatch(ctx, expenses interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddBatch", reflect.TypeOf((*MockExpensesRepository)(nil).AddBatch), ctx, expenses)
+}
+
+// AddOccurrence mocks base method.
+func (m *MockExpensesRepository) AddOccurrence(ctx context.Context, expense model.Expense, recurringId string, occurrenceAt time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddOccurrence", ctx, expense, recurringId, occurrenceAt)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddOccurrence indicates an expected call of AddOccurrence.
+func (mr *MockExpensesRepositoryMockRecorder) AddOccurrence(ctx, expense, recurringId, occurrenceAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddOccurrence", reflect.TypeOf((*MockExpensesRepository)(nil).AddOccurrence), ctx, expense, recurringId, occurrenceAt)
+}
+
+// AddTransfer mocks base method.
+func (m *MockExpensesRepository) AddTransfer(ctx context.Context, transfer model.Transfer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddTransfer", ctx, transfer)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddTransfer indicates an expected call of AddTransfer.
+func (mr *MockExpensesRepositoryMockRecorder) AddTransfer(ctx, transfer interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddTransfer", reflect.TypeOf((*MockExpensesRepository)(nil).AddTransfer), ctx, transfer)
+}
+
+// Delete mocks base method.
+func (m *MockExpensesRepository) Delete(ctx context.Context, id string, userId int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id, userId)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockExpensesRepositoryMockRecorder) Delete(ctx, id, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockExpensesRepository)(nil).Delete), ctx, id, userId)
+}
+
+// DeleteCategory mocks base method.
+func (m *MockExpensesRepository) DeleteCategory(ctx context.Context, userId int64, name string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCategory", ctx, userId, name)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteCategory indicates an expected call of DeleteCategory.
+func (mr *MockExpensesRepositoryMockRecorder) DeleteCategory(ctx, userId, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCategory", reflect.TypeOf((*MockExpensesRepository)(nil).DeleteCategory), ctx, userId, name)
+}
+
+// GetAccountBalances mocks base method.
+func (m *MockExpensesRepository) GetAccountBalances(ctx context.Context, userId int64) ([]model.AccountBalance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccountBalances", ctx, userId)
+	ret0, _ := ret[0].([]model.AccountBalance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccountBalances indicates an expected call of GetAccountBalances.
+func (mr *MockExpensesRepositoryMockRecorder) GetAccountBalances(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountBalances", reflect.TypeOf((*MockExpensesRepository)(nil).GetAccountBalances), ctx, userId)
+}
+
+// GetCategories mocks base method.
+func (m *MockExpensesRepository) GetCategories(ctx context.Context, userId int64) ([]model.ExpenseCategory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCategories", ctx, userId)
+	ret0, _ := ret[0].([]model.ExpenseCategory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCategories indicates an expected call of GetCategories.
+func (mr *MockExpensesRepositoryMockRecorder) GetCategories(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCategories", reflect.TypeOf((*MockExpensesRepository)(nil).GetCategories), ctx, userId)
+}
+
 // GetExpenses mocks base method.
-func (m *MockExpensesRepository) GetExpenses(ctx context.Context, period model.ExpensePeriod, userId int64) ([]*model.Expense, error) {
+func (m *MockExpensesRepository) GetExpenses(ctx context.Context, dateRange model.DateRange, userId int64) ([]*model.Expense, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetExpenses", ctx, period, userId)
+	ret := m.ctrl.Call(m, "GetExpenses", ctx, dateRange, userId)
 	ret0, _ := ret[0].([]*model.Expense)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetExpenses indicates an expected call of GetExpenses.
-func (mr *MockExpensesRepositoryMockRecorder) GetExpenses(ctx, period, userId interface{}) *gomock.Call {
+func (mr *MockExpensesRepositoryMockRecorder) GetExpenses(ctx, dateRange, userId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExpenses", reflect.TypeOf((*MockExpensesRepository)(nil).GetExpenses), ctx, period, userId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExpenses", reflect.TypeOf((*MockExpensesRepository)(nil).GetExpenses), ctx, dateRange, userId)
 }
 
 // GetFreeLimit mocks base method.
-func (m *MockExpensesRepository) GetFreeLimit(ctx context.Context, category string, userId int64) (int64, bool, error) {
+func (m *MockExpensesRepository) GetFreeLimit(ctx context.Context, limit model.ExpenseLimit, dateRange model.DateRange) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetFreeLimit", ctx, category, userId)
+	ret := m.ctrl.Call(m, "GetFreeLimit", ctx, limit, dateRange)
 	ret0, _ := ret[0].(int64)
-	ret1, _ := ret[1].(bool)
-	ret2, _ := ret[2].(error)
-	return ret0, ret1, ret2
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // GetFreeLimit indicates an expected call of GetFreeLimit.
-func (mr *MockExpensesRepositoryMockRecorder) GetFreeLimit(ctx, category, userId interface{}) *gomock.Call {
+func (mr *MockExpensesRepositoryMockRecorder) GetFreeLimit(ctx, limit, dateRange interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFreeLimit", reflect.TypeOf((*MockExpensesRepository)(nil).GetFreeLimit), ctx, limit, dateRange)
+}
+
+// GetLimits mocks base method.
+func (m *MockExpensesRepository) GetLimits(ctx context.Context, category string, userId int64) ([]model.ExpenseLimit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLimits", ctx, category, userId)
+	ret0, _ := ret[0].([]model.ExpenseLimit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLimits indicates an expected call of GetLimits.
+func (mr *MockExpensesRepositoryMockRecorder) GetLimits(ctx, category, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLimits", reflect.TypeOf((*MockExpensesRepository)(nil).GetLimits), ctx, category, userId)
+}
+
+// GetTopCategories mocks base method.
+func (m *MockExpensesRepository) GetTopCategories(ctx context.Context, userId int64, limit int) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTopCategories", ctx, userId, limit)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTopCategories indicates an expected call of GetTopCategories.
+func (mr *MockExpensesRepositoryMockRecorder) GetTopCategories(ctx, userId, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTopCategories", reflect.TypeOf((*MockExpensesRepository)(nil).GetTopCategories), ctx, userId, limit)
+}
+
+// GetTrend mocks base method.
+func (m *MockExpensesRepository) GetTrend(ctx context.Context, query model.TrendQuery, userId int64) ([]model.TrendPoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTrend", ctx, query, userId)
+	ret0, _ := ret[0].([]model.TrendPoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTrend indicates an expected call of GetTrend.
+func (mr *MockExpensesRepositoryMockRecorder) GetTrend(ctx, query, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTrend", reflect.TypeOf((*MockExpensesRepository)(nil).GetTrend), ctx, query, userId)
+}
+
+// MergeCategories mocks base method.
+func (m *MockExpensesRepository) MergeCategories(ctx context.Context, userId int64, from, to string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MergeCategories", ctx, userId, from, to)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MergeCategories indicates an expected call of MergeCategories.
+func (mr *MockExpensesRepositoryMockRecorder) MergeCategories(ctx, userId, from, to interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFreeLimit", reflect.TypeOf((*MockExpensesRepository)(nil).GetFreeLimit), ctx, category, userId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MergeCategories", reflect.TypeOf((*MockExpensesRepository)(nil).MergeCategories), ctx, userId, from, to)
+}
+
+// RenameCategory mocks base method.
+func (m *MockExpensesRepository) RenameCategory(ctx context.Context, userId int64, name, newName string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenameCategory", ctx, userId, name, newName)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RenameCategory indicates an expected call of RenameCategory.
+func (mr *MockExpensesRepositoryMockRecorder) RenameCategory(ctx, userId, name, newName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenameCategory", reflect.TypeOf((*MockExpensesRepository)(nil).RenameCategory), ctx, userId, name, newName)
+}
+
+// SaveLimitNotification mocks base method.
+func (m *MockExpensesRepository) SaveLimitNotification(ctx context.Context, limit model.ExpenseLimit, periodStart time.Time, threshold int) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveLimitNotification", ctx, limit, periodStart, threshold)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveLimitNotification indicates an expected call of SaveLimitNotification.
+func (mr *MockExpensesRepositoryMockRecorder) SaveLimitNotification(ctx, limit, periodStart, threshold interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveLimitNotification", reflect.TypeOf((*MockExpensesRepository)(nil).SaveLimitNotification), ctx, limit, periodStart, threshold)
 }
 
 // SetLimit mocks base method.
-func (m *MockExpensesRepository) SetLimit(ctx context.Context, category string, userId, amount int64) error {
+func (m *MockExpensesRepository) SetLimit(ctx context.Context, limit model.ExpenseLimit) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SetLimit", ctx, category, userId, amount)
+	ret := m.ctrl.Call(m, "SetLimit", ctx, limit)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // SetLimit indicates an expected call of SetLimit.
-func (mr *MockExpensesRepositoryMockRecorder) SetLimit(ctx, category, userId, amount interface{}) *gomock.Call {
+func (mr *MockExpensesRepositoryMockRecorder) SetLimit(ctx, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLimit", reflect.TypeOf((*MockExpensesRepository)(nil).SetLimit), ctx, limit)
+}
+
+// SetLimitThresholds mocks base method.
+func (m *MockExpensesRepository) SetLimitThresholds(ctx context.Context, limit model.ExpenseLimit) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetLimitThresholds", ctx, limit)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetLimitThresholds indicates an expected call of SetLimitThresholds.
+func (mr *MockExpensesRepositoryMockRecorder) SetLimitThresholds(ctx, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLimitThresholds", reflect.TypeOf((*MockExpensesRepository)(nil).SetLimitThresholds), ctx, limit)
+}
+
+// Update mocks base method.
+func (m *MockExpensesRepository) Update(ctx context.Context, expense model.Expense, shares []model.ExpenseShare) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, expense, shares)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockExpensesRepositoryMockRecorder) Update(ctx, expense, shares interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLimit", reflect.TypeOf((*MockExpensesRepository)(nil).SetLimit), ctx, category, userId, amount)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockExpensesRepository)(nil).Update), ctx, expense, shares)
 }
@@ -0,0 +1,65 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/import_tokens.go
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockImportTokensRepository is a mock of ImportTokensRepository interface.
+type MockImportTokensRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockImportTokensRepositoryMockRecorder
+}
+
+// MockImportTokensRepositoryMockRecorder is the mock recorder for MockImportTokensRepository.
+type MockImportTokensRepositoryMockRecorder struct {
+	mock *MockImportTokensRepository
+}
+
+// NewMockImportTokensRepository creates a new mock instance.
+func NewMockImportTokensRepository(ctrl *gomock.Controller) *MockImportTokensRepository {
+	mock := &MockImportTokensRepository{ctrl: ctrl}
+	mock.recorder = &MockImportTokensRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockImportTokensRepository) EXPECT() *MockImportTokensRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetTokenUser mocks base method.
+func (m *MockImportTokensRepository) GetTokenUser(ctx context.Context, tokenHash string) (int64, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTokenUser", ctx, tokenHash)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetTokenUser indicates an expected call of GetTokenUser.
+func (mr *MockImportTokensRepositoryMockRecorder) GetTokenUser(ctx, tokenHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTokenUser", reflect.TypeOf((*MockImportTokensRepository)(nil).GetTokenUser), ctx, tokenHash)
+}
+
+// SaveToken mocks base method.
+func (m *MockImportTokensRepository) SaveToken(ctx context.Context, userId int64, tokenHash string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveToken", ctx, userId, tokenHash)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveToken indicates an expected call of SaveToken.
+func (mr *MockImportTokensRepositoryMockRecorder) SaveToken(ctx, userId, tokenHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveToken", reflect.TypeOf((*MockImportTokensRepository)(nil).SaveToken), ctx, userId, tokenHash)
+}
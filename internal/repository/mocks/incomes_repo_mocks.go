@@ -0,0 +1,66 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/incomes.go
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	model "gitlab.ozon.dev/cranky4/tg-bot/internal/model"
+)
+
+// MockIncomesRepository is a mock of IncomesRepository interface.
+type MockIncomesRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIncomesRepositoryMockRecorder
+}
+
+// MockIncomesRepositoryMockRecorder is the mock recorder for MockIncomesRepository.
+type MockIncomesRepositoryMockRecorder struct {
+	mock *MockIncomesRepository
+}
+
+// NewMockIncomesRepository creates a new mock instance.
+func NewMockIncomesRepository(ctrl *gomock.Controller) *MockIncomesRepository {
+	mock := &MockIncomesRepository{ctrl: ctrl}
+	mock.recorder = &MockIncomesRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIncomesRepository) EXPECT() *MockIncomesRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddIncome mocks base method.
+func (m *MockIncomesRepository) AddIncome(ctx context.Context, income model.Income) (model.Income, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddIncome", ctx, income)
+	ret0, _ := ret[0].(model.Income)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddIncome indicates an expected call of AddIncome.
+func (mr *MockIncomesRepositoryMockRecorder) AddIncome(ctx, income interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddIncome", reflect.TypeOf((*MockIncomesRepository)(nil).AddIncome), ctx, income)
+}
+
+// GetIncomes mocks base method.
+func (m *MockIncomesRepository) GetIncomes(ctx context.Context, dateRange model.DateRange, userId int64) ([]model.Income, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIncomes", ctx, dateRange, userId)
+	ret0, _ := ret[0].([]model.Income)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIncomes indicates an expected call of GetIncomes.
+func (mr *MockIncomesRepositoryMockRecorder) GetIncomes(ctx, dateRange, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIncomes", reflect.TypeOf((*MockIncomesRepository)(nil).GetIncomes), ctx, dateRange, userId)
+}
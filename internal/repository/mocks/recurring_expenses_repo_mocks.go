@@ -0,0 +1,112 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/recurring_expenses.go
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	model "gitlab.ozon.dev/cranky4/tg-bot/internal/model"
+)
+
+// MockRecurringExpensesRepository is a mock of RecurringExpensesRepository interface.
+type MockRecurringExpensesRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRecurringExpensesRepositoryMockRecorder
+}
+
+// MockRecurringExpensesRepositoryMockRecorder is the mock recorder for MockRecurringExpensesRepository.
+type MockRecurringExpensesRepositoryMockRecorder struct {
+	mock *MockRecurringExpensesRepository
+}
+
+// NewMockRecurringExpensesRepository creates a new mock instance.
+func NewMockRecurringExpensesRepository(ctrl *gomock.Controller) *MockRecurringExpensesRepository {
+	mock := &MockRecurringExpensesRepository{ctrl: ctrl}
+	mock.recorder = &MockRecurringExpensesRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRecurringExpensesRepository) EXPECT() *MockRecurringExpensesRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddRecurring mocks base method.
+func (m *MockRecurringExpensesRepository) AddRecurring(ctx context.Context, recurring model.RecurringExpense) (model.RecurringExpense, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddRecurring", ctx, recurring)
+	ret0, _ := ret[0].(model.RecurringExpense)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddRecurring indicates an expected call of AddRecurring.
+func (mr *MockRecurringExpensesRepositoryMockRecorder) AddRecurring(ctx, recurring interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddRecurring", reflect.TypeOf((*MockRecurringExpensesRepository)(nil).AddRecurring), ctx, recurring)
+}
+
+// GetDueRecurring mocks base method.
+func (m *MockRecurringExpensesRepository) GetDueRecurring(ctx context.Context, now time.Time, limit int) ([]model.RecurringExpense, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDueRecurring", ctx, now, limit)
+	ret0, _ := ret[0].([]model.RecurringExpense)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDueRecurring indicates an expected call of GetDueRecurring.
+func (mr *MockRecurringExpensesRepositoryMockRecorder) GetDueRecurring(ctx, now, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDueRecurring", reflect.TypeOf((*MockRecurringExpensesRepository)(nil).GetDueRecurring), ctx, now, limit)
+}
+
+// GetRecurring mocks base method.
+func (m *MockRecurringExpensesRepository) GetRecurring(ctx context.Context, userId int64) ([]model.RecurringExpense, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRecurring", ctx, userId)
+	ret0, _ := ret[0].([]model.RecurringExpense)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRecurring indicates an expected call of GetRecurring.
+func (mr *MockRecurringExpensesRepositoryMockRecorder) GetRecurring(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecurring", reflect.TypeOf((*MockRecurringExpensesRepository)(nil).GetRecurring), ctx, userId)
+}
+
+// Reschedule mocks base method.
+func (m *MockRecurringExpensesRepository) Reschedule(ctx context.Context, recurring model.RecurringExpense, nextRunAt time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reschedule", ctx, recurring, nextRunAt)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Reschedule indicates an expected call of Reschedule.
+func (mr *MockRecurringExpensesRepositoryMockRecorder) Reschedule(ctx, recurring, nextRunAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reschedule", reflect.TypeOf((*MockRecurringExpensesRepository)(nil).Reschedule), ctx, recurring, nextRunAt)
+}
+
+// SetPaused mocks base method.
+func (m *MockRecurringExpensesRepository) SetPaused(ctx context.Context, userId int64, id string, paused bool, nextRunAt time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetPaused", ctx, userId, id, paused, nextRunAt)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetPaused indicates an expected call of SetPaused.
+func (mr *MockRecurringExpensesRepositoryMockRecorder) SetPaused(ctx, userId, id, paused, nextRunAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPaused", reflect.TypeOf((*MockRecurringExpensesRepository)(nil).SetPaused), ctx, userId, id, paused, nextRunAt)
+}
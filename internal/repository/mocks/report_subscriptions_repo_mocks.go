@@ -0,0 +1,111 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/report_subscriptions.go
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	model "gitlab.ozon.dev/cranky4/tg-bot/internal/model"
+)
+
+// MockReportSubscriptionsRepository is a mock of ReportSubscriptionsRepository interface.
+type MockReportSubscriptionsRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockReportSubscriptionsRepositoryMockRecorder
+}
+
+// MockReportSubscriptionsRepositoryMockRecorder is the mock recorder for MockReportSubscriptionsRepository.
+type MockReportSubscriptionsRepositoryMockRecorder struct {
+	mock *MockReportSubscriptionsRepository
+}
+
+// NewMockReportSubscriptionsRepository creates a new mock instance.
+func NewMockReportSubscriptionsRepository(ctrl *gomock.Controller) *MockReportSubscriptionsRepository {
+	mock := &MockReportSubscriptionsRepository{ctrl: ctrl}
+	mock.recorder = &MockReportSubscriptionsRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReportSubscriptionsRepository) EXPECT() *MockReportSubscriptionsRepositoryMockRecorder {
+	return m.recorder
+}
+
+// DeleteSubscription mocks base method.
+func (m *MockReportSubscriptionsRepository) DeleteSubscription(ctx context.Context, userId int64, period model.ExpensePeriod) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSubscription", ctx, userId, period)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteSubscription indicates an expected call of DeleteSubscription.
+func (mr *MockReportSubscriptionsRepositoryMockRecorder) DeleteSubscription(ctx, userId, period interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSubscription", reflect.TypeOf((*MockReportSubscriptionsRepository)(nil).DeleteSubscription), ctx, userId, period)
+}
+
+// GetDueSubscriptions mocks base method.
+func (m *MockReportSubscriptionsRepository) GetDueSubscriptions(ctx context.Context, now time.Time, limit int) ([]model.ReportSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDueSubscriptions", ctx, now, limit)
+	ret0, _ := ret[0].([]model.ReportSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDueSubscriptions indicates an expected call of GetDueSubscriptions.
+func (mr *MockReportSubscriptionsRepositoryMockRecorder) GetDueSubscriptions(ctx, now, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDueSubscriptions", reflect.TypeOf((*MockReportSubscriptionsRepository)(nil).GetDueSubscriptions), ctx, now, limit)
+}
+
+// GetSubscriptions mocks base method.
+func (m *MockReportSubscriptionsRepository) GetSubscriptions(ctx context.Context, userId int64) ([]model.ReportSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubscriptions", ctx, userId)
+	ret0, _ := ret[0].([]model.ReportSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSubscriptions indicates an expected call of GetSubscriptions.
+func (mr *MockReportSubscriptionsRepositoryMockRecorder) GetSubscriptions(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubscriptions", reflect.TypeOf((*MockReportSubscriptionsRepository)(nil).GetSubscriptions), ctx, userId)
+}
+
+// Reschedule mocks base method.
+func (m *MockReportSubscriptionsRepository) Reschedule(ctx context.Context, subscription model.ReportSubscription, nextRunAt time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reschedule", ctx, subscription, nextRunAt)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Reschedule indicates an expected call of Reschedule.
+func (mr *MockReportSubscriptionsRepositoryMockRecorder) Reschedule(ctx, subscription, nextRunAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reschedule", reflect.TypeOf((*MockReportSubscriptionsRepository)(nil).Reschedule), ctx, subscription, nextRunAt)
+}
+
+// SaveSubscription mocks base method.
+func (m *MockReportSubscriptionsRepository) SaveSubscription(ctx context.Context, subscription model.ReportSubscription) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveSubscription", ctx, subscription)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveSubscription indicates an expected call of SaveSubscription.
+func (mr *MockReportSubscriptionsRepositoryMockRecorder) SaveSubscription(ctx, subscription interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveSubscription", reflect.TypeOf((*MockReportSubscriptionsRepository)(nil).SaveSubscription), ctx, subscription)
+}
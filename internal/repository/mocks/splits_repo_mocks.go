@@ -0,0 +1,123 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/splits.go
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	model "gitlab.ozon.dev/cranky4/tg-bot/internal/model"
+)
+
+// MockSplitsRepository is a mock of SplitsRepository interface.
+type MockSplitsRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockSplitsRepositoryMockRecorder
+}
+
+// MockSplitsRepositoryMockRecorder is the mock recorder for MockSplitsRepository.
+type MockSplitsRepositoryMockRecorder struct {
+	mock *MockSplitsRepository
+}
+
+// NewMockSplitsRepository creates a new mock instance.
+func NewMockSplitsRepository(ctrl *gomock.Controller) *MockSplitsRepository {
+	mock := &MockSplitsRepository{ctrl: ctrl}
+	mock.recorder = &MockSplitsRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSplitsRepository) EXPECT() *MockSplitsRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddSettlement mocks base method.
+func (m *MockSplitsRepository) AddSettlement(ctx context.Context, settlement model.Settlement) (model.Settlement, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddSettlement", ctx, settlement)
+	ret0, _ := ret[0].(model.Settlement)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddSettlement indicates an expected call of AddSettlement.
+func (mr *MockSplitsRepositoryMockRecorder) AddSettlement(ctx, settlement interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddSettlement", reflect.TypeOf((*MockSplitsRepository)(nil).AddSettlement), ctx, settlement)
+}
+
+// AddShares mocks base method.
+func (m *MockSplitsRepository) AddShares(ctx context.Context, shares []model.ExpenseShare) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddShares", ctx, shares)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddShares indicates an expected call of AddShares.
+func (mr *MockSplitsRepositoryMockRecorder) AddShares(ctx, shares interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddShares", reflect.TypeOf((*MockSplitsRepository)(nil).AddShares), ctx, shares)
+}
+
+// DeleteShares mocks base method.
+func (m *MockSplitsRepository) DeleteShares(ctx context.Context, expenseId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteShares", ctx, expenseId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteShares indicates an expected call of DeleteShares.
+func (mr *MockSplitsRepositoryMockRecorder) DeleteShares(ctx, expenseId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteShares", reflect.TypeOf((*MockSplitsRepository)(nil).DeleteShares), ctx, expenseId)
+}
+
+// GetBalances mocks base method.
+func (m *MockSplitsRepository) GetBalances(ctx context.Context, budgetId int64) (map[int64]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBalances", ctx, budgetId)
+	ret0, _ := ret[0].(map[int64]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBalances indicates an expected call of GetBalances.
+func (mr *MockSplitsRepositoryMockRecorder) GetBalances(ctx, budgetId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBalances", reflect.TypeOf((*MockSplitsRepository)(nil).GetBalances), ctx, budgetId)
+}
+
+// GetShares mocks base method.
+func (m *MockSplitsRepository) GetShares(ctx context.Context, expenseId string) ([]model.ExpenseShare, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetShares", ctx, expenseId)
+	ret0, _ := ret[0].([]model.ExpenseShare)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetShares indicates an expected call of GetShares.
+func (mr *MockSplitsRepositoryMockRecorder) GetShares(ctx, expenseId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetShares", reflect.TypeOf((*MockSplitsRepository)(nil).GetShares), ctx, expenseId)
+}
+
+// ReplaceShares mocks base method.
+func (m *MockSplitsRepository) ReplaceShares(ctx context.Context, expenseId string, shares []model.ExpenseShare) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceShares", ctx, expenseId, shares)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplaceShares indicates an expected call of ReplaceShares.
+func (mr *MockSplitsRepositoryMockRecorder) ReplaceShares(ctx, expenseId, shares interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceShares", reflect.TypeOf((*MockSplitsRepository)(nil).ReplaceShares), ctx, expenseId, shares)
+}
@@ -0,0 +1,66 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/user_settings.go
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	model "gitlab.ozon.dev/cranky4/tg-bot/internal/model"
+)
+
+// MockUserSettingsRepository is a mock of UserSettingsRepository interface.
+type MockUserSettingsRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserSettingsRepositoryMockRecorder
+}
+
+// MockUserSettingsRepositoryMockRecorder is the mock recorder for MockUserSettingsRepository.
+type MockUserSettingsRepositoryMockRecorder struct {
+	mock *MockUserSettingsRepository
+}
+
+// NewMockUserSettingsRepository creates a new mock instance.
+func NewMockUserSettingsRepository(ctrl *gomock.Controller) *MockUserSettingsRepository {
+	mock := &MockUserSettingsRepository{ctrl: ctrl}
+	mock.recorder = &MockUserSettingsRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUserSettingsRepository) EXPECT() *MockUserSettingsRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetSettings mocks base method.
+func (m *MockUserSettingsRepository) GetSettings(ctx context.Context, userId int64) (model.UserSettings, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSettings", ctx, userId)
+	ret0, _ := ret[0].(model.UserSettings)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetSettings indicates an expected call of GetSettings.
+func (mr *MockUserSettingsRepositoryMockRecorder) GetSettings(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSettings", reflect.TypeOf((*MockUserSettingsRepository)(nil).GetSettings), ctx, userId)
+}
+
+// SaveSettings mocks base method.
+func (m *MockUserSettingsRepository) SaveSettings(ctx context.Context, settings model.UserSettings) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveSettings", ctx, settings)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveSettings indicates an expected call of SaveSettings.
+func (mr *MockUserSettingsRepositoryMockRecorder) SaveSettings(ctx, settings interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveSettings", reflect.TypeOf((*MockUserSettingsRepository)(nil).SaveSettings), ctx, settings)
+}
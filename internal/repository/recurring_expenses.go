@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/model"
+)
+
+type RecurringExpensesRepository interface {
+	AddRecurring(ctx context.Context, recurring model.RecurringExpense) (model.RecurringExpense, error)
+	GetRecurring(ctx context.Context, userId int64) ([]model.RecurringExpense, error)
+	// SetPaused приостанавливает или возобновляет трату, при возобновлении следующая трата будет в nextRunAt
+	SetPaused(ctx context.Context, userId int64, id string, paused bool, nextRunAt time.Time) (bool, error)
+	// GetDueRecurring возвращает не больше limit активных трат, время которых наступило к now
+	GetDueRecurring(ctx context.Context, now time.Time, limit int) ([]model.RecurringExpense, error)
+	// Reschedule переносит трату на nextRunAt, если ее еще не перенесли с recurring.NextRunAt.
+	// Вернет false, если трату уже обработала другая реплика
+	Reschedule(ctx context.Context, recurring model.RecurringExpense, nextRunAt time.Time) (bool, error)
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/model"
+)
+
+type ReportSubscriptionsRepository interface {
+	SaveSubscription(ctx context.Context, subscription model.ReportSubscription) error
+	GetSubscriptions(ctx context.Context, userId int64) ([]model.ReportSubscription, error)
+	DeleteSubscription(ctx context.Context, userId int64, period model.ExpensePeriod) (bool, error)
+	// GetDueSubscriptions возвращает не больше limit подписок, время отправки которых наступило к now
+	GetDueSubscriptions(ctx context.Context, now time.Time, limit int) ([]model.ReportSubscription, error)
+	// Reschedule переносит отправку на nextRunAt, если ее еще не перенесли с subscription.NextRunAt.
+	// Вернет false, если подписку уже обработала другая реплика
+	Reschedule(ctx context.Context, subscription model.ReportSubscription, nextRunAt time.Time) (bool, error)
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/model"
+)
+
+type SplitsRepository interface {
+	AddShares(ctx context.Context, shares []model.ExpenseShare) error
+	GetShares(ctx context.Context, expenseId string) ([]model.ExpenseShare, error)
+	DeleteShares(ctx context.Context, expenseId string) error
+	// ReplaceShares заменяет доли траты одной транзакцией, так что при ошибке остаются прежние доли
+	ReplaceShares(ctx context.Context, expenseId string, shares []model.ExpenseShare) error
+	AddSettlement(ctx context.Context, settlement model.Settlement) (model.Settlement, error)
+	// GetBalances возвращает [участник]баланс общего бюджета с учетом возвратов долгов:
+	// положительный - участнику должны, отрицательный - участник должен
+	GetBalances(ctx context.Context, budgetId int64) (map[int64]int64, error)
+}
@@ -0,0 +1,85 @@
+package expenses_sql_repo
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/config"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/model"
+	repo "gitlab.ozon.dev/cranky4/tg-bot/internal/repository"
+)
+
+const (
+	AttachmentUpsertSQL = `INSERT INTO expense_attachments (expense_id, user_id, storage_key, file_name, mime_type, size) 
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (expense_id) DO UPDATE SET storage_key = EXCLUDED.storage_key, file_name = EXCLUDED.file_name,
+			mime_type = EXCLUDED.mime_type, size = EXCLUDED.size, created_at = now()`
+	AttachmentSelectSQL = `SELECT expense_id, user_id, storage_key, file_name, mime_type, size, created_at 
+		FROM expense_attachments WHERE expense_id = $1 AND user_id = $2`
+
+	saveAttachmentErrMsg = "ошибка в методе saveAttachment"
+	getAttachmentErrMsg  = "ошибка в методе getAttachment"
+)
+
+type attachmentsRepository struct {
+	db *sql.DB
+}
+
+func NewAttachmentsRepository(conf config.DatabaseConf) (repo.AttachmentsRepository, error) {
+	db, err := sql.Open("pgx", conf.Dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	return &attachmentsRepository{
+		db: db,
+	}, nil
+}
+
+func (r *attachmentsRepository) SaveAttachment(ctx context.Context, attachment model.Attachment) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "AttachmentsRepository_SaveAttachment")
+	defer span.Finish()
+
+	_, err := r.db.ExecContext(
+		ctx,
+		AttachmentUpsertSQL,
+		attachment.ExpenseID, attachment.UserId, attachment.Key, attachment.FileName, attachment.MimeType, attachment.Size,
+	)
+	if err != nil {
+		return errors.Wrap(err, saveAttachmentErrMsg)
+	}
+
+	return nil
+}
+
+func (r *attachmentsRepository) GetAttachment(ctx context.Context, expenseId string, userId int64) (model.Attachment, bool, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "AttachmentsRepository_GetAttachment")
+	defer span.Finish()
+
+	// ИД траты вводит пользователь, не uuid - такой траты нет
+	if _, err := uuid.Parse(expenseId); err != nil {
+		return model.Attachment{}, false, nil
+	}
+
+	var attachment model.Attachment
+	err := r.db.QueryRowContext(ctx, AttachmentSelectSQL, expenseId, userId).Scan(
+		&attachment.ExpenseID,
+		&attachment.UserId,
+		&attachment.Key,
+		&attachment.FileName,
+		&attachment.MimeType,
+		&attachment.Size,
+		&attachment.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Attachment{}, false, nil
+		}
+		return model.Attachment{}, false, errors.Wrap(err, getAttachmentErrMsg)
+	}
+
+	return attachment, true, nil
+}
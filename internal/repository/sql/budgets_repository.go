@@ -0,0 +1,234 @@
+package expenses_sql_repo
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/config"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/model"
+	repo "gitlab.ozon.dev/cranky4/tg-bot/internal/repository"
+)
+
+const (
+	BudgetInsertSQL       = "INSERT INTO budgets (name, join_code, owner_id) VALUES ($1, $2, $3) RETURNING id"
+	BudgetSelectSQL       = "SELECT id, name, join_code, owner_id FROM budgets WHERE id = $1"
+	BudgetSelectByCodeSQL = "SELECT id, name, join_code, owner_id FROM budgets WHERE upper(join_code) = upper($1)"
+	UserBudgetsSelectSQL  = `SELECT b.id, b.name, b.join_code, b.owner_id FROM budgets b
+		JOIN budget_members m ON m.budget_id = b.id WHERE m.user_id = $1 ORDER BY b.created_at`
+	BudgetMemberUpsertSQL = `INSERT INTO budget_members (budget_id, user_id, name, role) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (budget_id, user_id) DO UPDATE SET name = EXCLUDED.name, role = EXCLUDED.role, updated_at = now()`
+	BudgetMembersSelectSQL = "SELECT budget_id, user_id, name, role FROM budget_members WHERE budget_id = $1 ORDER BY created_at"
+	BudgetMemberDeleteSQL  = "DELETE FROM budget_members WHERE budget_id = $1 AND user_id = $2"
+	ActiveBudgetUpsertSQL  = `INSERT INTO active_budgets (user_id, budget_id) VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE SET budget_id = EXCLUDED.budget_id, updated_at = now()`
+	ActiveBudgetDeleteSQL = "DELETE FROM active_budgets WHERE user_id = $1"
+	// участие в активном бюджете, строки нет, если пользователь вышел из бюджета
+	ActiveMemberSelectSQL = `SELECT m.budget_id, m.user_id, m.name, m.role FROM active_budgets a
+		JOIN budget_members m ON m.budget_id = a.budget_id AND m.user_id = a.user_id WHERE a.user_id = $1`
+
+	addBudgetErrMsg       = "ошибка в методе addBudget"
+	getBudgetErrMsg       = "ошибка в методе getBudget"
+	getUserBudgetsErrMsg  = "ошибка в методе getUserBudgets"
+	saveMemberErrMsg      = "ошибка в методе saveMember"
+	getMembersErrMsg      = "ошибка в методе getMembers"
+	deleteMemberErrMsg    = "ошибка в методе deleteMember"
+	setActiveBudgetErrMsg = "ошибка в методе setActiveBudget"
+	getActiveMemberErrMsg = "ошибка в методе getActiveMember"
+)
+
+type budgetsRepository struct {
+	db *sql.DB
+}
+
+func NewBudgetsRepository(conf config.DatabaseConf) (repo.BudgetsRepository, error) {
+	db, err := sql.Open("pgx", conf.Dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	return &budgetsRepository{
+		db: db,
+	}, nil
+}
+
+func (r *budgetsRepository) AddBudget(ctx context.Context, budget model.Budget, owner model.BudgetMember) (model.Budget, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "BudgetsRepository_AddBudget")
+	defer span.Finish()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return model.Budget{}, errors.Wrap(err, addBudgetErrMsg)
+	}
+
+	err = tx.QueryRowContext(ctx, BudgetInsertSQL, budget.Name, budget.JoinCode, budget.OwnerId).Scan(&budget.ID)
+	if err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			return model.Budget{}, errors.Wrap(rollbackErr, cannotRollbackTransactionErrMsg)
+		}
+		return model.Budget{}, errors.Wrap(err, addBudgetErrMsg)
+	}
+
+	_, err = tx.ExecContext(ctx, BudgetMemberUpsertSQL, budget.ID, owner.UserID, owner.Name, string(owner.Role))
+	if err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			return model.Budget{}, errors.Wrap(rollbackErr, cannotRollbackTransactionErrMsg)
+		}
+		return model.Budget{}, errors.Wrap(err, addBudgetErrMsg)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return model.Budget{}, errors.Wrap(err, addBudgetErrMsg)
+	}
+
+	return budget, nil
+}
+
+func (r *budgetsRepository) GetBudget(ctx context.Context, budgetId int64) (model.Budget, bool, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "BudgetsRepository_GetBudget")
+	defer span.Finish()
+
+	return r.getBudget(ctx, BudgetSelectSQL, budgetId)
+}
+
+func (r *budgetsRepository) GetBudgetByCode(ctx context.Context, joinCode string) (model.Budget, bool, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "BudgetsRepository_GetBudgetByCode")
+	defer span.Finish()
+
+	return r.getBudget(ctx, BudgetSelectByCodeSQL, joinCode)
+}
+
+func (r *budgetsRepository) getBudget(ctx context.Context, query string, arg interface{}) (model.Budget, bool, error) {
+	var budget model.Budget
+	err := r.db.QueryRowContext(ctx, query, arg).Scan(&budget.ID, &budget.Name, &budget.JoinCode, &budget.OwnerId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Budget{}, false, nil
+		}
+		return model.Budget{}, false, errors.Wrap(err, getBudgetErrMsg)
+	}
+
+	return budget, true, nil
+}
+
+func (r *budgetsRepository) GetUserBudgets(ctx context.Context, userId int64) ([]model.Budget, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "BudgetsRepository_GetUserBudgets")
+	defer span.Finish()
+
+	rows, err := r.db.QueryContext(ctx, UserBudgetsSelectSQL, userId)
+	if err != nil {
+		return []model.Budget{}, errors.Wrap(err, getUserBudgetsErrMsg)
+	}
+	defer rows.Close() //nolint:errcheck
+
+	budgets := make([]model.Budget, 0)
+	for rows.Next() {
+		var budget model.Budget
+		if err = rows.Scan(&budget.ID, &budget.Name, &budget.JoinCode, &budget.OwnerId); err != nil {
+			return []model.Budget{}, errors.Wrap(err, getUserBudgetsErrMsg)
+		}
+
+		budgets = append(budgets, budget)
+	}
+
+	if err = rows.Err(); err != nil {
+		return []model.Budget{}, errors.Wrap(err, getUserBudgetsErrMsg)
+	}
+
+	return budgets, nil
+}
+
+func (r *budgetsRepository) SaveMember(ctx context.Context, member model.BudgetMember) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "BudgetsRepository_SaveMember")
+	defer span.Finish()
+
+	_, err := r.db.ExecContext(ctx, BudgetMemberUpsertSQL, member.BudgetID, member.UserID, member.Name, string(member.Role))
+	if err != nil {
+		return errors.Wrap(err, saveMemberErrMsg)
+	}
+
+	return nil
+}
+
+func (r *budgetsRepository) GetMembers(ctx context.Context, budgetId int64) ([]model.BudgetMember, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "BudgetsRepository_GetMembers")
+	defer span.Finish()
+
+	rows, err := r.db.QueryContext(ctx, BudgetMembersSelectSQL, budgetId)
+	if err != nil {
+		return []model.BudgetMember{}, errors.Wrap(err, getMembersErrMsg)
+	}
+	defer rows.Close() //nolint:errcheck
+
+	members := make([]model.BudgetMember, 0)
+	for rows.Next() {
+		var member model.BudgetMember
+		var role string
+		if err = rows.Scan(&member.BudgetID, &member.UserID, &member.Name, &role); err != nil {
+			return []model.BudgetMember{}, errors.Wrap(err, getMembersErrMsg)
+		}
+		member.Role = model.BudgetRole(role)
+
+		members = append(members, member)
+	}
+
+	if err = rows.Err(); err != nil {
+		return []model.BudgetMember{}, errors.Wrap(err, getMembersErrMsg)
+	}
+
+	return members, nil
+}
+
+func (r *budgetsRepository) DeleteMember(ctx context.Context, budgetId, userId int64) (bool, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "BudgetsRepository_DeleteMember")
+	defer span.Finish()
+
+	res, err := r.db.ExecContext(ctx, BudgetMemberDeleteSQL, budgetId, userId)
+	if err != nil {
+		return false, errors.Wrap(err, deleteMemberErrMsg)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, errors.Wrap(err, deleteMemberErrMsg)
+	}
+
+	return affected > 0, nil
+}
+
+func (r *budgetsRepository) SetActiveBudget(ctx context.Context, userId, budgetId int64) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "BudgetsRepository_SetActiveBudget")
+	defer span.Finish()
+
+	var err error
+	// личный бюджет активен по-умолчанию
+	if model.IsSharedBudget(budgetId) {
+		_, err = r.db.ExecContext(ctx, ActiveBudgetUpsertSQL, userId, budgetId)
+	} else {
+		_, err = r.db.ExecContext(ctx, ActiveBudgetDeleteSQL, userId)
+	}
+	if err != nil {
+		return errors.Wrap(err, setActiveBudgetErrMsg)
+	}
+
+	return nil
+}
+
+func (r *budgetsRepository) GetActiveMember(ctx context.Context, userId int64) (model.BudgetMember, bool, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "BudgetsRepository_GetActiveMember")
+	defer span.Finish()
+
+	var member model.BudgetMember
+	var role string
+	err := r.db.QueryRowContext(ctx, ActiveMemberSelectSQL, userId).Scan(&member.BudgetID, &member.UserID, &member.Name, &role)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.BudgetMember{}, false, nil
+		}
+		return model.BudgetMember{}, false, errors.Wrap(err, getActiveMemberErrMsg)
+	}
+	member.Role = model.BudgetRole(role)
+
+	return member, true, nil
+}
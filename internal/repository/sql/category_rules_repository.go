@@ -0,0 +1,94 @@
+package expenses_sql_repo
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/config"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/model"
+	repo "gitlab.ozon.dev/cranky4/tg-bot/internal/repository"
+)
+
+const (
+	CategoryRulesSelectSQL = "SELECT type, pattern, category, user_id FROM category_rules WHERE user_id = $1 ORDER BY created_at"
+	CategoryRuleUpsertSQL  = `INSERT INTO category_rules (user_id, type, pattern, category) 
+		VALUES($1,$2,$3,$4) ON CONFLICT (user_id, lower(pattern)) 
+		DO UPDATE SET type = EXCLUDED.type, pattern = EXCLUDED.pattern, category = EXCLUDED.category, updated_at = now()`
+	CategoryRuleDeleteSQL = "DELETE FROM category_rules WHERE user_id = $1 AND lower(pattern) = lower($2)"
+
+	saveRuleErrMsg   = "ошибка в методе saveRule"
+	getRulesErrMsg   = "ошибка в методе getRules"
+	deleteRuleErrMsg = "ошибка в методе deleteRule"
+)
+
+type categoryRulesRepository struct {
+	db *sql.DB
+}
+
+func NewCategoryRulesRepository(conf config.DatabaseConf) (repo.CategoryRulesRepository, error) {
+	db, err := sql.Open("pgx", conf.Dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	return &categoryRulesRepository{
+		db: db,
+	}, nil
+}
+
+func (r *categoryRulesRepository) SaveRule(ctx context.Context, rule model.CategoryRule) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "CategoryRulesRepository_SaveRule")
+	defer span.Finish()
+
+	_, err := r.db.ExecContext(ctx, CategoryRuleUpsertSQL, rule.UserId, string(rule.Type), rule.Pattern, rule.Category)
+	if err != nil {
+		return errors.Wrap(err, saveRuleErrMsg)
+	}
+
+	return nil
+}
+
+func (r *categoryRulesRepository) GetRules(ctx context.Context, userId int64) ([]model.CategoryRule, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "CategoryRulesRepository_GetRules")
+	defer span.Finish()
+
+	rows, err := r.db.QueryContext(ctx, CategoryRulesSelectSQL, userId)
+	if err != nil {
+		return []model.CategoryRule{}, errors.Wrap(err, getRulesErrMsg)
+	}
+
+	defer rows.Close() //nolint:errcheck
+
+	rules := make([]model.CategoryRule, 0)
+	for rows.Next() {
+		var rule model.CategoryRule
+		var ruleType string
+		if err = rows.Scan(&ruleType, &rule.Pattern, &rule.Category, &rule.UserId); err != nil {
+			return []model.CategoryRule{}, errors.Wrap(err, getRulesErrMsg)
+		}
+		rule.Type = model.CategoryRuleType(ruleType)
+
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
+
+func (r *categoryRulesRepository) DeleteRule(ctx context.Context, userId int64, pattern string) (bool, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "CategoryRulesRepository_DeleteRule")
+	defer span.Finish()
+
+	res, err := r.db.ExecContext(ctx, CategoryRuleDeleteSQL, userId, pattern)
+	if err != nil {
+		return false, errors.Wrap(err, deleteRuleErrMsg)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, errors.Wrap(err, deleteRuleErrMsg)
+	}
+
+	return affected > 0, nil
+}
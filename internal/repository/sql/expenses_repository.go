@@ -3,6 +3,7 @@ package expenses_sql_repo
 import (
 	"context"
 	"database/sql"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -14,35 +15,100 @@ import (
 )
 
 const (
-	ExpenseCategorySearchSQL = "SELECT id, name FROM expense_categories WHERE name ILIKE $1 LIMIT 1"
-	ExpenseCategoryInsertSQL = "INSERT INTO expense_categories(id, name) VALUES ($1, $2)"
-
-	ExpensesInsertSQL = "INSERT INTO expenses(id, amount, datetime, category_id, user_id) VALUES ($1,$2,$3,$4,$5)"
-	ExpensesSelectSQL = "SELECT e.id, e.amount, e.datetime, c.id as categoryId, c.name, e.user_id " +
+	ExpenseCategorySearchSQL = "SELECT id, name FROM expense_categories WHERE user_id = $1 AND name ILIKE $2 LIMIT 1"
+	ExpenseCategoryInsertSQL = "INSERT INTO expense_categories(id, name, user_id) VALUES ($1, $2, $3)"
+	ExpenseCategoriesListSQL = "SELECT id, name FROM expense_categories WHERE user_id = $1 ORDER BY name"
+	ExpenseCategoryRenameSQL = "UPDATE expense_categories SET name = $1 WHERE id = $2 AND user_id = $3"
+	ExpenseCategoryDeleteSQL = "DELETE FROM expense_categories WHERE id = $1 AND user_id = $2"
+	ExpensesCountCategorySQL = "SELECT COUNT(id) FROM expenses WHERE category_id = $1 AND user_id = $2"
+	ExpensesMoveCategorySQL  = "UPDATE expenses SET category_id = $1 WHERE category_id = $2 AND user_id = $3"
+	LimitsMoveCategorySQL    = `UPDATE expenses_limits el SET category_id = $1 WHERE el.category_id = $2 AND el.user_id = $3
+		AND NOT EXISTS (SELECT 1 FROM expenses_limits t WHERE t.category_id = $1 AND t.user_id = $3 AND t.period = el.period)`
+	// правила хранят категорию по названию и переводятся на новое название вместе с категорией
+	CategoryRulesMoveCategorySQL = `UPDATE category_rules SET category = $1, updated_at = now() 
+		WHERE user_id = $2 AND lower(category) = lower($3)`
+	CategoryRulesDeleteCategorySQL = "DELETE FROM category_rules WHERE user_id = $1 AND lower(category) = lower($2)"
+
+	ExpensesInsertSQL = "INSERT INTO expenses(id, amount, datetime, category_id, user_id, account_id, author_id) " +
+		"VALUES ($1,$2,$3,$4,$5,NULLIF($6, '')::uuid,NULLIF($7, 0))"
+	// отметка вставляется первой: при повторном добавлении того же времени трата не сохраняется
+	RecurringOccurrenceInsertSQL = `INSERT INTO recurring_occurrences(recurring_id, occurrence_at, expense_id) 
+		VALUES ($1,$2,$3) ON CONFLICT DO NOTHING`
+	ExpensesUpdateSQL = "UPDATE expenses SET amount = $1, datetime = $2, category_id = $3 WHERE id = $4 AND user_id = $5"
+	ExpensesDeleteSQL = "DELETE FROM expenses WHERE id = $1 AND user_id = $2"
+	ExpensesSelectSQL = "SELECT e.id, e.amount, e.datetime, c.id as categoryId, c.name, e.user_id, " +
+		"COALESCE(a.id::text, ''), COALESCE(a.name, ''), COALESCE(e.author_id, e.user_id) " +
 		"FROM expenses e INNER JOIN expense_categories c ON e.category_id = c.id " +
-		"WHERE e.datetime > $1 AND e.user_id = $2 ORDER BY e.created_at DESC"
+		"LEFT JOIN accounts a ON e.account_id = a.id " +
+		"WHERE e.datetime >= $1 AND e.datetime < $2 AND e.user_id = $3 ORDER BY e.created_at DESC"
 
-	ExpensesSelectCountSQL = "SELECT COUNT(id) FROM expenses WHERE datetime > $1 AND user_id = $2"
-	UpsertLimitSQL         = `INSERT INTO expenses_limits (category_id, amount, user_id) 
-		VALUES($1,$2,$3) ON CONFLICT (category_id, user_id) 
+	ExpensesSelectCountSQL = "SELECT COUNT(id) FROM expenses WHERE datetime >= $1 AND datetime < $2 AND user_id = $3"
+	UpsertLimitSQL         = `INSERT INTO expenses_limits (category_id, amount, user_id, period, scope) 
+		VALUES($1,$2,$3,$4,'category') ON CONFLICT (user_id, category_id, period) WHERE scope = 'category' 
+		DO UPDATE SET amount = EXCLUDED.amount`
+	UpsertTotalLimitSQL = `INSERT INTO expenses_limits (amount, user_id, period, scope) 
+		VALUES($1,$2,$3,'total') ON CONFLICT (user_id, period) WHERE scope = 'total' 
 		DO UPDATE SET amount = EXCLUDED.amount`
-	FreeLimitSQL = `SELECT el.amount - SUM(e.amount) FROM expenses e
-		LEFT JOIN expenses_limits el ON e.category_id = el.category_id AND e.user_id = el.user_id
-		WHERE e.category_id = $1 AND e.datetime >= date_trunc('month', now()) AND e.user_id = $2
-		GROUP BY el.category_id, el.user_id;
-	`
+	LimitsSelectSQL = `SELECT el.scope, el.period, el.amount, el.thresholds, COALESCE(c.id::text, ''), COALESCE(c.name, '') 
+		FROM expenses_limits el LEFT JOIN expense_categories c ON el.category_id = c.id 
+		WHERE el.user_id = $1 AND (el.scope = 'total' OR c.name ILIKE $2)`
+	LimitThresholdsUpdateSQL = `UPDATE expenses_limits SET thresholds = $1 
+		WHERE user_id = $2 AND period = $3 AND scope = $4 
+		AND (scope = 'total' OR category_id IN (SELECT id FROM expense_categories WHERE user_id = $2 AND name ILIKE $5))`
+	LimitNotificationInsertSQL = `INSERT INTO limit_notifications 
+		(user_id, limit_scope, limit_period, category_id, period_start, threshold) 
+		VALUES ($1, $2, $3, NULLIF($4, '')::uuid, $5, $6) ON CONFLICT DO NOTHING`
+	CategorySpentSQL = `SELECT COALESCE(SUM(amount), 0) FROM expenses 
+		WHERE category_id = $1 AND user_id = $2 AND datetime >= $3 AND datetime < $4`
+	TotalSpentSQL = `SELECT COALESCE(SUM(amount), 0) FROM expenses 
+		WHERE user_id = $1 AND datetime >= $2 AND datetime < $3`
+	AccountSearchSQL  = "SELECT id, name, currency, opening_balance FROM accounts WHERE user_id = $1 AND name ILIKE $2 LIMIT 1"
+	AccountInsertSQL  = "INSERT INTO accounts(id, name, currency, opening_balance, user_id) VALUES ($1,$2,$3,$4,$5)"
+	TransferInsertSQL = `INSERT INTO transfers(id, from_account_id, to_account_id, amount, datetime, user_id) 
+		VALUES ($1,$2,$3,$4,$5,$6)`
+	// остаток счета: начальный баланс, входящие и исходящие переводы, траты со счета
+	AccountBalancesSQL = `SELECT a.id, a.name, a.currency, a.opening_balance, a.opening_balance 
+		+ COALESCE((SELECT SUM(t.amount) FROM transfers t WHERE t.to_account_id = a.id), 0) 
+		- COALESCE((SELECT SUM(t.amount) FROM transfers t WHERE t.from_account_id = a.id), 0) 
+		- COALESCE((SELECT SUM(e.amount) FROM expenses e WHERE e.account_id = a.id), 0) 
+		FROM accounts a WHERE a.user_id = $1 ORDER BY a.created_at`
+	// datetime хранится без часового пояса во времени пользователя. Неделя в date_trunc начинается с понедельника,
+	// поэтому для другого начала недели время сдвигается на $5 дней и обратно
+	TrendSelectSQL = `SELECT date_trunc($4::text, e.datetime - make_interval(days => $5::int)) + make_interval(days => $5::int), 
+		CASE WHEN $6::boolean THEN c.name ELSE '' END, SUM(e.amount) 
+		FROM expenses e INNER JOIN expense_categories c ON e.category_id = c.id 
+		WHERE e.datetime >= $1 AND e.datetime < $2 AND e.user_id = $3 
+		GROUP BY 1, 2 ORDER BY 1, 3 DESC, 2`
+	TopCategoriesSQL = "SELECT c.name FROM expenses e INNER JOIN expense_categories c ON e.category_id = c.id " +
+		"WHERE e.user_id = $1 GROUP BY c.name ORDER BY COUNT(e.id) DESC, c.name LIMIT $2"
 
 	addExpenseErrMsg                = "ошибка в методе addExpense"
+	addExpensesBatchErrMsg          = "ошибка в методе addExpensesBatch"
+	addOccurrenceErrMsg             = "ошибка в методе addOccurrence"
+	updateExpenseErrMsg             = "ошибка в методе updateExpense"
+	deleteExpenseErrMsg             = "ошибка в методе deleteExpense"
 	findCategoryErrMsg              = "ошибка в методе findCategory"
 	createNewCategoryErrMsg         = "ошибка в методе createNewCategory"
 	createNewExpenseErrMsg          = "ошибка в методе createNewExpense"
 	getExpensesErrMsg               = "ошибка в методе getExpenses"
+	getTrendErrMsg                  = "ошибка в методе getTrend"
 	expenseSelectErrMsg             = "ошибка в методе findExpenses"
 	expenseSelectCountErrMsg        = "ошибка в методе findCountExpenses"
 	setLimitErrMsg                  = "ошибка в методе setLimit"
 	upsertLimitErrMsg               = "ошибка в методе upsertLimit"
-	limitReachedErrMsg              = "ошибка в методе limitReached"
-	freeLimitErrMsg                 = "ошибка в методе findFreeLimit"
+	getLimitsErrMsg                 = "ошибка в методе getLimits"
+	setLimitThresholdsErrMsg        = "ошибка в методе setLimitThresholds"
+	saveLimitNotificationErrMsg     = "ошибка в методе saveLimitNotification"
+	freeLimitErrMsg                 = "ошибка в методе getFreeLimit"
+	topCategoriesErrMsg             = "ошибка в методе getTopCategories"
+	getCategoriesErrMsg             = "ошибка в методе getCategories"
+	renameCategoryErrMsg            = "ошибка в методе renameCategory"
+	mergeCategoriesErrMsg           = "ошибка в методе mergeCategories"
+	deleteCategoryErrMsg            = "ошибка в методе deleteCategory"
+	addAccountErrMsg                = "ошибка в методе addAccount"
+	findAccountErrMsg               = "ошибка в методе findAccount"
+	addTransferErrMsg               = "ошибка в методе addTransfer"
+	getAccountBalancesErrMsg        = "ошибка в методе getAccountBalances"
 	cannotRollbackTransactionErrMsg = "ошибка отката транзакции"
 )
 
@@ -65,7 +131,18 @@ func (r *repository) Add(ctx context.Context, ex model.Expense) error {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "ExpensesRepository_Add")
 	defer span.Finish()
 
-	category, found, err := r.findCategory(ctx, ex.Category)
+	if ex.Account != "" {
+		account, found, err := r.findAccount(ctx, ex.UserId, ex.Account)
+		if err != nil {
+			return errors.Wrap(err, addExpenseErrMsg)
+		}
+		if !found {
+			return repo.ErrAccountNotFound
+		}
+		ex.AccountID = account.ID
+	}
+
+	category, found, err := r.findCategory(ctx, ex.UserId, ex.Category)
 	if err != nil {
 		return errors.Wrap(err, addExpenseErrMsg)
 	}
@@ -84,7 +161,7 @@ func (r *repository) Add(ctx context.Context, ex model.Expense) error {
 	}()
 
 	if !found {
-		category, err = r.createNewCategory(ctx, tx, ex.Category)
+		category, err = r.createNewCategory(ctx, tx, ex.UserId, ex.Category)
 		if err != nil {
 			return errors.Wrap(err, addExpenseErrMsg)
 		}
@@ -98,11 +175,195 @@ func (r *repository) Add(ctx context.Context, ex model.Expense) error {
 	return err
 }
 
-func (r *repository) GetExpenses(ctx context.Context, p model.ExpensePeriod, userId int64) ([]*model.Expense, error) {
+func (r *repository) AddBatch(ctx context.Context, expenses []model.Expense) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "ExpensesRepository_AddBatch")
+	defer span.Finish()
+
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return errors.Wrap(err, addExpensesBatchErrMsg)
+	}
+
+	defer func() {
+		if err != nil {
+			err = tx.Rollback()
+		} else {
+			err = tx.Commit()
+		}
+	}()
+
+	// категории, созданные в транзакции, еще не видны вне ее
+	categoryIds := make(map[string]string)
+	for _, ex := range expenses {
+		key := strings.ToLower(ex.Category)
+		categoryId, ok := categoryIds[key]
+		if !ok {
+			var category model.ExpenseCategory
+			var found bool
+			category, found, err = r.findCategory(ctx, ex.UserId, ex.Category)
+			if err != nil {
+				return errors.Wrap(err, addExpensesBatchErrMsg)
+			}
+			if !found {
+				category, err = r.createNewCategory(ctx, tx, ex.UserId, ex.Category)
+				if err != nil {
+					return errors.Wrap(err, addExpensesBatchErrMsg)
+				}
+			}
+			categoryId = category.ID
+			categoryIds[key] = categoryId
+		}
+		ex.CategoryID = categoryId
+
+		if err = r.createExpense(ctx, tx, ex); err != nil {
+			return errors.Wrap(err, addExpensesBatchErrMsg)
+		}
+	}
+
+	return err
+}
+
+func (r *repository) AddOccurrence(ctx context.Context, ex model.Expense, recurringId string, occurrenceAt time.Time) (bool, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "ExpensesRepository_AddOccurrence")
+	defer span.Finish()
+
+	category, found, err := r.findCategory(ctx, ex.UserId, ex.Category)
+	if err != nil {
+		return false, errors.Wrap(err, addOccurrenceErrMsg)
+	}
+
+	if ex.ID == "" {
+		ex.ID = uuid.NewString()
+	}
+
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return false, errors.Wrap(err, addOccurrenceErrMsg)
+	}
+
+	defer func() {
+		if err != nil {
+			err = tx.Rollback()
+		} else {
+			err = tx.Commit()
+		}
+	}()
+
+	res, err := tx.ExecContext(ctx, RecurringOccurrenceInsertSQL, recurringId, occurrenceAt, ex.ID)
+	if err != nil {
+		return false, errors.Wrap(err, addOccurrenceErrMsg)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, errors.Wrap(err, addOccurrenceErrMsg)
+	}
+	if affected == 0 {
+		return false, nil
+	}
+
+	if !found {
+		category, err = r.createNewCategory(ctx, tx, ex.UserId, ex.Category)
+		if err != nil {
+			return false, errors.Wrap(err, addOccurrenceErrMsg)
+		}
+	}
+	ex.CategoryID = category.ID
+
+	if err = r.createExpense(ctx, tx, ex); err != nil {
+		return false, errors.Wrap(err, addOccurrenceErrMsg)
+	}
+
+	return true, err
+}
+
+func (r *repository) Update(ctx context.Context, ex model.Expense, shares []model.ExpenseShare) (bool, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "ExpensesRepository_Update")
+	defer span.Finish()
+
+	if _, err := uuid.Parse(ex.ID); err != nil {
+		return false, nil
+	}
+
+	category, found, err := r.findCategory(ctx, ex.UserId, ex.Category)
+	if err != nil {
+		return false, errors.Wrap(err, updateExpenseErrMsg)
+	}
+
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return false, errors.Wrap(err, updateExpenseErrMsg)
+	}
+
+	defer func() {
+		if err != nil {
+			err = tx.Rollback()
+		} else {
+			err = tx.Commit()
+		}
+	}()
+
+	if !found {
+		category, err = r.createNewCategory(ctx, tx, ex.UserId, ex.Category)
+		if err != nil {
+			return false, errors.Wrap(err, updateExpenseErrMsg)
+		}
+	}
+
+	res, err := tx.ExecContext(ctx, ExpensesUpdateSQL, ex.Amount, ex.Datetime, category.ID, ex.ID, ex.UserId)
+	if err != nil {
+		return false, errors.Wrap(err, updateExpenseErrMsg)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, errors.Wrap(err, updateExpenseErrMsg)
+	}
+	if affected == 0 || len(shares) == 0 {
+		return affected > 0, err
+	}
+
+	// доли пересчитаны от новой суммы и заменяются вместе с тратой
+	if _, err = tx.ExecContext(ctx, ExpenseSharesDeleteSQL, ex.ID); err != nil {
+		return false, errors.Wrap(err, updateExpenseErrMsg)
+	}
+
+	for _, share := range shares {
+		_, err = tx.ExecContext(ctx, ExpenseShareInsertSQL, share.ExpenseID, share.BudgetID, share.PayerID, share.UserID, share.Amount)
+		if err != nil {
+			return false, errors.Wrap(err, updateExpenseErrMsg)
+		}
+	}
+
+	return true, err
+}
+
+func (r *repository) Delete(ctx context.Context, id string, userId int64) (bool, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "ExpensesRepository_Delete")
+	defer span.Finish()
+
+	if _, err := uuid.Parse(id); err != nil {
+		return false, nil
+	}
+
+	res, err := r.db.ExecContext(ctx, ExpensesDeleteSQL, id, userId)
+	if err != nil {
+		return false, errors.Wrap(err, deleteExpenseErrMsg)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, errors.Wrap(err, deleteExpenseErrMsg)
+	}
+
+	return affected > 0, nil
+}
+
+func (r *repository) GetExpenses(ctx context.Context, dateRange model.DateRange, userId int64) ([]*model.Expense, error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "ExpensesRepository_GetExpenses")
 	defer span.Finish()
 
-	exps, err := r.findExpenses(ctx, p.GetStart(time.Now()), userId)
+	exps, err := r.findExpenses(ctx, dateRange, userId)
 	if err != nil {
 		return []*model.Expense{}, errors.Wrap(err, getExpensesErrMsg)
 	}
@@ -110,10 +371,62 @@ func (r *repository) GetExpenses(ctx context.Context, p model.ExpensePeriod, use
 	return exps, nil
 }
 
-func (r *repository) SetLimit(ctx context.Context, categoryName string, userId, amount int64) error {
+// GetTrend группирует траты по дням, неделям или месяцам и, если нужно, по категориям
+func (r *repository) GetTrend(ctx context.Context, query model.TrendQuery, userId int64) ([]model.TrendPoint, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "ExpensesRepository_GetTrend")
+	defer span.Finish()
+
+	weekShift := 0
+	if query.Bucket == model.WeekTrendBucket {
+		weekShift = (int(query.WeekStart) - int(time.Monday) + 7) % 7
+	}
+
+	rows, err := r.db.QueryContext(
+		ctx,
+		TrendSelectSQL,
+		query.Range.From,
+		query.Range.To,
+		userId,
+		string(query.Bucket),
+		weekShift,
+		query.ByCategory,
+	)
+	if err != nil {
+		return []model.TrendPoint{}, errors.Wrap(err, getTrendErrMsg)
+	}
+
+	defer rows.Close() //nolint:errcheck
+
+	points := make([]model.TrendPoint, 0)
+	for rows.Next() {
+		var point model.TrendPoint
+		var from time.Time
+		if err = rows.Scan(&from, &point.Category, &point.Amount); err != nil {
+			return []model.TrendPoint{}, errors.Wrap(err, getTrendErrMsg)
+		}
+
+		// начало интервала приходит без часового пояса
+		point.From = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, query.Location)
+		points = append(points, point)
+	}
+
+	return points, nil
+}
+
+// SetLimit создает или изменяет лимит категории или общий лимит на период
+func (r *repository) SetLimit(ctx context.Context, limit model.ExpenseLimit) error {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "ExpensesRepository_SetLimit")
 	defer span.Finish()
 
+	if limit.Scope == model.TotalLimitScope {
+		_, err := r.db.ExecContext(ctx, UpsertTotalLimitSQL, limit.Amount, limit.UserId, model.LimitPeriodName(limit.Period))
+		if err != nil {
+			return errors.Wrap(err, setLimitErrMsg)
+		}
+
+		return nil
+	}
+
 	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{})
 	if err != nil {
 		return errors.Wrap(err, setLimitErrMsg)
@@ -127,43 +440,433 @@ func (r *repository) SetLimit(ctx context.Context, categoryName string, userId,
 		}
 	}()
 
-	category, found, err := r.findCategory(ctx, categoryName)
+	category, found, err := r.findCategory(ctx, limit.UserId, limit.Category)
 	if err != nil {
 		return errors.Wrap(err, setLimitErrMsg)
 	}
 
 	if !found {
-		category, err = r.createNewCategory(ctx, tx, categoryName)
+		category, err = r.createNewCategory(ctx, tx, limit.UserId, limit.Category)
 		if err != nil {
 			return errors.Wrap(err, setLimitErrMsg)
 		}
 	}
 
-	if err = r.upsertLimit(ctx, tx, category.ID, userId, amount); err != nil {
+	if err = r.upsertLimit(ctx, tx, category.ID, limit); err != nil {
 		return errors.Wrap(err, setLimitErrMsg)
 	}
 
 	return err
 }
 
-func (r *repository) GetFreeLimit(ctx context.Context, categoryName string, userId int64) (int64, bool, error) {
+// GetLimits возвращает лимиты категории и общие лимиты пользователя
+func (r *repository) GetLimits(ctx context.Context, categoryName string, userId int64) ([]model.ExpenseLimit, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "ExpensesRepository_GetLimits")
+	defer span.Finish()
+
+	rows, err := r.db.QueryContext(ctx, LimitsSelectSQL, userId, categoryName)
+	if err != nil {
+		return []model.ExpenseLimit{}, errors.Wrap(err, getLimitsErrMsg)
+	}
+
+	defer rows.Close() //nolint:errcheck
+
+	limits := make([]model.ExpenseLimit, 0)
+	for rows.Next() {
+		var scope, period, thresholds string
+		limit := model.ExpenseLimit{UserId: userId}
+		if err = rows.Scan(&scope, &period, &limit.Amount, &thresholds, &limit.CategoryID, &limit.Category); err != nil {
+			return []model.ExpenseLimit{}, errors.Wrap(err, getLimitsErrMsg)
+		}
+		limit.Scope = model.LimitScope(scope)
+		limit.Period, _ = model.ParseLimitPeriod(period)
+		limit.Thresholds, _ = model.ParseThresholds(thresholds)
+
+		limits = append(limits, limit)
+	}
+
+	return limits, nil
+}
+
+// GetFreeLimit возвращает остаток лимита в диапазоне дат
+func (r *repository) GetFreeLimit(ctx context.Context, limit model.ExpenseLimit, dateRange model.DateRange) (int64, error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "ExpensesRepository_GetFreeLimit")
 	defer span.Finish()
 
-	category, found, err := r.findCategory(ctx, categoryName)
+	spent, err := r.findSpent(ctx, limit, dateRange)
+	if err != nil {
+		return 0, errors.Wrap(err, freeLimitErrMsg)
+	}
+
+	return limit.Amount - spent, nil
+}
+
+// SetLimitThresholds изменяет пороги уведомлений существующего лимита
+func (r *repository) SetLimitThresholds(ctx context.Context, limit model.ExpenseLimit) (bool, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "ExpensesRepository_SetLimitThresholds")
+	defer span.Finish()
+
+	res, err := r.db.ExecContext(
+		ctx,
+		LimitThresholdsUpdateSQL,
+		model.FormatThresholds(limit.Thresholds),
+		limit.UserId,
+		model.LimitPeriodName(limit.Period),
+		string(limit.Scope),
+		limit.Category,
+	)
 	if err != nil {
-		return 0, false, errors.Wrap(err, limitReachedErrMsg)
+		return false, errors.Wrap(err, setLimitThresholdsErrMsg)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, errors.Wrap(err, setLimitThresholdsErrMsg)
+	}
+
+	return affected > 0, nil
+}
+
+// SaveLimitNotification запоминает уведомление о пороге лимита в периоде,
+// возвращает false, если такое уведомление уже было
+func (r *repository) SaveLimitNotification(ctx context.Context, limit model.ExpenseLimit, periodStart time.Time, threshold int) (bool, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "ExpensesRepository_SaveLimitNotification")
+	defer span.Finish()
+
+	res, err := r.db.ExecContext(
+		ctx,
+		LimitNotificationInsertSQL,
+		limit.UserId,
+		string(limit.Scope),
+		model.LimitPeriodName(limit.Period),
+		limit.CategoryID,
+		periodStart,
+		threshold,
+	)
+	if err != nil {
+		return false, errors.Wrap(err, saveLimitNotificationErrMsg)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, errors.Wrap(err, saveLimitNotificationErrMsg)
+	}
+
+	return affected > 0, nil
+}
+
+func (r *repository) GetTopCategories(ctx context.Context, userId int64, limit int) ([]string, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "ExpensesRepository_GetTopCategories")
+	defer span.Finish()
+
+	rows, err := r.db.QueryContext(ctx, TopCategoriesSQL, userId, limit)
+	if err != nil {
+		return []string{}, errors.Wrap(err, topCategoriesErrMsg)
+	}
+
+	defer rows.Close() //nolint:errcheck
+
+	categories := make([]string, 0, limit)
+	for rows.Next() {
+		var name string
+		if err = rows.Scan(&name); err != nil {
+			return []string{}, errors.Wrap(err, topCategoriesErrMsg)
+		}
+
+		categories = append(categories, name)
+	}
+
+	return categories, nil
+}
+
+func (r *repository) GetCategories(ctx context.Context, userId int64) ([]model.ExpenseCategory, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "ExpensesRepository_GetCategories")
+	defer span.Finish()
+
+	rows, err := r.db.QueryContext(ctx, ExpenseCategoriesListSQL, userId)
+	if err != nil {
+		return []model.ExpenseCategory{}, errors.Wrap(err, getCategoriesErrMsg)
+	}
+
+	defer rows.Close() //nolint:errcheck
+
+	categories := make([]model.ExpenseCategory, 0)
+	for rows.Next() {
+		var id, name string
+		if err = rows.Scan(&id, &name); err != nil {
+			return []model.ExpenseCategory{}, errors.Wrap(err, getCategoriesErrMsg)
+		}
+
+		categories = append(categories, model.ExpenseCategory{ID: id, Name: name, UserId: userId})
+	}
+
+	return categories, nil
+}
+
+func (r *repository) RenameCategory(ctx context.Context, userId int64, name, newName string) (bool, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "ExpensesRepository_RenameCategory")
+	defer span.Finish()
+
+	category, found, err := r.findCategory(ctx, userId, name)
+	if err != nil {
+		return false, errors.Wrap(err, renameCategoryErrMsg)
+	}
+
+	if !found {
+		return false, nil
+	}
+
+	existing, found, err := r.findCategory(ctx, userId, newName)
+	if err != nil {
+		return false, errors.Wrap(err, renameCategoryErrMsg)
+	}
+
+	// допускается смена регистра в названии той же категории
+	if found && existing.ID != category.ID {
+		return false, repo.ErrCategoryExists
+	}
+
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return false, errors.Wrap(err, renameCategoryErrMsg)
+	}
+
+	defer func() {
+		if err != nil {
+			err = tx.Rollback()
+		} else {
+			err = tx.Commit()
+		}
+	}()
+
+	if _, err = tx.ExecContext(ctx, ExpenseCategoryRenameSQL, newName, category.ID, userId); err != nil {
+		return false, errors.Wrap(err, renameCategoryErrMsg)
+	}
+
+	if _, err = tx.ExecContext(ctx, CategoryRulesMoveCategorySQL, newName, userId, category.Name); err != nil {
+		return false, errors.Wrap(err, renameCategoryErrMsg)
+	}
+
+	return true, err
+}
+
+func (r *repository) MergeCategories(ctx context.Context, userId int64, from, to string) (bool, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "ExpensesRepository_MergeCategories")
+	defer span.Finish()
+
+	source, found, err := r.findCategory(ctx, userId, from)
+	if err != nil {
+		return false, errors.Wrap(err, mergeCategoriesErrMsg)
 	}
 
 	if !found {
-		return 0, false, nil
+		return false, nil
+	}
+
+	target, found, err := r.findCategory(ctx, userId, to)
+	if err != nil {
+		return false, errors.Wrap(err, mergeCategoriesErrMsg)
+	}
+
+	if !found {
+		return false, nil
+	}
+
+	if source.ID == target.ID {
+		return true, nil
+	}
+
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return false, errors.Wrap(err, mergeCategoriesErrMsg)
+	}
+
+	defer func() {
+		if err != nil {
+			err = tx.Rollback()
+		} else {
+			err = tx.Commit()
+		}
+	}()
+
+	if _, err = tx.ExecContext(ctx, ExpensesMoveCategorySQL, target.ID, source.ID, userId); err != nil {
+		return false, errors.Wrap(err, mergeCategoriesErrMsg)
 	}
 
-	return r.findFreeLimit(ctx, category.ID, userId)
+	// лимит переносится, только если у целевой категории своего лимита нет
+	if _, err = tx.ExecContext(ctx, LimitsMoveCategorySQL, target.ID, source.ID, userId); err != nil {
+		return false, errors.Wrap(err, mergeCategoriesErrMsg)
+	}
+
+	if _, err = tx.ExecContext(ctx, CategoryRulesMoveCategorySQL, target.Name, userId, source.Name); err != nil {
+		return false, errors.Wrap(err, mergeCategoriesErrMsg)
+	}
+
+	if _, err = tx.ExecContext(ctx, ExpenseCategoryDeleteSQL, source.ID, userId); err != nil {
+		return false, errors.Wrap(err, mergeCategoriesErrMsg)
+	}
+
+	return true, err
 }
 
-func (r *repository) findCategory(ctx context.Context, categoryName string) (model.ExpenseCategory, bool, error) {
-	row := r.db.QueryRowContext(ctx, ExpenseCategorySearchSQL, categoryName)
+func (r *repository) DeleteCategory(ctx context.Context, userId int64, name string) (bool, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "ExpensesRepository_DeleteCategory")
+	defer span.Finish()
+
+	category, found, err := r.findCategory(ctx, userId, name)
+	if err != nil {
+		return false, errors.Wrap(err, deleteCategoryErrMsg)
+	}
+
+	if !found {
+		return false, nil
+	}
+
+	var count int
+	if err = r.db.QueryRowContext(ctx, ExpensesCountCategorySQL, category.ID, userId).Scan(&count); err != nil {
+		return false, errors.Wrap(err, deleteCategoryErrMsg)
+	}
+
+	if count > 0 {
+		return false, repo.ErrCategoryHasExpenses
+	}
+
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return false, errors.Wrap(err, deleteCategoryErrMsg)
+	}
+
+	defer func() {
+		if err != nil {
+			err = tx.Rollback()
+		} else {
+			err = tx.Commit()
+		}
+	}()
+
+	// лимит категории удаляется каскадно, правила хранят название и удаляются явно
+	if _, err = tx.ExecContext(ctx, ExpenseCategoryDeleteSQL, category.ID, userId); err != nil {
+		return false, errors.Wrap(err, deleteCategoryErrMsg)
+	}
+
+	if _, err = tx.ExecContext(ctx, CategoryRulesDeleteCategorySQL, userId, category.Name); err != nil {
+		return false, errors.Wrap(err, deleteCategoryErrMsg)
+	}
+
+	return true, err
+}
+
+func (r *repository) AddAccount(ctx context.Context, account model.Account) (model.Account, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "ExpensesRepository_AddAccount")
+	defer span.Finish()
+
+	_, found, err := r.findAccount(ctx, account.UserId, account.Name)
+	if err != nil {
+		return model.Account{}, errors.Wrap(err, addAccountErrMsg)
+	}
+	if found {
+		return model.Account{}, repo.ErrAccountExists
+	}
+
+	id, err := uuid.NewUUID()
+	if err != nil {
+		return model.Account{}, errors.Wrap(err, addAccountErrMsg)
+	}
+	account.ID = id.String()
+
+	_, err = r.db.ExecContext(ctx, AccountInsertSQL, account.ID, account.Name, account.Currency, account.OpeningBalance, account.UserId)
+	if err != nil {
+		return model.Account{}, errors.Wrap(err, addAccountErrMsg)
+	}
+
+	return account, nil
+}
+
+func (r *repository) AddTransfer(ctx context.Context, transfer model.Transfer) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "ExpensesRepository_AddTransfer")
+	defer span.Finish()
+
+	from, fromFound, err := r.findAccount(ctx, transfer.UserId, transfer.FromAccount)
+	if err != nil {
+		return errors.Wrap(err, addTransferErrMsg)
+	}
+
+	to, toFound, err := r.findAccount(ctx, transfer.UserId, transfer.ToAccount)
+	if err != nil {
+		return errors.Wrap(err, addTransferErrMsg)
+	}
+
+	if !fromFound || !toFound {
+		return repo.ErrAccountNotFound
+	}
+
+	id, err := uuid.NewUUID()
+	if err != nil {
+		return errors.Wrap(err, addTransferErrMsg)
+	}
+
+	_, err = r.db.ExecContext(ctx, TransferInsertSQL, id.String(), from.ID, to.ID, transfer.Amount, transfer.Datetime, transfer.UserId)
+	if err != nil {
+		return errors.Wrap(err, addTransferErrMsg)
+	}
+
+	return nil
+}
+
+func (r *repository) GetAccountBalances(ctx context.Context, userId int64) ([]model.AccountBalance, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "ExpensesRepository_GetAccountBalances")
+	defer span.Finish()
+
+	rows, err := r.db.QueryContext(ctx, AccountBalancesSQL, userId)
+	if err != nil {
+		return []model.AccountBalance{}, errors.Wrap(err, getAccountBalancesErrMsg)
+	}
+	defer rows.Close() //nolint:errcheck
+
+	balances := make([]model.AccountBalance, 0)
+	for rows.Next() {
+		balance := model.AccountBalance{Account: model.Account{UserId: userId}}
+		err = rows.Scan(
+			&balance.Account.ID,
+			&balance.Account.Name,
+			&balance.Account.Currency,
+			&balance.Account.OpeningBalance,
+			&balance.Balance,
+		)
+		if err != nil {
+			return []model.AccountBalance{}, errors.Wrap(err, getAccountBalancesErrMsg)
+		}
+
+		balances = append(balances, balance)
+	}
+
+	if err = rows.Err(); err != nil {
+		return []model.AccountBalance{}, errors.Wrap(err, getAccountBalancesErrMsg)
+	}
+
+	return balances, nil
+}
+
+// findAccount ищет счет пользователя по названию без учета регистра
+func (r *repository) findAccount(ctx context.Context, userId int64, name string) (model.Account, bool, error) {
+	account := model.Account{UserId: userId}
+	err := r.db.QueryRowContext(ctx, AccountSearchSQL, userId, strings.Trim(name, " ")).Scan(
+		&account.ID,
+		&account.Name,
+		&account.Currency,
+		&account.OpeningBalance,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.Account{}, false, nil
+	} else if err != nil {
+		return model.Account{}, false, errors.Wrap(err, findAccountErrMsg)
+	}
+
+	return account, true, nil
+}
+
+func (r *repository) findCategory(ctx context.Context, userId int64, categoryName string) (model.ExpenseCategory, bool, error) {
+	row := r.db.QueryRowContext(ctx, ExpenseCategorySearchSQL, userId, categoryName)
 
 	if errors.Is(row.Err(), sql.ErrNoRows) {
 		return model.ExpenseCategory{}, false, nil
@@ -177,42 +880,49 @@ func (r *repository) findCategory(ctx context.Context, categoryName string) (mod
 	}
 
 	return model.ExpenseCategory{
-		ID:   id,
-		Name: name,
+		ID:     id,
+		Name:   name,
+		UserId: userId,
 	}, true, nil
 }
 
-func (r *repository) createNewCategory(ctx context.Context, tx *sql.Tx, categoryName string) (model.ExpenseCategory, error) {
+func (r *repository) createNewCategory(ctx context.Context, tx *sql.Tx, userId int64, categoryName string) (model.ExpenseCategory, error) {
 	id, err := uuid.NewUUID()
 	if err != nil {
 		return model.ExpenseCategory{}, errors.Wrap(err, createNewCategoryErrMsg)
 	}
 
-	if _, err = tx.ExecContext(ctx, ExpenseCategoryInsertSQL, id.String(), categoryName); err != nil {
+	if _, err = tx.ExecContext(ctx, ExpenseCategoryInsertSQL, id.String(), categoryName, userId); err != nil {
 		return model.ExpenseCategory{}, errors.Wrap(err, createNewCategoryErrMsg)
 	}
 
 	return model.ExpenseCategory{
-		ID:   id.String(),
-		Name: categoryName,
+		ID:     id.String(),
+		Name:   categoryName,
+		UserId: userId,
 	}, nil
 }
 
 func (r *repository) createExpense(ctx context.Context, tx *sql.Tx, ex model.Expense) error {
-	id, err := uuid.NewUUID()
-	if err != nil {
-		return errors.Wrap(err, createNewExpenseErrMsg)
+	// ИД задан заранее, если с тратой сохраняются связанные записи
+	if ex.ID == "" {
+		id, err := uuid.NewUUID()
+		if err != nil {
+			return errors.Wrap(err, createNewExpenseErrMsg)
+		}
+		ex.ID = id.String()
 	}
 
-	if _, err = tx.ExecContext(ctx, ExpensesInsertSQL, id.String(), ex.Amount, ex.Datetime, ex.CategoryID, ex.UserId); err != nil {
+	_, err := tx.ExecContext(ctx, ExpensesInsertSQL, ex.ID, ex.Amount, ex.Datetime, ex.CategoryID, ex.UserId, ex.AccountID, ex.AuthorId)
+	if err != nil {
 		return errors.Wrap(err, createNewExpenseErrMsg)
 	}
 
 	return nil
 }
 
-func (r *repository) findCountExpenses(ctx context.Context, from time.Time, userId int64) (int, error) {
-	row := r.db.QueryRowContext(ctx, ExpensesSelectCountSQL, from, userId)
+func (r *repository) findCountExpenses(ctx context.Context, dateRange model.DateRange, userId int64) (int, error) {
+	row := r.db.QueryRowContext(ctx, ExpensesSelectCountSQL, dateRange.From, dateRange.To, userId)
 	if errors.Is(row.Err(), sql.ErrNoRows) {
 		return 0, nil
 	} else if row.Err() != nil {
@@ -227,13 +937,13 @@ func (r *repository) findCountExpenses(ctx context.Context, from time.Time, user
 	return count, nil
 }
 
-func (r *repository) findExpenses(ctx context.Context, from time.Time, userId int64) ([]*model.Expense, error) {
-	count, err := r.findCountExpenses(ctx, from, userId)
+func (r *repository) findExpenses(ctx context.Context, dateRange model.DateRange, userId int64) ([]*model.Expense, error) {
+	count, err := r.findCountExpenses(ctx, dateRange, userId)
 	if err != nil {
 		return []*model.Expense{}, err
 	}
 
-	rows, err := r.db.QueryContext(ctx, ExpensesSelectSQL, from, userId)
+	rows, err := r.db.QueryContext(ctx, ExpensesSelectSQL, dateRange.From, dateRange.To, userId)
 	if err != nil {
 		return []*model.Expense{}, errors.Wrap(err, expenseSelectErrMsg)
 	}
@@ -243,11 +953,12 @@ func (r *repository) findExpenses(ctx context.Context, from time.Time, userId in
 	exps := make([]*model.Expense, 0, count)
 
 	for rows.Next() {
-		var id, categoryID, categoryName string
-		var userId, amount int64
+		var id, categoryID, categoryName, accountID, accountName string
+		var userId, authorId, amount int64
 		var datetime time.Time
 
-		if err = rows.Scan(&id, &amount, &datetime, &categoryID, &categoryName, &userId); err != nil {
+		err = rows.Scan(&id, &amount, &datetime, &categoryID, &categoryName, &userId, &accountID, &accountName, &authorId)
+		if err != nil {
 			return []*model.Expense{}, errors.Wrap(err, expenseSelectErrMsg)
 		}
 
@@ -257,39 +968,38 @@ func (r *repository) findExpenses(ctx context.Context, from time.Time, userId in
 			Datetime:   datetime,
 			CategoryID: categoryID,
 			Category:   categoryName,
+			AccountID:  accountID,
+			Account:    accountName,
 			UserId:     userId,
+			AuthorId:   authorId,
 		})
 	}
 
 	return exps, nil
 }
 
-func (r *repository) upsertLimit(ctx context.Context, tx *sql.Tx, categoryID string, userId, amount int64) error {
-	if _, err := tx.ExecContext(ctx, UpsertLimitSQL, categoryID, amount, userId); err != nil {
+func (r *repository) upsertLimit(ctx context.Context, tx *sql.Tx, categoryID string, limit model.ExpenseLimit) error {
+	_, err := tx.ExecContext(ctx, UpsertLimitSQL, categoryID, limit.Amount, limit.UserId, model.LimitPeriodName(limit.Period))
+	if err != nil {
 		return errors.Wrap(err, upsertLimitErrMsg)
 	}
 
 	return nil
 }
 
-func (r *repository) findFreeLimit(ctx context.Context, categoryID string, userId int64) (int64, bool, error) {
-	row := r.db.QueryRowContext(ctx, FreeLimitSQL, categoryID, userId)
-
-	if errors.Is(row.Err(), sql.ErrNoRows) {
-		return 0, false, nil
-	}
-
-	var freeLimit sql.NullInt64
-	if err := row.Scan(&freeLimit); err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return 0, false, nil
-		}
-		return 0, true, errors.Wrap(err, freeLimitErrMsg)
+// findSpent возвращает сумму трат, на которые действует лимит
+func (r *repository) findSpent(ctx context.Context, limit model.ExpenseLimit, dateRange model.DateRange) (int64, error) {
+	var row *sql.Row
+	if limit.Scope == model.TotalLimitScope {
+		row = r.db.QueryRowContext(ctx, TotalSpentSQL, limit.UserId, dateRange.From, dateRange.To)
+	} else {
+		row = r.db.QueryRowContext(ctx, CategorySpentSQL, limit.CategoryID, limit.UserId, dateRange.From, dateRange.To)
 	}
 
-	if !freeLimit.Valid {
-		return 0, false, nil
+	var spent int64
+	if err := row.Scan(&spent); err != nil {
+		return 0, err
 	}
 
-	return freeLimit.Int64, true, nil
+	return spent, nil
 }
@@ -0,0 +1,61 @@
+package expenses_sql_repo
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/config"
+	repo "gitlab.ozon.dev/cranky4/tg-bot/internal/repository"
+)
+
+const (
+	ImportTokenUpsertSQL = `INSERT INTO import_tokens (user_id, token_hash) VALUES ($1, $2) 
+		ON CONFLICT (user_id) DO UPDATE SET token_hash = EXCLUDED.token_hash, created_at = now()`
+	ImportTokenUserSelectSQL = "SELECT user_id FROM import_tokens WHERE token_hash = $1"
+
+	saveImportTokenErrMsg    = "ошибка в методе saveImportToken"
+	getImportTokenUserErrMsg = "ошибка в методе getImportTokenUser"
+)
+
+type importTokensRepository struct {
+	db *sql.DB
+}
+
+func NewImportTokensRepository(conf config.DatabaseConf) (repo.ImportTokensRepository, error) {
+	db, err := sql.Open("pgx", conf.Dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	return &importTokensRepository{
+		db: db,
+	}, nil
+}
+
+func (r *importTokensRepository) SaveToken(ctx context.Context, userId int64, tokenHash string) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "ImportTokensRepository_SaveToken")
+	defer span.Finish()
+
+	if _, err := r.db.ExecContext(ctx, ImportTokenUpsertSQL, userId, tokenHash); err != nil {
+		return errors.Wrap(err, saveImportTokenErrMsg)
+	}
+
+	return nil
+}
+
+func (r *importTokensRepository) GetTokenUser(ctx context.Context, tokenHash string) (int64, bool, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "ImportTokensRepository_GetTokenUser")
+	defer span.Finish()
+
+	var userId int64
+	if err := r.db.QueryRowContext(ctx, ImportTokenUserSelectSQL, tokenHash).Scan(&userId); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, false, nil
+		}
+		return 0, false, errors.Wrap(err, getImportTokenUserErrMsg)
+	}
+
+	return userId, true, nil
+}
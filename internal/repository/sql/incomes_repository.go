@@ -0,0 +1,82 @@
+package expenses_sql_repo
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/config"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/model"
+	repo "gitlab.ozon.dev/cranky4/tg-bot/internal/repository"
+)
+
+const (
+	IncomeInsertSQL  = "INSERT INTO incomes(id, user_id, amount, source, datetime) VALUES ($1,$2,$3,$4,$5)"
+	IncomesSelectSQL = `SELECT id, user_id, amount, source, datetime FROM incomes 
+		WHERE datetime >= $1 AND datetime < $2 AND user_id = $3 ORDER BY datetime`
+
+	addIncomeErrMsg  = "ошибка в методе addIncome"
+	getIncomesErrMsg = "ошибка в методе getIncomes"
+)
+
+type incomesRepository struct {
+	db *sql.DB
+}
+
+func NewIncomesRepository(conf config.DatabaseConf) (repo.IncomesRepository, error) {
+	db, err := sql.Open("pgx", conf.Dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	return &incomesRepository{
+		db: db,
+	}, nil
+}
+
+func (r *incomesRepository) AddIncome(ctx context.Context, income model.Income) (model.Income, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "IncomesRepository_AddIncome")
+	defer span.Finish()
+
+	id, err := uuid.NewUUID()
+	if err != nil {
+		return model.Income{}, errors.Wrap(err, addIncomeErrMsg)
+	}
+	income.ID = id.String()
+
+	_, err = r.db.ExecContext(ctx, IncomeInsertSQL, income.ID, income.UserId, income.Amount, income.Source, income.Datetime)
+	if err != nil {
+		return model.Income{}, errors.Wrap(err, addIncomeErrMsg)
+	}
+
+	return income, nil
+}
+
+func (r *incomesRepository) GetIncomes(ctx context.Context, dateRange model.DateRange, userId int64) ([]model.Income, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "IncomesRepository_GetIncomes")
+	defer span.Finish()
+
+	rows, err := r.db.QueryContext(ctx, IncomesSelectSQL, dateRange.From, dateRange.To, userId)
+	if err != nil {
+		return []model.Income{}, errors.Wrap(err, getIncomesErrMsg)
+	}
+	defer rows.Close() //nolint:errcheck
+
+	incomes := make([]model.Income, 0)
+	for rows.Next() {
+		var income model.Income
+		if err = rows.Scan(&income.ID, &income.UserId, &income.Amount, &income.Source, &income.Datetime); err != nil {
+			return []model.Income{}, errors.Wrap(err, getIncomesErrMsg)
+		}
+
+		incomes = append(incomes, income)
+	}
+
+	if err = rows.Err(); err != nil {
+		return []model.Income{}, errors.Wrap(err, getIncomesErrMsg)
+	}
+
+	return incomes, nil
+}
@@ -0,0 +1,205 @@
+package expenses_sql_repo
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/config"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/model"
+	repo "gitlab.ozon.dev/cranky4/tg-bot/internal/repository"
+)
+
+const (
+	RecurringExpenseInsertSQL = `INSERT INTO recurring_expenses
+		(id, user_id, amount, currency, category, period, weekday, day, hour, minute, next_run_at)
+		VALUES($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11)`
+	RecurringExpensesSelectSQL = `SELECT id, user_id, amount, currency, category, period, weekday, day, hour, minute, next_run_at, paused
+		FROM recurring_expenses WHERE user_id = $1 ORDER BY created_at`
+	RecurringExpensePauseSQL  = "UPDATE recurring_expenses SET paused = true, updated_at = now() WHERE id = $1 AND user_id = $2"
+	RecurringExpenseResumeSQL = `UPDATE recurring_expenses SET paused = false, next_run_at = $3, updated_at = now()
+		WHERE id = $1 AND user_id = $2`
+	DueRecurringExpensesSelectSQL = `SELECT id, user_id, amount, currency, category, period, weekday, day, hour, minute, next_run_at, paused
+		FROM recurring_expenses WHERE NOT paused AND next_run_at <= $1 ORDER BY next_run_at LIMIT $2`
+	// переносит трату, только если ее не перенесла другая реплика
+	RecurringExpenseRescheduleSQL = `UPDATE recurring_expenses SET next_run_at = $3, updated_at = now()
+		WHERE id = $1 AND NOT paused AND next_run_at = $2`
+
+	addRecurringErrMsg        = "ошибка в методе addRecurring"
+	getRecurringErrMsg        = "ошибка в методе getRecurring"
+	setPausedErrMsg           = "ошибка в методе setPaused"
+	getDueRecurringErrMsg     = "ошибка в методе getDueRecurring"
+	rescheduleRecurringErrMsg = "ошибка в методе rescheduleRecurring"
+)
+
+type recurringExpensesRepository struct {
+	db *sql.DB
+}
+
+func NewRecurringExpensesRepository(conf config.DatabaseConf) (repo.RecurringExpensesRepository, error) {
+	db, err := sql.Open("pgx", conf.Dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	return &recurringExpensesRepository{
+		db: db,
+	}, nil
+}
+
+func (r *recurringExpensesRepository) AddRecurring(ctx context.Context, recurring model.RecurringExpense) (model.RecurringExpense, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "RecurringExpensesRepository_AddRecurring")
+	defer span.Finish()
+
+	id, err := uuid.NewUUID()
+	if err != nil {
+		return model.RecurringExpense{}, errors.Wrap(err, addRecurringErrMsg)
+	}
+	recurring.ID = id.String()
+
+	_, err = r.db.ExecContext(
+		ctx,
+		RecurringExpenseInsertSQL,
+		recurring.ID,
+		recurring.UserId,
+		recurring.Amount,
+		recurring.Currency,
+		recurring.Category,
+		model.SchedulePeriodName(recurring.Schedule.Period),
+		int(recurring.Schedule.Weekday),
+		recurring.Schedule.Day,
+		recurring.Schedule.Hour,
+		recurring.Schedule.Minute,
+		recurring.NextRunAt,
+	)
+	if err != nil {
+		return model.RecurringExpense{}, errors.Wrap(err, addRecurringErrMsg)
+	}
+
+	return recurring, nil
+}
+
+func (r *recurringExpensesRepository) GetRecurring(ctx context.Context, userId int64) ([]model.RecurringExpense, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "RecurringExpensesRepository_GetRecurring")
+	defer span.Finish()
+
+	rows, err := r.db.QueryContext(ctx, RecurringExpensesSelectSQL, userId)
+	if err != nil {
+		return []model.RecurringExpense{}, errors.Wrap(err, getRecurringErrMsg)
+	}
+
+	recurring, err := scanRecurringExpenses(rows)
+	if err != nil {
+		return []model.RecurringExpense{}, errors.Wrap(err, getRecurringErrMsg)
+	}
+
+	return recurring, nil
+}
+
+func (r *recurringExpensesRepository) SetPaused(
+	ctx context.Context,
+	userId int64,
+	id string,
+	paused bool,
+	nextRunAt time.Time,
+) (bool, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "RecurringExpensesRepository_SetPaused")
+	defer span.Finish()
+
+	if _, err := uuid.Parse(id); err != nil {
+		return false, nil
+	}
+
+	var res sql.Result
+	var err error
+	if paused {
+		res, err = r.db.ExecContext(ctx, RecurringExpensePauseSQL, id, userId)
+	} else {
+		res, err = r.db.ExecContext(ctx, RecurringExpenseResumeSQL, id, userId, nextRunAt)
+	}
+	if err != nil {
+		return false, errors.Wrap(err, setPausedErrMsg)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, errors.Wrap(err, setPausedErrMsg)
+	}
+
+	return affected > 0, nil
+}
+
+func (r *recurringExpensesRepository) GetDueRecurring(ctx context.Context, now time.Time, limit int) ([]model.RecurringExpense, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "RecurringExpensesRepository_GetDueRecurring")
+	defer span.Finish()
+
+	rows, err := r.db.QueryContext(ctx, DueRecurringExpensesSelectSQL, now, limit)
+	if err != nil {
+		return []model.RecurringExpense{}, errors.Wrap(err, getDueRecurringErrMsg)
+	}
+
+	recurring, err := scanRecurringExpenses(rows)
+	if err != nil {
+		return []model.RecurringExpense{}, errors.Wrap(err, getDueRecurringErrMsg)
+	}
+
+	return recurring, nil
+}
+
+func (r *recurringExpensesRepository) Reschedule(
+	ctx context.Context,
+	recurring model.RecurringExpense,
+	nextRunAt time.Time,
+) (bool, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "RecurringExpensesRepository_Reschedule")
+	defer span.Finish()
+
+	res, err := r.db.ExecContext(ctx, RecurringExpenseRescheduleSQL, recurring.ID, recurring.NextRunAt, nextRunAt)
+	if err != nil {
+		return false, errors.Wrap(err, rescheduleRecurringErrMsg)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, errors.Wrap(err, rescheduleRecurringErrMsg)
+	}
+
+	return affected > 0, nil
+}
+
+func scanRecurringExpenses(rows *sql.Rows) ([]model.RecurringExpense, error) {
+	defer rows.Close() //nolint:errcheck
+
+	recurring := make([]model.RecurringExpense, 0)
+	for rows.Next() {
+		var rec model.RecurringExpense
+		var period string
+		var weekday int
+		err := rows.Scan(
+			&rec.ID,
+			&rec.UserId,
+			&rec.Amount,
+			&rec.Currency,
+			&rec.Category,
+			&period,
+			&weekday,
+			&rec.Schedule.Day,
+			&rec.Schedule.Hour,
+			&rec.Schedule.Minute,
+			&rec.NextRunAt,
+			&rec.Paused,
+		)
+		if err != nil {
+			return nil, err
+		}
+		rec.Schedule.Period, _ = model.ParseSchedulePeriod(period)
+		rec.Schedule.Weekday = time.Weekday(weekday)
+
+		recurring = append(recurring, rec)
+	}
+
+	return recurring, rows.Err()
+}
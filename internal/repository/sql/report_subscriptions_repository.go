@@ -0,0 +1,179 @@
+package expenses_sql_repo
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/config"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/model"
+	repo "gitlab.ozon.dev/cranky4/tg-bot/internal/repository"
+)
+
+const (
+	ReportSubscriptionsSelectSQL = `SELECT user_id, period, weekday, day, hour, minute, next_run_at
+		FROM report_subscriptions WHERE user_id = $1 ORDER BY period DESC`
+	ReportSubscriptionUpsertSQL = `INSERT INTO report_subscriptions (user_id, period, weekday, day, hour, minute, next_run_at)
+		VALUES($1,$2,$3,$4,$5,$6,$7) ON CONFLICT (user_id, period)
+		DO UPDATE SET weekday = EXCLUDED.weekday, day = EXCLUDED.day, hour = EXCLUDED.hour, minute = EXCLUDED.minute,
+			next_run_at = EXCLUDED.next_run_at, updated_at = now()`
+	ReportSubscriptionDeleteSQL     = "DELETE FROM report_subscriptions WHERE user_id = $1 AND period = $2"
+	DueReportSubscriptionsSelectSQL = `SELECT user_id, period, weekday, day, hour, minute, next_run_at
+		FROM report_subscriptions WHERE next_run_at <= $1 ORDER BY next_run_at LIMIT $2`
+	// переносит отправку, только если ее не перенесла другая реплика
+	ReportSubscriptionRescheduleSQL = `UPDATE report_subscriptions SET next_run_at = $4, updated_at = now()
+		WHERE user_id = $1 AND period = $2 AND next_run_at = $3`
+
+	saveSubscriptionErrMsg       = "ошибка в методе saveSubscription"
+	getSubscriptionsErrMsg       = "ошибка в методе getSubscriptions"
+	deleteSubscriptionErrMsg     = "ошибка в методе deleteSubscription"
+	getDueSubscriptionsErrMsg    = "ошибка в методе getDueSubscriptions"
+	rescheduleSubscriptionErrMsg = "ошибка в методе reschedule"
+)
+
+type reportSubscriptionsRepository struct {
+	db *sql.DB
+}
+
+func NewReportSubscriptionsRepository(conf config.DatabaseConf) (repo.ReportSubscriptionsRepository, error) {
+	db, err := sql.Open("pgx", conf.Dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	return &reportSubscriptionsRepository{
+		db: db,
+	}, nil
+}
+
+func (r *reportSubscriptionsRepository) SaveSubscription(ctx context.Context, subscription model.ReportSubscription) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "ReportSubscriptionsRepository_SaveSubscription")
+	defer span.Finish()
+
+	_, err := r.db.ExecContext(
+		ctx,
+		ReportSubscriptionUpsertSQL,
+		subscription.UserId,
+		model.SchedulePeriodName(subscription.Schedule.Period),
+		int(subscription.Schedule.Weekday),
+		subscription.Schedule.Day,
+		subscription.Schedule.Hour,
+		subscription.Schedule.Minute,
+		subscription.NextRunAt,
+	)
+	if err != nil {
+		return errors.Wrap(err, saveSubscriptionErrMsg)
+	}
+
+	return nil
+}
+
+func (r *reportSubscriptionsRepository) GetSubscriptions(ctx context.Context, userId int64) ([]model.ReportSubscription, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "ReportSubscriptionsRepository_GetSubscriptions")
+	defer span.Finish()
+
+	rows, err := r.db.QueryContext(ctx, ReportSubscriptionsSelectSQL, userId)
+	if err != nil {
+		return []model.ReportSubscription{}, errors.Wrap(err, getSubscriptionsErrMsg)
+	}
+
+	subscriptions, err := scanSubscriptions(rows)
+	if err != nil {
+		return []model.ReportSubscription{}, errors.Wrap(err, getSubscriptionsErrMsg)
+	}
+
+	return subscriptions, nil
+}
+
+func (r *reportSubscriptionsRepository) DeleteSubscription(ctx context.Context, userId int64, period model.ExpensePeriod) (bool, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "ReportSubscriptionsRepository_DeleteSubscription")
+	defer span.Finish()
+
+	res, err := r.db.ExecContext(ctx, ReportSubscriptionDeleteSQL, userId, model.SchedulePeriodName(period))
+	if err != nil {
+		return false, errors.Wrap(err, deleteSubscriptionErrMsg)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, errors.Wrap(err, deleteSubscriptionErrMsg)
+	}
+
+	return affected > 0, nil
+}
+
+func (r *reportSubscriptionsRepository) GetDueSubscriptions(ctx context.Context, now time.Time, limit int) ([]model.ReportSubscription, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "ReportSubscriptionsRepository_GetDueSubscriptions")
+	defer span.Finish()
+
+	rows, err := r.db.QueryContext(ctx, DueReportSubscriptionsSelectSQL, now, limit)
+	if err != nil {
+		return []model.ReportSubscription{}, errors.Wrap(err, getDueSubscriptionsErrMsg)
+	}
+
+	subscriptions, err := scanSubscriptions(rows)
+	if err != nil {
+		return []model.ReportSubscription{}, errors.Wrap(err, getDueSubscriptionsErrMsg)
+	}
+
+	return subscriptions, nil
+}
+
+func (r *reportSubscriptionsRepository) Reschedule(
+	ctx context.Context,
+	subscription model.ReportSubscription,
+	nextRunAt time.Time,
+) (bool, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "ReportSubscriptionsRepository_Reschedule")
+	defer span.Finish()
+
+	res, err := r.db.ExecContext(
+		ctx,
+		ReportSubscriptionRescheduleSQL,
+		subscription.UserId,
+		model.SchedulePeriodName(subscription.Schedule.Period),
+		subscription.NextRunAt,
+		nextRunAt,
+	)
+	if err != nil {
+		return false, errors.Wrap(err, rescheduleSubscriptionErrMsg)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, errors.Wrap(err, rescheduleSubscriptionErrMsg)
+	}
+
+	return affected > 0, nil
+}
+
+func scanSubscriptions(rows *sql.Rows) ([]model.ReportSubscription, error) {
+	defer rows.Close() //nolint:errcheck
+
+	subscriptions := make([]model.ReportSubscription, 0)
+	for rows.Next() {
+		var subscription model.ReportSubscription
+		var period string
+		var weekday int
+		err := rows.Scan(
+			&subscription.UserId,
+			&period,
+			&weekday,
+			&subscription.Schedule.Day,
+			&subscription.Schedule.Hour,
+			&subscription.Schedule.Minute,
+			&subscription.NextRunAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		subscription.Schedule.Period, _ = model.ParseSchedulePeriod(period)
+		subscription.Schedule.Weekday = time.Weekday(weekday)
+
+		subscriptions = append(subscriptions, subscription)
+	}
+
+	return subscriptions, rows.Err()
+}
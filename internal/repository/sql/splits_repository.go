@@ -0,0 +1,212 @@
+package expenses_sql_repo
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/config"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/model"
+	repo "gitlab.ozon.dev/cranky4/tg-bot/internal/repository"
+)
+
+const (
+	ExpenseShareInsertSQL = `INSERT INTO expense_shares (expense_id, budget_id, payer_id, user_id, amount) 
+		VALUES ($1, $2, $3, $4, $5)`
+	ExpenseSharesSelectSQL = "SELECT expense_id, budget_id, payer_id, user_id, amount FROM expense_shares WHERE expense_id = $1 ORDER BY user_id"
+	ExpenseSharesDeleteSQL = "DELETE FROM expense_shares WHERE expense_id = $1"
+	SettlementInsertSQL    = `INSERT INTO debt_settlements (id, budget_id, from_user_id, to_user_id, amount, datetime) 
+		VALUES ($1, $2, $3, $4, $5, $6)`
+	// свою долю плательщик никому не должен, возврат долга увеличивает баланс отдавшего
+	DebtBalancesSelectSQL = `SELECT user_id, SUM(amount) FROM (
+			SELECT payer_id AS user_id, amount FROM expense_shares WHERE budget_id = $1 AND payer_id <> user_id
+			UNION ALL
+			SELECT user_id, -amount FROM expense_shares WHERE budget_id = $1 AND payer_id <> user_id
+			UNION ALL
+			SELECT from_user_id, amount FROM debt_settlements WHERE budget_id = $1
+			UNION ALL
+			SELECT to_user_id, -amount FROM debt_settlements WHERE budget_id = $1
+		) b GROUP BY user_id`
+
+	addSharesErrMsg     = "ошибка в методе addShares"
+	getSharesErrMsg     = "ошибка в методе getShares"
+	deleteSharesErrMsg  = "ошибка в методе deleteShares"
+	replaceSharesErrMsg = "ошибка в методе replaceShares"
+	addSettlementErrMsg = "ошибка в методе addSettlement"
+	getBalancesErrMsg   = "ошибка в методе getBalances"
+)
+
+type splitsRepository struct {
+	db *sql.DB
+}
+
+func NewSplitsRepository(conf config.DatabaseConf) (repo.SplitsRepository, error) {
+	db, err := sql.Open("pgx", conf.Dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	return &splitsRepository{
+		db: db,
+	}, nil
+}
+
+func (r *splitsRepository) AddShares(ctx context.Context, shares []model.ExpenseShare) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "SplitsRepository_AddShares")
+	defer span.Finish()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, addSharesErrMsg)
+	}
+
+	for _, share := range shares {
+		_, err = tx.ExecContext(ctx, ExpenseShareInsertSQL, share.ExpenseID, share.BudgetID, share.PayerID, share.UserID, share.Amount)
+		if err != nil {
+			if rollbackErr := tx.Rollback(); rollbackErr != nil {
+				return errors.Wrap(rollbackErr, cannotRollbackTransactionErrMsg)
+			}
+			return errors.Wrap(err, addSharesErrMsg)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return errors.Wrap(err, addSharesErrMsg)
+	}
+
+	return nil
+}
+
+func (r *splitsRepository) GetShares(ctx context.Context, expenseId string) ([]model.ExpenseShare, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "SplitsRepository_GetShares")
+	defer span.Finish()
+
+	if _, err := uuid.Parse(expenseId); err != nil {
+		return []model.ExpenseShare{}, nil
+	}
+
+	rows, err := r.db.QueryContext(ctx, ExpenseSharesSelectSQL, expenseId)
+	if err != nil {
+		return []model.ExpenseShare{}, errors.Wrap(err, getSharesErrMsg)
+	}
+	defer rows.Close() //nolint:errcheck
+
+	shares := make([]model.ExpenseShare, 0)
+	for rows.Next() {
+		var share model.ExpenseShare
+		if err = rows.Scan(&share.ExpenseID, &share.BudgetID, &share.PayerID, &share.UserID, &share.Amount); err != nil {
+			return []model.ExpenseShare{}, errors.Wrap(err, getSharesErrMsg)
+		}
+
+		shares = append(shares, share)
+	}
+
+	if err = rows.Err(); err != nil {
+		return []model.ExpenseShare{}, errors.Wrap(err, getSharesErrMsg)
+	}
+
+	return shares, nil
+}
+
+func (r *splitsRepository) DeleteShares(ctx context.Context, expenseId string) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "SplitsRepository_DeleteShares")
+	defer span.Finish()
+
+	if _, err := uuid.Parse(expenseId); err != nil {
+		return nil
+	}
+
+	if _, err := r.db.ExecContext(ctx, ExpenseSharesDeleteSQL, expenseId); err != nil {
+		return errors.Wrap(err, deleteSharesErrMsg)
+	}
+
+	return nil
+}
+
+func (r *splitsRepository) ReplaceShares(ctx context.Context, expenseId string, shares []model.ExpenseShare) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "SplitsRepository_ReplaceShares")
+	defer span.Finish()
+
+	if _, err := uuid.Parse(expenseId); err != nil {
+		return nil
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, replaceSharesErrMsg)
+	}
+
+	if _, err = tx.ExecContext(ctx, ExpenseSharesDeleteSQL, expenseId); err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			return errors.Wrap(rollbackErr, cannotRollbackTransactionErrMsg)
+		}
+		return errors.Wrap(err, replaceSharesErrMsg)
+	}
+
+	for _, share := range shares {
+		_, err = tx.ExecContext(ctx, ExpenseShareInsertSQL, share.ExpenseID, share.BudgetID, share.PayerID, share.UserID, share.Amount)
+		if err != nil {
+			if rollbackErr := tx.Rollback(); rollbackErr != nil {
+				return errors.Wrap(rollbackErr, cannotRollbackTransactionErrMsg)
+			}
+			return errors.Wrap(err, replaceSharesErrMsg)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return errors.Wrap(err, replaceSharesErrMsg)
+	}
+
+	return nil
+}
+
+func (r *splitsRepository) AddSettlement(ctx context.Context, settlement model.Settlement) (model.Settlement, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "SplitsRepository_AddSettlement")
+	defer span.Finish()
+
+	id, err := uuid.NewUUID()
+	if err != nil {
+		return model.Settlement{}, errors.Wrap(err, addSettlementErrMsg)
+	}
+	settlement.ID = id.String()
+
+	_, err = r.db.ExecContext(
+		ctx,
+		SettlementInsertSQL,
+		settlement.ID, settlement.BudgetID, settlement.FromUserID, settlement.ToUserID, settlement.Amount, settlement.Datetime,
+	)
+	if err != nil {
+		return model.Settlement{}, errors.Wrap(err, addSettlementErrMsg)
+	}
+
+	return settlement, nil
+}
+
+func (r *splitsRepository) GetBalances(ctx context.Context, budgetId int64) (map[int64]int64, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "SplitsRepository_GetBalances")
+	defer span.Finish()
+
+	rows, err := r.db.QueryContext(ctx, DebtBalancesSelectSQL, budgetId)
+	if err != nil {
+		return nil, errors.Wrap(err, getBalancesErrMsg)
+	}
+	defer rows.Close() //nolint:errcheck
+
+	balances := make(map[int64]int64)
+	for rows.Next() {
+		var userId, balance int64
+		if err = rows.Scan(&userId, &balance); err != nil {
+			return nil, errors.Wrap(err, getBalancesErrMsg)
+		}
+
+		balances[userId] = balance
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, errors.Wrap(err, getBalancesErrMsg)
+	}
+
+	return balances, nil
+}
@@ -0,0 +1,80 @@
+package expenses_sql_repo
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/config"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/model"
+	repo "gitlab.ozon.dev/cranky4/tg-bot/internal/repository"
+)
+
+const (
+	UserSettingsSelectSQL = "SELECT user_id, currency, period_mode, week_start, timezone FROM users_settings WHERE user_id = $1"
+	UserSettingsUpsertSQL = `INSERT INTO users_settings (user_id, currency, period_mode, week_start, timezone) 
+		VALUES($1,$2,$3,$4,$5) ON CONFLICT (user_id) 
+		DO UPDATE SET currency = EXCLUDED.currency, period_mode = EXCLUDED.period_mode, 
+			week_start = EXCLUDED.week_start, timezone = EXCLUDED.timezone, updated_at = now()`
+
+	getSettingsErrMsg  = "ошибка в методе getSettings"
+	saveSettingsErrMsg = "ошибка в методе saveSettings"
+)
+
+type settingsRepository struct {
+	db *sql.DB
+}
+
+func NewSettingsRepository(conf config.DatabaseConf) (repo.UserSettingsRepository, error) {
+	db, err := sql.Open("pgx", conf.Dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	return &settingsRepository{
+		db: db,
+	}, nil
+}
+
+func (r *settingsRepository) GetSettings(ctx context.Context, userId int64) (model.UserSettings, bool, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "UserSettingsRepository_GetSettings")
+	defer span.Finish()
+
+	row := r.db.QueryRowContext(ctx, UserSettingsSelectSQL, userId)
+
+	var settings model.UserSettings
+	var periodMode string
+	var weekStart int
+	if err := row.Scan(&settings.UserID, &settings.Currency, &periodMode, &weekStart, &settings.Timezone); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.UserSettings{}, false, nil
+		}
+		return model.UserSettings{}, false, errors.Wrap(err, getSettingsErrMsg)
+	}
+	settings.PeriodMode = model.PeriodMode(periodMode)
+	settings.WeekStart = time.Weekday(weekStart)
+
+	return settings, true, nil
+}
+
+func (r *settingsRepository) SaveSettings(ctx context.Context, settings model.UserSettings) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "UserSettingsRepository_SaveSettings")
+	defer span.Finish()
+
+	_, err := r.db.ExecContext(
+		ctx,
+		UserSettingsUpsertSQL,
+		settings.UserID,
+		settings.Currency,
+		string(settings.PeriodMode),
+		int(settings.WeekStart),
+		settings.Timezone,
+	)
+	if err != nil {
+		return errors.Wrap(err, saveSettingsErrMsg)
+	}
+
+	return nil
+}
@@ -0,0 +1,12 @@
+package repository
+
+import (
+	"context"
+
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/model"
+)
+
+type UserSettingsRepository interface {
+	GetSettings(ctx context.Context, userId int64) (model.UserSettings, bool, error)
+	SaveSettings(ctx context.Context, settings model.UserSettings) error
+}
@@ -2,6 +2,7 @@ package cache
 
 import (
 	"context"
+	"fmt"
 	"time"
 )
 
@@ -15,3 +16,14 @@ type Cache interface {
 	Get(ctx context.Context, key string) (any, bool, error)
 	Del(ctx context.Context, key string) (bool, error)
 }
+
+// ReportsVersionKey ключ версии закешированных отчетов пользователя,
+// его удаление делает недействительными все отчеты пользователя
+func ReportsVersionKey(userId int64) string {
+	return fmt.Sprintf("reports-version-%d", userId)
+}
+
+// DialogKey ключ состояния многошагового диалога пользователя в чате
+func DialogKey(chatId, userId int64) string {
+	return fmt.Sprintf("dialog-%d-%d", chatId, userId)
+}
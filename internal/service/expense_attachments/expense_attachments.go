@@ -0,0 +1,89 @@
+package expenseattachments
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	blobstorage "gitlab.ozon.dev/cranky4/tg-bot/internal/clients/blob_storage"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/model"
+	repo "gitlab.ozon.dev/cranky4/tg-bot/internal/repository"
+)
+
+const (
+	errAttachMessage        = "ошибка сохранения чека"
+	errGetAttachmentMessage = "ошибка получения чека"
+	errEmptyFileMessage     = "пустой файл"
+)
+
+type ExpenseAttachments interface {
+	// Attach сохраняет файл в хранилище и прикрепляет его к трате бюджета userId
+	Attach(ctx context.Context, expenseId string, userId int64, file model.File) error
+	// Get возвращает файл, прикрепленный к трате бюджета userId
+	Get(ctx context.Context, expenseId string, userId int64) (*model.File, bool, error)
+}
+
+type expenseAttachments struct {
+	repo    repo.AttachmentsRepository
+	storage blobstorage.BlobStorage
+}
+
+func NewExpenseAttachments(repo repo.AttachmentsRepository, storage blobstorage.BlobStorage) ExpenseAttachments {
+	return &expenseAttachments{
+		repo:    repo,
+		storage: storage,
+	}
+}
+
+func (s *expenseAttachments) Attach(ctx context.Context, expenseId string, userId int64, file model.File) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "ExpenseAttachments_Attach")
+	defer span.Finish()
+
+	if len(file.Data) == 0 {
+		return errors.Wrap(errors.New(errEmptyFileMessage), errAttachMessage)
+	}
+
+	// файлы бюджета лежат рядом, расширение сохраняется для просмотра в хранилище
+	key := fmt.Sprintf("%d/%s%s", userId, expenseId, strings.ToLower(filepath.Ext(file.Name)))
+	if err := s.storage.Put(ctx, key, file.Data); err != nil {
+		return errors.Wrap(err, errAttachMessage)
+	}
+
+	err := s.repo.SaveAttachment(ctx, model.Attachment{
+		ExpenseID: expenseId,
+		UserId:    userId,
+		Key:       key,
+		FileName:  file.Name,
+		MimeType:  file.MimeType,
+		Size:      int64(len(file.Data)),
+	})
+	if err != nil {
+		return errors.Wrap(err, errAttachMessage)
+	}
+
+	return nil
+}
+
+func (s *expenseAttachments) Get(ctx context.Context, expenseId string, userId int64) (*model.File, bool, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "ExpenseAttachments_Get")
+	defer span.Finish()
+
+	attachment, found, err := s.repo.GetAttachment(ctx, expenseId, userId)
+	if err != nil {
+		return nil, false, errors.Wrap(err, errGetAttachmentMessage)
+	}
+
+	if !found {
+		return nil, false, nil
+	}
+
+	data, err := s.storage.Get(ctx, attachment.Key)
+	if err != nil {
+		return nil, false, errors.Wrap(err, errGetAttachmentMessage)
+	}
+
+	return &model.File{Name: attachment.FileName, MimeType: attachment.MimeType, Data: data}, true, nil
+}
@@ -0,0 +1,56 @@
+package expenseattachments
+
+import (
+	"context"
+	"testing"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/stretchr/testify/assert"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/clients/blob_storage/local"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/config"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/model"
+	memoryrepo "gitlab.ozon.dev/cranky4/tg-bot/internal/repository/memory"
+)
+
+func TestAttachedFileShouldBeReturnedInItsBudget(t *testing.T) {
+	ctx := context.Background()
+	_, wrapedCtx := opentracing.StartSpanFromContext(ctx, "ExpenseAttachments_Attach")
+
+	storage, err := local.NewLocalStorage(config.BlobStorageConf{Dir: t.TempDir()})
+	assert.NoError(t, err)
+	attachments := NewExpenseAttachments(memoryrepo.NewAttachmentsRepository(), storage)
+
+	receipt := model.File{Name: "Receipt.JPG", MimeType: "image/jpeg", Data: []byte("receipt")}
+	err = attachments.Attach(wrapedCtx, "1b4e28ba-2fa1-11d2-883f-0016d3cca427", -100, receipt)
+	assert.NoError(t, err)
+
+	data, err := storage.Get(ctx, "-100/1b4e28ba-2fa1-11d2-883f-0016d3cca427.jpg")
+	assert.NoError(t, err)
+	assert.Equal(t, receipt.Data, data)
+
+	file, found, err := attachments.Get(wrapedCtx, "1b4e28ba-2fa1-11d2-883f-0016d3cca427", -100)
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, &receipt, file)
+
+	// чужой бюджет чек не видит
+	_, found, err = attachments.Get(wrapedCtx, "1b4e28ba-2fa1-11d2-883f-0016d3cca427", 100)
+	assert.NoError(t, err)
+	assert.False(t, found)
+}
+
+func TestAttachShouldRejectEmptyFile(t *testing.T) {
+	ctx := context.Background()
+	_, wrapedCtx := opentracing.StartSpanFromContext(ctx, "ExpenseAttachments_Attach")
+
+	storage, err := local.NewLocalStorage(config.BlobStorageConf{Dir: t.TempDir()})
+	assert.NoError(t, err)
+	attachments := NewExpenseAttachments(memoryrepo.NewAttachmentsRepository(), storage)
+
+	err = attachments.Attach(wrapedCtx, "1b4e28ba-2fa1-11d2-883f-0016d3cca427", 100, model.File{Name: "receipt.jpg"})
+	assert.Error(t, err)
+
+	_, found, err := attachments.Get(wrapedCtx, "1b4e28ba-2fa1-11d2-883f-0016d3cca427", 100)
+	assert.NoError(t, err)
+	assert.False(t, found)
+}
@@ -0,0 +1,66 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/service/expense_attachments/expense_attachments.go
+
+// Package mock_expenseattachments is a generated GoMock package.
+package mock_expenseattachments
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	model "gitlab.ozon.dev/cranky4/tg-bot/internal/model"
+)
+
+// MockExpenseAttachments is a mock of ExpenseAttachments interface.
+type MockExpenseAttachments struct {
+	ctrl     *gomock.Controller
+	recorder *MockExpenseAttachmentsMockRecorder
+}
+
+// MockExpenseAttachmentsMockRecorder is the mock recorder for MockExpenseAttachments.
+type MockExpenseAttachmentsMockRecorder struct {
+	mock *MockExpenseAttachments
+}
+
+// NewMockExpenseAttachments creates a new mock instance.
+func NewMockExpenseAttachments(ctrl *gomock.Controller) *MockExpenseAttachments {
+	mock := &MockExpenseAttachments{ctrl: ctrl}
+	mock.recorder = &MockExpenseAttachmentsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExpenseAttachments) EXPECT() *MockExpenseAttachmentsMockRecorder {
+	return m.recorder
+}
+
+// Attach mocks base method.
+func (m *MockExpenseAttachments) Attach(ctx context.Context, expenseId string, userId int64, file model.File) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Attach", ctx, expenseId, userId, file)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Attach indicates an expected call of Attach.
+func (mr *MockExpenseAttachmentsMockRecorder) Attach(ctx, expenseId, userId, file interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Attach", reflect.TypeOf((*MockExpenseAttachments)(nil).Attach), ctx, expenseId, userId, file)
+}
+
+// Get mocks base method.
+func (m *MockExpenseAttachments) Get(ctx context.Context, expenseId string, userId int64) (*model.File, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, expenseId, userId)
+	ret0, _ := ret[0].(*model.File)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockExpenseAttachmentsMockRecorder) Get(ctx, expenseId, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockExpenseAttachments)(nil).Get), ctx, expenseId, userId)
+}
@@ -0,0 +1,172 @@
+package expense_exporter
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/model"
+	repo "gitlab.ozon.dev/cranky4/tg-bot/internal/repository"
+	serviceconverter "gitlab.ozon.dev/cranky4/tg-bot/internal/service/converter"
+)
+
+const (
+	primitiveCurrencyMultiplier = 100
+	dateFormat                  = "2006-01-02 15:04:05"
+	fileDateFormat              = "2006-01-02"
+	sheetName                   = "Траты"
+	// utf8BOM нужен Excel, чтобы открыть csv с кириллицей в правильной кодировке
+	utf8BOM = "\ufeff"
+
+	errExportMessage        = "ошибка выгрузки трат"
+	errUnknownFormatMessage = "неизвестный формат выгрузки %s"
+)
+
+var exportHeader = []string{"Дата", "Категория", "Сумма", "Валюта", "Счет", "ИД"}
+
+type ExpenseExporter interface {
+	// Export выгружает траты бюджета userId за диапазон дат в файл, суммы в валюте currency
+	Export(ctx context.Context, format model.ExportFormat, dateRange model.DateRange, currency string, userId int64) (*ExpenseExport, error)
+}
+
+// ExpenseExport файл с тратами за период
+type ExpenseExport struct {
+	UserID   int64 // получатель выгрузки
+	ChatID   int64 // чат, в который отправляется файл, 0 - личный чат UserID
+	BudgetID int64 // бюджет, траты которого выгружены
+	Format   model.ExportFormat
+	Range    model.DateRange
+	Count    int // количество трат
+	File     model.File
+}
+
+type exporter struct {
+	repo      repo.ExpensesRepository
+	converter serviceconverter.Converter
+}
+
+func NewExporter(repo repo.ExpensesRepository, conv serviceconverter.Converter) ExpenseExporter {
+	return &exporter{
+		repo:      repo,
+		converter: conv,
+	}
+}
+
+func (e *exporter) Export(
+	ctx context.Context,
+	format model.ExportFormat,
+	dateRange model.DateRange,
+	currency string,
+	userId int64,
+) (*ExpenseExport, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "ExpenseExporter_Export")
+	defer span.Finish()
+
+	expenses, err := e.repo.GetExpenses(ctx, dateRange, userId)
+	if err != nil {
+		return nil, errors.Wrap(err, errExportMessage)
+	}
+
+	sort.SliceStable(expenses, func(i, j int) bool {
+		return expenses[i].Datetime.Before(expenses[j].Datetime)
+	})
+
+	// даты трат хранятся по часам пользователя, пояс берем из диапазона
+	loc := dateRange.From.Location()
+
+	var data bytes.Buffer
+	switch format {
+	case model.CSVExportFormat:
+		err = e.writeCSV(&data, expenses, currency, loc)
+	case model.XLSXExportFormat:
+		err = e.writeXLSX(&data, expenses, currency, loc)
+	default:
+		return nil, fmt.Errorf(errUnknownFormatMessage, format)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, errExportMessage)
+	}
+
+	return &ExpenseExport{
+		UserID:   userId,
+		BudgetID: userId,
+		Format:   format,
+		Range:    dateRange,
+		Count:    len(expenses),
+		File: model.File{
+			Name:     exportFileName(format, dateRange),
+			MimeType: format.MimeType(),
+			Data:     data.Bytes(),
+		},
+	}, nil
+}
+
+func (e *exporter) writeCSV(data *bytes.Buffer, expenses []*model.Expense, currency string, loc *time.Location) error {
+	data.WriteString(utf8BOM)
+
+	w := csv.NewWriter(data)
+	if err := w.Write(exportHeader); err != nil {
+		return err
+	}
+
+	for _, ex := range expenses {
+		err := w.Write([]string{
+			model.WallClock(ex.Datetime, loc).Format(dateFormat),
+			ex.Category,
+			strconv.FormatFloat(e.fromPrimitive(ex.Amount, currency), 'f', 2, 64),
+			currency,
+			ex.Account,
+			ex.ID,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	w.Flush()
+
+	return w.Error()
+}
+
+func (e *exporter) writeXLSX(data *bytes.Buffer, expenses []*model.Expense, currency string, loc *time.Location) error {
+	rows := make([][]interface{}, 0, len(expenses)+1)
+
+	header := make([]interface{}, 0, len(exportHeader))
+	for _, title := range exportHeader {
+		header = append(header, title)
+	}
+	rows = append(rows, header)
+
+	for _, ex := range expenses {
+		rows = append(rows, []interface{}{
+			model.WallClock(ex.Datetime, loc).Format(dateFormat),
+			ex.Category,
+			e.fromPrimitive(ex.Amount, currency),
+			currency,
+			ex.Account,
+			ex.ID,
+		})
+	}
+
+	return writeXLSX(data, sheetName, rows)
+}
+
+func (e *exporter) fromPrimitive(amount int64, currency string) float64 {
+	return e.converter.FromRUB(float64(amount), currency) / primitiveCurrencyMultiplier
+}
+
+// exportFileName имя файла с включенной последней датой: expenses_2022-10-01_2022-10-31.csv
+func exportFileName(format model.ExportFormat, dateRange model.DateRange) string {
+	return fmt.Sprintf(
+		"expenses_%s_%s.%s",
+		dateRange.From.Format(fileDateFormat),
+		dateRange.To.Add(-time.Nanosecond).Format(fileDateFormat),
+		format,
+	)
+}
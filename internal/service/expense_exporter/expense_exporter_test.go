@@ -0,0 +1,133 @@
+package expense_exporter
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/opentracing/opentracing-go"
+	"github.com/stretchr/testify/assert"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/clients/exchangerate"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/model"
+	repomocks "gitlab.ozon.dev/cranky4/tg-bot/internal/repository/mocks"
+	serviceconverter "gitlab.ozon.dev/cranky4/tg-bot/internal/service/converter"
+)
+
+type testGetter struct{}
+
+func (g *testGetter) Get(ctx context.Context) (*exchangerate.ExchangeResponse, error) {
+	return &exchangerate.ExchangeResponse{
+		Rates: exchangerate.Rates{
+			USD: 2,
+			EUR: 3,
+			CNY: 4,
+		},
+	}, nil
+}
+
+var testConverter = serviceconverter.NewConverter(&testGetter{})
+
+var testExpenses = []*model.Expense{
+	{
+		ID:       "2",
+		Amount:   35000,
+		Category: "Кафе",
+		Account:  "Карта",
+		Datetime: time.Date(2022, 10, 2, 9, 30, 0, 0, time.UTC),
+		UserId:   100,
+	},
+	{
+		ID:       "1",
+		Amount:   12550,
+		Category: `Дом, "ремонт"`,
+		Datetime: time.Date(2022, 10, 1, 12, 56, 0, 0, time.UTC),
+		UserId:   100,
+	},
+}
+
+func TestExportCSVShouldSortExpensesByDate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	_, wrapedCtx := opentracing.StartSpanFromContext(ctx, "ExpenseExporter_Export")
+	dateRange := model.NewDateRange(time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC), time.Date(2022, 11, 1, 0, 0, 0, 0, time.UTC))
+
+	repo := repomocks.NewMockExpensesRepository(ctrl)
+	repo.EXPECT().GetExpenses(wrapedCtx, dateRange, int64(100)).Return(testExpenses, nil)
+
+	export, err := NewExporter(repo, testConverter).Export(ctx, model.CSVExportFormat, dateRange, "RUB", 100)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, export.Count)
+	assert.Equal(t, "expenses_2022-10-01_2022-10-31.csv", export.File.Name)
+	assert.Equal(t, "text/csv", export.File.MimeType)
+	assert.Equal(t, "\ufeffДата,Категория,Сумма,Валюта,Счет,ИД\n"+
+		"2022-10-01 12:56:00,\"Дом, \"\"ремонт\"\"\",125.50,RUB,,1\n"+
+		"2022-10-02 09:30:00,Кафе,350.00,RUB,Карта,2\n", string(export.File.Data))
+}
+
+func TestExportShouldKeepStoredWallClock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	_, wrapedCtx := opentracing.StartSpanFromContext(ctx, "ExpenseExporter_Export")
+	moscow := time.FixedZone("", 3*60*60)
+	dateRange := model.NewDateRange(time.Date(2022, 10, 1, 0, 0, 0, 0, moscow), time.Date(2022, 11, 1, 0, 0, 0, 0, moscow))
+
+	// база возвращает часы пользователя с меткой UTC
+	repo := repomocks.NewMockExpensesRepository(ctrl)
+	repo.EXPECT().GetExpenses(wrapedCtx, dateRange, int64(100)).Return([]*model.Expense{
+		{
+			ID:       "2",
+			Amount:   35000,
+			Category: "Кафе",
+			Account:  "Карта",
+			Datetime: time.Date(2022, 10, 2, 9, 30, 0, 0, time.UTC),
+			UserId:   100,
+		},
+	}, nil)
+
+	export, err := NewExporter(repo, testConverter).Export(ctx, model.CSVExportFormat, dateRange, "RUB", 100)
+	assert.NoError(t, err)
+	assert.Contains(t, string(export.File.Data), "2022-10-02 09:30:00,Кафе,350.00,RUB,Карта,2\n")
+}
+
+func TestExportXLSXShouldWriteWorkbook(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	_, wrapedCtx := opentracing.StartSpanFromContext(ctx, "ExpenseExporter_Export")
+	dateRange := model.NewDateRange(time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	repo := repomocks.NewMockExpensesRepository(ctrl)
+	repo.EXPECT().GetExpenses(wrapedCtx, dateRange, int64(100)).Return(testExpenses, nil)
+
+	export, err := NewExporter(repo, testConverter).Export(ctx, model.XLSXExportFormat, dateRange, "RUB", 100)
+	assert.NoError(t, err)
+	assert.Equal(t, "expenses_2022-01-01_2022-12-31.xlsx", export.File.Name)
+
+	archive, err := zip.NewReader(bytes.NewReader(export.File.Data), int64(len(export.File.Data)))
+	assert.NoError(t, err)
+
+	files := make(map[string]string)
+	for _, file := range archive.File {
+		r, err := file.Open()
+		assert.NoError(t, err)
+		content, err := io.ReadAll(r)
+		assert.NoError(t, err)
+		files[file.Name] = string(content)
+	}
+
+	assert.Contains(t, files, "[Content_Types].xml")
+	assert.Contains(t, files, "_rels/.rels")
+	assert.Contains(t, files["xl/workbook.xml"], `<sheet name="Траты" sheetId="1" r:id="rId1"/>`)
+	assert.Contains(t, files["xl/worksheets/sheet1.xml"], `<c r="B2" t="inlineStr"><is><t>Дом, &#34;ремонт&#34;</t></is></c>`)
+	assert.Contains(t, files["xl/worksheets/sheet1.xml"], `<c r="C3"><v>350</v></c>`)
+}
+
+func TestXLSXColumnNames(t *testing.T) {
+	assert.Equal(t, "A", xlsxColumn(0))
+	assert.Equal(t, "Z", xlsxColumn(25))
+	assert.Equal(t, "AA", xlsxColumn(26))
+	assert.Equal(t, "BA", xlsxColumn(52))
+}
@@ -0,0 +1,52 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/service/expense_exporter/expense_exporter.go
+
+// Package mock_expense_exporter is a generated GoMock package.
+package mock_expense_exporter
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	model "gitlab.ozon.dev/cranky4/tg-bot/internal/model"
+	expense_exporter "gitlab.ozon.dev/cranky4/tg-bot/internal/service/expense_exporter"
+)
+
+// MockExpenseExporter is a mock of ExpenseExporter interface.
+type MockExpenseExporter struct {
+	ctrl     *gomock.Controller
+	recorder *MockExpenseExporterMockRecorder
+}
+
+// MockExpenseExporterMockRecorder is the mock recorder for MockExpenseExporter.
+type MockExpenseExporterMockRecorder struct {
+	mock *MockExpenseExporter
+}
+
+// NewMockExpenseExporter creates a new mock instance.
+func NewMockExpenseExporter(ctrl *gomock.Controller) *MockExpenseExporter {
+	mock := &MockExpenseExporter{ctrl: ctrl}
+	mock.recorder = &MockExpenseExporterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExpenseExporter) EXPECT() *MockExpenseExporterMockRecorder {
+	return m.recorder
+}
+
+// Export mocks base method.
+func (m *MockExpenseExporter) Export(ctx context.Context, format model.ExportFormat, dateRange model.DateRange, currency string, userId int64) (*expense_exporter.ExpenseExport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Export", ctx, format, dateRange, currency, userId)
+	ret0, _ := ret[0].(*expense_exporter.ExpenseExport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Export indicates an expected call of Export.
+func (mr *MockExpenseExporterMockRecorder) Export(ctx, format, dateRange, currency, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Export", reflect.TypeOf((*MockExpenseExporter)(nil).Export), ctx, format, dateRange, currency, userId)
+}
@@ -0,0 +1,83 @@
+package expense_importer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// utf8BOM Excel добавляет в начало csv, в том числе в файл выгрузки /export
+const utf8BOM = "\ufeff"
+
+// delimiters разделители колонок, банки выгружают csv и с запятой, и с точкой с запятой
+var delimiters = []rune{';', ',', '\t'}
+
+// csvRecord строка файла, line - номер строки с 1, err - строку не удалось разобрать
+type csvRecord struct {
+	line   int
+	fields []string
+	err    error
+}
+
+// readRecords читает строки csv, первая строка - заголовок.
+// Ошибка формата строки не прерывает чтение, а попадает в отчет об импорте
+func readRecords(data []byte) ([]csvRecord, error) {
+	data = bytes.TrimPrefix(data, []byte(utf8BOM))
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comma = detectDelimiter(data)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	records := make([]csvRecord, 0)
+	for {
+		fields, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			records = append(records, csvRecord{line: parseErr.StartLine, err: parseErr.Err})
+			continue
+		} else if err != nil {
+			return nil, errors.Wrap(err, errImportMessage)
+		}
+
+		line, _ := reader.FieldPos(0)
+		records = append(records, csvRecord{line: line, fields: fields})
+
+		// заголовок не считается
+		if len(records) > MaxRows+1 {
+			return nil, fmt.Errorf(errTooManyRowsMessage, MaxRows)
+		}
+	}
+
+	if len(records) < 2 {
+		return nil, errors.New(errEmptyFileMessage)
+	}
+
+	if records[0].err != nil {
+		return nil, fmt.Errorf(errRowFormatMessage, records[0].err.Error())
+	}
+
+	return records, nil
+}
+
+// detectDelimiter выбирает разделитель, который чаще встречается в заголовке
+func detectDelimiter(data []byte) rune {
+	header, _, _ := strings.Cut(string(data), "\n")
+
+	delimiter, count := delimiters[0], -1
+	for _, d := range delimiters {
+		if c := strings.Count(header, string(d)); c > count {
+			delimiter, count = d, c
+		}
+	}
+
+	return delimiter
+}
@@ -0,0 +1,390 @@
+package expense_importer
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/model"
+	repo "gitlab.ozon.dev/cranky4/tg-bot/internal/repository"
+	serviceconverter "gitlab.ozon.dev/cranky4/tg-bot/internal/service/converter"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/service/expense_processor"
+)
+
+const (
+	// MaxRows ограничивает размер файла, чтобы импорт не держал транзакции слишком долго
+	MaxRows     = 5000
+	previewSize = 10
+
+	errImportMessage          = "ошибка импорта трат"
+	errEmptyFileMessage       = "в файле нет строк с тратами"
+	errTooManyRowsMessage     = "в файле больше %d строк, разделите его на части"
+	errMappingFieldMessage    = "неизвестное поле %s, доступны: date, amount, category, currency"
+	errMappingFormatMessage   = "колонки задаются как поле=колонка через ;, например date=Дата;amount=Сумма"
+	errColumnNotFoundMessage  = "в файле нет колонки %s"
+	errColumnNumberMessage    = "в файле нет колонки с номером %d"
+	errRowFormatMessage       = "не удалось прочитать строку: %s"
+	errRowDateMessage         = "не удалось распознать дату %s"
+	errRowAmountMessage       = "не удалось распознать сумму %s"
+	errRowZeroAmountMessage   = "сумма должна быть больше нуля"
+	errRowCategoryMessage     = "не указана категория"
+	errRowCurrencyMessage     = "неизвестная валюта %s"
+	errRowMissingFieldMessage = "в строке нет колонки %s"
+)
+
+// dateLayouts форматы дат в выписках банков и в выгрузке /export
+var dateLayouts = []string{
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"02.01.2006 15:04:05",
+	"02.01.2006 15:04",
+	"02.01.2006",
+	"02.01.06",
+	time.RFC3339,
+}
+
+type ExpenseImporter interface {
+	// Import разбирает csv и добавляет траты в бюджет options.BudgetID от имени options.UserID
+	Import(ctx context.Context, data []byte, options ImportOptions) (*ImportResult, error)
+}
+
+// ColumnMapping колонки файла для полей траты: название из заголовка или номер колонки, начиная с 1
+type ColumnMapping struct {
+	Date     string
+	Amount   string
+	Category string
+	Currency string // если колонки нет в файле, суммы в валюте пользователя
+}
+
+// DefaultColumnMapping совпадает с заголовком файла выгрузки /export
+var DefaultColumnMapping = ColumnMapping{
+	Date:     "Дата",
+	Amount:   "Сумма",
+	Category: "Категория",
+	Currency: "Валюта",
+}
+
+// ParseColumnMapping разбирает колонки вида date=Дата операции;amount=3;category=Описание,
+// не заданные поля берутся из DefaultColumnMapping
+func ParseColumnMapping(raw string) (ColumnMapping, error) {
+	mapping := DefaultColumnMapping
+
+	for _, pair := range strings.Split(raw, ";") {
+		if strings.Trim(pair, " ") == "" {
+			continue
+		}
+
+		field, column, ok := strings.Cut(pair, "=")
+		column = strings.Trim(column, " ")
+		if !ok || column == "" {
+			return ColumnMapping{}, errors.New(errMappingFormatMessage)
+		}
+
+		switch strings.ToLower(strings.Trim(field, " ")) {
+		case "date":
+			mapping.Date = column
+		case "amount":
+			mapping.Amount = column
+		case "category":
+			mapping.Category = column
+		case "currency":
+			mapping.Currency = column
+		default:
+			return ColumnMapping{}, fmt.Errorf(errMappingFieldMessage, strings.Trim(field, " "))
+		}
+	}
+
+	return mapping, nil
+}
+
+// ImportOptions параметры импорта
+type ImportOptions struct {
+	UserID   int64 // автор трат, по его настройкам разбираются даты и суммы без валюты
+	BudgetID int64
+	Mapping  ColumnMapping
+	DryRun   bool // только проверить файл, ничего не сохраняя
+}
+
+// ImportResult итог импорта файла
+type ImportResult struct {
+	DryRun     bool
+	Rows       int // строк с тратами в файле
+	Added      int // добавлено трат, при DryRun - будет добавлено
+	Duplicates int
+	Errors     []RowError
+	Preview    []ImportedRow // первые строки файла, которые удалось разобрать
+}
+
+// RowError ошибка в строке файла, Row - номер строки с учетом заголовка
+type RowError struct {
+	Row     int
+	Message string
+}
+
+// ImportedRow разобранная строка файла
+type ImportedRow struct {
+	Row       int
+	Datetime  time.Time
+	Amount    float64
+	Currency  string
+	Category  string // после применения правил пользователя
+	Duplicate bool
+}
+
+type importer struct {
+	processor    expense_processor.ExpenseProcessor
+	settingsRepo repo.UserSettingsRepository
+	converter    serviceconverter.Converter
+}
+
+func NewImporter(
+	processor expense_processor.ExpenseProcessor,
+	settingsRepo repo.UserSettingsRepository,
+	conv serviceconverter.Converter,
+) ExpenseImporter {
+	return &importer{
+		processor:    processor,
+		settingsRepo: settingsRepo,
+		converter:    conv,
+	}
+}
+
+func (i *importer) Import(ctx context.Context, data []byte, options ImportOptions) (*ImportResult, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "ExpenseImporter_Import")
+	defer span.Finish()
+
+	settings, found, err := i.settingsRepo.GetSettings(ctx, options.UserID)
+	if err != nil {
+		return nil, errors.Wrap(err, errImportMessage)
+	}
+	if !found {
+		settings = model.DefaultUserSettings(options.UserID)
+	}
+
+	records, err := readRecords(data)
+	if err != nil {
+		return nil, err
+	}
+
+	columns, err := resolveColumns(records[0].fields, options.Mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ImportResult{DryRun: options.DryRun}
+	rows := make([]ImportedRow, 0, len(records)-1)
+	for _, record := range records[1:] {
+		if record.err != nil {
+			result.Errors = append(result.Errors, RowError{Row: record.line, Message: fmt.Sprintf(errRowFormatMessage, record.err.Error())})
+			continue
+		}
+
+		if isEmptyRecord(record.fields) {
+			continue
+		}
+
+		row, err := i.parseRow(record, columns, settings)
+		if err != nil {
+			result.Errors = append(result.Errors, RowError{Row: record.line, Message: err.Error()})
+			continue
+		}
+		rows = append(rows, row)
+	}
+	result.Rows = len(rows) + len(result.Errors)
+
+	if result.Rows == 0 {
+		return nil, errors.New(errEmptyFileMessage)
+	}
+
+	items := make([]expense_processor.ImportItem, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, expense_processor.ImportItem{
+			Amount:   row.Amount,
+			Currency: row.Currency,
+			Category: row.Category,
+			Datetime: row.Datetime,
+		})
+	}
+
+	imported, err := i.processor.ImportExpenses(ctx, items, options.BudgetID, options.UserID, options.DryRun)
+	if err != nil {
+		return nil, errors.Wrap(err, errImportMessage)
+	}
+
+	for n, item := range imported {
+		rows[n].Category = item.Category
+		rows[n].Duplicate = item.Duplicate
+
+		if item.Duplicate {
+			result.Duplicates++
+		} else {
+			result.Added++
+		}
+	}
+
+	if len(rows) > previewSize {
+		rows = rows[:previewSize]
+	}
+	result.Preview = rows
+
+	return result, nil
+}
+
+// columnIndexes номера колонок полей траты в строке, -1 - колонки нет
+type columnIndexes struct {
+	date     int
+	amount   int
+	category int
+	currency int
+}
+
+// resolveColumns находит колонки полей по заголовку файла, колонка валюты необязательна
+func resolveColumns(header []string, mapping ColumnMapping) (columnIndexes, error) {
+	var columns columnIndexes
+	var err error
+
+	if columns.date, err = findColumn(header, mapping.Date); err != nil {
+		return columnIndexes{}, err
+	}
+	if columns.amount, err = findColumn(header, mapping.Amount); err != nil {
+		return columnIndexes{}, err
+	}
+	if columns.category, err = findColumn(header, mapping.Category); err != nil {
+		return columnIndexes{}, err
+	}
+	if columns.currency, err = findColumn(header, mapping.Currency); err != nil {
+		columns.currency = -1
+	}
+
+	return columns, nil
+}
+
+func findColumn(header []string, column string) (int, error) {
+	if number, err := strconv.Atoi(column); err == nil {
+		if number < 1 || number > len(header) {
+			return 0, fmt.Errorf(errColumnNumberMessage, number)
+		}
+		return number - 1, nil
+	}
+
+	for n, name := range header {
+		if strings.EqualFold(strings.Trim(name, " "), column) {
+			return n, nil
+		}
+	}
+
+	return 0, fmt.Errorf(errColumnNotFoundMessage, column)
+}
+
+func (i *importer) parseRow(record csvRecord, columns columnIndexes, settings model.UserSettings) (ImportedRow, error) {
+	field := func(index int, name string) (string, error) {
+		if index >= len(record.fields) {
+			return "", fmt.Errorf(errRowMissingFieldMessage, name)
+		}
+		return strings.Trim(record.fields[index], " "), nil
+	}
+
+	rawDate, err := field(columns.date, "date")
+	if err != nil {
+		return ImportedRow{}, err
+	}
+	datetime, err := parseDate(rawDate, settings.Location())
+	if err != nil {
+		return ImportedRow{}, err
+	}
+
+	rawAmount, err := field(columns.amount, "amount")
+	if err != nil {
+		return ImportedRow{}, err
+	}
+	amount, err := parseAmount(rawAmount)
+	if err != nil {
+		return ImportedRow{}, err
+	}
+
+	category, err := field(columns.category, "category")
+	if err != nil {
+		return ImportedRow{}, err
+	}
+	if category == "" {
+		return ImportedRow{}, errors.New(errRowCategoryMessage)
+	}
+
+	currency := settings.Currency
+	if columns.currency >= 0 && columns.currency < len(record.fields) {
+		if raw := strings.ToUpper(strings.Trim(record.fields[columns.currency], " ")); raw != "" {
+			currency = raw
+		}
+	}
+	if _, ok := i.converter.GetAvailableCurrencies()[currency]; !ok {
+		return ImportedRow{}, fmt.Errorf(errRowCurrencyMessage, currency)
+	}
+
+	return ImportedRow{
+		Row:      record.line,
+		Datetime: datetime,
+		Amount:   amount,
+		Currency: currency,
+		Category: category,
+	}, nil
+}
+
+// parseDate разбирает дату в часовом поясе пользователя, если в ней нет своего
+func parseDate(raw string, loc *time.Location) (time.Time, error) {
+	for _, layout := range dateLayouts {
+		if datetime, err := time.ParseInLocation(layout, raw, loc); err == nil {
+			return datetime.In(loc), nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf(errRowDateMessage, raw)
+}
+
+// parseAmount разбирает сумму с пробелами между разрядами и десятичной запятой.
+// В выписках списания отрицательные, поэтому знак не учитывается
+func parseAmount(raw string) (float64, error) {
+	amount := strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '\u00a0', '\u202f', '\'':
+			return -1
+		}
+		return r
+	}, raw)
+
+	// десятичный разделитель - последний из точки и запятой, второй разделяет разряды
+	if strings.LastIndex(amount, ",") > strings.LastIndex(amount, ".") {
+		amount = strings.ReplaceAll(amount, ".", "")
+		amount = strings.Replace(amount, ",", ".", 1)
+	} else {
+		amount = strings.ReplaceAll(amount, ",", "")
+	}
+
+	value, err := strconv.ParseFloat(amount, 64)
+	if err != nil {
+		return 0, fmt.Errorf(errRowAmountMessage, raw)
+	}
+
+	if value < 0 {
+		value = -value
+	}
+	if value == 0 {
+		return 0, errors.New(errRowZeroAmountMessage)
+	}
+
+	return value, nil
+}
+
+func isEmptyRecord(fields []string) bool {
+	for _, field := range fields {
+		if strings.Trim(field, " ") != "" {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,134 @@
+package expense_importer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/opentracing/opentracing-go"
+	"github.com/stretchr/testify/assert"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/clients/exchangerate"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/model"
+	repomocks "gitlab.ozon.dev/cranky4/tg-bot/internal/repository/mocks"
+	serviceconverter "gitlab.ozon.dev/cranky4/tg-bot/internal/service/converter"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/service/expense_processor"
+	exp_processor_mock "gitlab.ozon.dev/cranky4/tg-bot/internal/service/expense_processor/mocks"
+)
+
+type testGetter struct{}
+
+func (g *testGetter) Get(ctx context.Context) (*exchangerate.ExchangeResponse, error) {
+	return &exchangerate.ExchangeResponse{
+		Rates: exchangerate.Rates{
+			USD: 2,
+			EUR: 3,
+			CNY: 4,
+		},
+	}, nil
+}
+
+var testConverter = serviceconverter.NewConverter(&testGetter{})
+
+func TestImportBankStatementShouldReportRowErrors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	_, wrapedCtx := opentracing.StartSpanFromContext(ctx, "ExpenseImporter_Import")
+	userId := int64(100)
+	budgetId := int64(-5)
+
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+	settingsRepo.EXPECT().GetSettings(wrapedCtx, userId).Return(model.UserSettings{UserID: userId, Currency: "USD"}, true, nil)
+
+	processor := exp_processor_mock.NewMockExpenseProcessor(ctrl)
+	processor.EXPECT().ImportExpenses(wrapedCtx, []expense_processor.ImportItem{
+		{Amount: 1234.5, Currency: "USD", Category: "Супермаркет", Datetime: time.Date(2022, 10, 1, 12, 30, 0, 0, time.Local)},
+		{Amount: 350, Currency: "RUB", Category: "Кафе", Datetime: time.Date(2022, 10, 2, 0, 0, 0, 0, time.Local)},
+	}, budgetId, userId, false).Return([]expense_processor.ImportItemResult{
+		{Category: "Продукты"},
+		{Category: "Кафе", Duplicate: true},
+	}, nil)
+
+	data := "Дата операции;Описание;Сумма операции;Валюта\n" +
+		"01.10.2022 12:30;Супермаркет;-1 234,50;\n" +
+		"02.10.2022;Кафе;350;rub\n" +
+		"\n" +
+		"вчера;Такси;200;RUB\n" +
+		"03.10.2022;;200;RUB\n" +
+		"03.10.2022;Такси;0;RUB\n" +
+		"03.10.2022;Такси;200;BTC\n"
+
+	mapping, err := ParseColumnMapping("date=Дата операции; category=2; amount=Сумма операции")
+	assert.NoError(t, err)
+
+	importer := NewImporter(processor, settingsRepo, testConverter)
+	result, err := importer.Import(ctx, []byte(data), ImportOptions{UserID: userId, BudgetID: budgetId, Mapping: mapping})
+	assert.NoError(t, err)
+	assert.Equal(t, &ImportResult{
+		Rows:       6,
+		Added:      1,
+		Duplicates: 1,
+		Errors: []RowError{
+			{Row: 5, Message: "не удалось распознать дату вчера"},
+			{Row: 6, Message: "не указана категория"},
+			{Row: 7, Message: "сумма должна быть больше нуля"},
+			{Row: 8, Message: "неизвестная валюта BTC"},
+		},
+		Preview: []ImportedRow{
+			{Row: 2, Datetime: time.Date(2022, 10, 1, 12, 30, 0, 0, time.Local), Amount: 1234.5, Currency: "USD", Category: "Продукты"},
+			{Row: 3, Datetime: time.Date(2022, 10, 2, 0, 0, 0, 0, time.Local), Amount: 350, Currency: "RUB", Category: "Кафе", Duplicate: true},
+		},
+	}, result)
+}
+
+func TestImportShouldReadExportFileOnDryRun(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	_, wrapedCtx := opentracing.StartSpanFromContext(ctx, "ExpenseImporter_Import")
+	userId := int64(100)
+
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+	settingsRepo.EXPECT().GetSettings(wrapedCtx, userId)
+
+	processor := exp_processor_mock.NewMockExpenseProcessor(ctrl)
+	processor.EXPECT().ImportExpenses(wrapedCtx, []expense_processor.ImportItem{
+		{Amount: 125.5, Currency: "RUB", Category: `Дом, "ремонт"`, Datetime: time.Date(2022, 10, 1, 12, 56, 0, 0, time.Local)},
+	}, userId, userId, true).Return([]expense_processor.ImportItemResult{{Category: `Дом, "ремонт"`}}, nil)
+
+	data := "\ufeffДата,Категория,Сумма,Валюта,Счет,ИД\n" +
+		`2022-10-01 12:56:00,"Дом, ""ремонт""",125.50,RUB,,1` + "\n"
+
+	importer := NewImporter(processor, settingsRepo, testConverter)
+	result, err := importer.Import(ctx, []byte(data), ImportOptions{UserID: userId, BudgetID: userId, Mapping: DefaultColumnMapping, DryRun: true})
+	assert.NoError(t, err)
+	assert.True(t, result.DryRun)
+	assert.Equal(t, 1, result.Rows)
+	assert.Equal(t, 1, result.Added)
+	assert.Len(t, result.Preview, 1)
+}
+
+func TestImportShouldFailWithoutMappedColumn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	_, wrapedCtx := opentracing.StartSpanFromContext(ctx, "ExpenseImporter_Import")
+	userId := int64(100)
+
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+	settingsRepo.EXPECT().GetSettings(wrapedCtx, userId)
+
+	importer := NewImporter(exp_processor_mock.NewMockExpenseProcessor(ctrl), settingsRepo, testConverter)
+	_, err := importer.Import(ctx, []byte("Дата;Описание;Сумма\n2022-10-01;Кафе;100\n"), ImportOptions{UserID: userId, BudgetID: userId, Mapping: DefaultColumnMapping})
+	assert.EqualError(t, err, "в файле нет колонки Категория")
+}
+
+func TestParseColumnMappingShouldRejectUnknownFields(t *testing.T) {
+	_, err := ParseColumnMapping("date=Дата;comment=Комментарий")
+	assert.EqualError(t, err, "неизвестное поле comment, доступны: date, amount, category, currency")
+
+	_, err = ParseColumnMapping("date")
+	assert.Error(t, err)
+
+	mapping, err := ParseColumnMapping("")
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultColumnMapping, mapping)
+}
@@ -0,0 +1,51 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/service/expense_importer/expense_importer.go
+
+// Package mock_expense_importer is a generated GoMock package.
+package mock_expense_importer
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	expense_importer "gitlab.ozon.dev/cranky4/tg-bot/internal/service/expense_importer"
+)
+
+// MockExpenseImporter is a mock of ExpenseImporter interface.
+type MockExpenseImporter struct {
+	ctrl     *gomock.Controller
+	recorder *MockExpenseImporterMockRecorder
+}
+
+// MockExpenseImporterMockRecorder is the mock recorder for MockExpenseImporter.
+type MockExpenseImporterMockRecorder struct {
+	mock *MockExpenseImporter
+}
+
+// NewMockExpenseImporter creates a new mock instance.
+func NewMockExpenseImporter(ctrl *gomock.Controller) *MockExpenseImporter {
+	mock := &MockExpenseImporter{ctrl: ctrl}
+	mock.recorder = &MockExpenseImporterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExpenseImporter) EXPECT() *MockExpenseImporterMockRecorder {
+	return m.recorder
+}
+
+// Import mocks base method.
+func (m *MockExpenseImporter) Import(ctx context.Context, data []byte, options expense_importer.ImportOptions) (*expense_importer.ImportResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Import", ctx, data, options)
+	ret0, _ := ret[0].(*expense_importer.ImportResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Import indicates an expected call of Import.
+func (mr *MockExpenseImporterMockRecorder) Import(ctx, data, options interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Import", reflect.TypeOf((*MockExpenseImporter)(nil).Import), ctx, data, options)
+}
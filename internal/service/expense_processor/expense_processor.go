@@ -2,97 +2,1087 @@ package expense_processor
 
 import (
 	"context"
-	"fmt"
+	"math"
 	"strings"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/opentracing/opentracing-go"
 	"github.com/pkg/errors"
+	blobstorage "gitlab.ozon.dev/cranky4/tg-bot/internal/clients/blob_storage"
 	"gitlab.ozon.dev/cranky4/tg-bot/internal/model"
 	repo "gitlab.ozon.dev/cranky4/tg-bot/internal/repository"
 	"gitlab.ozon.dev/cranky4/tg-bot/internal/service/cache"
 	serviceconverter "gitlab.ozon.dev/cranky4/tg-bot/internal/service/converter"
+	limitnotifier "gitlab.ozon.dev/cranky4/tg-bot/internal/service/limit_notifier"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/service/logger"
 )
 
 const (
 	primitiveCurrencyMultiplier = 100
+	importBatchSize             = 100
 
-	errSaveExpenseMessage = "ошибка сохранения траты"
-	errSetLimitMessage    = "ошибка создания лимита"
+	errSaveExpenseMessage    = "ошибка сохранения траты"
+	errUpdateExpenseMessage  = "ошибка изменения траты"
+	errDeleteExpenseMessage  = "ошибка удаления траты"
+	errDeleteReceiptMessage  = "ошибка удаления чека траты"
+	errListExpensesMessage   = "ошибка получения списка трат"
+	errFreeLimitsMessage     = "ошибка получения остатка лимитов"
+	errSetLimitThresholdsMsg = "ошибка изменения порогов уведомлений лимита"
+	errNotifyLimitsMessage   = "ошибка уведомления о лимитах"
+	errSetLimitMessage       = "ошибка создания лимита"
+	errTopCategoriesMessage  = "ошибка получения популярных категорий"
+	errCategoriesMessage     = "ошибка получения категорий"
+	errRenameCategoryMsg     = "ошибка переименования категории"
+	errMergeCategoriesMsg    = "ошибка объединения категорий"
+	errDeleteCategoryMsg     = "ошибка удаления категории"
+	errCategoryRulesMessage  = "ошибка получения правил категорий"
+	errSaveCategoryRuleMsg   = "ошибка сохранения правила категории"
+	errDeleteCategoryRuleMsg = "ошибка удаления правила категории"
+	errSaveIncomeMessage     = "ошибка сохранения дохода"
+	errAddAccountMessage     = "ошибка создания счета"
+	errTransferMessage       = "ошибка перевода между счетами"
+	errBalancesMessage       = "ошибка получения остатков на счетах"
+	errSplitExpenseMessage   = "ошибка разделения траты"
+	errSettleDebtMessage     = "ошибка возврата долга"
+	errDebtsMessage          = "ошибка расчета долгов"
+	errImportExpensesMessage = "ошибка импорта трат"
 )
 
 type ExpenseProcessor interface {
-	AddExpense(ctx context.Context, amount float64, currency string, category string, datetime time.Time, userId int64) (*model.Expense, error)
-	GetFreeLimit(ctx context.Context, category, currency string, userId int64) (float64, bool, error)
-	SetLimit(ctx context.Context, category string, userId int64, amount float64, currency string) (float64, error)
+	// AddExpense добавляет трату в бюджет userId от имени authorId, для траты без счета account пустой
+	AddExpense(
+		ctx context.Context,
+		amount float64,
+		currency string,
+		category string,
+		account string,
+		datetime time.Time,
+		userId int64,
+		authorId int64,
+	) (*model.Expense, error)
+	// AddRecurringExpense добавляет трату регулярной траты за время occurrence. Если трата за это время уже добавлена,
+	// возвращает false, поэтому добавление можно повторять после сбоя
+	AddRecurringExpense(ctx context.Context, recurring model.RecurringExpense, occurrence time.Time) (*model.Expense, bool, error)
+	UpdateExpense(ctx context.Context, id string, amount float64, currency string, category string, datetime time.Time, userId int64) (*model.Expense, bool, error)
+	DeleteExpense(ctx context.Context, id string, userId int64) (bool, error)
+	ListExpenses(ctx context.Context, dateRange model.DateRange, currency string, userId int64) ([]ExpenseItem, error)
+	GetFreeLimits(ctx context.Context, category string, userId int64, settings model.UserSettings, now time.Time) ([]FreeLimit, error)
+	SetLimit(ctx context.Context, category string, period model.ExpensePeriod, userId int64, amount float64, currency string) (float64, error)
+	SetLimitThresholds(ctx context.Context, category string, period model.ExpensePeriod, userId int64, thresholds []int) (bool, error)
+	GetTopCategories(ctx context.Context, userId int64, limit int) ([]string, error)
+	GetCategories(ctx context.Context, userId int64) ([]string, error)
+	RenameCategory(ctx context.Context, userId int64, name, newName string) (bool, error)
+	MergeCategories(ctx context.Context, userId int64, from, to string) (bool, error)
+	DeleteCategory(ctx context.Context, userId int64, name string) (bool, error)
+	SaveCategoryRule(ctx context.Context, rule model.CategoryRule) error
+	GetCategoryRules(ctx context.Context, userId int64) ([]model.CategoryRule, error)
+	DeleteCategoryRule(ctx context.Context, userId int64, pattern string) (bool, error)
+	AddIncome(ctx context.Context, amount float64, currency string, source string, datetime time.Time, userId int64) (*model.Income, error)
+	AddAccount(ctx context.Context, name string, currency string, openingBalance float64, userId int64) (*model.Account, error)
+	Transfer(ctx context.Context, from, to string, amount float64, currency string, datetime time.Time, userId int64) error
+	GetBalances(ctx context.Context, userId int64) ([]AccountBalance, error)
+	// SplitExpense добавляет в бюджет userId трату, которую оплатил payerId, и делит ее поровну между участниками
+	SplitExpense(
+		ctx context.Context,
+		amount float64,
+		currency string,
+		category string,
+		datetime time.Time,
+		userId int64,
+		payerId int64,
+		participants []int64,
+	) (*model.Expense, []Share, error)
+	// SettleDebt записывает возврат долга участником fromUserId участнику toUserId
+	SettleDebt(ctx context.Context, amount float64, currency string, datetime time.Time, userId, fromUserId, toUserId int64) error
+	// GetDebts возвращает переводы, закрывающие долги участников бюджета userId
+	GetDebts(ctx context.Context, currency string, userId int64) ([]Debt, error)
+	// ImportExpenses добавляет траты в бюджет userId от имени authorId пачками, пропуская дубли.
+	// При dryRun ничего не сохраняет, только возвращает результат по каждой трате
+	ImportExpenses(ctx context.Context, items []ImportItem, userId int64, authorId int64, dryRun bool) ([]ImportItemResult, error)
+}
+
+// ExpenseItem трата с суммой в валюте пользователя
+type ExpenseItem struct {
+	ID       string
+	Amount   float64
+	Category string
+	Datetime time.Time
+}
+
+// FreeLimit остаток лимита в валюте пользователя
+type FreeLimit struct {
+	Scope    model.LimitScope
+	Period   model.ExpensePeriod
+	Category string
+	Amount   float64
+	Free     float64
+}
+
+// AccountBalance остаток на счете в валюте счета
+type AccountBalance struct {
+	Name     string
+	Currency string
+	Balance  float64
+}
+
+// Share доля участника в трате в валюте пользователя
+type Share struct {
+	UserID int64
+	Amount float64
+}
+
+// ImportItem трата из файла импорта в валюте Currency
+type ImportItem struct {
+	Amount   float64
+	Currency string
+	Category string
+	Datetime time.Time
+}
+
+// ImportItemResult итог импорта траты
+type ImportItemResult struct {
+	Category  string // категория после применения правил пользователя
+	Duplicate bool   // трата с той же датой, суммой и категорией уже есть в бюджете
+}
+
+// Debt перевод от должника кредитору в валюте пользователя
+type Debt struct {
+	FromUserID int64
+	ToUserID   int64
+	Amount     float64
 }
 
 type processor struct {
-	repo      repo.ExpensesRepository
-	converter serviceconverter.Converter
-	cache     cache.Cache
+	repo            repo.ExpensesRepository
+	incomesRepo     repo.IncomesRepository
+	splitsRepo      repo.SplitsRepository
+	rulesRepo       repo.CategoryRulesRepository
+	settingsRepo    repo.UserSettingsRepository
+	attachmentsRepo repo.AttachmentsRepository
+	storage         blobstorage.BlobStorage
+	converter       serviceconverter.Converter
+	cache           cache.Cache
+	notifier        limitnotifier.LimitNotifier
 }
 
-func NewProcessor(repo repo.ExpensesRepository, conv serviceconverter.Converter, cache cache.Cache) ExpenseProcessor {
+func NewProcessor(
+	repo repo.ExpensesRepository,
+	incomesRepo repo.IncomesRepository,
+	splitsRepo repo.SplitsRepository,
+	rulesRepo repo.CategoryRulesRepository,
+	settingsRepo repo.UserSettingsRepository,
+	attachmentsRepo repo.AttachmentsRepository,
+	storage blobstorage.BlobStorage,
+	conv serviceconverter.Converter,
+	cache cache.Cache,
+	notifier limitnotifier.LimitNotifier,
+) ExpenseProcessor {
 	return &processor{
-		repo:      repo,
-		converter: conv,
-		cache:     cache,
+		repo:            repo,
+		incomesRepo:     incomesRepo,
+		splitsRepo:      splitsRepo,
+		rulesRepo:       rulesRepo,
+		settingsRepo:    settingsRepo,
+		attachmentsRepo: attachmentsRepo,
+		storage:         storage,
+		converter:       conv,
+		cache:           cache,
+		notifier:        notifier,
 	}
 }
 
-func (p *processor) AddExpense(ctx context.Context, amount float64, currency string, category string, datetime time.Time, userId int64) (*model.Expense, error) {
+func (p *processor) AddExpense(
+	ctx context.Context,
+	amount float64,
+	currency string,
+	category string,
+	account string,
+	datetime time.Time,
+	userId int64,
+	authorId int64,
+) (*model.Expense, error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "AddExpense")
 	defer span.Finish()
 
+	// ИД известен до сохранения, чтобы прикрепить к трате чек
+	return p.addExpense(ctx, model.Expense{
+		ID:       uuid.NewString(),
+		Amount:   toPrimitive(p.converter.ToRUB(amount, currency)),
+		Category: category,
+		Account:  strings.Trim(account, " "),
+		Datetime: datetime,
+		UserId:   userId,
+		AuthorId: authorId,
+	})
+}
+
+// addExpense сохраняет трату с категорией по правилам пользователя и уведомляет о лимитах
+func (p *processor) addExpense(ctx context.Context, ex model.Expense) (*model.Expense, error) {
+	category, err := p.applyCategoryRules(ctx, ex.UserId, strings.Trim(ex.Category, " "))
+	if err != nil {
+		return nil, errors.Wrap(err, errSaveExpenseMessage)
+	}
+	ex.Category = category
+
+	if err := p.repo.Add(ctx, ex); err != nil {
+		return nil, errors.Wrap(err, errSaveExpenseMessage)
+	}
+
+	if err := p.expenseAdded(ctx, ex); err != nil {
+		return nil, err
+	}
+
+	return &ex, nil
+}
+
+func (p *processor) AddRecurringExpense(
+	ctx context.Context,
+	recurring model.RecurringExpense,
+	occurrence time.Time,
+) (*model.Expense, bool, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "AddRecurringExpense")
+	defer span.Finish()
+
+	category, err := p.applyCategoryRules(ctx, recurring.UserId, strings.Trim(recurring.Category, " "))
+	if err != nil {
+		return nil, false, errors.Wrap(err, errSaveExpenseMessage)
+	}
+
+	ex := model.Expense{
+		ID:       uuid.NewString(),
+		Amount:   toPrimitive(p.converter.ToRUB(float64(recurring.Amount)/primitiveCurrencyMultiplier, recurring.Currency)),
+		Category: category,
+		Datetime: occurrence,
+		UserId:   recurring.UserId,
+		AuthorId: recurring.UserId,
+	}
+
+	added, err := p.repo.AddOccurrence(ctx, ex, recurring.ID, occurrence)
+	if err != nil {
+		return nil, false, errors.Wrap(err, errSaveExpenseMessage)
+	}
+	if !added {
+		return nil, false, nil
+	}
+
+	if err := p.expenseAdded(ctx, ex); err != nil {
+		return nil, false, err
+	}
+
+	return &ex, true, nil
+}
+
+// expenseAdded сбрасывает кеш отчетов после добавления траты и уведомляет о лимитах
+func (p *processor) expenseAdded(ctx context.Context, ex model.Expense) error {
+	if err := p.resetReportsCache(ctx, ex.UserId); err != nil {
+		return err
+	}
+
+	// трата уже сохранена, ошибка уведомления не должна ее отменять
+	if err := p.notifyLimitThresholds(ctx, ex); err != nil {
+		logger.Error(errNotifyLimitsMessage, logger.LogDataItem{Key: "error", Value: err.Error()})
+	}
+
+	return nil
+}
+
+func (p *processor) UpdateExpense(ctx context.Context, id string, amount float64, currency string, category string, datetime time.Time, userId int64) (*model.Expense, bool, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "UpdateExpense")
+	defer span.Finish()
+
 	convertedAmount := p.converter.ToRUB(amount, currency)
 
 	ex := model.Expense{
-		Amount:   int64(convertedAmount * primitiveCurrencyMultiplier),
+		ID:       id,
+		Amount:   toPrimitive(convertedAmount),
 		Category: strings.Trim(category, " "),
 		Datetime: datetime,
 		UserId:   userId,
 	}
 
-	if err := p.repo.Add(ctx, ex); err != nil {
-		return nil, errors.Wrap(err, errSaveExpenseMessage)
+	// доли разделенной траты пересчитываются от новой суммы и сохраняются вместе с тратой
+	shares, err := p.resplitExpense(ctx, ex)
+	if err != nil {
+		return nil, false, errors.Wrap(err, errUpdateExpenseMessage)
 	}
 
-	// сбрасываем кеш при добавлении новой траты
-	for _, period := range []model.ExpensePeriod{model.Week, model.Month, model.Year} {
-		cacheKey := fmt.Sprintf("%d-%v-%s", userId, period, time.Now().Format("2006-01-02"))
-		_, err := p.cache.Del(ctx, cacheKey)
-		if err != nil {
-			return nil, err
+	found, err := p.repo.Update(ctx, ex, shares)
+	if err != nil {
+		return nil, false, errors.Wrap(err, errUpdateExpenseMessage)
+	}
+
+	if !found {
+		return nil, false, nil
+	}
+
+	if err := p.resetReportsCache(ctx, userId); err != nil {
+		return nil, false, err
+	}
+
+	return &ex, true, nil
+}
+
+// resplitExpense возвращает доли разделенной траты, пересчитанные от новой суммы, для неразделенной траты - nil
+func (p *processor) resplitExpense(ctx context.Context, ex model.Expense) ([]model.ExpenseShare, error) {
+	shares, err := p.splitsRepo.GetShares(ctx, ex.ID)
+	if err != nil || len(shares) == 0 {
+		return nil, err
+	}
+
+	participants := make([]int64, 0, len(shares))
+	for _, share := range shares {
+		participants = append(participants, share.UserID)
+	}
+
+	return newExpenseShares(ex.ID, ex.Amount, shares[0].BudgetID, shares[0].PayerID, participants), nil
+}
+
+func (p *processor) DeleteExpense(ctx context.Context, id string, userId int64) (bool, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "DeleteExpense")
+	defer span.Finish()
+
+	// запись о чеке удаляется вместе с тратой, ключ файла нужен до удаления
+	attachment, hasAttachment, err := p.attachmentsRepo.GetAttachment(ctx, id, userId)
+	if err != nil {
+		return false, errors.Wrap(err, errDeleteExpenseMessage)
+	}
+
+	found, err := p.repo.Delete(ctx, id, userId)
+	if err != nil {
+		return false, errors.Wrap(err, errDeleteExpenseMessage)
+	}
+
+	if !found {
+		return false, nil
+	}
+
+	if err := p.splitsRepo.DeleteShares(ctx, id); err != nil {
+		return false, errors.Wrap(err, errDeleteExpenseMessage)
+	}
+
+	// трата уже удалена, оставшийся в хранилище файл не повод сообщать пользователю об ошибке
+	if hasAttachment {
+		if err := p.storage.Delete(ctx, attachment.Key); err != nil {
+			logger.Error(errDeleteReceiptMessage, logger.LogDataItem{Key: "error", Value: err.Error()})
 		}
 	}
 
-	return &ex, nil
+	if err := p.resetReportsCache(ctx, userId); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (p *processor) ListExpenses(ctx context.Context, dateRange model.DateRange, currency string, userId int64) ([]ExpenseItem, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "ListExpenses")
+	defer span.Finish()
+
+	expenses, err := p.repo.GetExpenses(ctx, dateRange, userId)
+	if err != nil {
+		return nil, errors.Wrap(err, errListExpensesMessage)
+	}
+
+	items := make([]ExpenseItem, 0, len(expenses))
+	for _, e := range expenses {
+		items = append(items, ExpenseItem{
+			ID:       e.ID,
+			Amount:   p.converter.FromRUB(float64(e.Amount), currency) / primitiveCurrencyMultiplier,
+			Category: e.Category,
+			Datetime: e.Datetime,
+		})
+	}
+
+	return items, nil
+}
+
+// AddIncome сохраняет доход в рублях, доходы учитываются в отчетах
+func (p *processor) AddIncome(ctx context.Context, amount float64, currency string, source string, datetime time.Time, userId int64) (*model.Income, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "AddIncome")
+	defer span.Finish()
+
+	convertedAmount := p.converter.ToRUB(amount, currency)
+
+	income, err := p.incomesRepo.AddIncome(ctx, model.Income{
+		Amount:   toPrimitive(convertedAmount),
+		Source:   strings.Trim(source, " "),
+		Datetime: datetime,
+		UserId:   userId,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, errSaveIncomeMessage)
+	}
+
+	if err := p.resetReportsCache(ctx, userId); err != nil {
+		return nil, err
+	}
+
+	return &income, nil
+}
+
+// AddAccount создает счет, начальный баланс указывается в валюте счета
+func (p *processor) AddAccount(ctx context.Context, name string, currency string, openingBalance float64, userId int64) (*model.Account, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "AddAccount")
+	defer span.Finish()
+
+	account, err := p.repo.AddAccount(ctx, model.Account{
+		Name:           strings.Trim(name, " "),
+		Currency:       currency,
+		OpeningBalance: toPrimitive(p.converter.ToRUB(openingBalance, currency)),
+		UserId:         userId,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, errAddAccountMessage)
+	}
+
+	return &account, nil
+}
+
+func (p *processor) Transfer(ctx context.Context, from, to string, amount float64, currency string, datetime time.Time, userId int64) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "Transfer")
+	defer span.Finish()
+
+	err := p.repo.AddTransfer(ctx, model.Transfer{
+		FromAccount: strings.Trim(from, " "),
+		ToAccount:   strings.Trim(to, " "),
+		Amount:      toPrimitive(p.converter.ToRUB(amount, currency)),
+		Datetime:    datetime,
+		UserId:      userId,
+	})
+	if err != nil {
+		return errors.Wrap(err, errTransferMessage)
+	}
+
+	return nil
 }
 
-func (p *processor) GetFreeLimit(ctx context.Context, category, currency string, userId int64) (float64, bool, error) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "GetFreeLimit")
+// GetBalances возвращает остатки на счетах в валютах счетов
+func (p *processor) GetBalances(ctx context.Context, userId int64) ([]AccountBalance, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "GetBalances")
 	defer span.Finish()
 
-	freeLimit, hasLimit, err := p.repo.GetFreeLimit(ctx, strings.Trim(category, " "), userId)
+	balances, err := p.repo.GetAccountBalances(ctx, userId)
 	if err != nil {
-		return 0, false, errors.Wrap(err, errSaveExpenseMessage)
+		return nil, errors.Wrap(err, errBalancesMessage)
+	}
+
+	items := make([]AccountBalance, 0, len(balances))
+	for _, balance := range balances {
+		items = append(items, AccountBalance{
+			Name:     balance.Account.Name,
+			Currency: balance.Account.Currency,
+			Balance:  p.converter.FromRUB(float64(balance.Balance), balance.Account.Currency) / primitiveCurrencyMultiplier,
+		})
 	}
 
-	convertedFreeLimit := p.converter.FromRUB(float64(freeLimit), currency)
+	return items, nil
+}
+
+// resetReportsCache сбрасывает закешированные отчеты пользователя
+func (p *processor) resetReportsCache(ctx context.Context, userId int64) error {
+	_, err := p.cache.Del(ctx, cache.ReportsVersionKey(userId))
+
+	return err
+}
+
+// GetFreeLimits возвращает остатки лимитов категории и общих лимитов бюджета userId.
+// Границы периодов вычисляются от now по настройкам пользователя
+func (p *processor) GetFreeLimits(
+	ctx context.Context,
+	category string,
+	userId int64,
+	settings model.UserSettings,
+	now time.Time,
+) ([]FreeLimit, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "GetFreeLimits")
+	defer span.Finish()
+
+	limits, err := p.repo.GetLimits(ctx, strings.Trim(category, " "), userId)
+	if err != nil {
+		return nil, errors.Wrap(err, errFreeLimitsMessage)
+	}
+
+	model.SortLimits(limits)
+
+	freeLimits := make([]FreeLimit, 0, len(limits))
+	for _, limit := range limits {
+		free, err := p.repo.GetFreeLimit(ctx, limit, limit.Period.GetPeriodRange(now, settings.PeriodMode, settings.WeekStart))
+		if err != nil {
+			return nil, errors.Wrap(err, errFreeLimitsMessage)
+		}
+
+		freeLimits = append(freeLimits, FreeLimit{
+			Scope:    limit.Scope,
+			Period:   limit.Period,
+			Category: limit.Category,
+			Amount:   p.converter.FromRUB(float64(limit.Amount), settings.Currency) / primitiveCurrencyMultiplier,
+			Free:     p.converter.FromRUB(float64(free), settings.Currency) / primitiveCurrencyMultiplier,
+		})
+	}
 
-	return convertedFreeLimit / primitiveCurrencyMultiplier, hasLimit, nil
+	return freeLimits, nil
 }
 
-func (p *processor) SetLimit(ctx context.Context, category string, userId int64, amount float64, currency string) (float64, error) {
+// SetLimit устанавливает лимит категории на период, для пустой категории - общий лимит на все траты
+func (p *processor) SetLimit(ctx context.Context, category string, period model.ExpensePeriod, userId int64, amount float64, currency string) (float64, error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "SetLimit")
 	defer span.Finish()
 
 	convertedAmount := p.converter.ToRUB(amount, currency)
 
-	if err := p.repo.SetLimit(ctx, category, userId, int64(convertedAmount*primitiveCurrencyMultiplier)); err != nil {
+	limit := model.ExpenseLimit{
+		Scope:    model.CategoryLimitScope,
+		Period:   period,
+		Category: strings.Trim(category, " "),
+		Amount:   toPrimitive(convertedAmount),
+		UserId:   userId,
+	}
+	if limit.Category == "" {
+		limit.Scope = model.TotalLimitScope
+	}
+
+	if err := p.repo.SetLimit(ctx, limit); err != nil {
 		return 0, errors.Wrap(err, errSetLimitMessage)
 	}
 
+	// отчеты показывают расход лимитов категорий
+	if err := p.resetReportsCache(ctx, userId); err != nil {
+		return 0, err
+	}
+
 	return convertedAmount, nil
 }
+
+// SetLimitThresholds изменяет пороги уведомлений лимита категории, для пустой категории - общего лимита
+func (p *processor) SetLimitThresholds(ctx context.Context, category string, period model.ExpensePeriod, userId int64, thresholds []int) (bool, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "SetLimitThresholds")
+	defer span.Finish()
+
+	limit := model.ExpenseLimit{
+		Scope:      model.CategoryLimitScope,
+		Period:     period,
+		Category:   strings.Trim(category, " "),
+		Thresholds: thresholds,
+		UserId:     userId,
+	}
+	if limit.Category == "" {
+		limit.Scope = model.TotalLimitScope
+	}
+
+	found, err := p.repo.SetLimitThresholds(ctx, limit)
+	if err != nil {
+		return false, errors.Wrap(err, errSetLimitThresholdsMsg)
+	}
+
+	if !found {
+		return false, nil
+	}
+
+	if err := p.resetReportsCache(ctx, userId); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// notifyLimitThresholds уведомляет автора траты о порогах лимитов, пройденных после траты.
+// О каждом пороге уведомляет один раз за календарный период лимита, даже в скользящем режиме периодов,
+// если пройдено сразу несколько порогов - уведомляет только о старшем
+func (p *processor) notifyLimitThresholds(ctx context.Context, ex model.Expense) error {
+	// у регулярных трат общего бюджета нет автора, которому можно написать
+	if ex.AuthorId == 0 || model.IsSharedBudget(ex.AuthorId) {
+		return nil
+	}
+
+	limits, err := p.repo.GetLimits(ctx, ex.Category, ex.UserId)
+	if err != nil || len(limits) == 0 {
+		return err
+	}
+
+	settings, found, err := p.settingsRepo.GetSettings(ctx, ex.AuthorId)
+	if err != nil {
+		return err
+	}
+
+	if !found {
+		settings = model.DefaultUserSettings(ex.AuthorId)
+	}
+
+	now := time.Now().In(settings.Location())
+	model.SortLimits(limits)
+
+	for _, limit := range limits {
+		if len(limit.Thresholds) == 0 || limit.Amount <= 0 {
+			continue
+		}
+
+		// трата за прошлый период не расходует текущий лимит
+		limitRange := limit.Period.GetPeriodRange(now, settings.PeriodMode, settings.WeekStart)
+		if !limitRange.Contains(ex.Datetime) {
+			continue
+		}
+
+		free, err := p.repo.GetFreeLimit(ctx, limit, limitRange)
+		if err != nil {
+			return err
+		}
+
+		periodStart := limit.Period.GetPeriodRange(now, model.CalendarPeriodMode, settings.WeekStart).From
+		usedPercent := (limit.Amount - free) * 100 / limit.Amount
+
+		crossed := 0
+		for _, threshold := range limit.Thresholds {
+			if usedPercent < int64(threshold) {
+				continue
+			}
+
+			saved, err := p.repo.SaveLimitNotification(ctx, limit, periodStart, threshold)
+			if err != nil {
+				return err
+			}
+
+			if saved && threshold > crossed {
+				crossed = threshold
+			}
+		}
+
+		if crossed == 0 {
+			continue
+		}
+
+		err = p.notifier.Notify(ctx, model.LimitAlert{
+			UserID:    ex.AuthorId,
+			Scope:     limit.Scope,
+			Period:    limit.Period,
+			Category:  limit.Category,
+			Threshold: crossed,
+			Amount:    p.converter.FromRUB(float64(limit.Amount), settings.Currency) / primitiveCurrencyMultiplier,
+			Free:      p.converter.FromRUB(float64(free), settings.Currency) / primitiveCurrencyMultiplier,
+			Currency:  settings.Currency,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetTopCategories возвращает самые используемые категории пользователя
+func (p *processor) GetTopCategories(ctx context.Context, userId int64, limit int) ([]string, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "GetTopCategories")
+	defer span.Finish()
+
+	categories, err := p.repo.GetTopCategories(ctx, userId, limit)
+	if err != nil {
+		return nil, errors.Wrap(err, errTopCategoriesMessage)
+	}
+
+	return categories, nil
+}
+
+func (p *processor) GetCategories(ctx context.Context, userId int64) ([]string, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "GetCategories")
+	defer span.Finish()
+
+	categories, err := p.repo.GetCategories(ctx, userId)
+	if err != nil {
+		return nil, errors.Wrap(err, errCategoriesMessage)
+	}
+
+	names := make([]string, 0, len(categories))
+	for _, c := range categories {
+		names = append(names, c.Name)
+	}
+
+	return names, nil
+}
+
+func (p *processor) RenameCategory(ctx context.Context, userId int64, name, newName string) (bool, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "RenameCategory")
+	defer span.Finish()
+
+	found, err := p.repo.RenameCategory(ctx, userId, strings.Trim(name, " "), strings.Trim(newName, " "))
+	if err != nil {
+		return false, errors.Wrap(err, errRenameCategoryMsg)
+	}
+
+	if found {
+		if err = p.resetReportsCache(ctx, userId); err != nil {
+			return false, errors.Wrap(err, errRenameCategoryMsg)
+		}
+	}
+
+	return found, nil
+}
+
+// MergeCategories переносит траты и лимит категории from в категорию to и удаляет from
+func (p *processor) MergeCategories(ctx context.Context, userId int64, from, to string) (bool, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "MergeCategories")
+	defer span.Finish()
+
+	found, err := p.repo.MergeCategories(ctx, userId, strings.Trim(from, " "), strings.Trim(to, " "))
+	if err != nil {
+		return false, errors.Wrap(err, errMergeCategoriesMsg)
+	}
+
+	if found {
+		if err = p.resetReportsCache(ctx, userId); err != nil {
+			return false, errors.Wrap(err, errMergeCategoriesMsg)
+		}
+	}
+
+	return found, nil
+}
+
+// DeleteCategory удаляет категорию без трат вместе с ее лимитом
+func (p *processor) DeleteCategory(ctx context.Context, userId int64, name string) (bool, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "DeleteCategory")
+	defer span.Finish()
+
+	found, err := p.repo.DeleteCategory(ctx, userId, strings.Trim(name, " "))
+	if err != nil {
+		return false, errors.Wrap(err, errDeleteCategoryMsg)
+	}
+
+	return found, nil
+}
+
+// applyCategoryRules заменяет введенную категорию по правилам пользователя.
+// Синоним срабатывает раньше ключевых слов, из ключевых слов выбирается самое длинное
+func (p *processor) applyCategoryRules(ctx context.Context, userId int64, category string) (string, error) {
+	rules, err := p.rulesRepo.GetRules(ctx, userId)
+	if err != nil {
+		return "", err
+	}
+
+	return matchCategoryRule(rules, category), nil
+}
+
+// matchCategoryRule возвращает категорию по первому подходящему псевдониму
+// или по самому длинному подходящему ключевому слову
+func matchCategoryRule(rules []model.CategoryRule, category string) string {
+	var keywordRule *model.CategoryRule
+	for i := 0; i < len(rules); i++ {
+		if !rules[i].Matches(category) {
+			continue
+		}
+
+		if rules[i].Type == model.AliasCategoryRule {
+			return rules[i].Category
+		}
+
+		if keywordRule == nil || len(rules[i].Pattern) > len(keywordRule.Pattern) {
+			keywordRule = &rules[i]
+		}
+	}
+
+	if keywordRule != nil {
+		return keywordRule.Category
+	}
+
+	return category
+}
+
+// SaveCategoryRule создает правило или заменяет правило с тем же шаблоном
+func (p *processor) SaveCategoryRule(ctx context.Context, rule model.CategoryRule) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "SaveCategoryRule")
+	defer span.Finish()
+
+	rule.Pattern = strings.Trim(rule.Pattern, " ")
+	rule.Category = strings.Trim(rule.Category, " ")
+
+	if err := p.rulesRepo.SaveRule(ctx, rule); err != nil {
+		return errors.Wrap(err, errSaveCategoryRuleMsg)
+	}
+
+	return nil
+}
+
+func (p *processor) GetCategoryRules(ctx context.Context, userId int64) ([]model.CategoryRule, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "GetCategoryRules")
+	defer span.Finish()
+
+	rules, err := p.rulesRepo.GetRules(ctx, userId)
+	if err != nil {
+		return nil, errors.Wrap(err, errCategoryRulesMessage)
+	}
+
+	return rules, nil
+}
+
+func (p *processor) DeleteCategoryRule(ctx context.Context, userId int64, pattern string) (bool, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "DeleteCategoryRule")
+	defer span.Finish()
+
+	found, err := p.rulesRepo.DeleteRule(ctx, userId, strings.Trim(pattern, " "))
+	if err != nil {
+		return false, errors.Wrap(err, errDeleteCategoryRuleMsg)
+	}
+
+	return found, nil
+}
+
+func (p *processor) SplitExpense(
+	ctx context.Context,
+	amount float64,
+	currency string,
+	category string,
+	datetime time.Time,
+	userId int64,
+	payerId int64,
+	participants []int64,
+) (*model.Expense, []Share, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "SplitExpense")
+	defer span.Finish()
+
+	// трата целиком расходует бюджет и лимиты, автор траты - плательщик
+	ex, err := p.addExpense(ctx, model.Expense{
+		ID:       uuid.NewString(),
+		Amount:   toPrimitive(p.converter.ToRUB(amount, currency)),
+		Category: category,
+		Datetime: datetime,
+		UserId:   userId,
+		AuthorId: payerId,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	shares := newExpenseShares(ex.ID, ex.Amount, userId, payerId, participants)
+	if err = p.splitsRepo.AddShares(ctx, shares); err != nil {
+		// без долей трата не разделена, повторная команда не должна создать дубль
+		if _, deleteErr := p.repo.Delete(ctx, ex.ID, userId); deleteErr != nil {
+			logger.Error(errSplitExpenseMessage, logger.LogDataItem{Key: "error", Value: deleteErr.Error()})
+		}
+		return nil, nil, errors.Wrap(err, errSplitExpenseMessage)
+	}
+
+	items := make([]Share, 0, len(shares))
+	for _, share := range shares {
+		items = append(items, Share{
+			UserID: share.UserID,
+			Amount: p.converter.FromRUB(float64(share.Amount), currency) / primitiveCurrencyMultiplier,
+		})
+	}
+
+	return ex, items, nil
+}
+
+// toPrimitive переводит сумму в копейки с округлением, иначе 0.29 превращается в 28 копеек
+func toPrimitive(amount float64) int64 {
+	return int64(math.Round(amount * primitiveCurrencyMultiplier))
+}
+
+// newExpenseShares делит сумму траты поровну между участниками в порядке их перечисления
+func newExpenseShares(expenseId string, amount, budgetId, payerId int64, participants []int64) []model.ExpenseShare {
+	amounts := model.SplitAmount(amount, participants)
+
+	shares := make([]model.ExpenseShare, 0, len(participants))
+	for _, userId := range participants {
+		shares = append(shares, model.ExpenseShare{
+			ExpenseID: expenseId,
+			BudgetID:  budgetId,
+			PayerID:   payerId,
+			UserID:    userId,
+			Amount:    amounts[userId],
+		})
+	}
+
+	return shares
+}
+
+func (p *processor) SettleDebt(ctx context.Context, amount float64, currency string, datetime time.Time, userId, fromUserId, toUserId int64) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "SettleDebt")
+	defer span.Finish()
+
+	_, err := p.splitsRepo.AddSettlement(ctx, model.Settlement{
+		BudgetID:   userId,
+		FromUserID: fromUserId,
+		ToUserID:   toUserId,
+		Amount:     toPrimitive(p.converter.ToRUB(amount, currency)),
+		Datetime:   datetime,
+	})
+	if err != nil {
+		return errors.Wrap(err, errSettleDebtMessage)
+	}
+
+	return nil
+}
+
+func (p *processor) GetDebts(ctx context.Context, currency string, userId int64) ([]Debt, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "GetDebts")
+	defer span.Finish()
+
+	balances, err := p.splitsRepo.GetBalances(ctx, userId)
+	if err != nil {
+		return nil, errors.Wrap(err, errDebtsMessage)
+	}
+
+	settlements := model.SettleDebts(balances)
+
+	debts := make([]Debt, 0, len(settlements))
+	for _, debt := range settlements {
+		debts = append(debts, Debt{
+			FromUserID: debt.FromUserID,
+			ToUserID:   debt.ToUserID,
+			Amount:     p.converter.FromRUB(float64(debt.Amount), currency) / primitiveCurrencyMultiplier,
+		})
+	}
+
+	return debts, nil
+}
+
+func (p *processor) ImportExpenses(
+	ctx context.Context,
+	items []ImportItem,
+	userId int64,
+	authorId int64,
+	dryRun bool,
+) ([]ImportItemResult, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "ImportExpenses")
+	defer span.Finish()
+
+	results := make([]ImportItemResult, len(items))
+	if len(items) == 0 {
+		return results, nil
+	}
+
+	rules, err := p.rulesRepo.GetRules(ctx, userId)
+	if err != nil {
+		return nil, errors.Wrap(err, errImportExpensesMessage)
+	}
+
+	expenses := make([]model.Expense, 0, len(items))
+	for _, item := range items {
+		expenses = append(expenses, model.Expense{
+			ID:       uuid.NewString(),
+			Amount:   toPrimitive(p.converter.ToRUB(item.Amount, item.Currency)),
+			Category: matchCategoryRule(rules, strings.Trim(item.Category, " ")),
+			Datetime: item.Datetime,
+			UserId:   userId,
+			AuthorId: authorId,
+		})
+	}
+
+	// даты сравниваются по календарному дню, как он записан в файле: в базе хранятся те же показания часов
+	loc := items[0].Datetime.Location()
+	existing, err := p.countImportKeys(ctx, expenses, userId, loc)
+	if err != nil {
+		return nil, errors.Wrap(err, errImportExpensesMessage)
+	}
+
+	added := make([]model.Expense, 0, len(expenses))
+	for i, ex := range expenses {
+		results[i].Category = ex.Category
+
+		// одинаковые траты за день возможны, дублями считаются только уже сохраненные
+		key := newImportKey(ex)
+		if existing[key] > 0 {
+			existing[key]--
+			results[i].Duplicate = true
+			continue
+		}
+
+		added = append(added, ex)
+	}
+
+	if dryRun || len(added) == 0 {
+		return results, nil
+	}
+
+	saved := 0
+	for saved < len(added) {
+		end := saved + importBatchSize
+		if end > len(added) {
+			end = len(added)
+		}
+
+		if err = p.repo.AddBatch(ctx, added[saved:end]); err != nil {
+			break
+		}
+		saved = end
+	}
+
+	// сохраненные пачки уже попали в отчеты, даже если следующая не сохранилась
+	if saved > 0 {
+		if cacheErr := p.resetReportsCache(ctx, userId); cacheErr != nil {
+			return nil, cacheErr
+		}
+		p.notifyImportedLimits(ctx, added[:saved])
+	}
+
+	if err != nil {
+		return nil, errors.Wrap(err, errImportExpensesMessage)
+	}
+
+	return results, nil
+}
+
+// importKey признаки, по которым импортируемая трата считается дублем
+type importKey struct {
+	date     string
+	amount   int64
+	category string
+}
+
+// newImportKey ключ траты по ее дню без перевода в другой пояс: у сохраненных трат метка UTC не меняет дня пользователя
+func newImportKey(ex model.Expense) importKey {
+	return importKey{
+		date:     ex.Datetime.Format("2006-01-02"),
+		amount:   ex.Amount,
+		category: strings.ToLower(ex.Category),
+	}
+}
+
+// countImportKeys считает сохраненные траты бюджета за дни импортируемых трат
+func (p *processor) countImportKeys(ctx context.Context, expenses []model.Expense, userId int64, loc *time.Location) (map[importKey]int, error) {
+	from, to := model.WallClock(expenses[0].Datetime, loc), model.WallClock(expenses[0].Datetime, loc)
+	for _, ex := range expenses {
+		datetime := model.WallClock(ex.Datetime, loc)
+		if datetime.Before(from) {
+			from = datetime
+		}
+		if datetime.After(to) {
+			to = datetime
+		}
+	}
+
+	dateRange := model.NewDateRange(model.StartOfDay(from), model.StartOfDay(to).AddDate(0, 0, 1))
+
+	saved, err := p.repo.GetExpenses(ctx, dateRange, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make(map[importKey]int, len(saved))
+	for _, ex := range saved {
+		keys[newImportKey(*ex)]++
+	}
+
+	return keys, nil
+}
+
+// notifyImportedLimits проверяет лимиты один раз на категорию по самой поздней трате,
+// чтобы импорт не присылал уведомление на каждую строку
+func (p *processor) notifyImportedLimits(ctx context.Context, expenses []model.Expense) {
+	latest := make(map[string]model.Expense)
+	categories := make([]string, 0)
+	for _, ex := range expenses {
+		key := strings.ToLower(ex.Category)
+		prev, ok := latest[key]
+		if !ok {
+			categories = append(categories, key)
+		}
+		if !ok || ex.Datetime.After(prev.Datetime) {
+			latest[key] = ex
+		}
+	}
+
+	for _, category := range categories {
+		if err := p.notifyLimitThresholds(ctx, latest[category]); err != nil {
+			logger.Error(errNotifyLimitsMessage, logger.LogDataItem{Key: "error", Value: err.Error()})
+		}
+	}
+}
@@ -10,11 +10,15 @@ import (
 	"github.com/golang/mock/gomock"
 	"github.com/opentracing/opentracing-go"
 	"github.com/stretchr/testify/assert"
+	blobstorage "gitlab.ozon.dev/cranky4/tg-bot/internal/clients/blob_storage"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/clients/blob_storage/local"
 	"gitlab.ozon.dev/cranky4/tg-bot/internal/clients/exchangerate"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/config"
 	"gitlab.ozon.dev/cranky4/tg-bot/internal/model"
 	repomocks "gitlab.ozon.dev/cranky4/tg-bot/internal/repository/mocks"
 	cachemocks "gitlab.ozon.dev/cranky4/tg-bot/internal/service/cache/mocks"
 	serviceconverter "gitlab.ozon.dev/cranky4/tg-bot/internal/service/converter"
+	notifiermocks "gitlab.ozon.dev/cranky4/tg-bot/internal/service/limit_notifier/mocks"
 )
 
 type testGetter struct{}
@@ -31,9 +35,37 @@ func (g *testGetter) Get(ctx context.Context) (*exchangerate.ExchangeResponse, e
 
 var testConverter = serviceconverter.NewConverter(&testGetter{})
 
+// generatedIDMatcher сравнивает трату без ИД, который генерируется при добавлении
+type generatedIDMatcher struct {
+	expected model.Expense
+}
+
+func expenseWithGeneratedID(expected model.Expense) gomock.Matcher {
+	return generatedIDMatcher{expected: expected}
+}
+
+func (m generatedIDMatcher) Matches(x interface{}) bool {
+	ex, ok := x.(model.Expense)
+	if !ok || ex.ID == "" {
+		return false
+	}
+	ex.ID = ""
+
+	return gomock.Eq(m.expected).Matches(ex)
+}
+
+func (m generatedIDMatcher) String() string {
+	return fmt.Sprintf("is equal to %v with generated ID", m.expected)
+}
+
 func TestAddExpenseWillReturnExpense(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	repo := repomocks.NewMockExpensesRepository(ctrl)
+	incomesRepo := repomocks.NewMockIncomesRepository(ctrl)
+	splitsRepo := repomocks.NewMockSplitsRepository(ctrl)
+	rulesRepo := repomocks.NewMockCategoryRulesRepository(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+	notifier := notifiermocks.NewMockLimitNotifier(ctrl)
 	userId := int64(100)
 	date, err := time.Parse("2006-01-02 15:04:05", "2022-10-01 12:56:00")
 	assert.NoError(t, err)
@@ -42,27 +74,67 @@ func TestAddExpenseWillReturnExpense(t *testing.T) {
 	_, wrapedCtx := opentracing.StartSpanFromContext(ctx, "wrap1")
 
 	cache := cachemocks.NewMockCache(ctrl)
-	cache.EXPECT().Del(wrapedCtx, fmt.Sprintf("%d-%v-%s", userId, model.Week, time.Now().Format("2006-01-02")))
-	cache.EXPECT().Del(wrapedCtx, fmt.Sprintf("%d-%v-%s", userId, model.Month, time.Now().Format("2006-01-02")))
-	cache.EXPECT().Del(wrapedCtx, fmt.Sprintf("%d-%v-%s", userId, model.Year, time.Now().Format("2006-01-02")))
+	cache.EXPECT().Del(wrapedCtx, "reports-version-100")
 
-	processor := NewProcessor(repo, testConverter, cache)
+	processor := NewProcessor(repo, incomesRepo, splitsRepo, rulesRepo, settingsRepo, nil, nil, testConverter, cache, notifier)
 
-	repo.EXPECT().Add(wrapedCtx, model.Expense{
+	rulesRepo.EXPECT().GetRules(wrapedCtx, userId)
+	repo.EXPECT().Add(wrapedCtx, expenseWithGeneratedID(model.Expense{
 		Amount:   12550,
 		Category: "Категория",
 		Datetime: date,
 		UserId:   userId,
-	})
+		AuthorId: userId,
+	}))
+
+	repo.EXPECT().GetLimits(wrapedCtx, "Категория", userId)
 
-	exp, err := processor.AddExpense(ctx, 125.50, "RUB", "Категория", date, userId)
-	assert.NotNil(t, exp.ID)
+	exp, err := processor.AddExpense(ctx, 125.50, "RUB", "Категория", "", date, userId, userId)
+	assert.NotEmpty(t, exp.ID)
+	assert.NoError(t, err)
+}
+
+func TestAddExpenseShouldRoundAmount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := repomocks.NewMockExpensesRepository(ctrl)
+	incomesRepo := repomocks.NewMockIncomesRepository(ctrl)
+	splitsRepo := repomocks.NewMockSplitsRepository(ctrl)
+	rulesRepo := repomocks.NewMockCategoryRulesRepository(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+	notifier := notifiermocks.NewMockLimitNotifier(ctrl)
+	userId := int64(100)
+	date := time.Date(2022, 10, 1, 12, 56, 0, 0, time.UTC)
+
+	ctx := context.Background()
+	_, wrapedCtx := opentracing.StartSpanFromContext(ctx, "wrap1")
+
+	cache := cachemocks.NewMockCache(ctrl)
+	cache.EXPECT().Del(wrapedCtx, "reports-version-100")
+
+	processor := NewProcessor(repo, incomesRepo, splitsRepo, rulesRepo, settingsRepo, nil, nil, testConverter, cache, notifier)
+
+	rulesRepo.EXPECT().GetRules(wrapedCtx, userId)
+	repo.EXPECT().Add(wrapedCtx, expenseWithGeneratedID(model.Expense{
+		Amount:   29,
+		Category: "Категория",
+		Datetime: date,
+		UserId:   userId,
+		AuthorId: userId,
+	}))
+	repo.EXPECT().GetLimits(wrapedCtx, "Категория", userId)
+
+	_, err := processor.AddExpense(ctx, 0.29, "RUB", "Категория", "", date, userId, userId)
 	assert.NoError(t, err)
 }
 
 func TestAddExpenseWillReturnRepositoryError(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	repo := repomocks.NewMockExpensesRepository(ctrl)
+	incomesRepo := repomocks.NewMockIncomesRepository(ctrl)
+	splitsRepo := repomocks.NewMockSplitsRepository(ctrl)
+	rulesRepo := repomocks.NewMockCategoryRulesRepository(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+	notifier := notifiermocks.NewMockLimitNotifier(ctrl)
 	userId := int64(100)
 	date, err := time.Parse("2006-01-02 15:04:05", "2022-10-01 12:56:00")
 	assert.NoError(t, err)
@@ -71,96 +143,171 @@ func TestAddExpenseWillReturnRepositoryError(t *testing.T) {
 
 	cache := cachemocks.NewMockCache(ctrl)
 
-	processor := NewProcessor(repo, testConverter, cache)
+	processor := NewProcessor(repo, incomesRepo, splitsRepo, rulesRepo, settingsRepo, nil, nil, testConverter, cache, notifier)
 
-	repo.EXPECT().Add(wrapedCtx, model.Expense{
+	rulesRepo.EXPECT().GetRules(wrapedCtx, userId)
+	repo.EXPECT().Add(wrapedCtx, expenseWithGeneratedID(model.Expense{
 		Amount:   12550,
 		Category: "Категория",
 		Datetime: date,
 		UserId:   userId,
-	}).Return(errors.New("database error"))
+		AuthorId: userId,
+	})).Return(errors.New("database error"))
 
-	exp, err := processor.AddExpense(ctx, 125.50, "RUB", "Категория", date, userId)
+	exp, err := processor.AddExpense(ctx, 125.50, "RUB", "Категория", "", date, userId, userId)
 	assert.Nil(t, exp)
 	assert.Error(t, err)
 }
 
-func TestGetFreeLimitWithSuccess(t *testing.T) {
+func TestAddRecurringExpenseShouldSkipAlreadyAddedOccurrence(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := repomocks.NewMockExpensesRepository(ctrl)
+	incomesRepo := repomocks.NewMockIncomesRepository(ctrl)
+	splitsRepo := repomocks.NewMockSplitsRepository(ctrl)
+	rulesRepo := repomocks.NewMockCategoryRulesRepository(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+	notifier := notifiermocks.NewMockLimitNotifier(ctrl)
+	userId := int64(100)
+	occurrence := time.Date(2022, 11, 7, 0, 0, 0, 0, time.UTC)
+	recurring := model.RecurringExpense{ID: "rec-1", UserId: userId, Amount: 59900, Currency: "RUB", Category: "Подписки"}
+
+	ctx := context.Background()
+	_, wrapedCtx := opentracing.StartSpanFromContext(ctx, "wrap1")
+
+	cache := cachemocks.NewMockCache(ctrl)
+
+	processor := NewProcessor(repo, incomesRepo, splitsRepo, rulesRepo, settingsRepo, nil, nil, testConverter, cache, notifier)
+
+	rulesRepo.EXPECT().GetRules(wrapedCtx, userId)
+	repo.EXPECT().AddOccurrence(wrapedCtx, expenseWithGeneratedID(model.Expense{
+		Amount:   59900,
+		Category: "Подписки",
+		Datetime: occurrence,
+		UserId:   userId,
+		AuthorId: userId,
+	}), "rec-1", occurrence).Return(false, nil)
+
+	exp, added, err := processor.AddRecurringExpense(ctx, recurring, occurrence)
+	assert.NoError(t, err)
+	assert.False(t, added)
+	assert.Nil(t, exp)
+}
+
+func TestGetFreeLimitsWithSuccess(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	repo := repomocks.NewMockExpensesRepository(ctrl)
+	incomesRepo := repomocks.NewMockIncomesRepository(ctrl)
+	splitsRepo := repomocks.NewMockSplitsRepository(ctrl)
+	rulesRepo := repomocks.NewMockCategoryRulesRepository(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+	notifier := notifiermocks.NewMockLimitNotifier(ctrl)
 	userId := int64(100)
+	now := time.Now()
+	settings := model.UserSettings{UserID: userId, Currency: "RUB", PeriodMode: model.CalendarPeriodMode, WeekStart: time.Monday}
 
 	ctx := context.Background()
 	_, wrapedCtx := opentracing.StartSpanFromContext(ctx, "wrap1")
 
 	cache := cachemocks.NewMockCache(ctrl)
 
-	processor := NewProcessor(repo, testConverter, cache)
+	processor := NewProcessor(repo, incomesRepo, splitsRepo, rulesRepo, settingsRepo, nil, nil, testConverter, cache, notifier)
+
+	total := model.ExpenseLimit{Scope: model.TotalLimitScope, Period: model.Week, Amount: 50000, UserId: userId}
+	yearly := model.ExpenseLimit{Scope: model.CategoryLimitScope, Period: model.Year, Category: "Категория", Amount: 90000, UserId: userId}
+	monthly := model.ExpenseLimit{Scope: model.CategoryLimitScope, Period: model.Month, Category: "Категория", Amount: 20000, UserId: userId}
+
+	repo.EXPECT().GetLimits(wrapedCtx, "Категория", userId).Return([]model.ExpenseLimit{total, yearly, monthly}, nil)
 
-	repo.EXPECT().GetFreeLimit(wrapedCtx, "Категория", userId).Return(int64(10000), true, nil)
+	month, week, year := model.Month, model.Week, model.Year
+	repo.EXPECT().GetFreeLimit(wrapedCtx, monthly, month.GetPeriodRange(now, model.CalendarPeriodMode, time.Monday)).Return(int64(10000), nil)
+	repo.EXPECT().GetFreeLimit(wrapedCtx, yearly, year.GetPeriodRange(now, model.CalendarPeriodMode, time.Monday)).Return(int64(80000), nil)
+	repo.EXPECT().GetFreeLimit(wrapedCtx, total, week.GetPeriodRange(now, model.CalendarPeriodMode, time.Monday)).Return(int64(-500), nil)
 
-	limit, has, err := processor.GetFreeLimit(ctx, "Категория", "RUB", userId)
-	assert.Equal(t, 100.00, limit)
-	assert.True(t, has)
+	limits, err := processor.GetFreeLimits(ctx, " Категория", userId, settings, now)
 	assert.NoError(t, err)
+	assert.Equal(t, []FreeLimit{
+		{Scope: model.CategoryLimitScope, Period: model.Month, Category: "Категория", Amount: 200, Free: 100},
+		{Scope: model.CategoryLimitScope, Period: model.Year, Category: "Категория", Amount: 900, Free: 800},
+		{Scope: model.TotalLimitScope, Period: model.Week, Amount: 500, Free: -5},
+	}, limits)
 }
 
-func TestGetFreeLimitWithNoLimitSet(t *testing.T) {
+func TestGetFreeLimitsWithNoLimitSet(t *testing.T) {
 	ctrl := gomock.NewController(t)
 
 	repo := repomocks.NewMockExpensesRepository(ctrl)
+	incomesRepo := repomocks.NewMockIncomesRepository(ctrl)
+	splitsRepo := repomocks.NewMockSplitsRepository(ctrl)
+	rulesRepo := repomocks.NewMockCategoryRulesRepository(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+	notifier := notifiermocks.NewMockLimitNotifier(ctrl)
 	userId := int64(100)
 
 	cache := cachemocks.NewMockCache(ctrl)
 
-	processor := NewProcessor(repo, testConverter, cache)
+	processor := NewProcessor(repo, incomesRepo, splitsRepo, rulesRepo, settingsRepo, nil, nil, testConverter, cache, notifier)
 
 	ctx := context.Background()
 	_, wrapedCtx := opentracing.StartSpanFromContext(ctx, "wrap1")
 
-	repo.EXPECT().GetFreeLimit(wrapedCtx, "Категория", userId).Return(int64(0), false, nil)
+	repo.EXPECT().GetLimits(wrapedCtx, "Категория", userId).Return([]model.ExpenseLimit{}, nil)
 
-	limit, has, err := processor.GetFreeLimit(ctx, "Категория", "RUB", userId)
-	assert.Equal(t, 0.0, limit)
-	assert.False(t, has)
+	limits, err := processor.GetFreeLimits(ctx, "Категория", userId, model.UserSettings{UserID: userId, Currency: "RUB"}, time.Now())
+	assert.Len(t, limits, 0)
 	assert.NoError(t, err)
 }
 
-func TestGetFreeLimitWithRepoError(t *testing.T) {
+func TestGetFreeLimitsWithRepoError(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	repo := repomocks.NewMockExpensesRepository(ctrl)
+	incomesRepo := repomocks.NewMockIncomesRepository(ctrl)
+	splitsRepo := repomocks.NewMockSplitsRepository(ctrl)
+	rulesRepo := repomocks.NewMockCategoryRulesRepository(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+	notifier := notifiermocks.NewMockLimitNotifier(ctrl)
 	userId := int64(100)
 
 	cache := cachemocks.NewMockCache(ctrl)
 
-	processor := NewProcessor(repo, testConverter, cache)
+	processor := NewProcessor(repo, incomesRepo, splitsRepo, rulesRepo, settingsRepo, nil, nil, testConverter, cache, notifier)
 
 	ctx := context.Background()
 	_, wrapedCtx := opentracing.StartSpanFromContext(ctx, "wrap1")
 
-	repo.EXPECT().GetFreeLimit(wrapedCtx, "Категория", userId).Return(int64(0), false, errors.New("database error"))
+	repo.EXPECT().GetLimits(wrapedCtx, "Категория", userId).Return(nil, errors.New("database error"))
 
-	limit, has, err := processor.GetFreeLimit(ctx, "Категория", "RUB", userId)
-	assert.Equal(t, 0.0, limit)
-	assert.False(t, has)
+	limits, err := processor.GetFreeLimits(ctx, "Категория", userId, model.UserSettings{UserID: userId, Currency: "RUB"}, time.Now())
+	assert.Nil(t, limits)
 	assert.Error(t, err)
 }
 
 func TestSetLimitWithSuccess(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	repo := repomocks.NewMockExpensesRepository(ctrl)
+	incomesRepo := repomocks.NewMockIncomesRepository(ctrl)
+	splitsRepo := repomocks.NewMockSplitsRepository(ctrl)
+	rulesRepo := repomocks.NewMockCategoryRulesRepository(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+	notifier := notifiermocks.NewMockLimitNotifier(ctrl)
 	userId := int64(100)
 
 	ctx := context.Background()
 	_, wrapedCtx := opentracing.StartSpanFromContext(ctx, "wrap1")
 
 	cache := cachemocks.NewMockCache(ctrl)
+	cache.EXPECT().Del(wrapedCtx, "reports-version-100")
 
-	processor := NewProcessor(repo, testConverter, cache)
+	processor := NewProcessor(repo, incomesRepo, splitsRepo, rulesRepo, settingsRepo, nil, nil, testConverter, cache, notifier)
 
-	repo.EXPECT().SetLimit(wrapedCtx, "Категория", userId, int64(100000))
+	repo.EXPECT().SetLimit(wrapedCtx, model.ExpenseLimit{
+		Scope:    model.CategoryLimitScope,
+		Period:   model.Week,
+		Category: "Категория",
+		Amount:   100000,
+		UserId:   userId,
+	})
 
-	limit, err := processor.SetLimit(ctx, "Категория", userId, 1000.00, "RUB")
+	limit, err := processor.SetLimit(ctx, "Категория", model.Week, userId, 1000.00, "RUB")
 	assert.Equal(t, 1000.0, limit)
 	assert.NoError(t, err)
 }
@@ -168,6 +315,11 @@ func TestSetLimitWithSuccess(t *testing.T) {
 func TestSetLimitWithRepoError(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	repo := repomocks.NewMockExpensesRepository(ctrl)
+	incomesRepo := repomocks.NewMockIncomesRepository(ctrl)
+	splitsRepo := repomocks.NewMockSplitsRepository(ctrl)
+	rulesRepo := repomocks.NewMockCategoryRulesRepository(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+	notifier := notifiermocks.NewMockLimitNotifier(ctrl)
 	userId := int64(100)
 
 	ctx := context.Background()
@@ -175,11 +327,581 @@ func TestSetLimitWithRepoError(t *testing.T) {
 
 	cache := cachemocks.NewMockCache(ctrl)
 
-	processor := NewProcessor(repo, testConverter, cache)
+	processor := NewProcessor(repo, incomesRepo, splitsRepo, rulesRepo, settingsRepo, nil, nil, testConverter, cache, notifier)
 
-	repo.EXPECT().SetLimit(wrapedCtx, "Категория", userId, int64(100000)).Return(errors.New("database error"))
+	repo.EXPECT().SetLimit(wrapedCtx, model.ExpenseLimit{
+		Scope:  model.TotalLimitScope,
+		Period: model.Month,
+		Amount: 100000,
+		UserId: userId,
+	}).Return(errors.New("database error"))
 
-	limit, err := processor.SetLimit(ctx, "Категория", userId, 1000.00, "RUB")
+	limit, err := processor.SetLimit(ctx, "", model.Month, userId, 1000.00, "RUB")
 	assert.Equal(t, 0.0, limit)
 	assert.Error(t, err)
 }
+
+func TestSetLimitThresholdsWillResetCache(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := repomocks.NewMockExpensesRepository(ctrl)
+	incomesRepo := repomocks.NewMockIncomesRepository(ctrl)
+	splitsRepo := repomocks.NewMockSplitsRepository(ctrl)
+	rulesRepo := repomocks.NewMockCategoryRulesRepository(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+	notifier := notifiermocks.NewMockLimitNotifier(ctrl)
+	userId := int64(100)
+
+	ctx := context.Background()
+	_, wrapedCtx := opentracing.StartSpanFromContext(ctx, "wrap1")
+
+	cache := cachemocks.NewMockCache(ctrl)
+	cache.EXPECT().Del(wrapedCtx, "reports-version-100")
+
+	processor := NewProcessor(repo, incomesRepo, splitsRepo, rulesRepo, settingsRepo, nil, nil, testConverter, cache, notifier)
+
+	repo.EXPECT().SetLimitThresholds(wrapedCtx, model.ExpenseLimit{
+		Scope:      model.CategoryLimitScope,
+		Period:     model.Month,
+		Category:   "Категория",
+		Thresholds: []int{80, 100},
+		UserId:     userId,
+	}).Return(true, nil)
+
+	found, err := processor.SetLimitThresholds(ctx, "Категория", model.Month, userId, []int{80, 100})
+	assert.True(t, found)
+	assert.NoError(t, err)
+}
+
+func TestSetLimitThresholdsWillNotResetCacheWhenNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := repomocks.NewMockExpensesRepository(ctrl)
+	incomesRepo := repomocks.NewMockIncomesRepository(ctrl)
+	splitsRepo := repomocks.NewMockSplitsRepository(ctrl)
+	rulesRepo := repomocks.NewMockCategoryRulesRepository(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+	notifier := notifiermocks.NewMockLimitNotifier(ctrl)
+	userId := int64(100)
+
+	ctx := context.Background()
+	_, wrapedCtx := opentracing.StartSpanFromContext(ctx, "wrap1")
+
+	cache := cachemocks.NewMockCache(ctrl)
+
+	processor := NewProcessor(repo, incomesRepo, splitsRepo, rulesRepo, settingsRepo, nil, nil, testConverter, cache, notifier)
+
+	repo.EXPECT().SetLimitThresholds(wrapedCtx, model.ExpenseLimit{
+		Scope:      model.TotalLimitScope,
+		Period:     model.Week,
+		Thresholds: []int{90},
+		UserId:     userId,
+	}).Return(false, nil)
+
+	found, err := processor.SetLimitThresholds(ctx, "", model.Week, userId, []int{90})
+	assert.False(t, found)
+	assert.NoError(t, err)
+}
+
+func TestDeleteExpenseWillResetCache(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := repomocks.NewMockExpensesRepository(ctrl)
+	incomesRepo := repomocks.NewMockIncomesRepository(ctrl)
+	splitsRepo := repomocks.NewMockSplitsRepository(ctrl)
+	rulesRepo := repomocks.NewMockCategoryRulesRepository(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+	notifier := notifiermocks.NewMockLimitNotifier(ctrl)
+	userId := int64(100)
+	id := "1b4e28ba-2fa1-11d2-883f-0016d3cca427"
+
+	ctx := context.Background()
+	_, wrapedCtx := opentracing.StartSpanFromContext(ctx, "wrap1")
+
+	cache := cachemocks.NewMockCache(ctrl)
+	cache.EXPECT().Del(wrapedCtx, "reports-version-100")
+
+	attachmentsRepo := repomocks.NewMockAttachmentsRepository(ctrl)
+	processor := NewProcessor(repo, incomesRepo, splitsRepo, rulesRepo, settingsRepo, attachmentsRepo, nil, testConverter, cache, notifier)
+
+	attachmentsRepo.EXPECT().GetAttachment(wrapedCtx, id, userId)
+	repo.EXPECT().Delete(wrapedCtx, id, userId).Return(true, nil)
+	splitsRepo.EXPECT().DeleteShares(wrapedCtx, id)
+
+	found, err := processor.DeleteExpense(ctx, id, userId)
+	assert.True(t, found)
+	assert.NoError(t, err)
+}
+
+func TestDeleteExpenseShouldDeleteReceiptFile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := repomocks.NewMockExpensesRepository(ctrl)
+	incomesRepo := repomocks.NewMockIncomesRepository(ctrl)
+	splitsRepo := repomocks.NewMockSplitsRepository(ctrl)
+	rulesRepo := repomocks.NewMockCategoryRulesRepository(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+	attachmentsRepo := repomocks.NewMockAttachmentsRepository(ctrl)
+	notifier := notifiermocks.NewMockLimitNotifier(ctrl)
+	userId := int64(100)
+	id := "1b4e28ba-2fa1-11d2-883f-0016d3cca427"
+	key := "100/1b4e28ba-2fa1-11d2-883f-0016d3cca427.jpg"
+
+	ctx := context.Background()
+	_, wrapedCtx := opentracing.StartSpanFromContext(ctx, "wrap1")
+
+	storage, err := local.NewLocalStorage(config.BlobStorageConf{Dir: t.TempDir()})
+	assert.NoError(t, err)
+	assert.NoError(t, storage.Put(ctx, key, []byte("receipt")))
+
+	cache := cachemocks.NewMockCache(ctrl)
+	cache.EXPECT().Del(wrapedCtx, "reports-version-100")
+
+	processor := NewProcessor(repo, incomesRepo, splitsRepo, rulesRepo, settingsRepo, attachmentsRepo, storage, testConverter, cache, notifier)
+
+	attachmentsRepo.EXPECT().GetAttachment(wrapedCtx, id, userId).Return(model.Attachment{ExpenseID: id, UserId: userId, Key: key}, true, nil)
+	repo.EXPECT().Delete(wrapedCtx, id, userId).Return(true, nil)
+	splitsRepo.EXPECT().DeleteShares(wrapedCtx, id)
+
+	found, err := processor.DeleteExpense(ctx, id, userId)
+	assert.True(t, found)
+	assert.NoError(t, err)
+
+	_, err = storage.Get(ctx, key)
+	assert.ErrorIs(t, err, blobstorage.ErrBlobNotFound)
+}
+
+func TestAddIncomeWillResetCache(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := repomocks.NewMockExpensesRepository(ctrl)
+	incomesRepo := repomocks.NewMockIncomesRepository(ctrl)
+	splitsRepo := repomocks.NewMockSplitsRepository(ctrl)
+	rulesRepo := repomocks.NewMockCategoryRulesRepository(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+	notifier := notifiermocks.NewMockLimitNotifier(ctrl)
+	userId := int64(100)
+	date, err := time.Parse("2006-01-02", "2022-10-05")
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	_, wrapedCtx := opentracing.StartSpanFromContext(ctx, "wrap1")
+
+	cache := cachemocks.NewMockCache(ctrl)
+	cache.EXPECT().Del(wrapedCtx, "reports-version-100")
+
+	processor := NewProcessor(repo, incomesRepo, splitsRepo, rulesRepo, settingsRepo, nil, nil, testConverter, cache, notifier)
+
+	income := model.Income{
+		Amount:   15000000,
+		Source:   "Зарплата",
+		Datetime: date,
+		UserId:   userId,
+	}
+	incomesRepo.EXPECT().AddIncome(wrapedCtx, income).Return(income, nil)
+
+	added, err := processor.AddIncome(ctx, 150000, "RUB", " Зарплата ", date, userId)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(15000000), added.Amount)
+}
+
+func TestDeleteExpenseWillNotResetCacheWhenNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := repomocks.NewMockExpensesRepository(ctrl)
+	incomesRepo := repomocks.NewMockIncomesRepository(ctrl)
+	splitsRepo := repomocks.NewMockSplitsRepository(ctrl)
+	rulesRepo := repomocks.NewMockCategoryRulesRepository(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+	notifier := notifiermocks.NewMockLimitNotifier(ctrl)
+	userId := int64(100)
+
+	ctx := context.Background()
+	_, wrapedCtx := opentracing.StartSpanFromContext(ctx, "wrap1")
+
+	cache := cachemocks.NewMockCache(ctrl)
+
+	attachmentsRepo := repomocks.NewMockAttachmentsRepository(ctrl)
+	processor := NewProcessor(repo, incomesRepo, splitsRepo, rulesRepo, settingsRepo, attachmentsRepo, nil, testConverter, cache, notifier)
+
+	attachmentsRepo.EXPECT().GetAttachment(wrapedCtx, "unknown", userId)
+	repo.EXPECT().Delete(wrapedCtx, "unknown", userId).Return(false, nil)
+
+	found, err := processor.DeleteExpense(ctx, "unknown", userId)
+	assert.False(t, found)
+	assert.NoError(t, err)
+}
+
+func TestUpdateExpenseWillResetCache(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := repomocks.NewMockExpensesRepository(ctrl)
+	incomesRepo := repomocks.NewMockIncomesRepository(ctrl)
+	splitsRepo := repomocks.NewMockSplitsRepository(ctrl)
+	rulesRepo := repomocks.NewMockCategoryRulesRepository(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+	notifier := notifiermocks.NewMockLimitNotifier(ctrl)
+	userId := int64(100)
+	id := "1b4e28ba-2fa1-11d2-883f-0016d3cca427"
+	date, err := time.Parse("2006-01-02 15:04:05", "2022-10-01 12:56:00")
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	_, wrapedCtx := opentracing.StartSpanFromContext(ctx, "wrap1")
+
+	cache := cachemocks.NewMockCache(ctrl)
+	cache.EXPECT().Del(wrapedCtx, "reports-version-100")
+
+	processor := NewProcessor(repo, incomesRepo, splitsRepo, rulesRepo, settingsRepo, nil, nil, testConverter, cache, notifier)
+
+	// доли разделенной траты пересчитываются от новой суммы и сохраняются вместе с тратой
+	splitsRepo.EXPECT().GetShares(wrapedCtx, id).Return([]model.ExpenseShare{
+		{ExpenseID: id, BudgetID: userId, PayerID: 200, UserID: 200, Amount: 5000},
+		{ExpenseID: id, BudgetID: userId, PayerID: 200, UserID: 300, Amount: 5000},
+	}, nil)
+	repo.EXPECT().Update(wrapedCtx, model.Expense{
+		ID:       id,
+		Amount:   12550,
+		Category: "Категория",
+		Datetime: date,
+		UserId:   userId,
+	}, []model.ExpenseShare{
+		{ExpenseID: id, BudgetID: userId, PayerID: 200, UserID: 200, Amount: 6275},
+		{ExpenseID: id, BudgetID: userId, PayerID: 200, UserID: 300, Amount: 6275},
+	}).Return(true, nil)
+
+	exp, found, err := processor.UpdateExpense(ctx, id, 125.50, "RUB", "Категория", date, userId)
+	assert.True(t, found)
+	assert.Equal(t, int64(12550), exp.Amount)
+	assert.NoError(t, err)
+}
+
+func TestMergeCategoriesWillResetCache(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := repomocks.NewMockExpensesRepository(ctrl)
+	incomesRepo := repomocks.NewMockIncomesRepository(ctrl)
+	splitsRepo := repomocks.NewMockSplitsRepository(ctrl)
+	rulesRepo := repomocks.NewMockCategoryRulesRepository(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+	notifier := notifiermocks.NewMockLimitNotifier(ctrl)
+	userId := int64(100)
+
+	ctx := context.Background()
+	_, wrapedCtx := opentracing.StartSpanFromContext(ctx, "wrap1")
+
+	cache := cachemocks.NewMockCache(ctrl)
+	cache.EXPECT().Del(wrapedCtx, "reports-version-100")
+
+	processor := NewProcessor(repo, incomesRepo, splitsRepo, rulesRepo, settingsRepo, nil, nil, testConverter, cache, notifier)
+
+	repo.EXPECT().MergeCategories(wrapedCtx, userId, "Еда", "Продукты").Return(true, nil)
+
+	found, err := processor.MergeCategories(ctx, userId, " Еда", "Продукты ")
+	assert.True(t, found)
+	assert.NoError(t, err)
+}
+
+func TestAddExpenseWillApplyCategoryRules(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := repomocks.NewMockExpensesRepository(ctrl)
+	incomesRepo := repomocks.NewMockIncomesRepository(ctrl)
+	splitsRepo := repomocks.NewMockSplitsRepository(ctrl)
+	rulesRepo := repomocks.NewMockCategoryRulesRepository(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+	notifier := notifiermocks.NewMockLimitNotifier(ctrl)
+	userId := int64(100)
+	date, err := time.Parse("2006-01-02 15:04:05", "2022-10-01 12:56:00")
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	_, wrapedCtx := opentracing.StartSpanFromContext(ctx, "wrap1")
+
+	cache := cachemocks.NewMockCache(ctrl)
+	cache.EXPECT().Del(wrapedCtx, "reports-version-100").Times(3)
+
+	processor := NewProcessor(repo, incomesRepo, splitsRepo, rulesRepo, settingsRepo, nil, nil, testConverter, cache, notifier)
+
+	rulesRepo.EXPECT().GetRules(wrapedCtx, userId).Return([]model.CategoryRule{
+		{Type: model.KeywordCategoryRule, Pattern: "кофе", Category: "Кофе", UserId: userId},
+		{Type: model.KeywordCategoryRule, Pattern: "кофе с собой", Category: "Кафе", UserId: userId},
+		{Type: model.AliasCategoryRule, Pattern: "еда", Category: "Продукты", UserId: userId},
+	}, nil).Times(3)
+
+	for input, category := range map[string]string{
+		" ЕДА ": "Продукты",
+		"утренний кофе с собой": "Кафе",
+		"Такси": "Такси",
+	} {
+		repo.EXPECT().Add(wrapedCtx, expenseWithGeneratedID(model.Expense{
+			Amount:   12550,
+			Category: category,
+			Datetime: date,
+			UserId:   userId,
+			AuthorId: userId,
+		}))
+
+		repo.EXPECT().GetLimits(wrapedCtx, category, userId)
+
+		exp, err := processor.AddExpense(ctx, 125.50, "RUB", input, "", date, userId, userId)
+		assert.NoError(t, err)
+		assert.Equal(t, category, exp.Category)
+	}
+}
+
+func TestAddExpenseWillNotifyAboutCrossedLimitThreshold(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := repomocks.NewMockExpensesRepository(ctrl)
+	incomesRepo := repomocks.NewMockIncomesRepository(ctrl)
+	splitsRepo := repomocks.NewMockSplitsRepository(ctrl)
+	rulesRepo := repomocks.NewMockCategoryRulesRepository(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+	notifier := notifiermocks.NewMockLimitNotifier(ctrl)
+	userId := int64(100)
+	now := time.Now()
+
+	ctx := context.Background()
+	_, wrapedCtx := opentracing.StartSpanFromContext(ctx, "wrap1")
+
+	cache := cachemocks.NewMockCache(ctrl)
+	cache.EXPECT().Del(wrapedCtx, "reports-version-100")
+
+	processor := NewProcessor(repo, incomesRepo, splitsRepo, rulesRepo, settingsRepo, nil, nil, testConverter, cache, notifier)
+
+	settings := model.UserSettings{UserID: userId, Currency: "RUB", PeriodMode: model.RollingPeriodMode, WeekStart: time.Monday}
+	limit := model.ExpenseLimit{
+		Scope:      model.CategoryLimitScope,
+		Period:     model.Month,
+		Category:   "Кофе",
+		Amount:     100000,
+		Thresholds: []int{50, 80, 100},
+		UserId:     userId,
+	}
+	month := model.Month
+
+	rulesRepo.EXPECT().GetRules(wrapedCtx, userId)
+	repo.EXPECT().Add(wrapedCtx, gomock.Any())
+	repo.EXPECT().GetLimits(wrapedCtx, "Кофе", userId).Return([]model.ExpenseLimit{limit}, nil)
+	settingsRepo.EXPECT().GetSettings(wrapedCtx, userId).Return(settings, true, nil)
+	repo.EXPECT().GetFreeLimit(wrapedCtx, limit, month.GetPeriodRange(now, model.RollingPeriodMode, time.Monday)).Return(int64(15000), nil)
+	repo.EXPECT().SaveLimitNotification(wrapedCtx, limit, model.StartOfMonth(now), 50).Return(false, nil)
+	repo.EXPECT().SaveLimitNotification(wrapedCtx, limit, model.StartOfMonth(now), 80).Return(true, nil)
+	notifier.EXPECT().Notify(wrapedCtx, model.LimitAlert{
+		UserID:    userId,
+		Scope:     model.CategoryLimitScope,
+		Period:    model.Month,
+		Category:  "Кофе",
+		Threshold: 80,
+		Amount:    1000,
+		Free:      150,
+		Currency:  "RUB",
+	})
+
+	_, err := processor.AddExpense(ctx, 10, "RUB", "Кофе", "", now, userId, userId)
+	assert.NoError(t, err)
+}
+
+func TestGetBalancesShouldConvertToAccountCurrency(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := repomocks.NewMockExpensesRepository(ctrl)
+	incomesRepo := repomocks.NewMockIncomesRepository(ctrl)
+	splitsRepo := repomocks.NewMockSplitsRepository(ctrl)
+	rulesRepo := repomocks.NewMockCategoryRulesRepository(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+	notifier := notifiermocks.NewMockLimitNotifier(ctrl)
+	userId := int64(100)
+
+	ctx := context.Background()
+	_, wrapedCtx := opentracing.StartSpanFromContext(ctx, "wrap1")
+
+	cache := cachemocks.NewMockCache(ctrl)
+	processor := NewProcessor(repo, incomesRepo, splitsRepo, rulesRepo, settingsRepo, nil, nil, testConverter, cache, notifier)
+
+	repo.EXPECT().GetAccountBalances(wrapedCtx, userId).Return([]model.AccountBalance{
+		{Account: model.Account{Name: "Карта", Currency: "RUB"}, Balance: 965000},
+		{Account: model.Account{Name: "Наличные", Currency: "USD"}, Balance: 1000000},
+	}, nil)
+
+	balances, err := processor.GetBalances(ctx, userId)
+	assert.NoError(t, err)
+	assert.Equal(t, []AccountBalance{
+		{Name: "Карта", Currency: "RUB", Balance: 9650},
+		{Name: "Наличные", Currency: "USD", Balance: testConverter.FromRUB(1000000, "USD") / 100},
+	}, balances)
+}
+
+func TestSplitExpenseShouldSaveSharesOfParticipants(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := repomocks.NewMockExpensesRepository(ctrl)
+	incomesRepo := repomocks.NewMockIncomesRepository(ctrl)
+	splitsRepo := repomocks.NewMockSplitsRepository(ctrl)
+	rulesRepo := repomocks.NewMockCategoryRulesRepository(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+	notifier := notifiermocks.NewMockLimitNotifier(ctrl)
+	budgetId := int64(-1)
+	now := time.Now()
+
+	ctx := context.Background()
+	_, wrapedCtx := opentracing.StartSpanFromContext(ctx, "wrap1")
+
+	cache := cachemocks.NewMockCache(ctrl)
+	cache.EXPECT().Del(wrapedCtx, "reports-version--1")
+
+	processor := NewProcessor(repo, incomesRepo, splitsRepo, rulesRepo, settingsRepo, nil, nil, testConverter, cache, notifier)
+
+	var expenseId string
+	rulesRepo.EXPECT().GetRules(wrapedCtx, budgetId)
+	repo.EXPECT().Add(wrapedCtx, gomock.Any()).DoAndReturn(func(_ context.Context, ex model.Expense) error {
+		expenseId = ex.ID
+		assert.Equal(t, int64(300000), ex.Amount)
+		assert.Equal(t, int64(100), ex.AuthorId)
+		return nil
+	})
+	repo.EXPECT().GetLimits(wrapedCtx, "Ресторан", budgetId)
+	splitsRepo.EXPECT().AddShares(wrapedCtx, gomock.Any()).DoAndReturn(func(_ context.Context, shares []model.ExpenseShare) error {
+		assert.Equal(t, []model.ExpenseShare{
+			{ExpenseID: expenseId, BudgetID: budgetId, PayerID: 100, UserID: 100, Amount: 100000},
+			{ExpenseID: expenseId, BudgetID: budgetId, PayerID: 100, UserID: 200, Amount: 100000},
+			{ExpenseID: expenseId, BudgetID: budgetId, PayerID: 100, UserID: 300, Amount: 100000},
+		}, shares)
+		return nil
+	})
+
+	exp, shares, err := processor.SplitExpense(ctx, 3000, "RUB", "Ресторан", now, budgetId, 100, []int64{100, 200, 300})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, exp.ID)
+	assert.Equal(t, []Share{{UserID: 100, Amount: 1000}, {UserID: 200, Amount: 1000}, {UserID: 300, Amount: 1000}}, shares)
+}
+
+func TestGetDebtsShouldReturnMinimalTransfers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := repomocks.NewMockExpensesRepository(ctrl)
+	incomesRepo := repomocks.NewMockIncomesRepository(ctrl)
+	splitsRepo := repomocks.NewMockSplitsRepository(ctrl)
+	rulesRepo := repomocks.NewMockCategoryRulesRepository(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+	notifier := notifiermocks.NewMockLimitNotifier(ctrl)
+	budgetId := int64(-1)
+
+	ctx := context.Background()
+	_, wrapedCtx := opentracing.StartSpanFromContext(ctx, "wrap1")
+
+	cache := cachemocks.NewMockCache(ctrl)
+	processor := NewProcessor(repo, incomesRepo, splitsRepo, rulesRepo, settingsRepo, nil, nil, testConverter, cache, notifier)
+
+	splitsRepo.EXPECT().GetBalances(wrapedCtx, budgetId).Return(map[int64]int64{100: 200000, 200: -100000, 300: -100000}, nil)
+
+	debts, err := processor.GetDebts(ctx, "RUB", budgetId)
+	assert.NoError(t, err)
+	assert.Equal(t, []Debt{
+		{FromUserID: 200, ToUserID: 100, Amount: 1000},
+		{FromUserID: 300, ToUserID: 100, Amount: 1000},
+	}, debts)
+}
+
+func TestImportExpensesShouldSkipSavedDuplicatesAndNotifyOncePerCategory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := repomocks.NewMockExpensesRepository(ctrl)
+	incomesRepo := repomocks.NewMockIncomesRepository(ctrl)
+	splitsRepo := repomocks.NewMockSplitsRepository(ctrl)
+	rulesRepo := repomocks.NewMockCategoryRulesRepository(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+	notifier := notifiermocks.NewMockLimitNotifier(ctrl)
+	userId := int64(100)
+	date := time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	ctx := context.Background()
+	_, wrapedCtx := opentracing.StartSpanFromContext(ctx, "wrap1")
+
+	cache := cachemocks.NewMockCache(ctrl)
+	cache.EXPECT().Del(wrapedCtx, "reports-version-100")
+
+	processor := NewProcessor(repo, incomesRepo, splitsRepo, rulesRepo, settingsRepo, nil, nil, testConverter, cache, notifier)
+
+	rulesRepo.EXPECT().GetRules(wrapedCtx, userId).Return([]model.CategoryRule{
+		{Type: model.AliasCategoryRule, Pattern: "еда", Category: "Продукты", UserId: userId},
+	}, nil)
+	repo.EXPECT().GetExpenses(wrapedCtx, model.NewDateRange(date, date.AddDate(0, 0, 2)), userId).Return([]*model.Expense{
+		{Amount: 12550, Category: "кофе", Datetime: date.Add(9 * time.Hour), UserId: userId},
+	}, nil)
+	repo.EXPECT().AddBatch(wrapedCtx, gomock.Any()).DoAndReturn(func(_ context.Context, expenses []model.Expense) error {
+		assert.Len(t, expenses, 3)
+		for _, ex := range expenses {
+			assert.NotEmpty(t, ex.ID)
+			assert.Equal(t, userId, ex.AuthorId)
+		}
+		assert.Equal(t, int64(12550), expenses[0].Amount)
+		assert.Equal(t, int64(100000), expenses[2].Amount)
+
+		return nil
+	})
+	repo.EXPECT().GetLimits(wrapedCtx, "Кофе", userId)
+	repo.EXPECT().GetLimits(wrapedCtx, "Продукты", userId)
+
+	results, err := processor.ImportExpenses(ctx, []ImportItem{
+		{Amount: 125.50, Currency: "RUB", Category: "Кофе", Datetime: date},
+		{Amount: 125.50, Currency: "RUB", Category: "Кофе", Datetime: date},
+		{Amount: 500, Currency: "RUB", Category: "Еда", Datetime: date.AddDate(0, 0, 1)},
+		{Amount: 1000, Currency: "RUB", Category: "Еда", Datetime: date.AddDate(0, 0, 1)},
+	}, userId, userId, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []ImportItemResult{
+		{Category: "Кофе", Duplicate: true},
+		{Category: "Кофе"},
+		{Category: "Продукты"},
+		{Category: "Продукты"},
+	}, results)
+}
+
+func TestImportExpensesShouldFindDuplicatesByStoredWallClock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := repomocks.NewMockExpensesRepository(ctrl)
+	incomesRepo := repomocks.NewMockIncomesRepository(ctrl)
+	splitsRepo := repomocks.NewMockSplitsRepository(ctrl)
+	rulesRepo := repomocks.NewMockCategoryRulesRepository(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+	notifier := notifiermocks.NewMockLimitNotifier(ctrl)
+	cache := cachemocks.NewMockCache(ctrl)
+	userId := int64(100)
+	moscow := time.FixedZone("", 3*60*60)
+
+	ctx := context.Background()
+	_, wrapedCtx := opentracing.StartSpanFromContext(ctx, "wrap1")
+
+	processor := NewProcessor(repo, incomesRepo, splitsRepo, rulesRepo, settingsRepo, nil, nil, testConverter, cache, notifier)
+
+	// трата перед полуночью хранится с теми же часами и меткой UTC, перевод в Москву унес бы ее на следующий день
+	rulesRepo.EXPECT().GetRules(wrapedCtx, userId)
+	repo.EXPECT().GetExpenses(
+		wrapedCtx,
+		model.NewDateRange(time.Date(2022, 10, 1, 0, 0, 0, 0, moscow), time.Date(2022, 10, 2, 0, 0, 0, 0, moscow)),
+		userId,
+	).Return([]*model.Expense{
+		{Amount: 35000, Category: "Такси", Datetime: time.Date(2022, 10, 1, 23, 30, 0, 0, time.UTC), UserId: userId},
+	}, nil)
+
+	results, err := processor.ImportExpenses(ctx, []ImportItem{
+		{Amount: 350, Currency: "RUB", Category: "Такси", Datetime: time.Date(2022, 10, 1, 23, 30, 0, 0, moscow)},
+	}, userId, userId, true)
+	assert.NoError(t, err)
+	assert.Equal(t, []ImportItemResult{{Category: "Такси", Duplicate: true}}, results)
+}
+
+func TestImportExpensesShouldNotSaveOnDryRun(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := repomocks.NewMockExpensesRepository(ctrl)
+	incomesRepo := repomocks.NewMockIncomesRepository(ctrl)
+	splitsRepo := repomocks.NewMockSplitsRepository(ctrl)
+	rulesRepo := repomocks.NewMockCategoryRulesRepository(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+	notifier := notifiermocks.NewMockLimitNotifier(ctrl)
+	cache := cachemocks.NewMockCache(ctrl)
+	userId := int64(100)
+	date := time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	ctx := context.Background()
+	_, wrapedCtx := opentracing.StartSpanFromContext(ctx, "wrap1")
+
+	processor := NewProcessor(repo, incomesRepo, splitsRepo, rulesRepo, settingsRepo, nil, nil, testConverter, cache, notifier)
+
+	rulesRepo.EXPECT().GetRules(wrapedCtx, userId)
+	repo.EXPECT().GetExpenses(wrapedCtx, gomock.Any(), userId)
+
+	results, err := processor.ImportExpenses(ctx, []ImportItem{
+		{Amount: 10, Currency: "RUB", Category: "Такси", Datetime: date},
+	}, userId, userId, true)
+	assert.NoError(t, err)
+	assert.Equal(t, []ImportItemResult{{Category: "Такси"}}, results)
+}
@@ -11,6 +11,7 @@ import (
 
 	gomock "github.com/golang/mock/gomock"
 	model "gitlab.ozon.dev/cranky4/tg-bot/internal/model"
+	expense_processor "gitlab.ozon.dev/cranky4/tg-bot/internal/service/expense_processor"
 )
 
 // MockExpenseProcessor is a mock of ExpenseProcessor interface.
@@ -36,48 +37,362 @@ func (m *MockExpenseProcessor) EXPECT() *MockExpenseProcessorMockRecorder {
 	return m.recorder
 }
 
+// AddAccount mocks base method.
+func (m *MockExpenseProcessor) AddAccount(ctx context.Context, name, currency string, openingBalance float64, userId int64) (*model.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddAccount", ctx, name, currency, openingBalance, userId)
+	ret0, _ := ret[0].(*model.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddAccount indicates an expected call of AddAccount.
+func (mr *MockExpenseProcessorMockRecorder) AddAccount(ctx, name, currency, openingBalance, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAccount", reflect.TypeOf((*MockExpenseProcessor)(nil).AddAccount), ctx, name, currency, openingBalance, userId)
+}
+
 // AddExpense mocks base method.
-func (m *MockExpenseProcessor) AddExpense(ctx context.Context, amount float64, currency, category string, datetime time.Time, userId int64) (*model.Expense, error) {
+func (m *MockExpenseProcessor) AddExpense(ctx context.Context, amount float64, currency, category, account string, datetime time.Time, userId, authorId int64) (*model.Expense, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AddExpense", ctx, amount, currency, category, datetime, userId)
+	ret := m.ctrl.Call(m, "AddExpense", ctx, amount, currency, category, account, datetime, userId, authorId)
 	ret0, _ := ret[0].(*model.Expense)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // AddExpense indicates an expected call of AddExpense.
-func (mr *MockExpenseProcessorMockRecorder) AddExpense(ctx, amount, currency, category, datetime, userId interface{}) *gomock.Call {
+func (mr *MockExpenseProcessorMockRecorder) AddExpense(ctx, amount, currency, category, account, datetime, userId, authorId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddExpense", reflect.TypeOf((*MockExpenseProcessor)(nil).AddExpense), ctx, amount, currency, category, datetime, userId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddExpense", reflect.TypeOf((*MockExpenseProcessor)(nil).AddExpense), ctx, amount, currency, category, account, datetime, userId, authorId)
 }
 
-// GetFreeLimit mocks base method.
-func (m *MockExpenseProcessor) GetFreeLimit(ctx context.Context, category, currency string, userId int64) (float64, bool, error) {
+// AddIncome mocks base method.
+func (m *MockExpenseProcessor) AddIncome(ctx context.Context, amount float64, currency, source string, datetime time.Time, userId int64) (*model.Income, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetFreeLimit", ctx, category, currency, userId)
-	ret0, _ := ret[0].(float64)
+	ret := m.ctrl.Call(m, "AddIncome", ctx, amount, currency, source, datetime, userId)
+	ret0, _ := ret[0].(*model.Income)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddIncome indicates an expected call of AddIncome.
+func (mr *MockExpenseProcessorMockRecorder) AddIncome(ctx, amount, currency, source, datetime, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddIncome", reflect.TypeOf((*MockExpenseProcessor)(nil).AddIncome), ctx, amount, currency, source, datetime, userId)
+}
+
+// AddRecurringExpense mocks base method.
+func (m *MockExpenseProcessor) AddRecurringExpense(ctx context.Context, recurring model.RecurringExpense, occurrence time.Time) (*model.Expense, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddRecurringExpense", ctx, recurring, occurrence)
+	ret0, _ := ret[0].(*model.Expense)
 	ret1, _ := ret[1].(bool)
 	ret2, _ := ret[2].(error)
 	return ret0, ret1, ret2
 }
 
-// GetFreeLimit indicates an expected call of GetFreeLimit.
-func (mr *MockExpenseProcessorMockRecorder) GetFreeLimit(ctx, category, currency, userId interface{}) *gomock.Call {
+// AddRecurringExpense indicates an expected call of AddRecurringExpense.
+func (mr *MockExpenseProcessorMockRecorder) AddRecurringExpense(ctx, recurring, occurrence interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddRecurringExpense", reflect.TypeOf((*MockExpenseProcessor)(nil).AddRecurringExpense), ctx, recurring, occurrence)
+}
+
+// DeleteCategory mocks base method.
+func (m *MockExpenseProcessor) DeleteCategory(ctx context.Context, userId int64, name string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCategory", ctx, userId, name)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteCategory indicates an expected call of DeleteCategory.
+func (mr *MockExpenseProcessorMockRecorder) DeleteCategory(ctx, userId, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCategory", reflect.TypeOf((*MockExpenseProcessor)(nil).DeleteCategory), ctx, userId, name)
+}
+
+// DeleteCategoryRule mocks base method.
+func (m *MockExpenseProcessor) DeleteCategoryRule(ctx context.Context, userId int64, pattern string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCategoryRule", ctx, userId, pattern)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteCategoryRule indicates an expected call of DeleteCategoryRule.
+func (mr *MockExpenseProcessorMockRecorder) DeleteCategoryRule(ctx, userId, pattern interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCategoryRule", reflect.TypeOf((*MockExpenseProcessor)(nil).DeleteCategoryRule), ctx, userId, pattern)
+}
+
+// DeleteExpense mocks base method.
+func (m *MockExpenseProcessor) DeleteExpense(ctx context.Context, id string, userId int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpense", ctx, id, userId)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpense indicates an expected call of DeleteExpense.
+func (mr *MockExpenseProcessorMockRecorder) DeleteExpense(ctx, id, userId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFreeLimit", reflect.TypeOf((*MockExpenseProcessor)(nil).GetFreeLimit), ctx, category, currency, userId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpense", reflect.TypeOf((*MockExpenseProcessor)(nil).DeleteExpense), ctx, id, userId)
+}
+
+// GetBalances mocks base method.
+func (m *MockExpenseProcessor) GetBalances(ctx context.Context, userId int64) ([]expense_processor.AccountBalance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBalances", ctx, userId)
+	ret0, _ := ret[0].([]expense_processor.AccountBalance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBalances indicates an expected call of GetBalances.
+func (mr *MockExpenseProcessorMockRecorder) GetBalances(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBalances", reflect.TypeOf((*MockExpenseProcessor)(nil).GetBalances), ctx, userId)
+}
+
+// GetCategories mocks base method.
+func (m *MockExpenseProcessor) GetCategories(ctx context.Context, userId int64) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCategories", ctx, userId)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCategories indicates an expected call of GetCategories.
+func (mr *MockExpenseProcessorMockRecorder) GetCategories(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCategories", reflect.TypeOf((*MockExpenseProcessor)(nil).GetCategories), ctx, userId)
+}
+
+// GetCategoryRules mocks base method.
+func (m *MockExpenseProcessor) GetCategoryRules(ctx context.Context, userId int64) ([]model.CategoryRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCategoryRules", ctx, userId)
+	ret0, _ := ret[0].([]model.CategoryRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCategoryRules indicates an expected call of GetCategoryRules.
+func (mr *MockExpenseProcessorMockRecorder) GetCategoryRules(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCategoryRules", reflect.TypeOf((*MockExpenseProcessor)(nil).GetCategoryRules), ctx, userId)
+}
+
+// GetDebts mocks base method.
+func (m *MockExpenseProcessor) GetDebts(ctx context.Context, currency string, userId int64) ([]expense_processor.Debt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDebts", ctx, currency, userId)
+	ret0, _ := ret[0].([]expense_processor.Debt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDebts indicates an expected call of GetDebts.
+func (mr *MockExpenseProcessorMockRecorder) GetDebts(ctx, currency, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDebts", reflect.TypeOf((*MockExpenseProcessor)(nil).GetDebts), ctx, currency, userId)
+}
+
+// GetFreeLimits mocks base method.
+func (m *MockExpenseProcessor) GetFreeLimits(ctx context.Context, category string, userId int64, settings model.UserSettings, now time.Time) ([]expense_processor.FreeLimit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFreeLimits", ctx, category, userId, settings, now)
+	ret0, _ := ret[0].([]expense_processor.FreeLimit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFreeLimits indicates an expected call of GetFreeLimits.
+func (mr *MockExpenseProcessorMockRecorder) GetFreeLimits(ctx, category, userId, settings, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFreeLimits", reflect.TypeOf((*MockExpenseProcessor)(nil).GetFreeLimits), ctx, category, userId, settings, now)
+}
+
+// GetTopCategories mocks base method.
+func (m *MockExpenseProcessor) GetTopCategories(ctx context.Context, userId int64, limit int) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTopCategories", ctx, userId, limit)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTopCategories indicates an expected call of GetTopCategories.
+func (mr *MockExpenseProcessorMockRecorder) GetTopCategories(ctx, userId, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTopCategories", reflect.TypeOf((*MockExpenseProcessor)(nil).GetTopCategories), ctx, userId, limit)
+}
+
+// ImportExpenses mocks base method.
+func (m *MockExpenseProcessor) ImportExpenses(ctx context.Context, items []expense_processor.ImportItem, userId, authorId int64, dryRun bool) ([]expense_processor.ImportItemResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportExpenses", ctx, items, userId, authorId, dryRun)
+	ret0, _ := ret[0].([]expense_processor.ImportItemResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportExpenses indicates an expected call of ImportExpenses.
+func (mr *MockExpenseProcessorMockRecorder) ImportExpenses(ctx, items, userId, authorId, dryRun interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportExpenses", reflect.TypeOf((*MockExpenseProcessor)(nil).ImportExpenses), ctx, items, userId, authorId, dryRun)
+}
+
+// ListExpenses mocks base method.
+func (m *MockExpenseProcessor) ListExpenses(ctx context.Context, dateRange model.DateRange, currency string, userId int64) ([]expense_processor.ExpenseItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListExpenses", ctx, dateRange, currency, userId)
+	ret0, _ := ret[0].([]expense_processor.ExpenseItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListExpenses indicates an expected call of ListExpenses.
+func (mr *MockExpenseProcessorMockRecorder) ListExpenses(ctx, dateRange, currency, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExpenses", reflect.TypeOf((*MockExpenseProcessor)(nil).ListExpenses), ctx, dateRange, currency, userId)
+}
+
+// MergeCategories mocks base method.
+func (m *MockExpenseProcessor) MergeCategories(ctx context.Context, userId int64, from, to string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MergeCategories", ctx, userId, from, to)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MergeCategories indicates an expected call of MergeCategories.
+func (mr *MockExpenseProcessorMockRecorder) MergeCategories(ctx, userId, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MergeCategories", reflect.TypeOf((*MockExpenseProcessor)(nil).MergeCategories), ctx, userId, from, to)
+}
+
+// RenameCategory mocks base method.
+func (m *MockExpenseProcessor) RenameCategory(ctx context.Context, userId int64, name, newName string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenameCategory", ctx, userId, name, newName)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RenameCategory indicates an expected call of RenameCategory.
+func (mr *MockExpenseProcessorMockRecorder) RenameCategory(ctx, userId, name, newName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenameCategory", reflect.TypeOf((*MockExpenseProcessor)(nil).RenameCategory), ctx, userId, name, newName)
+}
+
+// SaveCategoryRule mocks base method.
+func (m *MockExpenseProcessor) SaveCategoryRule(ctx context.Context, rule model.CategoryRule) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveCategoryRule", ctx, rule)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveCategoryRule indicates an expected call of SaveCategoryRule.
+func (mr *MockExpenseProcessorMockRecorder) SaveCategoryRule(ctx, rule interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveCategoryRule", reflect.TypeOf((*MockExpenseProcessor)(nil).SaveCategoryRule), ctx, rule)
 }
 
 // SetLimit mocks base method.
-func (m *MockExpenseProcessor) SetLimit(ctx context.Context, category string, userId int64, amount float64, currency string) (float64, error) {
+func (m *MockExpenseProcessor) SetLimit(ctx context.Context, category string, period model.ExpensePeriod, userId int64, amount float64, currency string) (float64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SetLimit", ctx, category, userId, amount, currency)
+	ret := m.ctrl.Call(m, "SetLimit", ctx, category, period, userId, amount, currency)
 	ret0, _ := ret[0].(float64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // SetLimit indicates an expected call of SetLimit.
-func (mr *MockExpenseProcessorMockRecorder) SetLimit(ctx, category, userId, amount, currency interface{}) *gomock.Call {
+func (mr *MockExpenseProcessorMockRecorder) SetLimit(ctx, category, period, userId, amount, currency interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLimit", reflect.TypeOf((*MockExpenseProcessor)(nil).SetLimit), ctx, category, period, userId, amount, currency)
+}
+
+// SetLimitThresholds mocks base method.
+func (m *MockExpenseProcessor) SetLimitThresholds(ctx context.Context, category string, period model.ExpensePeriod, userId int64, thresholds []int) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetLimitThresholds", ctx, category, period, userId, thresholds)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetLimitThresholds indicates an expected call of SetLimitThresholds.
+func (mr *MockExpenseProcessorMockRecorder) SetLimitThresholds(ctx, category, period, userId, thresholds interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLimitThresholds", reflect.TypeOf((*MockExpenseProcessor)(nil).SetLimitThresholds), ctx, category, period, userId, thresholds)
+}
+
+// SettleDebt mocks base method.
+func (m *MockExpenseProcessor) SettleDebt(ctx context.Context, amount float64, currency string, datetime time.Time, userId, fromUserId, toUserId int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SettleDebt", ctx, amount, currency, datetime, userId, fromUserId, toUserId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SettleDebt indicates an expected call of SettleDebt.
+func (mr *MockExpenseProcessorMockRecorder) SettleDebt(ctx, amount, currency, datetime, userId, fromUserId, toUserId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SettleDebt", reflect.TypeOf((*MockExpenseProcessor)(nil).SettleDebt), ctx, amount, currency, datetime, userId, fromUserId, toUserId)
+}
+
+// SplitExpense mocks base method.
+func (m *MockExpenseProcessor) SplitExpense(ctx context.Context, amount float64, currency, category string, datetime time.Time, userId, payerId int64, participants []int64) (*model.Expense, []expense_processor.Share, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SplitExpense", ctx, amount, currency, category, datetime, userId, payerId, participants)
+	ret0, _ := ret[0].(*model.Expense)
+	ret1, _ := ret[1].([]expense_processor.Share)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// SplitExpense indicates an expected call of SplitExpense.
+func (mr *MockExpenseProcessorMockRecorder) SplitExpense(ctx, amount, currency, category, datetime, userId, payerId, participants interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SplitExpense", reflect.TypeOf((*MockExpenseProcessor)(nil).SplitExpense), ctx, amount, currency, category, datetime, userId, payerId, participants)
+}
+
+// Transfer mocks base method.
+func (m *MockExpenseProcessor) Transfer(ctx context.Context, from, to string, amount float64, currency string, datetime time.Time, userId int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Transfer", ctx, from, to, amount, currency, datetime, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Transfer indicates an expected call of Transfer.
+func (mr *MockExpenseProcessorMockRecorder) Transfer(ctx, from, to, amount, currency, datetime, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Transfer", reflect.TypeOf((*MockExpenseProcessor)(nil).Transfer), ctx, from, to, amount, currency, datetime, userId)
+}
+
+// UpdateExpense mocks base method.
+func (m *MockExpenseProcessor) UpdateExpense(ctx context.Context, id string, amount float64, currency, category string, datetime time.Time, userId int64) (*model.Expense, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateExpense", ctx, id, amount, currency, category, datetime, userId)
+	ret0, _ := ret[0].(*model.Expense)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// UpdateExpense indicates an expected call of UpdateExpense.
+func (mr *MockExpenseProcessorMockRecorder) UpdateExpense(ctx, id, amount, currency, category, datetime, userId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLimit", reflect.TypeOf((*MockExpenseProcessor)(nil).SetLimit), ctx, category, userId, amount, currency)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateExpense", reflect.TypeOf((*MockExpenseProcessor)(nil).UpdateExpense), ctx, id, amount, currency, category, datetime, userId)
 }
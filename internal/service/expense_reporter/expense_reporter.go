@@ -4,6 +4,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/opentracing/opentracing-go"
@@ -16,20 +19,72 @@ import (
 const (
 	primitiveCurrencyMultiplier = 100
 	dateFormat                  = "2006-01-02 15:04:05"
+	// maxTimelinePoints столбцов графика трат по времени, длинный период делится на интервалы в несколько дней
+	maxTimelinePoints = 31
 )
 
 type ExpenseReporter interface {
-	GetReport(ctx context.Context, period model.ExpensePeriod, currencty string, userId int64) (*ExpenseReport, error)
+	// GetReport формирует отчет по тратам бюджета userId со счета account, для пустого account - по всем тратам
+	GetReport(ctx context.Context, period model.ExpensePeriod, dateRange model.DateRange, currency, account string, userId int64) (*ExpenseReport, error)
+	// GetTrend формирует отчет о динамике трат бюджета userId по интервалам
+	GetTrend(ctx context.Context, query model.TrendQuery, currency string, userId int64) (*TrendReport, error)
 }
 
 type ExpenseReport struct {
-	Rows   map[string]float64
-	UserID int64
-	Period model.ExpensePeriod
+	Rows          []ReportRow       // категории по убыванию расходов
+	UserID        int64             // получатель отчета
+	ChatID        int64             // чат, в который отправляется отчет, 0 - личный чат UserID
+	BudgetID      int64             // бюджет, по которому сформирован отчет
+	Members       map[int64]float64 // [участник]расходы, только для общего бюджета
+	Period        model.ExpensePeriod
+	Range         model.DateRange
+	Account       string
+	TotalIncome   float64
+	TotalExpenses float64
+	Balance       float64 // доходы за вычетом расходов
+	Timeline      []TimelinePoint
+	// PreviousRange предыдущий такой же период, с которым сравниваются расходы
+	PreviousRange         model.DateRange
+	PreviousTotalExpenses float64
+}
+
+// ReportRow расходы категории за период
+type ReportRow struct {
+	Category   string
+	Amount     float64
+	Share      float64 // доля в расходах периода, проценты
+	Previous   float64 // расходы категории за предыдущий период
+	Limit      float64 // лимит категории на период отчета, 0 - лимита нет
+	LimitUsage float64 // израсходовано лимита, проценты
+}
+
+// TimelinePoint расходы за интервал периода, интервал начинается в From и длится сутки или несколько суток
+type TimelinePoint struct {
+	From   time.Time
+	Amount float64
+}
+
+// TrendReport траты по интервалам. Без группировки по категориям в отчете есть все интервалы диапазона,
+// с группировкой - только интервалы с тратами, категории внутри интервала по убыванию суммы
+type TrendReport struct {
+	UserID     int64 // получатель отчета
+	ChatID     int64 // чат, в который отправляется отчет, 0 - личный чат UserID
+	BudgetID   int64 // бюджет, по которому сформирован отчет
+	Range      model.DateRange
+	Bucket     model.TrendBucket
+	ByCategory bool
+	Points     []TrendReportPoint
+}
+
+// TrendReportPoint траты за интервал, начинающийся в From, в валюте отчета
+type TrendReportPoint struct {
+	From     time.Time
+	Category string
+	Amount   float64
 }
 
 func (r ExpenseReport) IsEmpty() bool {
-	return len(r.Rows) == 0
+	return len(r.Rows) == 0 && r.TotalIncome == 0
 }
 
 func (r ExpenseReport) MarshalBinary() (data []byte, err error) {
@@ -37,24 +92,48 @@ func (r ExpenseReport) MarshalBinary() (data []byte, err error) {
 }
 
 type reporter struct {
-	repo      repo.ExpensesRepository
-	converter serviceconverter.Converter
-	cache     cache.Cache
+	repo        repo.ExpensesRepository
+	incomesRepo repo.IncomesRepository
+	converter   serviceconverter.Converter
+	cache       cache.Cache
 }
 
-func NewReporter(repo repo.ExpensesRepository, conv serviceconverter.Converter, cache cache.Cache) ExpenseReporter {
+func NewReporter(
+	repo repo.ExpensesRepository,
+	incomesRepo repo.IncomesRepository,
+	conv serviceconverter.Converter,
+	cache cache.Cache,
+) ExpenseReporter {
 	return &reporter{
-		repo:      repo,
-		converter: conv,
-		cache:     cache,
+		repo:        repo,
+		incomesRepo: incomesRepo,
+		converter:   conv,
+		cache:       cache,
 	}
 }
 
-func (r *reporter) GetReport(ctx context.Context, period model.ExpensePeriod, currency string, userId int64) (*ExpenseReport, error) {
+// GetReport формирует отчет за период, для произвольного периода model.Custom используется dateRange
+func (r *reporter) GetReport(
+	ctx context.Context,
+	period model.ExpensePeriod,
+	dateRange model.DateRange,
+	currency, account string,
+	userId int64,
+) (*ExpenseReport, error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "ExpenseReporter_GetReport")
 	defer span.Finish()
 
-	report, ok, err := r.getCached(ctx, userId, period)
+	if dateRange.IsEmpty() {
+		dateRange = period.GetRange(time.Now())
+	}
+
+	version, err := r.getReportsVersion(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	cacheKey := getCacheKey(userId, dateRange, currency, account, version)
+
+	report, ok, err := r.getCached(ctx, cacheKey)
 	if err != nil {
 		return nil, err
 	}
@@ -63,31 +142,109 @@ func (r *reporter) GetReport(ctx context.Context, period model.ExpensePeriod, cu
 		return &report, nil
 	}
 
-	expenses, err := r.repo.GetExpenses(ctx, period, userId)
+	expenses, err := r.repo.GetExpenses(ctx, dateRange, userId)
 	if err != nil {
 		return nil, err
 	}
 
+	// доходы не привязаны к счетам, в отчет по счету не попадают
+	incomes := make([]model.Income, 0)
+	if account == "" {
+		incomes, err = r.incomesRepo.GetIncomes(ctx, dateRange, userId)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	result := make(map[string]int64) // [категория]сумма
+	members := make(map[int64]int64) // [участник]сумма
 	report = ExpenseReport{
-		Rows:   make(map[string]float64),
-		UserID: userId,
-		Period: period,
+		Rows:     make([]ReportRow, 0),
+		UserID:   userId,
+		BudgetID: userId,
+		Period:   period,
+		Range:    dateRange,
+		Account:  account,
 	}
 
+	var totalExpenses, totalIncome int64
 	for _, e := range expenses {
-		if e.UserId == userId {
+		if includeExpense(e, account) {
 			result[e.Category] += e.Amount
+			members[e.AuthorId] += e.Amount
+			totalExpenses += e.Amount
+		}
+	}
+
+	for _, income := range incomes {
+		totalIncome += income.Amount
+	}
+
+	if totalExpenses > 0 {
+		// сравнение с предыдущим периодом
+		report.PreviousRange = dateRange.Previous()
+		previousExpenses, err := r.repo.GetExpenses(ctx, report.PreviousRange, userId)
+		if err != nil {
+			return nil, err
+		}
+
+		previous := make(map[string]int64) // [категория]сумма
+		var previousTotal int64
+		for _, e := range previousExpenses {
+			if includeExpense(e, account) {
+				previous[e.Category] += e.Amount
+				previousTotal += e.Amount
+			}
+		}
+		report.PreviousTotalExpenses = r.fromPrimitive(previousTotal, currency)
+
+		for category, amount := range result {
+			row := ReportRow{
+				Category: category,
+				Amount:   r.fromPrimitive(amount, currency),
+				Share:    float64(amount) / float64(totalExpenses) * 100,
+				Previous: r.fromPrimitive(previous[category], currency),
+			}
+
+			// лимиты считают траты со всех счетов, поэтому в отчет по счету не попадают
+			if account == "" && period != model.Custom {
+				limit, ok, err := r.getCategoryLimit(ctx, category, period, userId)
+				if err != nil {
+					return nil, err
+				}
+				if ok && limit.Amount > 0 {
+					row.Limit = r.fromPrimitive(limit.Amount, currency)
+					row.LimitUsage = float64(amount) / float64(limit.Amount) * 100
+				}
+			}
+
+			report.Rows = append(report.Rows, row)
 		}
+
+		sort.Slice(report.Rows, func(i, j int) bool {
+			if report.Rows[i].Amount != report.Rows[j].Amount {
+				return report.Rows[i].Amount > report.Rows[j].Amount
+			}
+			return report.Rows[i].Category < report.Rows[j].Category
+		})
 	}
 
-	for category, amount := range result {
-		converted := r.converter.FromRUB(float64(amount/primitiveCurrencyMultiplier), currency)
+	if model.IsSharedBudget(userId) {
+		report.Members = make(map[int64]float64, len(members))
+		for member, amount := range members {
+			report.Members[member] = r.fromPrimitive(amount, currency)
+		}
+	}
 
-		report.Rows[category] = converted
+	if totalExpenses > 0 {
+		report.Timeline = r.getTimeline(expenses, dateRange, currency, account)
 	}
 
-	err = r.cache.Set(ctx, getCacheKey(userId, period), report, 24*time.Hour)
+	report.TotalExpenses = r.fromPrimitive(totalExpenses, currency)
+	report.TotalIncome = r.fromPrimitive(totalIncome, currency)
+	report.Balance = r.fromPrimitive(totalIncome-totalExpenses, currency)
+
+	err = r.cache.Set(ctx, cacheKey, report, 24*time.Hour)
 	if err != nil {
 		return nil, err
 	}
@@ -95,11 +252,125 @@ func (r *reporter) GetReport(ctx context.Context, period model.ExpensePeriod, cu
 	return &report, nil
 }
 
-func (r *reporter) getCached(ctx context.Context, userId int64, period model.ExpensePeriod) (ExpenseReport, bool, error) {
+// GetTrend группирует траты по интервалам запроса в часовом поясе query.Location
+func (r *reporter) GetTrend(ctx context.Context, query model.TrendQuery, currency string, userId int64) (*TrendReport, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "ExpenseReporter_GetTrend")
+	defer span.Finish()
+
+	points, err := r.repo.GetTrend(ctx, query, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	trend := &TrendReport{
+		UserID:     userId,
+		BudgetID:   userId,
+		Range:      query.Range,
+		Bucket:     query.Bucket,
+		ByCategory: query.ByCategory,
+		Points:     make([]TrendReportPoint, 0, len(points)),
+	}
+
+	if query.ByCategory {
+		for _, point := range points {
+			trend.Points = append(trend.Points, TrendReportPoint{
+				From:     point.From,
+				Category: point.Category,
+				Amount:   r.fromPrimitive(point.Amount, currency),
+			})
+		}
+
+		return trend, nil
+	}
+
+	amounts := make(map[int64]int64, len(points)) // [начало интервала]сумма
+	for _, point := range points {
+		amounts[point.From.Unix()] += point.Amount
+	}
+
+	for _, from := range query.Bucket.Buckets(query.Range.In(query.Location), query.WeekStart) {
+		trend.Points = append(trend.Points, TrendReportPoint{
+			From:   from,
+			Amount: r.fromPrimitive(amounts[from.Unix()], currency),
+		})
+	}
+
+	return trend, nil
+}
+
+// getCategoryLimit возвращает лимит категории на период отчета
+func (r *reporter) getCategoryLimit(
+	ctx context.Context,
+	category string,
+	period model.ExpensePeriod,
+	userId int64,
+) (model.ExpenseLimit, bool, error) {
+	limits, err := r.repo.GetLimits(ctx, category, userId)
+	if err != nil {
+		return model.ExpenseLimit{}, false, err
+	}
+
+	for _, limit := range limits {
+		if limit.Scope == model.CategoryLimitScope && limit.Period == period {
+			return limit, true, nil
+		}
+	}
+
+	return model.ExpenseLimit{}, false, nil
+}
+
+// includeExpense проверяет, что трата относится к счету отчета
+func includeExpense(e *model.Expense, account string) bool {
+	return account == "" || strings.EqualFold(e.Account, account)
+}
+
+// getTimeline делит период на интервалы по суткам от начала периода, так что границы интервалов
+// совпадают с полуночью пользователя. Если суток больше maxTimelinePoints, интервал длится несколько суток
+func (r *reporter) getTimeline(
+	expenses []*model.Expense,
+	dateRange model.DateRange,
+	currency, account string,
+) []TimelinePoint {
+	days := int((dateRange.To.Sub(dateRange.From) + 24*time.Hour - 1) / (24 * time.Hour))
+	if days < 1 {
+		return nil
+	}
+
+	stepDays := (days + maxTimelinePoints - 1) / maxTimelinePoints
+	step := time.Duration(stepDays) * 24 * time.Hour
+	amounts := make([]int64, (days+stepDays-1)/stepDays)
+
+	for _, e := range expenses {
+		// время траты - показания часов пользователя, диапазон задан в его поясе
+		datetime := model.WallClock(e.Datetime, dateRange.From.Location())
+		if !includeExpense(e, account) || !dateRange.Contains(datetime) {
+			continue
+		}
+
+		amounts[int(datetime.Sub(dateRange.From)/step)] += e.Amount
+	}
+
+	timeline := make([]TimelinePoint, 0, len(amounts))
+	for i, amount := range amounts {
+		timeline = append(timeline, TimelinePoint{
+			From:   dateRange.From.Add(time.Duration(i) * step),
+			Amount: r.fromPrimitive(amount, currency),
+		})
+	}
+
+	return timeline
+}
+
+// fromPrimitive переводит сумму в копейках рублей в валюту отчета
+func (r *reporter) fromPrimitive(amount int64, currency string) float64 {
+	return r.converter.FromRUB(float64(amount)/primitiveCurrencyMultiplier, currency)
+}
+
+func (r *reporter) getCached(ctx context.Context, cacheKey string) (ExpenseReport, bool, error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "ExpenseReporter_getCached")
 	defer span.Finish()
 
-	value, ok, err := r.cache.Get(ctx, getCacheKey(userId, period))
+	value, ok, err := r.cache.Get(ctx, cacheKey)
 	if err != nil {
 		return ExpenseReport{}, false, err
 	}
@@ -120,6 +391,35 @@ func (r *reporter) getCached(ctx context.Context, userId int64, period model.Exp
 	return ExpenseReport{}, false, nil
 }
 
-func getCacheKey(userId int64, period model.ExpensePeriod) string {
-	return fmt.Sprintf("%d-%v-%s", userId, period, time.Now().Format("2006-01-02"))
+// getReportsVersion возвращает версию закешированных отчетов пользователя,
+// при ее отсутствии создается новая, так что ранее закешированные отчеты не используются
+func (r *reporter) getReportsVersion(ctx context.Context, userId int64) (string, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "ExpenseReporter_getReportsVersion")
+	defer span.Finish()
+
+	value, ok, err := r.cache.Get(ctx, cache.ReportsVersionKey(userId))
+	if err != nil {
+		return "", err
+	}
+
+	if ok {
+		return fmt.Sprintf("%v", value), nil
+	}
+
+	version := strconv.FormatInt(time.Now().UnixNano(), 10)
+	if err = r.cache.Set(ctx, cache.ReportsVersionKey(userId), version, 0); err != nil {
+		return "", err
+	}
+
+	return version, nil
+}
+
+func getCacheKey(userId int64, dateRange model.DateRange, currency, account, version string) string {
+	// точные границы в UTC: одинаковые даты в разных часовых поясах - разные диапазоны
+	period := dateRange.From.UTC().Format(time.RFC3339) + ".." + dateRange.To.UTC().Format(time.RFC3339)
+	if account != "" {
+		return fmt.Sprintf("%d-%s-%s-%s-%s", userId, period, currency, strings.ToLower(account), version)
+	}
+
+	return fmt.Sprintf("%d-%s-%s-%s", userId, period, currency, version)
 }
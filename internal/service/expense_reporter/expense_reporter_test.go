@@ -3,7 +3,6 @@ package expense_reporter
 import (
 	"context"
 	"errors"
-	"fmt"
 	"testing"
 	"time"
 
@@ -34,6 +33,7 @@ var testConverter = serviceconverter.NewConverter(&testGetter{})
 func TestGetReportWithSuccess(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	repo := repomocks.NewMockExpensesRepository(ctrl)
+	incomesRepo := repomocks.NewMockIncomesRepository(ctrl)
 	userId := int64(100)
 	period := model.Week
 
@@ -44,18 +44,31 @@ func TestGetReportWithSuccess(t *testing.T) {
 	_, wrapedCtx := opentracing.StartSpanFromContext(ctx, "wrap1")
 	_, wrapedCtx2 := opentracing.StartSpanFromContext(wrapedCtx, "wrap2")
 
+	dateRange := period.GetRange(time.Now())
+
 	cache := cachemocks.NewMockCache(ctrl)
-	cacheKey := fmt.Sprintf("%d-%v-%s", userId, period, time.Now().Format("2006-01-02"))
+	cache.EXPECT().Get(wrapedCtx2, "reports-version-100").Return("1", true, nil)
+	cacheKey := getCacheKey(userId, dateRange, "RUB", "", "1")
 	cache.EXPECT().Get(wrapedCtx2, cacheKey).Return(nil, false, nil)
 	cache.EXPECT().Set(wrapedCtx, cacheKey, ExpenseReport{
-		Rows:   map[string]float64{"Категория": 125},
-		UserID: userId,
-		Period: period,
+		Rows: []ReportRow{
+			{Category: "Категория", Amount: 125.5, Share: 100, Previous: 100, Limit: 250, LimitUsage: 50.2},
+		},
+		UserID:                userId,
+		BudgetID:              userId,
+		Period:                period,
+		Range:                 dateRange,
+		TotalIncome:           1000,
+		TotalExpenses:         125.5,
+		Balance:               874.5,
+		Timeline:              zeroTimeline(dateRange),
+		PreviousRange:         dateRange.Previous(),
+		PreviousTotalExpenses: 150,
 	}, 24*time.Hour)
 
-	reporter := NewReporter(repo, testConverter, cache)
+	reporter := NewReporter(repo, incomesRepo, testConverter, cache)
 
-	repo.EXPECT().GetExpenses(wrapedCtx, period, userId).Return([]*model.Expense{
+	repo.EXPECT().GetExpenses(wrapedCtx, dateRange, userId).Return([]*model.Expense{
 		{
 			Amount:   12550,
 			Category: "Категория",
@@ -63,8 +76,25 @@ func TestGetReportWithSuccess(t *testing.T) {
 			UserId:   userId,
 		},
 	}, nil)
+	incomesRepo.EXPECT().GetIncomes(wrapedCtx, dateRange, userId).Return([]model.Income{
+		{
+			Amount:   100000,
+			Source:   "Зарплата",
+			Datetime: date,
+			UserId:   userId,
+		},
+	}, nil)
+	repo.EXPECT().GetExpenses(wrapedCtx, dateRange.Previous(), userId).Return([]*model.Expense{
+		{Amount: 10000, Category: "Категория", UserId: userId},
+		{Amount: 5000, Category: "Дом", UserId: userId},
+	}, nil)
+	repo.EXPECT().GetLimits(wrapedCtx, "Категория", userId).Return([]model.ExpenseLimit{
+		{Scope: model.TotalLimitScope, Period: model.Week, Amount: 100000, UserId: userId},
+		{Scope: model.CategoryLimitScope, Period: model.Month, Category: "Категория", Amount: 100000, UserId: userId},
+		{Scope: model.CategoryLimitScope, Period: model.Week, Category: "Категория", Amount: 25000, UserId: userId},
+	}, nil)
 
-	report, err := reporter.GetReport(ctx, period, "RUB", userId)
+	report, err := reporter.GetReport(ctx, period, model.DateRange{}, "RUB", "", userId)
 	assert.NoError(t, err)
 	assert.False(t, report.IsEmpty())
 	assert.Len(t, report.Rows, 1)
@@ -73,6 +103,7 @@ func TestGetReportWithSuccess(t *testing.T) {
 func TestGetReportWithEmptyReport(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	repo := repomocks.NewMockExpensesRepository(ctrl)
+	incomesRepo := repomocks.NewMockIncomesRepository(ctrl)
 	userId := int64(100)
 	period := model.Week
 
@@ -80,20 +111,26 @@ func TestGetReportWithEmptyReport(t *testing.T) {
 	_, wrapedCtx := opentracing.StartSpanFromContext(ctx, "wrap1")
 	_, wrapedCtx2 := opentracing.StartSpanFromContext(wrapedCtx, "wrap2")
 
+	dateRange := period.GetRange(time.Now())
+
 	cache := cachemocks.NewMockCache(ctrl)
-	cacheKey := fmt.Sprintf("%d-%v-%s", userId, period, time.Now().Format("2006-01-02"))
+	cache.EXPECT().Get(wrapedCtx2, "reports-version-100").Return("1", true, nil)
+	cacheKey := getCacheKey(userId, dateRange, "RUB", "", "1")
 	cache.EXPECT().Get(wrapedCtx2, cacheKey).Return(nil, false, nil)
 	cache.EXPECT().Set(wrapedCtx, cacheKey, ExpenseReport{
-		Rows:   map[string]float64{},
-		UserID: userId,
-		Period: period,
+		Rows:     []ReportRow{},
+		UserID:   userId,
+		BudgetID: userId,
+		Period:   period,
+		Range:    dateRange,
 	}, 24*time.Hour)
 
-	reporter := NewReporter(repo, testConverter, cache)
+	reporter := NewReporter(repo, incomesRepo, testConverter, cache)
 
-	repo.EXPECT().GetExpenses(wrapedCtx, period, userId).Return([]*model.Expense{}, nil)
+	repo.EXPECT().GetExpenses(wrapedCtx, dateRange, userId).Return([]*model.Expense{}, nil)
+	incomesRepo.EXPECT().GetIncomes(wrapedCtx, dateRange, userId).Return([]model.Income{}, nil)
 
-	report, err := reporter.GetReport(ctx, period, "RUB", userId)
+	report, err := reporter.GetReport(ctx, period, model.DateRange{}, "RUB", "", userId)
 	assert.NoError(t, err)
 	assert.True(t, report.IsEmpty())
 	assert.Len(t, report.Rows, 0)
@@ -102,6 +139,7 @@ func TestGetReportWithEmptyReport(t *testing.T) {
 func TestGetReportWithDBError(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	repo := repomocks.NewMockExpensesRepository(ctrl)
+	incomesRepo := repomocks.NewMockIncomesRepository(ctrl)
 	userId := int64(100)
 	period := model.Week
 
@@ -109,15 +147,282 @@ func TestGetReportWithDBError(t *testing.T) {
 	_, wrapedCtx := opentracing.StartSpanFromContext(ctx, "wrap1")
 	_, wrapedCtx2 := opentracing.StartSpanFromContext(wrapedCtx, "wrap2")
 
+	dateRange := period.GetRange(time.Now())
+
 	cache := cachemocks.NewMockCache(ctrl)
-	cacheKey := fmt.Sprintf("%d-%v-%s", userId, period, time.Now().Format("2006-01-02"))
+	cache.EXPECT().Get(wrapedCtx2, "reports-version-100").Return("1", true, nil)
+	cacheKey := getCacheKey(userId, dateRange, "RUB", "", "1")
 	cache.EXPECT().Get(wrapedCtx2, cacheKey).Return(nil, false, nil)
 
-	reporter := NewReporter(repo, testConverter, cache)
+	reporter := NewReporter(repo, incomesRepo, testConverter, cache)
 
-	repo.EXPECT().GetExpenses(wrapedCtx, period, userId).Return([]*model.Expense{}, errors.New("database error"))
+	repo.EXPECT().GetExpenses(wrapedCtx, dateRange, userId).Return([]*model.Expense{}, errors.New("database error"))
 
-	report, err := reporter.GetReport(ctx, period, "RUB", userId)
+	report, err := reporter.GetReport(ctx, period, model.DateRange{}, "RUB", "", userId)
 	assert.Error(t, err)
 	assert.Nil(t, report)
 }
+
+func TestGetReportForCustomRangeWithSuccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := repomocks.NewMockExpensesRepository(ctrl)
+	incomesRepo := repomocks.NewMockIncomesRepository(ctrl)
+	userId := int64(100)
+
+	from, err := time.Parse("2006-01-02", "2022-09-01")
+	assert.NoError(t, err)
+	dateRange := model.NewDateRange(from, from.AddDate(0, 1, 0))
+
+	ctx := context.Background()
+	_, wrapedCtx := opentracing.StartSpanFromContext(ctx, "wrap1")
+	_, wrapedCtx2 := opentracing.StartSpanFromContext(wrapedCtx, "wrap2")
+
+	cache := cachemocks.NewMockCache(ctrl)
+	cache.EXPECT().Get(wrapedCtx2, "reports-version-100").Return(nil, false, nil)
+	cache.EXPECT().Set(wrapedCtx2, "reports-version-100", gomock.Any(), time.Duration(0))
+	cache.EXPECT().Get(wrapedCtx2, gomock.Any()).Return(nil, false, nil)
+	cache.EXPECT().Set(wrapedCtx, gomock.Any(), gomock.Any(), 24*time.Hour)
+
+	reporter := NewReporter(repo, incomesRepo, testConverter, cache)
+
+	repo.EXPECT().GetExpenses(wrapedCtx, dateRange, userId).Return([]*model.Expense{
+		{
+			Amount:   12550,
+			Category: "Категория",
+			Datetime: from,
+			UserId:   userId,
+		},
+	}, nil)
+	incomesRepo.EXPECT().GetIncomes(wrapedCtx, dateRange, userId).Return([]model.Income{}, nil)
+	repo.EXPECT().GetExpenses(wrapedCtx, model.NewDateRange(from.AddDate(0, -1, 0), from), userId).Return([]*model.Expense{}, nil)
+
+	report, err := reporter.GetReport(ctx, model.Custom, dateRange, "RUB", "", userId)
+	assert.NoError(t, err)
+	assert.Equal(t, "2022-09-01..2022-09-30", report.Range.String())
+	assert.Len(t, report.Rows, 1)
+	assert.Len(t, report.Timeline, 30)
+	assert.Equal(t, TimelinePoint{From: from, Amount: 125.5}, report.Timeline[0])
+	assert.Equal(t, TimelinePoint{From: from.AddDate(0, 0, 29)}, report.Timeline[29])
+}
+
+func TestGetReportTimelineShouldUseStoredWallClock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := repomocks.NewMockExpensesRepository(ctrl)
+	incomesRepo := repomocks.NewMockIncomesRepository(ctrl)
+	userId := int64(100)
+
+	moscow := time.FixedZone("", 3*60*60)
+	from := time.Date(2022, 9, 1, 0, 0, 0, 0, moscow)
+	dateRange := model.NewDateRange(from, from.AddDate(0, 1, 0))
+
+	ctx := context.Background()
+	_, wrapedCtx := opentracing.StartSpanFromContext(ctx, "wrap1")
+	_, wrapedCtx2 := opentracing.StartSpanFromContext(wrapedCtx, "wrap2")
+
+	cache := cachemocks.NewMockCache(ctrl)
+	cache.EXPECT().Get(wrapedCtx2, "reports-version-100").Return("1", true, nil)
+	cache.EXPECT().Get(wrapedCtx2, gomock.Any()).Return(nil, false, nil)
+	cache.EXPECT().Set(wrapedCtx, gomock.Any(), gomock.Any(), 24*time.Hour)
+
+	reporter := NewReporter(repo, incomesRepo, testConverter, cache)
+
+	// база возвращает часы пользователя с меткой UTC: траты перед полуночью остаются в своих сутках
+	repo.EXPECT().GetExpenses(wrapedCtx, dateRange, userId).Return([]*model.Expense{
+		{Amount: 10000, Category: "Кафе", Datetime: time.Date(2022, 9, 1, 23, 30, 0, 0, time.UTC), UserId: userId},
+		{Amount: 5000, Category: "Дом", Datetime: time.Date(2022, 9, 30, 23, 30, 0, 0, time.UTC), UserId: userId},
+	}, nil)
+	incomesRepo.EXPECT().GetIncomes(wrapedCtx, dateRange, userId).Return([]model.Income{}, nil)
+	repo.EXPECT().GetExpenses(wrapedCtx, gomock.Any(), userId).Return([]*model.Expense{}, nil)
+
+	report, err := reporter.GetReport(ctx, model.Custom, dateRange, "RUB", "", userId)
+	assert.NoError(t, err)
+	assert.Len(t, report.Timeline, 30)
+	assert.Equal(t, TimelinePoint{From: from, Amount: 100}, report.Timeline[0])
+	assert.Equal(t, TimelinePoint{From: from.AddDate(0, 0, 1)}, report.Timeline[1])
+	assert.Equal(t, TimelinePoint{From: from.AddDate(0, 0, 29), Amount: 50}, report.Timeline[29])
+}
+
+func TestGetReportForYearShouldGroupTimelineByWeeks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := repomocks.NewMockExpensesRepository(ctrl)
+	incomesRepo := repomocks.NewMockIncomesRepository(ctrl)
+	userId := int64(100)
+
+	from := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	dateRange := model.NewDateRange(from, from.AddDate(1, 0, 0))
+
+	ctx := context.Background()
+	_, wrapedCtx := opentracing.StartSpanFromContext(ctx, "wrap1")
+	_, wrapedCtx2 := opentracing.StartSpanFromContext(wrapedCtx, "wrap2")
+
+	cache := cachemocks.NewMockCache(ctrl)
+	cache.EXPECT().Get(wrapedCtx2, "reports-version-100").Return("1", true, nil)
+	cache.EXPECT().Get(wrapedCtx2, gomock.Any()).Return(nil, false, nil)
+	cache.EXPECT().Set(wrapedCtx, gomock.Any(), gomock.Any(), 24*time.Hour)
+
+	reporter := NewReporter(repo, incomesRepo, testConverter, cache)
+
+	repo.EXPECT().GetExpenses(wrapedCtx, dateRange, userId).Return([]*model.Expense{
+		{Amount: 10000, Category: "Кафе", Datetime: from.AddDate(0, 0, 12), UserId: userId},
+		{Amount: 5000, Category: "Дом", Datetime: from.AddDate(0, 0, 13), UserId: userId},
+		{Amount: 20000, Category: "Дом", Datetime: from.AddDate(0, 0, 364), UserId: userId},
+	}, nil)
+	incomesRepo.EXPECT().GetIncomes(wrapedCtx, dateRange, userId).Return([]model.Income{}, nil)
+	repo.EXPECT().GetExpenses(wrapedCtx, model.NewDateRange(from.AddDate(-1, 0, 0), from), userId).Return([]*model.Expense{}, nil)
+
+	report, err := reporter.GetReport(ctx, model.Custom, dateRange, "RUB", "", userId)
+	assert.NoError(t, err)
+	// категории по убыванию расходов
+	assert.Len(t, report.Rows, 2)
+	assert.Equal(t, "Дом", report.Rows[0].Category)
+	assert.Equal(t, 250.0, report.Rows[0].Amount)
+	assert.InDelta(t, 71.43, report.Rows[0].Share, 0.01)
+	assert.Equal(t, "Кафе", report.Rows[1].Category)
+	assert.InDelta(t, 28.57, report.Rows[1].Share, 0.01)
+	// 365 дней по 12 дней в интервале
+	assert.Len(t, report.Timeline, 31)
+	assert.Equal(t, TimelinePoint{From: from.AddDate(0, 0, 12), Amount: 150}, report.Timeline[1])
+	assert.Equal(t, TimelinePoint{From: from.AddDate(0, 0, 360), Amount: 200}, report.Timeline[30])
+}
+
+func TestGetReportForAccountShouldSkipOtherAccountsAndIncomes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := repomocks.NewMockExpensesRepository(ctrl)
+	incomesRepo := repomocks.NewMockIncomesRepository(ctrl)
+	userId := int64(100)
+	period := model.Month
+
+	ctx := context.Background()
+	_, wrapedCtx := opentracing.StartSpanFromContext(ctx, "wrap1")
+	_, wrapedCtx2 := opentracing.StartSpanFromContext(wrapedCtx, "wrap2")
+
+	dateRange := period.GetRange(time.Now())
+
+	cache := cachemocks.NewMockCache(ctrl)
+	cache.EXPECT().Get(wrapedCtx2, "reports-version-100").Return("1", true, nil)
+	cacheKey := getCacheKey(userId, dateRange, "RUB", "Карта", "1")
+	cache.EXPECT().Get(wrapedCtx2, cacheKey).Return(nil, false, nil)
+	cache.EXPECT().Set(wrapedCtx, cacheKey, ExpenseReport{
+		Rows:          []ReportRow{{Category: "Кафе", Amount: 350, Share: 100, Previous: 100}},
+		UserID:        userId,
+		BudgetID:      userId,
+		Period:        period,
+		Range:         dateRange,
+		Account:       "Карта",
+		TotalExpenses: 350,
+		Balance:       -350,
+		Timeline:      zeroTimeline(dateRange),
+		PreviousRange: dateRange.Previous(),
+		// траты с других счетов не сравниваются
+		PreviousTotalExpenses: 100,
+	}, 24*time.Hour)
+
+	reporter := NewReporter(repo, incomesRepo, testConverter, cache)
+
+	repo.EXPECT().GetExpenses(wrapedCtx, dateRange, userId).Return([]*model.Expense{
+		{Amount: 35000, Category: "Кафе", Account: "Карта", UserId: userId},
+		{Amount: 12000, Category: "Кафе", Account: "Наличные", UserId: userId},
+		{Amount: 50000, Category: "Дом", UserId: userId},
+	}, nil)
+	repo.EXPECT().GetExpenses(wrapedCtx, dateRange.Previous(), userId).Return([]*model.Expense{
+		{Amount: 10000, Category: "Кафе", Account: "карта", UserId: userId},
+		{Amount: 30000, Category: "Кафе", Account: "Наличные", UserId: userId},
+	}, nil)
+
+	report, err := reporter.GetReport(ctx, period, model.DateRange{}, "RUB", "Карта", userId)
+	assert.NoError(t, err)
+	assert.Len(t, report.Rows, 1)
+}
+
+// zeroTimeline интервалы по суткам периода без трат
+func zeroTimeline(dateRange model.DateRange) []TimelinePoint {
+	timeline := make([]TimelinePoint, 0)
+	for from := dateRange.From; from.Before(dateRange.To); from = from.Add(24 * time.Hour) {
+		timeline = append(timeline, TimelinePoint{From: from})
+	}
+
+	return timeline
+}
+
+func TestGetTrendShouldFillEmptyBuckets(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := repomocks.NewMockExpensesRepository(ctrl)
+	userId := int64(100)
+
+	moscow := time.FixedZone("MSK", 3*60*60)
+	from := time.Date(2022, 10, 1, 0, 0, 0, 0, moscow)
+	query := model.TrendQuery{
+		// диапазон приходит в UTC
+		Range:     model.NewDateRange(from, from.AddDate(0, 0, 3)).In(time.UTC),
+		Bucket:    model.DayTrendBucket,
+		Location:  moscow,
+		WeekStart: time.Monday,
+	}
+
+	ctx := context.Background()
+	_, wrapedCtx := opentracing.StartSpanFromContext(ctx, "wrap1")
+
+	repo.EXPECT().GetTrend(wrapedCtx, query, userId).Return([]model.TrendPoint{
+		{From: from.AddDate(0, 0, 2), Amount: 12550},
+	}, nil)
+
+	reporter := NewReporter(repo, repomocks.NewMockIncomesRepository(ctrl), testConverter, cachemocks.NewMockCache(ctrl))
+
+	trend, err := reporter.GetTrend(ctx, query, "RUB", userId)
+	assert.NoError(t, err)
+	assert.Equal(t, []TrendReportPoint{
+		{From: from},
+		{From: from.AddDate(0, 0, 1)},
+		{From: from.AddDate(0, 0, 2), Amount: 125.5},
+	}, trend.Points)
+}
+
+func TestGetTrendByCategoryShouldKeepRepositoryOrder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := repomocks.NewMockExpensesRepository(ctrl)
+	userId := int64(100)
+
+	from := time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC)
+	query := model.TrendQuery{
+		Range:      model.NewDateRange(from, from.AddDate(0, 3, 0)),
+		Bucket:     model.MonthTrendBucket,
+		ByCategory: true,
+		Location:   time.UTC,
+	}
+
+	ctx := context.Background()
+	_, wrapedCtx := opentracing.StartSpanFromContext(ctx, "wrap1")
+
+	repo.EXPECT().GetTrend(wrapedCtx, query, userId).Return([]model.TrendPoint{
+		{From: from, Category: "Дом", Amount: 20000},
+		{From: from, Category: "Кафе", Amount: 10000},
+		{From: from.AddDate(0, 2, 0), Category: "Кафе", Amount: 5000},
+	}, nil)
+
+	reporter := NewReporter(repo, repomocks.NewMockIncomesRepository(ctrl), testConverter, cachemocks.NewMockCache(ctrl))
+
+	trend, err := reporter.GetTrend(ctx, query, "RUB", userId)
+	assert.NoError(t, err)
+	assert.Equal(t, &TrendReport{
+		UserID:     userId,
+		BudgetID:   userId,
+		Range:      query.Range,
+		Bucket:     model.MonthTrendBucket,
+		ByCategory: true,
+		Points: []TrendReportPoint{
+			{From: from, Category: "Дом", Amount: 200},
+			{From: from, Category: "Кафе", Amount: 100},
+			{From: from.AddDate(0, 2, 0), Category: "Кафе", Amount: 50},
+		},
+	}, trend)
+}
+
+func TestCacheKeyShouldDependOnRangeTimezone(t *testing.T) {
+	moscow := time.FixedZone("MSK", 3*60*60)
+	utcRange := model.NewDateRange(time.Date(2022, 11, 1, 0, 0, 0, 0, time.UTC), time.Date(2022, 11, 2, 0, 0, 0, 0, time.UTC))
+	moscowRange := model.NewDateRange(time.Date(2022, 11, 1, 0, 0, 0, 0, moscow), time.Date(2022, 11, 2, 0, 0, 0, 0, moscow))
+
+	assert.Equal(t, utcRange.String(), moscowRange.String())
+	assert.NotEqual(t, getCacheKey(100, utcRange, "RUB", "", "1"), getCacheKey(100, moscowRange, "RUB", "", "1"))
+	assert.Equal(t, "100-2022-10-31T21:00:00Z..2022-11-01T21:00:00Z-RUB-1", getCacheKey(100, moscowRange, "RUB", "", "1"))
+}
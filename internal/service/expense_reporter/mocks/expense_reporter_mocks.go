@@ -37,16 +37,31 @@ func (m *MockExpenseReporter) EXPECT() *MockExpenseReporterMockRecorder {
 }
 
 // GetReport mocks base method.
-func (m *MockExpenseReporter) GetReport(ctx context.Context, period model.ExpensePeriod, currencty string, userId int64) (*expense_reporter.ExpenseReport, error) {
+func (m *MockExpenseReporter) GetReport(ctx context.Context, period model.ExpensePeriod, dateRange model.DateRange, currency, account string, userId int64) (*expense_reporter.ExpenseReport, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetReport", ctx, period, currencty, userId)
+	ret := m.ctrl.Call(m, "GetReport", ctx, period, dateRange, currency, account, userId)
 	ret0, _ := ret[0].(*expense_reporter.ExpenseReport)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetReport indicates an expected call of GetReport.
-func (mr *MockExpenseReporterMockRecorder) GetReport(ctx, period, currencty, userId interface{}) *gomock.Call {
+func (mr *MockExpenseReporterMockRecorder) GetReport(ctx, period, dateRange, currency, account, userId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReport", reflect.TypeOf((*MockExpenseReporter)(nil).GetReport), ctx, period, currencty, userId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReport", reflect.TypeOf((*MockExpenseReporter)(nil).GetReport), ctx, period, dateRange, currency, account, userId)
+}
+
+// GetTrend mocks base method.
+func (m *MockExpenseReporter) GetTrend(ctx context.Context, query model.TrendQuery, currency string, userId int64) (*expense_reporter.TrendReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTrend", ctx, query, currency, userId)
+	ret0, _ := ret[0].(*expense_reporter.TrendReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTrend indicates an expected call of GetTrend.
+func (mr *MockExpenseReporterMockRecorder) GetTrend(ctx, query, currency, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTrend", reflect.TypeOf((*MockExpenseReporter)(nil).GetTrend), ctx, query, currency, userId)
 }
@@ -0,0 +1,49 @@
+package limitnotifier
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/opentracing/opentracing-go"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/model"
+)
+
+const (
+	msgCategoryLimitAlert = "%s лимит категории %s израсходован на %d%%. Свободно %.02f %s"
+	msgTotalLimitAlert    = "Общий %s лимит израсходован на %d%%. Свободно %.02f %s"
+)
+
+// MessageSender отправляет сообщение пользователю
+type MessageSender interface {
+	SendMessage(text string, userID int64, buttons []string) error
+}
+
+// LimitNotifier уведомляет пользователя о превышении порога лимита
+type LimitNotifier interface {
+	Notify(ctx context.Context, alert model.LimitAlert) error
+}
+
+type limitNotifier struct {
+	sender MessageSender
+}
+
+func NewLimitNotifier(sender MessageSender) LimitNotifier {
+	return &limitNotifier{
+		sender: sender,
+	}
+}
+
+func (n *limitNotifier) Notify(ctx context.Context, alert model.LimitAlert) error {
+	span, _ := opentracing.StartSpanFromContext(ctx, "LimitNotifier_Notify")
+	defer span.Finish()
+
+	var text string
+	if alert.Scope == model.TotalLimitScope {
+		text = fmt.Sprintf(msgTotalLimitAlert, strings.ToLower(alert.Period.String()), alert.Threshold, alert.Free, alert.Currency)
+	} else {
+		text = fmt.Sprintf(msgCategoryLimitAlert, alert.Period.String(), alert.Category, alert.Threshold, alert.Free, alert.Currency)
+	}
+
+	return n.sender.SendMessage(text, alert.UserID, nil)
+}
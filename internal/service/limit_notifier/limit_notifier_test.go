@@ -0,0 +1,52 @@
+package limitnotifier
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/model"
+)
+
+type testSender struct {
+	text   string
+	userID int64
+}
+
+func (s *testSender) SendMessage(text string, userID int64, buttons []string) error {
+	s.text, s.userID = text, userID
+
+	return nil
+}
+
+func TestNotifyShouldSendCategoryAndTotalLimitAlerts(t *testing.T) {
+	ctx := context.Background()
+	sender := &testSender{}
+	notifier := NewLimitNotifier(sender)
+
+	err := notifier.Notify(ctx, model.LimitAlert{
+		UserID:    100,
+		Scope:     model.CategoryLimitScope,
+		Period:    model.Month,
+		Category:  "Кофе",
+		Threshold: 80,
+		Amount:    1000,
+		Free:      150.5,
+		Currency:  "RUB",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(100), sender.userID)
+	assert.Equal(t, "Месячный лимит категории Кофе израсходован на 80%. Свободно 150.50 RUB", sender.text)
+
+	err = notifier.Notify(ctx, model.LimitAlert{
+		UserID:    100,
+		Scope:     model.TotalLimitScope,
+		Period:    model.Week,
+		Threshold: 100,
+		Amount:    1000,
+		Free:      -20,
+		Currency:  "USD",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "Общий недельный лимит израсходован на 100%. Свободно -20.00 USD", sender.text)
+}
@@ -0,0 +1,87 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/service/limit_notifier/limit_notifier.go
+
+// Package mock_limitnotifier is a generated GoMock package.
+package mock_limitnotifier
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	model "gitlab.ozon.dev/cranky4/tg-bot/internal/model"
+)
+
+// MockMessageSender is a mock of MessageSender interface.
+type MockMessageSender struct {
+	ctrl     *gomock.Controller
+	recorder *MockMessageSenderMockRecorder
+}
+
+// MockMessageSenderMockRecorder is the mock recorder for MockMessageSender.
+type MockMessageSenderMockRecorder struct {
+	mock *MockMessageSender
+}
+
+// NewMockMessageSender creates a new mock instance.
+func NewMockMessageSender(ctrl *gomock.Controller) *MockMessageSender {
+	mock := &MockMessageSender{ctrl: ctrl}
+	mock.recorder = &MockMessageSenderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMessageSender) EXPECT() *MockMessageSenderMockRecorder {
+	return m.recorder
+}
+
+// SendMessage mocks base method.
+func (m *MockMessageSender) SendMessage(text string, userID int64, buttons []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendMessage", text, userID, buttons)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendMessage indicates an expected call of SendMessage.
+func (mr *MockMessageSenderMockRecorder) SendMessage(text, userID, buttons interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMessage", reflect.TypeOf((*MockMessageSender)(nil).SendMessage), text, userID, buttons)
+}
+
+// MockLimitNotifier is a mock of LimitNotifier interface.
+type MockLimitNotifier struct {
+	ctrl     *gomock.Controller
+	recorder *MockLimitNotifierMockRecorder
+}
+
+// MockLimitNotifierMockRecorder is the mock recorder for MockLimitNotifier.
+type MockLimitNotifierMockRecorder struct {
+	mock *MockLimitNotifier
+}
+
+// NewMockLimitNotifier creates a new mock instance.
+func NewMockLimitNotifier(ctrl *gomock.Controller) *MockLimitNotifier {
+	mock := &MockLimitNotifier{ctrl: ctrl}
+	mock.recorder = &MockLimitNotifierMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLimitNotifier) EXPECT() *MockLimitNotifierMockRecorder {
+	return m.recorder
+}
+
+// Notify mocks base method.
+func (m *MockLimitNotifier) Notify(ctx context.Context, alert model.LimitAlert) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Notify", ctx, alert)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Notify indicates an expected call of Notify.
+func (mr *MockLimitNotifierMockRecorder) Notify(ctx, alert interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Notify", reflect.TypeOf((*MockLimitNotifier)(nil).Notify), ctx, alert)
+}
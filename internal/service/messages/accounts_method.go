@@ -0,0 +1,146 @@
+package servicemessages
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/repository"
+)
+
+func (m *Model) addAccount(ctx context.Context, msg Message) (string, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "addAccount")
+	defer span.Finish()
+
+	parts := strings.Split(msg.CommandArguments, expenseArgumentsSeparator)
+	name := strings.Trim(parts[0], " ")
+	if name == "" || len(parts) > 3 {
+		return "", errors.New(errAddAccountInvalidParameterMessage)
+	}
+
+	settings, err := m.getUserSettings(ctx, msg.UserID)
+	if err != nil {
+		return "", err
+	}
+
+	currency := settings.Currency
+	if len(parts) > 1 {
+		if value := strings.ToUpper(strings.Trim(parts[1], " ")); value != "" {
+			if _, ok := m.currencies[value]; !ok {
+				return "", fmt.Errorf(errUnknownCurrency, value)
+			}
+			currency = value
+		}
+	}
+
+	// начальный баланс необязателен и указывается в валюте счета
+	var openingBalance float64
+	if len(parts) > 2 && strings.Trim(parts[2], " ") != "" {
+		parser := newExpenseInputParser(m.currencies, time.Now())
+		if openingBalance, _, err = parser.parseAmount(parts[2]); err != nil {
+			return "", err
+		}
+	}
+
+	account, err := m.expenseProcessor.AddAccount(ctx, name, currency, openingBalance, msg.BudgetID)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf(msgAccountAdded, account.Name, openingBalance, currency), nil
+}
+
+func (m *Model) transfer(ctx context.Context, msg Message) (string, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "transfer")
+	defer span.Finish()
+
+	parts := strings.Split(msg.CommandArguments, expenseArgumentsSeparator)
+	if len(parts) < 3 || len(parts) > 4 {
+		return "", errors.New(errTransferInvalidParameterMessage)
+	}
+
+	from, to := strings.Trim(parts[1], " "), strings.Trim(parts[2], " ")
+	if from == "" || to == "" {
+		return "", errors.New(errTransferInvalidParameterMessage)
+	}
+
+	if strings.EqualFold(from, to) {
+		return "", errors.New(errTransferSameAccount)
+	}
+
+	settings, err := m.getUserSettings(ctx, msg.UserID)
+	if err != nil {
+		return "", err
+	}
+
+	parser := newExpenseInputParser(m.currencies, time.Now().In(settings.Location()))
+	amount, currency, err := parser.parseAmount(parts[0])
+	if err != nil {
+		return "", err
+	}
+	if currency == "" {
+		currency = settings.Currency
+	}
+
+	datetime := parser.now
+	if len(parts) == 4 {
+		if rawDatetime := strings.Trim(parts[3], " "); rawDatetime != "" {
+			var ok bool
+			if datetime, ok = parser.parseDatetime(rawDatetime); !ok {
+				return "", fmt.Errorf(errAddExpenseInvalidDatetimeParameterMessage, rawDatetime)
+			}
+		}
+	}
+
+	if err = m.expenseProcessor.Transfer(ctx, from, to, amount, currency, datetime, msg.BudgetID); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf(msgTransferDone, amount, currency, from, to), nil
+}
+
+func (m *Model) balances(ctx context.Context, msg Message) (string, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "balances")
+	defer span.Finish()
+
+	balances, err := m.expenseProcessor.GetBalances(ctx, msg.BudgetID)
+	if err != nil {
+		return "", err
+	}
+
+	if len(balances) == 0 {
+		return msgNoAccounts, nil
+	}
+
+	var list strings.Builder
+	list.WriteString("Остатки на счетах:\n")
+	for _, balance := range balances {
+		list.WriteString(fmt.Sprintf("%s - %.02f %s\n", balance.Name, balance.Balance, balance.Currency))
+	}
+
+	return list.String(), nil
+}
+
+// findAccount возвращает название счета пользователя в исходном регистре, для пустого name - пустое название
+func (m *Model) findAccount(ctx context.Context, userId int64, name string) (string, error) {
+	name = strings.Trim(name, " ")
+	if name == "" {
+		return "", nil
+	}
+
+	balances, err := m.expenseProcessor.GetBalances(ctx, userId)
+	if err != nil {
+		return "", err
+	}
+
+	for _, balance := range balances {
+		if strings.EqualFold(balance.Name, name) {
+			return balance.Name, nil
+		}
+	}
+
+	return "", repository.ErrAccountNotFound
+}
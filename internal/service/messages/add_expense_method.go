@@ -3,63 +3,86 @@ package servicemessages
 import (
 	"context"
 	"fmt"
-	"strconv"
 	"strings"
 	"time"
 
 	"github.com/opentracing/opentracing-go"
-	"github.com/pkg/errors"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/model"
 )
 
-func (m *Model) addExpense(ctx context.Context, msg Message) (string, error) {
+func (m *Model) addExpense(ctx context.Context, msg Message) (string, []model.InlineButton, error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "addExpense")
 	defer span.Finish()
 
-	parts := strings.Split(msg.CommandArguments, ";")
-
-	if len(parts) != 3 {
-		return "", errors.New(errAddExpenseInvalidParameterMessage)
+	settings, err := m.getUserSettings(ctx, msg.UserID)
+	if err != nil {
+		return "", nil, err
 	}
 
-	trimmedAmount := strings.Trim(parts[0], " ")
-	amount, err := strconv.ParseFloat(trimmedAmount, 32)
-	if err != nil {
-		return "", fmt.Errorf(errInvalidAmountParameterMessage, trimmedAmount)
+	now := time.Now().In(settings.Location())
+	parser := newExpenseInputParser(m.currencies, now)
+
+	// указана только сумма - предлагаем выбрать категорию
+	if amount, currency, err := parser.parseAmount(msg.CommandArguments); err == nil {
+		if currency == "" {
+			currency = settings.Currency
+		}
+
+		return m.requestExpenseCategory(ctx, amount, currency, now, msg.BudgetID)
 	}
 
-	trimmedDatetime := strings.Trim(parts[2], " ")
-	datetime, err := time.Parse(datetimeFormat, trimmedDatetime)
+	args, err := parser.parse(msg.CommandArguments)
 	if err != nil {
-		return "", fmt.Errorf(errAddExpenseInvalidDatetimeParameterMessage, trimmedDatetime)
+		return "", nil, err
 	}
 
-	trimmedCategory := strings.Trim(parts[1], " ")
+	_, response, err := m.saveExpense(ctx, settings, args, msg.BudgetID, msg.UserID)
+
+	return response, nil, err
+}
 
-	if _, err = m.expenseProcessor.AddExpense(ctx, amount, m.currency, trimmedCategory, datetime, msg.UserID); err != nil {
-		return "", err
+// saveExpense сохраняет трату пользователя userID в бюджет budgetID и возвращает ее с ответом о свободном лимите категории
+func (m *Model) saveExpense(
+	ctx context.Context,
+	settings model.UserSettings,
+	args expenseArguments,
+	budgetID, userID int64,
+) (*model.Expense, string, error) {
+	currency := settings.Currency
+	if args.currency != "" {
+		currency = args.currency
 	}
 
-	freeLimit, hasLimit, err := m.expenseProcessor.GetFreeLimit(ctx, trimmedCategory, m.currency, msg.UserID)
+	// категория могла быть заменена правилами пользователя
+	ex, err := m.expenseProcessor.AddExpense(ctx, args.amount, currency, args.category, args.account, args.datetime, budgetID, userID)
 	if err != nil {
-		return "", err
+		return nil, "", err
 	}
 
-	var responseMsg string
-	responseMsg = msgExpenseAdded
+	freeLimits, err := m.expenseProcessor.GetFreeLimits(ctx, ex.Category, budgetID, settings, time.Now().In(settings.Location()))
+	if err != nil {
+		return nil, "", err
+	}
 
-	if hasLimit {
-		var addMsg string
+	response := fmt.Sprintf(msgExpenseAdded, args.amount, currency, ex.Category, args.datetime.Format(datetimeFormat))
+	if args.account != "" {
+		response += fmt.Sprintf(msgExpenseAccount, args.account)
+	}
 
-		if freeLimit > 0 {
-			addMsg = msgFreeLimit
-		} else {
-			addMsg = msgLimitReached
+	// трата расходует лимиты своей категории и общие лимиты
+	for _, limit := range freeLimits {
+		limitName := strings.ToLower(limit.Period.String())
+		if limit.Scope == model.TotalLimitScope {
+			limitName = fmt.Sprintf("общий %s", limitName)
 		}
-		responseMsg = fmt.Sprintf(
-			"%s.\n%s", responseMsg,
-			fmt.Sprintf(addMsg, freeLimit, m.currency),
-		)
+
+		limitMsg := msgFreeLimit
+		if limit.Free <= 0 {
+			limitMsg = msgLimitReached
+		}
+
+		response = fmt.Sprintf("%s.\n%s", response, fmt.Sprintf(limitMsg, limitName, limit.Free, settings.Currency))
 	}
 
-	return fmt.Sprintf(responseMsg, amount, m.currency, trimmedCategory, trimmedDatetime), nil
+	return ex, response, nil
 }
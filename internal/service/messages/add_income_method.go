@@ -0,0 +1,45 @@
+package servicemessages
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+)
+
+func (m *Model) addIncome(ctx context.Context, msg Message) (string, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "addIncome")
+	defer span.Finish()
+
+	parts := strings.Split(msg.CommandArguments, expenseArgumentsSeparator)
+	if len(parts) < 2 || len(parts) > 3 || strings.Trim(parts[1], " ") == "" {
+		return "", errors.New(errAddIncomeInvalidParameterMessage)
+	}
+
+	settings, err := m.getUserSettings(ctx, msg.UserID)
+	if err != nil {
+		return "", err
+	}
+
+	// доход вводится так же, как трата, вместо категории - источник
+	parser := newExpenseInputParser(m.currencies, time.Now().In(settings.Location()))
+	args, err := parser.parseSeparated(msg.CommandArguments)
+	if err != nil {
+		return "", err
+	}
+
+	currency := settings.Currency
+	if args.currency != "" {
+		currency = args.currency
+	}
+
+	income, err := m.expenseProcessor.AddIncome(ctx, args.amount, currency, args.category, args.datetime, msg.BudgetID)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf(msgIncomeAdded, args.amount, currency, income.Source, args.datetime.Format(datetimeFormat)), nil
+}
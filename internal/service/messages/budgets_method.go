@@ -0,0 +1,500 @@
+package servicemessages
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/model"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/repository"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/service/expense_reporter"
+)
+
+const (
+	joinLinkPrefix       = "join_" // аргумент /start в ссылке-приглашении
+	joinCodeLength       = 8
+	joinCodeAlphabet     = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789" // без похожих символов
+	personalBudgetSwitch = "personal"
+)
+
+// editCommands команды, которые меняют данные бюджета, недоступны участникам с ролью viewer
+var editCommands = map[string]struct{}{
+	addExpenseCommand:         {},
+	editExpenseCommand:        {},
+	deleteExpenseCommand:      {},
+	setLimitCommand:           {},
+	setLimitAlertsCommand:     {},
+	renameCategoryCommand:     {},
+	mergeCategoriesCommand:    {},
+	deleteCategoryCommand:     {},
+	aliasCommand:              {},
+	keywordCommand:            {},
+	deleteCategoryRuleCommand: {},
+	addRecurringCommand:       {},
+	pauseRecurringCommand:     {},
+	resumeRecurringCommand:    {},
+	addIncomeCommand:          {},
+	addAccountCommand:         {},
+	transferCommand:           {},
+	splitExpenseCommand:       {},
+	settleDebtCommand:         {},
+	importCommand:             {},
+}
+
+// privateChatCommands команды управления бюджетами, подписками и токеном импорта, в групповом чате бюджет - сам чат
+var privateChatCommands = map[string]struct{}{
+	createBudgetCommand:  {},
+	joinBudgetCommand:    {},
+	budgetsCommand:       {},
+	switchBudgetCommand:  {},
+	budgetMembersCommand: {},
+	setBudgetRoleCommand: {},
+	leaveBudgetCommand:   {},
+	subscribeCommand:     {},
+	unsubscribeCommand:   {},
+	subscriptionsCommand: {},
+	importTokenCommand:   {},
+}
+
+// resolveBudget возвращает участие пользователя в активном бюджете, по-умолчанию - личный бюджет.
+// В групповом чате бюджет общий для чата, добавлять траты могут все участники
+func (m *Model) resolveBudget(ctx context.Context, chatID, userID int64) (model.BudgetMember, error) {
+	if isGroupChat(chatID, userID) {
+		return model.BudgetMember{BudgetID: chatID, UserID: userID, Role: model.EditorBudgetRole}, nil
+	}
+
+	member, found, err := m.budgetsRepo.GetActiveMember(ctx, userID)
+	if err != nil {
+		return model.BudgetMember{}, err
+	}
+
+	if !found {
+		return model.PersonalBudgetMember(userID), nil
+	}
+
+	return member, nil
+}
+
+// checkBudgetAccess проверяет, что роль в активном бюджете и чат позволяют выполнить команду
+func checkBudgetAccess(msg Message) error {
+	if isGroupChat(msg.ChatID, msg.UserID) {
+		_, private := privateChatCommands[msg.Command]
+		if private || (msg.Command == startCommand && strings.HasPrefix(msg.CommandArguments, joinLinkPrefix)) {
+			return errors.New(errPrivateChatCommand)
+		}
+	}
+
+	if _, ok := editCommands[msg.Command]; ok && !msg.BudgetRole.CanEdit() {
+		return errors.New(errBudgetReadOnly)
+	}
+
+	return nil
+}
+
+// saveGroupChatMember запоминает имя участника группового чата, отправившего команду,
+// для отчета по участникам и разделения трат
+func (m *Model) saveGroupChatMember(ctx context.Context, msg Message) error {
+	if msg.Command == "" || !isGroupChat(msg.ChatID, msg.UserID) {
+		return nil
+	}
+
+	return m.saveGroupMember(ctx, msg.BudgetID, msg.UserID, memberName(msg))
+}
+
+func (m *Model) saveGroupMember(ctx context.Context, chatID, userID int64, name string) error {
+	member, found, err := m.findBudgetMember(ctx, chatID, userID)
+	if err != nil {
+		return err
+	}
+
+	if found && member.Name == name {
+		return nil
+	}
+
+	return m.budgetsRepo.SaveMember(ctx, model.BudgetMember{
+		BudgetID: chatID,
+		UserID:   userID,
+		Name:     name,
+		Role:     model.EditorBudgetRole,
+	})
+}
+
+func (m *Model) createBudget(ctx context.Context, msg Message) (string, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "createBudget")
+	defer span.Finish()
+
+	name := strings.Trim(msg.CommandArguments, " ")
+	if name == "" {
+		return "", errors.New(errCreateBudgetInvalidParameterMessage)
+	}
+
+	code, err := newJoinCode()
+	if err != nil {
+		return "", err
+	}
+
+	budget, err := m.budgetsRepo.AddBudget(
+		ctx,
+		model.Budget{Name: name, JoinCode: code, OwnerId: msg.UserID},
+		model.BudgetMember{UserID: msg.UserID, Name: memberName(msg), Role: model.OwnerBudgetRole},
+	)
+	if err != nil {
+		return "", err
+	}
+
+	if err = m.budgetsRepo.SetActiveBudget(ctx, msg.UserID, budget.ID); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf(msgBudgetCreated, budget.Name, joinInvitation(msg.BotName, budget.JoinCode)), nil
+}
+
+// joinBudget добавляет пользователя в бюджет по коду приглашения с ролью editor и делает бюджет активным.
+// Участник бюджета сохраняет свою роль
+func (m *Model) joinBudget(ctx context.Context, msg Message, code string) (string, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "joinBudget")
+	defer span.Finish()
+
+	code = strings.Trim(code, " ")
+	if code == "" {
+		return "", errors.New(errJoinCodeMissingMessage)
+	}
+
+	budget, found, err := m.budgetsRepo.GetBudgetByCode(ctx, code)
+	if err != nil {
+		return "", err
+	}
+
+	if !found {
+		return "", fmt.Errorf(errJoinCodeNotFound, code)
+	}
+
+	member, found, err := m.findBudgetMember(ctx, budget.ID, msg.UserID)
+	if err != nil {
+		return "", err
+	}
+
+	if !found {
+		member = model.BudgetMember{BudgetID: budget.ID, UserID: msg.UserID, Name: memberName(msg), Role: model.EditorBudgetRole}
+		if err = m.budgetsRepo.SaveMember(ctx, member); err != nil {
+			return "", err
+		}
+	}
+
+	if err = m.budgetsRepo.SetActiveBudget(ctx, msg.UserID, budget.ID); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf(msgBudgetJoined, budget.Name, member.Role), nil
+}
+
+func (m *Model) listBudgets(ctx context.Context, msg Message) (string, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "listBudgets")
+	defer span.Finish()
+
+	budgets, err := m.budgetsRepo.GetUserBudgets(ctx, msg.UserID)
+	if err != nil {
+		return "", err
+	}
+
+	var list strings.Builder
+	list.WriteString("Бюджеты:\n")
+	list.WriteString(budgetListItem("личный", personalBudgetSwitch, !model.IsSharedBudget(msg.BudgetID)))
+	for _, budget := range budgets {
+		list.WriteString(budgetListItem(budget.Name, strconv.FormatInt(budget.ID, 10), budget.ID == msg.BudgetID))
+	}
+
+	return list.String(), nil
+}
+
+func (m *Model) switchBudget(ctx context.Context, msg Message) (string, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "switchBudget")
+	defer span.Finish()
+
+	arg := strings.ToLower(strings.Trim(msg.CommandArguments, " "))
+	if arg == "" {
+		return "", errors.New(errSwitchBudgetInvalidParameterMessage)
+	}
+
+	if arg == personalBudgetSwitch {
+		if err := m.budgetsRepo.SetActiveBudget(ctx, msg.UserID, msg.UserID); err != nil {
+			return "", err
+		}
+
+		return msgPersonalBudgetActive, nil
+	}
+
+	budgetID, err := strconv.ParseInt(arg, 10, 64)
+	if err != nil || !model.IsSharedBudget(budgetID) {
+		return "", errors.New(errSwitchBudgetInvalidParameterMessage)
+	}
+
+	_, found, err := m.findBudgetMember(ctx, budgetID, msg.UserID)
+	if err != nil {
+		return "", err
+	}
+
+	if !found {
+		return "", repository.ErrBudgetNotFound
+	}
+
+	budget, found, err := m.budgetsRepo.GetBudget(ctx, budgetID)
+	if err != nil {
+		return "", err
+	}
+
+	if !found {
+		return "", repository.ErrBudgetNotFound
+	}
+
+	if err = m.budgetsRepo.SetActiveBudget(ctx, msg.UserID, budget.ID); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf(msgBudgetActive, budget.Name), nil
+}
+
+func (m *Model) listBudgetMembers(ctx context.Context, msg Message) (string, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "listBudgetMembers")
+	defer span.Finish()
+
+	budget, err := m.getActiveBudget(ctx, msg)
+	if err != nil {
+		return "", err
+	}
+
+	members, err := m.budgetsRepo.GetMembers(ctx, budget.ID)
+	if err != nil {
+		return "", err
+	}
+
+	var list strings.Builder
+	list.WriteString(fmt.Sprintf("Участники бюджета %s:\n", budget.Name))
+	for _, member := range members {
+		list.WriteString(fmt.Sprintf("%s (ИД %d) - %s\n", member.Name, member.UserID, member.Role))
+	}
+	list.WriteString(joinInvitation(msg.BotName, budget.JoinCode))
+
+	return list.String(), nil
+}
+
+func (m *Model) setBudgetRole(ctx context.Context, msg Message) (string, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "setBudgetRole")
+	defer span.Finish()
+
+	parts := strings.Split(msg.CommandArguments, expenseArgumentsSeparator)
+	if len(parts) != 2 {
+		return "", errors.New(errSetBudgetRoleInvalidParameterMessage)
+	}
+
+	budget, err := m.getActiveBudget(ctx, msg)
+	if err != nil {
+		return "", err
+	}
+
+	if !msg.BudgetRole.CanManage() {
+		return "", errors.New(errBudgetManageForbidden)
+	}
+
+	role, ok := model.ParseBudgetRole(parts[1])
+	if !ok {
+		return "", fmt.Errorf(errUnknownBudgetRole, strings.Trim(parts[1], " "))
+	}
+
+	members, err := m.budgetsRepo.GetMembers(ctx, budget.ID)
+	if err != nil {
+		return "", err
+	}
+
+	member, found := matchBudgetMember(members, parts[0])
+	if !found {
+		return "", fmt.Errorf(errBudgetMemberNotFound, strings.Trim(parts[0], " "))
+	}
+
+	// у бюджета всегда должен оставаться владелец
+	if member.UserID == msg.UserID {
+		return "", errors.New(errOwnBudgetRoleChange)
+	}
+
+	member.Role = role
+	if err = m.budgetsRepo.SaveMember(ctx, member); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf(msgBudgetRoleSet, member.Name, member.Role), nil
+}
+
+func (m *Model) leaveBudget(ctx context.Context, msg Message) (string, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "leaveBudget")
+	defer span.Finish()
+
+	budget, err := m.getActiveBudget(ctx, msg)
+	if err != nil {
+		return "", err
+	}
+
+	if msg.BudgetRole == model.OwnerBudgetRole {
+		members, err := m.budgetsRepo.GetMembers(ctx, budget.ID)
+		if err != nil {
+			return "", err
+		}
+
+		owners := 0
+		for _, member := range members {
+			if member.Role == model.OwnerBudgetRole {
+				owners++
+			}
+		}
+
+		if owners < 2 {
+			return "", errors.New(errOwnerCannotLeaveBudget)
+		}
+	}
+
+	if _, err = m.budgetsRepo.DeleteMember(ctx, budget.ID, msg.UserID); err != nil {
+		return "", err
+	}
+
+	if err = m.budgetsRepo.SetActiveBudget(ctx, msg.UserID, msg.UserID); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf(msgBudgetLeft, budget.Name), nil
+}
+
+// getActiveBudget возвращает активный общий бюджет, для личного бюджета - ошибку
+func (m *Model) getActiveBudget(ctx context.Context, msg Message) (model.Budget, error) {
+	if !model.IsSharedBudget(msg.BudgetID) {
+		return model.Budget{}, errors.New(errPersonalBudgetActive)
+	}
+
+	budget, found, err := m.budgetsRepo.GetBudget(ctx, msg.BudgetID)
+	if err != nil {
+		return model.Budget{}, err
+	}
+
+	if !found {
+		return model.Budget{}, repository.ErrBudgetNotFound
+	}
+
+	return budget, nil
+}
+
+// formatMembersBreakdown возвращает расходы участников общего бюджета по убыванию суммы
+func (m *Model) formatMembersBreakdown(ctx context.Context, report *expense_reporter.ExpenseReport, currency string) (string, error) {
+	members, err := m.budgetsRepo.GetMembers(ctx, report.BudgetID)
+	if err != nil {
+		return "", err
+	}
+
+	names := memberNames(members)
+
+	authors := make([]int64, 0, len(report.Members))
+	for author := range report.Members {
+		authors = append(authors, author)
+	}
+	sort.Slice(authors, func(i, j int) bool {
+		return report.Members[authors[i]] > report.Members[authors[j]]
+	})
+
+	var breakdown strings.Builder
+	breakdown.WriteString("\nПо участникам:\n")
+	for _, author := range authors {
+		name, ok := names[author]
+		switch {
+		case author == report.BudgetID:
+			name = "регулярные траты"
+		case !ok:
+			// участник вышел из бюджета
+			name = strconv.FormatInt(author, 10)
+		}
+
+		breakdown.WriteString(fmt.Sprintf("%s - %.02f %s\n", name, report.Members[author], currency))
+	}
+
+	return breakdown.String(), nil
+}
+
+func (m *Model) findBudgetMember(ctx context.Context, budgetID, userID int64) (model.BudgetMember, bool, error) {
+	members, err := m.budgetsRepo.GetMembers(ctx, budgetID)
+	if err != nil {
+		return model.BudgetMember{}, false, err
+	}
+
+	for _, member := range members {
+		if member.UserID == userID {
+			return member, true, nil
+		}
+	}
+
+	return model.BudgetMember{}, false, nil
+}
+
+// matchBudgetMember ищет участника по имени в Telegram, в том числе с @, или по ИД
+func matchBudgetMember(members []model.BudgetMember, name string) (model.BudgetMember, bool) {
+	name = strings.TrimPrefix(strings.Trim(name, " "), "@")
+	for _, member := range members {
+		if strings.EqualFold(member.Name, name) || strconv.FormatInt(member.UserID, 10) == name {
+			return member, true
+		}
+	}
+
+	return model.BudgetMember{}, false
+}
+
+// memberNames имена участников бюджета по их ИД
+func memberNames(members []model.BudgetMember) map[int64]string {
+	names := make(map[int64]string, len(members))
+	for _, member := range members {
+		names[member.UserID] = member.Name
+	}
+
+	return names
+}
+
+// memberName имя участника бюджета: ник в Telegram, либо ИД пользователя
+func memberName(msg Message) string {
+	if msg.UserName != "" {
+		return msg.UserName
+	}
+
+	return strconv.FormatInt(msg.UserID, 10)
+}
+
+// joinInvitation ссылка-приглашение, если известно имя бота, иначе команда для вступления
+func joinInvitation(botName, code string) string {
+	if botName == "" {
+		return fmt.Sprintf("Код приглашения: %s, вступить: /%s %s\n", code, joinBudgetCommand, code)
+	}
+
+	return fmt.Sprintf("Код приглашения: %s, ссылка: https://t.me/%s?start=%s%s\n", code, botName, joinLinkPrefix, code)
+}
+
+func budgetListItem(name, switchArg string, active bool) string {
+	if active {
+		return fmt.Sprintf("%s - активный\n", name)
+	}
+
+	return fmt.Sprintf("%s - /%s %s\n", name, switchBudgetCommand, switchArg)
+}
+
+// newJoinCode возвращает случайный код приглашения
+func newJoinCode() (string, error) {
+	var code strings.Builder
+	max := big.NewInt(int64(len(joinCodeAlphabet)))
+	for i := 0; i < joinCodeLength; i++ {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		code.WriteByte(joinCodeAlphabet[n.Int64()])
+	}
+
+	return code.String(), nil
+}
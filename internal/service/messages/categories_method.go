@@ -0,0 +1,48 @@
+package servicemessages
+
+import (
+	"context"
+	"strings"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+)
+
+func (m *Model) listCategories(ctx context.Context, msg Message) (string, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "listCategories")
+	defer span.Finish()
+
+	categories, err := m.expenseProcessor.GetCategories(ctx, msg.BudgetID)
+	if err != nil {
+		return "", err
+	}
+
+	if len(categories) == 0 {
+		return msgNoCategories, nil
+	}
+
+	var list strings.Builder
+	list.WriteString("Ваши категории:\n")
+	for _, c := range categories {
+		list.WriteString("- ")
+		list.WriteString(c)
+		list.WriteString("\n")
+	}
+
+	return list.String(), nil
+}
+
+// parseCategoryPair разбирает аргументы вида Категория1;Категория2
+func parseCategoryPair(arguments string, errMessage string) (string, string, error) {
+	parts := strings.Split(arguments, ";")
+	if len(parts) != 2 {
+		return "", "", errors.New(errMessage)
+	}
+
+	first, second := strings.Trim(parts[0], " "), strings.Trim(parts[1], " ")
+	if first == "" || second == "" {
+		return "", "", errors.New(errMessage)
+	}
+
+	return first, second, nil
+}
@@ -0,0 +1,160 @@
+package servicemessages
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/model"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/service/logger"
+)
+
+const (
+	categoryPickerLimit    = 6
+	categoryCallbackPrefix = "ce" // выбор категории траты
+	callbackDataSeparator  = "|"
+)
+
+// requestExpenseCategory предлагает выбрать категорию траты из самых используемых
+func (m *Model) requestExpenseCategory(
+	ctx context.Context,
+	amount float64,
+	currency string,
+	datetime time.Time,
+	userID int64,
+) (string, []model.InlineButton, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "requestExpenseCategory")
+	defer span.Finish()
+
+	categories, err := m.expenseProcessor.GetTopCategories(ctx, userID, categoryPickerLimit)
+	if err != nil {
+		return "", nil, err
+	}
+
+	buttons := make([]model.InlineButton, 0, len(categories))
+	for _, category := range categories {
+		data := strings.Join([]string{
+			categoryCallbackPrefix,
+			strconv.FormatFloat(amount, 'f', -1, 64),
+			currency,
+			strconv.FormatInt(datetime.Unix(), 10),
+			categoryCallbackKey(category),
+		}, callbackDataSeparator)
+
+		buttons = append(buttons, model.InlineButton{Text: category, Data: data})
+	}
+
+	if len(buttons) == 0 {
+		return "", nil, errors.New(errNoCategoriesToPick)
+	}
+
+	return fmt.Sprintf(msgPickCategory, amount, currency), buttons, nil
+}
+
+// IncomingCallback обрабатывает нажатие на кнопку выбора категории и сохраняет трату
+func (m *Model) IncomingCallback(ctx context.Context, callback Callback) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "Messaging_IncomingCallback")
+	defer span.Finish()
+
+	logger.Debug(
+		"получено нажатие кнопки",
+		logger.LogDataItem{Key: "userId", Value: callback.UserID},
+		logger.LogDataItem{Key: "chatId", Value: callback.ChatID},
+		logger.LogDataItem{Key: "data", Value: callback.Data},
+	)
+
+	response, err := m.pickExpenseCategory(ctx, callback)
+	if err != nil {
+		response = err.Error()
+
+		logger.Error(response)
+	}
+
+	return m.tgClient.SendMessage(response, replyChatID(callback.ChatID, callback.UserID), mainMenu)
+}
+
+func (m *Model) pickExpenseCategory(ctx context.Context, callback Callback) (string, error) {
+	parts := strings.SplitN(callback.Data, callbackDataSeparator, 5)
+	if len(parts) != 5 || parts[0] != categoryCallbackPrefix {
+		return "", errors.New(errUnknownCallback)
+	}
+
+	amount, err := strconv.ParseFloat(parts[1], 64)
+	if err != nil {
+		return "", errors.New(errUnknownCallback)
+	}
+
+	timestamp, err := strconv.ParseInt(parts[3], 10, 64)
+	if err != nil {
+		return "", errors.New(errUnknownCallback)
+	}
+
+	member, err := m.resolveBudget(ctx, callback.ChatID, callback.UserID)
+	if err != nil {
+		return "", err
+	}
+
+	if !member.Role.CanEdit() {
+		return "", errors.New(errBudgetReadOnly)
+	}
+
+	// в группе выбрать категорию может любой участник, автор траты - нажавший на кнопку
+	if isGroupChat(callback.ChatID, callback.UserID) {
+		name := memberName(Message{UserID: callback.UserID, UserName: callback.UserName})
+		if err = m.saveGroupMember(ctx, member.BudgetID, callback.UserID, name); err != nil {
+			return "", err
+		}
+	}
+
+	category, err := m.findPickedCategory(ctx, parts[4], member.BudgetID)
+	if err != nil {
+		return "", err
+	}
+
+	settings, err := m.getUserSettings(ctx, callback.UserID)
+	if err != nil {
+		return "", err
+	}
+
+	_, response, err := m.saveExpense(ctx, settings, expenseArguments{
+		amount:   amount,
+		currency: parts[2],
+		category: category,
+		datetime: time.Unix(timestamp, 0).In(settings.Location()),
+	}, member.BudgetID, callback.UserID)
+
+	return response, err
+}
+
+// categoryCallbackKey короткий ключ категории для callback_data: в 64 байта не помещаются длинные
+// названия на кириллице вместе с суммой и датой
+func categoryCallbackKey(category string) string {
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(strings.ToLower(category)))
+
+	return fmt.Sprintf("%08x", hash.Sum32())
+}
+
+// findPickedCategory находит категорию бюджета по ключу из нажатой кнопки
+func (m *Model) findPickedCategory(ctx context.Context, key string, budgetID int64) (string, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "findPickedCategory")
+	defer span.Finish()
+
+	categories, err := m.expenseProcessor.GetCategories(ctx, budgetID)
+	if err != nil {
+		return "", err
+	}
+
+	for _, category := range categories {
+		if categoryCallbackKey(category) == key {
+			return category, nil
+		}
+	}
+
+	return "", errors.New(errPickedCategoryNotFound)
+}
@@ -0,0 +1,89 @@
+package servicemessages
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/model"
+)
+
+const categoryRuleSeparator = "=>"
+
+var categoryRuleTypeNames = map[model.CategoryRuleType]string{
+	model.AliasCategoryRule:   "синоним",
+	model.KeywordCategoryRule: "ключевое слово",
+}
+
+func (m *Model) saveCategoryRule(ctx context.Context, msg Message, ruleType model.CategoryRuleType) (string, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "saveCategoryRule")
+	defer span.Finish()
+
+	parts := strings.Split(msg.CommandArguments, categoryRuleSeparator)
+	if len(parts) != 2 {
+		return "", errors.New(errCategoryRuleInvalidParameterMessage)
+	}
+
+	pattern, category := strings.Trim(parts[0], " "), strings.Trim(parts[1], " ")
+	if pattern == "" || category == "" {
+		return "", errors.New(errCategoryRuleInvalidParameterMessage)
+	}
+
+	err := m.expenseProcessor.SaveCategoryRule(ctx, model.CategoryRule{
+		Type:     ruleType,
+		Pattern:  pattern,
+		Category: category,
+		UserId:   msg.BudgetID,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf(msgCategoryRuleSaved, pattern, category), nil
+}
+
+func (m *Model) listCategoryRules(ctx context.Context, msg Message) (string, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "listCategoryRules")
+	defer span.Finish()
+
+	rules, err := m.expenseProcessor.GetCategoryRules(ctx, msg.BudgetID)
+	if err != nil {
+		return "", err
+	}
+
+	if len(rules) == 0 {
+		return msgNoCategoryRules, nil
+	}
+
+	var list strings.Builder
+	list.WriteString("Правила категорий:\n")
+	for _, r := range rules {
+		list.WriteString(fmt.Sprintf("- %s %s %s (%s)\n", r.Pattern, categoryRuleSeparator, r.Category, categoryRuleTypeNames[r.Type]))
+	}
+	list.WriteString(fmt.Sprintf("Удалить правило: /%s Текст", deleteCategoryRuleCommand))
+
+	return list.String(), nil
+}
+
+func (m *Model) deleteCategoryRule(ctx context.Context, msg Message) (string, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "deleteCategoryRule")
+	defer span.Finish()
+
+	pattern := strings.Trim(msg.CommandArguments, " ")
+	if pattern == "" {
+		return "", errors.New(errDeleteCategoryRuleInvalidParameterMessage)
+	}
+
+	found, err := m.expenseProcessor.DeleteCategoryRule(ctx, msg.BudgetID, pattern)
+	if err != nil {
+		return "", err
+	}
+
+	if !found {
+		return "", fmt.Errorf(errCategoryRuleNotFound, pattern)
+	}
+
+	return fmt.Sprintf(msgCategoryRuleDeleted, pattern), nil
+}
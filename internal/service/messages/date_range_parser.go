@@ -0,0 +1,106 @@
+package servicemessages
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/model"
+)
+
+const (
+	dateFormat         = "2006-01-02"
+	dateRangeSeparator = ".."
+)
+
+// namedRanges именованные календарные диапазоны дат
+var namedRanges = map[string]func(now time.Time, weekStart time.Weekday) model.DateRange{
+	"today": func(now time.Time, weekStart time.Weekday) model.DateRange {
+		start := model.StartOfDay(now)
+		return model.NewDateRange(start, start.AddDate(0, 0, 1))
+	},
+	"yesterday": func(now time.Time, weekStart time.Weekday) model.DateRange {
+		start := model.StartOfDay(now).AddDate(0, 0, -1)
+		return model.NewDateRange(start, start.AddDate(0, 0, 1))
+	},
+	"this week": func(now time.Time, weekStart time.Weekday) model.DateRange {
+		start := model.StartOfWeek(now, weekStart)
+		return model.NewDateRange(start, start.AddDate(0, 0, 7))
+	},
+	"previous week": func(now time.Time, weekStart time.Weekday) model.DateRange {
+		start := model.StartOfWeek(now, weekStart).AddDate(0, 0, -7)
+		return model.NewDateRange(start, start.AddDate(0, 0, 7))
+	},
+	"this month": func(now time.Time, weekStart time.Weekday) model.DateRange {
+		start := model.StartOfMonth(now)
+		return model.NewDateRange(start, start.AddDate(0, 1, 0))
+	},
+	"previous month": func(now time.Time, weekStart time.Weekday) model.DateRange {
+		start := model.StartOfMonth(now).AddDate(0, -1, 0)
+		return model.NewDateRange(start, start.AddDate(0, 1, 0))
+	},
+	"this year": func(now time.Time, weekStart time.Weekday) model.DateRange {
+		start := model.StartOfYear(now)
+		return model.NewDateRange(start, start.AddDate(1, 0, 0))
+	},
+	"previous year": func(now time.Time, weekStart time.Weekday) model.DateRange {
+		start := model.StartOfYear(now).AddDate(-1, 0, 0)
+		return model.NewDateRange(start, start.AddDate(1, 0, 0))
+	},
+}
+
+// parseReportPeriod разбирает период отчета: week, month, year, диапазон дат 2022-09-01..2022-09-30
+// или именованный диапазон (this month, previous month, ...). По-умолчанию week.
+// Для диапазонов возвращается период model.Custom, календарные недели начинаются с weekStart
+func parseReportPeriod(argument string, now time.Time, weekStart time.Weekday) (model.ExpensePeriod, model.DateRange, error) {
+	argument = strings.ToLower(strings.Join(strings.Fields(argument), " "))
+
+	switch argument {
+	case "", "week":
+		return model.Week, model.DateRange{}, nil
+	case "month":
+		return model.Month, model.DateRange{}, nil
+	case "year":
+		return model.Year, model.DateRange{}, nil
+	}
+
+	if rangeGetter, ok := namedRanges[argument]; ok {
+		return model.Custom, rangeGetter(now, weekStart), nil
+	}
+
+	if strings.Contains(argument, dateRangeSeparator) {
+		dateRange, err := parseDateRange(argument, now.Location())
+		if err != nil {
+			return model.Week, model.DateRange{}, err
+		}
+
+		return model.Custom, dateRange, nil
+	}
+
+	return model.Week, model.DateRange{}, errors.New(errGetExpensesInvalidPeriodMessage)
+}
+
+// parseDateRange разбирает диапазон дат 2022-09-01..2022-09-30, последняя дата включается в диапазон
+func parseDateRange(argument string, loc *time.Location) (model.DateRange, error) {
+	parts := strings.Split(argument, dateRangeSeparator)
+	if len(parts) != 2 {
+		return model.DateRange{}, fmt.Errorf(errInvalidDateRangeMessage, argument)
+	}
+
+	from, err := time.ParseInLocation(dateFormat, strings.Trim(parts[0], " "), loc)
+	if err != nil {
+		return model.DateRange{}, fmt.Errorf(errInvalidDateRangeMessage, argument)
+	}
+
+	to, err := time.ParseInLocation(dateFormat, strings.Trim(parts[1], " "), loc)
+	if err != nil {
+		return model.DateRange{}, fmt.Errorf(errInvalidDateRangeMessage, argument)
+	}
+
+	if to.Before(from) {
+		return model.DateRange{}, fmt.Errorf(errInvalidDateRangeMessage, argument)
+	}
+
+	return model.NewDateRange(from, to.AddDate(0, 0, 1)), nil
+}
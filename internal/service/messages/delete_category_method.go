@@ -0,0 +1,31 @@
+package servicemessages
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+)
+
+func (m *Model) deleteCategory(ctx context.Context, msg Message) (string, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "deleteCategory")
+	defer span.Finish()
+
+	name := strings.Trim(msg.CommandArguments, " ")
+	if name == "" {
+		return "", errors.New(errDeleteCategoryInvalidParameterMessage)
+	}
+
+	found, err := m.expenseProcessor.DeleteCategory(ctx, msg.BudgetID, name)
+	if err != nil {
+		return "", err
+	}
+
+	if !found {
+		return "", fmt.Errorf(errCategoryNotFound, name)
+	}
+
+	return fmt.Sprintf(msgCategoryDeleted, name), nil
+}
@@ -0,0 +1,31 @@
+package servicemessages
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+)
+
+func (m *Model) deleteExpense(ctx context.Context, msg Message) (string, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "deleteExpense")
+	defer span.Finish()
+
+	id := strings.Trim(msg.CommandArguments, " ")
+	if id == "" {
+		return "", errors.New(errDeleteExpenseInvalidParameterMessage)
+	}
+
+	found, err := m.expenseProcessor.DeleteExpense(ctx, id, msg.BudgetID)
+	if err != nil {
+		return "", err
+	}
+
+	if !found {
+		return "", fmt.Errorf(errExpenseNotFound, id)
+	}
+
+	return fmt.Sprintf(msgExpenseDeleted, id), nil
+}
@@ -0,0 +1,274 @@
+package servicemessages
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/model"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/service/cache"
+)
+
+const (
+	dialogTimeout = 10 * time.Minute
+)
+
+// dialogState состояние многошагового диалога пользователя
+type dialogState struct {
+	Command   string    `json:"command"`
+	Answers   []string  `json:"answers"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// dialogStep шаг диалога: вопрос, варианты ответа и проверка ответа
+type dialogStep struct {
+	question string
+	buttons  func(ctx context.Context, m *Model, budgetID int64) []string
+	validate func(m *Model, answer string) error
+}
+
+// dialog многошаговый ввод аргументов команды, ответы собираются в аргументы через ;
+type dialog struct {
+	steps []dialogStep
+}
+
+var categoryStep = dialogStep{
+	question: "Выберите или введите категорию",
+	buttons: func(ctx context.Context, m *Model, budgetID int64) []string {
+		categories, err := m.expenseProcessor.GetTopCategories(ctx, budgetID, categoryPickerLimit)
+		if err != nil {
+			return nil
+		}
+		return categories
+	},
+	validate: func(m *Model, answer string) error {
+		if answer == "" || strings.Contains(answer, expenseArgumentsSeparator) {
+			return errors.New(errEmptyCategory)
+		}
+		return nil
+	},
+}
+
+var dialogs = map[string]dialog{
+	addExpenseCommand: {
+		steps: []dialogStep{
+			{
+				question: "Введите сумму траты, например 350 или 12 USD",
+				validate: func(m *Model, answer string) error {
+					_, _, err := newExpenseInputParser(m.currencies, time.Now()).parseAmount(answer)
+					return err
+				},
+			},
+			categoryStep,
+			{
+				question: "Введите дату: сегодня, вчера, 2022-10-01 или 2022-10-01 13:25:23",
+				buttons: func(ctx context.Context, m *Model, budgetID int64) []string {
+					return []string{"сегодня", "вчера"}
+				},
+				validate: func(m *Model, answer string) error {
+					if _, ok := newExpenseInputParser(m.currencies, time.Now()).parseDatetime(answer); !ok {
+						return fmt.Errorf(errAddExpenseInvalidDatetimeParameterMessage, answer)
+					}
+					return nil
+				},
+			},
+		},
+	},
+	setLimitCommand: {
+		steps: []dialogStep{
+			{
+				question: "Выберите или введите категорию, * - общий лимит на все траты",
+				buttons: func(ctx context.Context, m *Model, budgetID int64) []string {
+					return append(categoryStep.buttons(ctx, m, budgetID), totalLimitCategory)
+				},
+				validate: categoryStep.validate,
+			},
+			{
+				question: "Введите сумму лимита, например 12000.50",
+				validate: func(m *Model, answer string) error {
+					if _, err := strconv.ParseFloat(answer, 64); err != nil {
+						return fmt.Errorf(errInvalidAmountParameterMessage, answer)
+					}
+					return nil
+				},
+			},
+			{
+				question: "Выберите период лимита",
+				buttons: func(ctx context.Context, m *Model, budgetID int64) []string {
+					return []string{
+						model.LimitPeriodName(model.Week),
+						model.LimitPeriodName(model.Month),
+						model.LimitPeriodName(model.Quarter),
+						model.LimitPeriodName(model.Year),
+					}
+				},
+				validate: func(m *Model, answer string) error {
+					_, err := parseLimitPeriod(answer)
+					return err
+				},
+			},
+		},
+	},
+}
+
+// handleDialog ведет многошаговый диалог: начинает его для команды без аргументов,
+// принимает ответы на вопросы и по последнему ответу выполняет команду.
+// handled = false, если сообщение не относится к диалогу
+func (m *Model) handleDialog(ctx context.Context, msg Message) (
+	response string, btns []string, inlineBtns []model.InlineButton, handled bool, err error,
+) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "handleDialog")
+	defer span.Finish()
+
+	// в групповом чате у каждого участника свой диалог
+	key := cache.DialogKey(replyChatID(msg.ChatID, msg.UserID), msg.UserID)
+
+	if msg.Command == cancelCommand {
+		response, err = m.cancelDialog(ctx, key)
+		return response, mainMenu, nil, true, err
+	}
+
+	if msg.Command != "" {
+		// любая другая команда прерывает начатый диалог
+		if err = m.dropDialogState(ctx, key); err != nil {
+			return "", mainMenu, nil, true, err
+		}
+
+		if _, ok := dialogs[msg.Command]; !ok || strings.Trim(msg.CommandArguments, " ") != "" {
+			return "", nil, nil, false, nil
+		}
+
+		state := dialogState{Command: msg.Command}
+		if err = m.saveDialogState(ctx, key, state); err != nil {
+			return "", mainMenu, nil, true, err
+		}
+
+		response, btns = m.askDialogQuestion(ctx, state, msg.BudgetID)
+		return response, btns, nil, true, nil
+	}
+
+	state, found, err := m.getDialogState(ctx, key)
+	if err != nil {
+		return "", mainMenu, nil, true, err
+	}
+
+	if !found {
+		return "", nil, nil, false, nil
+	}
+
+	d := dialogs[state.Command]
+	answer := strings.Trim(msg.Text, " ")
+
+	if err = d.steps[len(state.Answers)].validate(m, answer); err != nil {
+		question, btns := m.askDialogQuestion(ctx, state, msg.BudgetID)
+		return fmt.Sprintf("%s\n%s", err.Error(), question), btns, nil, true, nil
+	}
+
+	state.Answers = append(state.Answers, answer)
+	if len(state.Answers) < len(d.steps) {
+		if err = m.saveDialogState(ctx, key, state); err != nil {
+			return "", mainMenu, nil, true, err
+		}
+
+		response, btns = m.askDialogQuestion(ctx, state, msg.BudgetID)
+		return response, btns, nil, true, nil
+	}
+
+	if err = m.dropDialogState(ctx, key); err != nil {
+		return "", mainMenu, nil, true, err
+	}
+
+	response, btns, inlineBtns, err = m.handleCommand(ctx, Message{
+		Command:          state.Command,
+		CommandArguments: strings.Join(state.Answers, expenseArgumentsSeparator),
+		UserID:           msg.UserID,
+		ChatID:           msg.ChatID,
+		UserName:         msg.UserName,
+		BotName:          msg.BotName,
+		BudgetID:         msg.BudgetID,
+		BudgetRole:       msg.BudgetRole,
+	})
+
+	return response, btns, inlineBtns, true, err
+}
+
+// askDialogQuestion возвращает вопрос текущего шага с вариантами ответа по бюджету budgetID и кнопкой отмены
+func (m *Model) askDialogQuestion(ctx context.Context, state dialogState, budgetID int64) (string, []string) {
+	step := dialogs[state.Command].steps[len(state.Answers)]
+
+	var btns []string
+	if step.buttons != nil {
+		btns = step.buttons(ctx, m, budgetID)
+	}
+	btns = append(btns, strings.Join([]string{"/", cancelCommand}, ""))
+
+	return step.question, btns
+}
+
+func (m *Model) cancelDialog(ctx context.Context, key string) (string, error) {
+	_, found, err := m.getDialogState(ctx, key)
+	if err != nil {
+		return "", err
+	}
+
+	if !found {
+		return "", errors.New(errNothingToCancel)
+	}
+
+	if err = m.dropDialogState(ctx, key); err != nil {
+		return "", err
+	}
+
+	return msgDialogCancelled, nil
+}
+
+// getDialogState возвращает начатый диалог по ключу cache.DialogKey, просроченный диалог не возвращается
+func (m *Model) getDialogState(ctx context.Context, key string) (dialogState, bool, error) {
+	value, found, err := m.dialogCache.Get(ctx, key)
+	if err != nil || !found {
+		return dialogState{}, false, err
+	}
+
+	jsonState, ok := value.(string)
+	if !ok {
+		return dialogState{}, false, nil
+	}
+
+	var state dialogState
+	if err = json.Unmarshal([]byte(jsonState), &state); err != nil {
+		return dialogState{}, false, err
+	}
+
+	// кэш в памяти не поддерживает время жизни ключей
+	if time.Since(state.UpdatedAt) > dialogTimeout {
+		return dialogState{}, false, m.dropDialogState(ctx, key)
+	}
+
+	if _, ok = dialogs[state.Command]; !ok {
+		return dialogState{}, false, nil
+	}
+
+	return state, true, nil
+}
+
+func (m *Model) saveDialogState(ctx context.Context, key string, state dialogState) error {
+	state.UpdatedAt = time.Now()
+
+	jsonState, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+
+	return m.dialogCache.Set(ctx, key, string(jsonState), dialogTimeout)
+}
+
+func (m *Model) dropDialogState(ctx context.Context, key string) error {
+	_, err := m.dialogCache.Del(ctx, key)
+
+	return err
+}
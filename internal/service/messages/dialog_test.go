@@ -0,0 +1,182 @@
+package servicemessages
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/model"
+	repomocks "gitlab.ozon.dev/cranky4/tg-bot/internal/repository/mocks"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/service/cache"
+	cachememory "gitlab.ozon.dev/cranky4/tg-bot/internal/service/cache/memory"
+	exp_processor_mock "gitlab.ozon.dev/cranky4/tg-bot/internal/service/expense_processor/mocks"
+	msgmocks "gitlab.ozon.dev/cranky4/tg-bot/internal/service/messages/mocks"
+	report_requester_mock "gitlab.ozon.dev/cranky4/tg-bot/internal/service/report_requester/mocks"
+)
+
+func TestAddExpenseDialogShouldAskStepByStepAndAddExpense(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	userId := int64(100)
+
+	processor := exp_processor_mock.NewMockExpenseProcessor(ctrl)
+	processor.EXPECT().GetTopCategories(gomock.Any(), userId, categoryPickerLimit).Return([]string{"Кофе"}, nil)
+	processor.EXPECT().AddExpense(gomock.Any(), 350.0, "RUB", "Кофе", "", gomock.Any(), userId, userId).
+		Return(&model.Expense{Category: "Кофе"}, nil)
+	processor.EXPECT().GetFreeLimits(gomock.Any(), "Кофе", userId, gomock.Any(), gomock.Any())
+	reportRequester := report_requester_mock.NewMockReportRequester(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+	settingsRepo.EXPECT().GetSettings(gomock.Any(), userId).Return(model.UserSettings{}, false, nil)
+
+	sender := msgmocks.NewMockMessageSender(ctrl)
+	gomock.InOrder(
+		sender.EXPECT().SendMessage("Введите сумму траты, например 350 или 12 USD", userId, []string{"/cancel"}),
+		sender.EXPECT().SendMessage("неверное значение суммы: много\nВведите сумму траты, например 350 или 12 USD", userId, []string{"/cancel"}),
+		sender.EXPECT().SendMessage("Выберите или введите категорию", userId, []string{"Кофе", "/cancel"}),
+		sender.EXPECT().SendMessage(
+			"Введите дату: сегодня, вчера, 2022-10-01 или 2022-10-01 13:25:23",
+			userId,
+			[]string{"сегодня", "вчера", "/cancel"},
+		),
+		sender.EXPECT().SendMessage(gomock.Any(), userId, mainMenu),
+	)
+
+	model := newTestModel(Deps{
+		TgClient:         sender,
+		ExpenseProcessor: processor,
+		ReportRequester:  reportRequester,
+		SettingsRepo:     settingsRepo,
+	})
+
+	for _, msg := range []Message{
+		{Command: addExpenseCommand, UserID: userId},
+		{Text: "много", UserID: userId},
+		{Text: "350", UserID: userId},
+		{Text: "Кофе", UserID: userId},
+		{Text: "сегодня", UserID: userId},
+	} {
+		assert.NoError(t, model.IncomingMessage(ctx, msg))
+	}
+}
+
+func TestSetLimitDialogShouldAskPeriodAndSetTotalLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	userId := int64(100)
+
+	processor := exp_processor_mock.NewMockExpenseProcessor(ctrl)
+	processor.EXPECT().GetTopCategories(gomock.Any(), userId, categoryPickerLimit).Return([]string{"Кофе"}, nil)
+	processor.EXPECT().SetLimit(gomock.Any(), "", model.Quarter, userId, 90000.0, "RUB").Return(90000.0, nil)
+	reportRequester := report_requester_mock.NewMockReportRequester(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+	settingsRepo.EXPECT().GetSettings(gomock.Any(), userId).Return(model.UserSettings{}, false, nil)
+
+	periods := []string{"week", "month", "quarter", "year", "/cancel"}
+	sender := msgmocks.NewMockMessageSender(ctrl)
+	gomock.InOrder(
+		sender.EXPECT().SendMessage(
+			"Выберите или введите категорию, * - общий лимит на все траты",
+			userId,
+			[]string{"Кофе", "*", "/cancel"},
+		),
+		sender.EXPECT().SendMessage("Введите сумму лимита, например 12000.50", userId, []string{"/cancel"}),
+		sender.EXPECT().SendMessage("Выберите период лимита", userId, periods),
+		sender.EXPECT().SendMessage(
+			"неизвестный период лимита day. Ожидается: week, month, quarter, year\nВыберите период лимита",
+			userId,
+			periods,
+		),
+		sender.EXPECT().SendMessage("Установлен общий квартальный лимит 90000.00 RUB", userId, mainMenu),
+	)
+
+	model := newTestModel(Deps{
+		TgClient:         sender,
+		ExpenseProcessor: processor,
+		ReportRequester:  reportRequester,
+		SettingsRepo:     settingsRepo,
+	})
+
+	for _, msg := range []Message{
+		{Command: setLimitCommand, UserID: userId},
+		{Text: "*", UserID: userId},
+		{Text: "90000", UserID: userId},
+		{Text: "day", UserID: userId},
+		{Text: "quarter", UserID: userId},
+	} {
+		assert.NoError(t, model.IncomingMessage(ctx, msg))
+	}
+}
+
+func TestSetLimitDialogShouldBeCancelled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	userId := int64(100)
+
+	processor := exp_processor_mock.NewMockExpenseProcessor(ctrl)
+	processor.EXPECT().GetTopCategories(gomock.Any(), userId, categoryPickerLimit).Return([]string{}, nil)
+	reportRequester := report_requester_mock.NewMockReportRequester(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+
+	sender := msgmocks.NewMockMessageSender(ctrl)
+	gomock.InOrder(
+		sender.EXPECT().SendMessage("Выберите или введите категорию, * - общий лимит на все траты", userId, []string{"*", "/cancel"}),
+		sender.EXPECT().SendMessage("Отменено", userId, mainMenu),
+		sender.EXPECT().SendMessage("нечего отменять", userId, mainMenu),
+		sender.EXPECT().SendMessage("не знаю эту команду", userId, mainMenu),
+	)
+
+	model := newTestModel(Deps{
+		TgClient:         sender,
+		ExpenseProcessor: processor,
+		ReportRequester:  reportRequester,
+		SettingsRepo:     settingsRepo,
+	})
+
+	for _, msg := range []Message{
+		{Command: setLimitCommand, UserID: userId},
+		{Command: cancelCommand, UserID: userId},
+		{Command: cancelCommand, UserID: userId},
+		{Text: "Дом", UserID: userId},
+	} {
+		assert.NoError(t, model.IncomingMessage(ctx, msg))
+	}
+}
+
+func TestExpiredDialogShouldBeIgnored(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	userId := int64(100)
+
+	dialogCache := cachememory.NewLRUCache(10)
+	state, err := json.Marshal(dialogState{
+		Command:   setLimitCommand,
+		Answers:   []string{"Дом"},
+		UpdatedAt: time.Now().Add(-dialogTimeout - time.Minute),
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, dialogCache.Set(ctx, cache.DialogKey(userId, userId), string(state), dialogTimeout))
+
+	processor := exp_processor_mock.NewMockExpenseProcessor(ctrl)
+	reportRequester := report_requester_mock.NewMockReportRequester(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+
+	sender := msgmocks.NewMockMessageSender(ctrl)
+	sender.EXPECT().SendMessage("не знаю эту команду", userId, mainMenu)
+
+	model := newTestModel(Deps{
+		TgClient:         sender,
+		ExpenseProcessor: processor,
+		ReportRequester:  reportRequester,
+		SettingsRepo:     settingsRepo,
+		DialogCache:      dialogCache,
+	})
+
+	assert.NoError(t, model.IncomingMessage(ctx, Message{Text: "12000", UserID: userId}))
+
+	_, found, err := dialogCache.Get(ctx, cache.DialogKey(userId, userId))
+	assert.NoError(t, err)
+	assert.False(t, found)
+}
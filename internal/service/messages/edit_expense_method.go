@@ -0,0 +1,48 @@
+package servicemessages
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+)
+
+func (m *Model) editExpense(ctx context.Context, msg Message) (string, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "editExpense")
+	defer span.Finish()
+
+	parts := strings.SplitN(strings.Trim(msg.CommandArguments, " "), " ", 2)
+	if len(parts) != 2 {
+		return "", errors.New(errEditExpenseInvalidParameterMessage)
+	}
+
+	id := parts[0]
+	settings, err := m.getUserSettings(ctx, msg.UserID)
+	if err != nil {
+		return "", err
+	}
+
+	args, err := newExpenseInputParser(m.currencies, time.Now().In(settings.Location())).parse(parts[1])
+	if err != nil {
+		return "", err
+	}
+
+	currency := settings.Currency
+	if args.currency != "" {
+		currency = args.currency
+	}
+
+	_, found, err := m.expenseProcessor.UpdateExpense(ctx, id, args.amount, currency, args.category, args.datetime, msg.BudgetID)
+	if err != nil {
+		return "", err
+	}
+
+	if !found {
+		return "", fmt.Errorf(errExpenseNotFound, id)
+	}
+
+	return fmt.Sprintf(msgExpenseUpdated, id, args.amount, currency, args.category, args.datetime.Format(datetimeFormat)), nil
+}
@@ -0,0 +1,199 @@
+package servicemessages
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	expenseArgumentsSeparator = ";"
+)
+
+// relativeDays смещение в днях для относительных дат
+var relativeDays = map[string]int{
+	"сегодня":   0,
+	"today":     0,
+	"вчера":     -1,
+	"yesterday": -1,
+	"позавчера": -2,
+}
+
+type expenseArguments struct {
+	amount   float64
+	currency string // пусто - валюта пользователя
+	category string
+	account  string // пусто - трата без счета
+	datetime time.Time
+}
+
+// expenseInputParser разбирает ввод траты в одном из форматов:
+//   - Сумма;Категория;Дата, например 120.50;Дом;2022-10-01 13:25:23
+//   - Сумма;Категория, дата по-умолчанию текущая
+//   - Сумма;Категория;Дата;Счет, дата может быть пустой: 350;Кафе;;Карта
+//   - Сумма Категория [Дата], например 350 кофе вчера
+//
+// Сумма может быть с запятой (120,50) и с валютой (12 USD), дата - полной (2022-10-01 13:25:23),
+// только датой (2022-10-01) или словом (сегодня, вчера, позавчера)
+type expenseInputParser struct {
+	currencies map[string]struct{}
+	now        time.Time
+}
+
+func newExpenseInputParser(currencies map[string]struct{}, now time.Time) *expenseInputParser {
+	return &expenseInputParser{
+		currencies: currencies,
+		now:        now,
+	}
+}
+
+func (p *expenseInputParser) parse(arguments string) (expenseArguments, error) {
+	arguments = strings.Trim(arguments, " ")
+	if arguments == "" {
+		return expenseArguments{}, errors.New(errAddExpenseInvalidParameterMessage)
+	}
+
+	if strings.Contains(arguments, expenseArgumentsSeparator) {
+		return p.parseSeparated(arguments)
+	}
+
+	return p.parseFree(arguments)
+}
+
+// parseSeparated разбирает формат Сумма;Категория[;Дата][;Счет]
+func (p *expenseInputParser) parseSeparated(arguments string) (expenseArguments, error) {
+	parts := strings.Split(arguments, expenseArgumentsSeparator)
+	if len(parts) < 2 || len(parts) > 4 {
+		return expenseArguments{}, errors.New(errAddExpenseInvalidParameterMessage)
+	}
+
+	amount, currency, err := p.parseAmount(parts[0])
+	if err != nil {
+		return expenseArguments{}, err
+	}
+
+	category := strings.Trim(parts[1], " ")
+	if category == "" {
+		return expenseArguments{}, errors.New(errAddExpenseInvalidParameterMessage)
+	}
+
+	datetime := p.now
+	if len(parts) >= 3 {
+		if rawDatetime := strings.Trim(parts[2], " "); rawDatetime != "" {
+			var ok bool
+			if datetime, ok = p.parseDatetime(rawDatetime); !ok {
+				return expenseArguments{}, fmt.Errorf(errAddExpenseInvalidDatetimeParameterMessage, rawDatetime)
+			}
+		}
+	}
+
+	var account string
+	if len(parts) == 4 {
+		account = strings.Trim(parts[3], " ")
+	}
+
+	return expenseArguments{
+		amount:   amount,
+		currency: currency,
+		category: category,
+		account:  account,
+		datetime: datetime,
+	}, nil
+}
+
+// parseFree разбирает формат Сумма [Валюта] Категория [Дата]
+func (p *expenseInputParser) parseFree(arguments string) (expenseArguments, error) {
+	fields := strings.Fields(arguments)
+
+	rawAmount := fields[0]
+	fields = fields[1:]
+	if len(fields) > 0 && p.isCurrency(fields[0]) {
+		rawAmount = rawAmount + " " + fields[0]
+		fields = fields[1:]
+	}
+
+	amount, currency, err := p.parseAmount(rawAmount)
+	if err != nil {
+		return expenseArguments{}, err
+	}
+
+	datetime, fields := p.extractDatetime(fields)
+	if len(fields) == 0 {
+		return expenseArguments{}, errors.New(errAddExpenseInvalidParameterMessage)
+	}
+
+	return expenseArguments{
+		amount:   amount,
+		currency: currency,
+		category: strings.Join(fields, " "),
+		datetime: datetime,
+	}, nil
+}
+
+// extractDatetime отделяет дату в конце ввода, оставляя хотя бы одно слово на категорию
+func (p *expenseInputParser) extractDatetime(fields []string) (time.Time, []string) {
+	// полная дата занимает два слова: 2022-10-01 13:25:23
+	for _, size := range []int{2, 1} {
+		if len(fields) <= size {
+			continue
+		}
+
+		if datetime, ok := p.parseDatetime(strings.Join(fields[len(fields)-size:], " ")); ok {
+			return datetime, fields[:len(fields)-size]
+		}
+	}
+
+	return p.now, fields
+}
+
+// parseAmount разбирает сумму с необязательной валютой: 120.50, 120,50, 12 USD, 12USD
+func (p *expenseInputParser) parseAmount(value string) (float64, string, error) {
+	value = strings.Trim(value, " ")
+
+	var currency string
+	if idx := strings.IndexFunc(value, unicode.IsLetter); idx == 0 {
+		return 0, "", fmt.Errorf(errInvalidAmountParameterMessage, value)
+	} else if idx > 0 {
+		currency = strings.ToUpper(strings.Trim(value[idx:], " "))
+		if !p.isCurrency(currency) {
+			return 0, "", fmt.Errorf(errUnknownCurrency, currency)
+		}
+		value = strings.Trim(value[:idx], " ")
+	}
+
+	amount, err := strconv.ParseFloat(strings.Replace(value, ",", ".", 1), 64)
+	if err != nil || amount <= 0 {
+		return 0, "", fmt.Errorf(errInvalidAmountParameterMessage, value)
+	}
+
+	return amount, currency, nil
+}
+
+// parseDatetime разбирает полную дату, дату без времени или относительную дату
+func (p *expenseInputParser) parseDatetime(value string) (time.Time, bool) {
+	value = strings.ToLower(strings.Trim(value, " "))
+
+	if days, ok := relativeDays[value]; ok {
+		return p.now.AddDate(0, 0, days), true
+	}
+
+	if datetime, err := time.ParseInLocation(datetimeFormat, value, p.now.Location()); err == nil {
+		return datetime, true
+	}
+
+	if date, err := time.ParseInLocation(dateFormat, value, p.now.Location()); err == nil {
+		return date, true
+	}
+
+	return time.Time{}, false
+}
+
+func (p *expenseInputParser) isCurrency(value string) bool {
+	_, found := p.currencies[strings.ToUpper(value)]
+
+	return found
+}
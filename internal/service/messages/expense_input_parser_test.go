@@ -0,0 +1,85 @@
+package servicemessages
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpenseInputParserShouldParseSupportedFormats(t *testing.T) {
+	now := time.Date(2022, time.October, 10, 15, 30, 0, 0, time.UTC)
+	parser := newExpenseInputParser(currencies, now)
+
+	tests := []struct {
+		input    string
+		expected expenseArguments
+	}{
+		{
+			input: "120.50;Дом;2022-10-01 13:25:23",
+			expected: expenseArguments{
+				amount: 120.50, category: "Дом", datetime: time.Date(2022, time.October, 1, 13, 25, 23, 0, time.UTC),
+			},
+		},
+		{
+			input:    "120,50;Дом",
+			expected: expenseArguments{amount: 120.50, category: "Дом", datetime: now},
+		},
+		{
+			input: "12 USD;Книги;2022-10-01",
+			expected: expenseArguments{
+				amount: 12, currency: "USD", category: "Книги", datetime: time.Date(2022, time.October, 1, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			input:    "350 кофе",
+			expected: expenseArguments{amount: 350, category: "кофе", datetime: now},
+		},
+		{
+			input:    "350 кофе вчера",
+			expected: expenseArguments{amount: 350, category: "кофе", datetime: now.AddDate(0, 0, -1)},
+		},
+		{
+			input:    "12usd такси сегодня",
+			expected: expenseArguments{amount: 12, currency: "USD", category: "такси", datetime: now},
+		},
+		{
+			input: "1500 eur новый телефон 2022-10-01 13:25:23",
+			expected: expenseArguments{
+				amount: 1500, currency: "EUR", category: "новый телефон", datetime: time.Date(2022, time.October, 1, 13, 25, 23, 0, time.UTC),
+			},
+		},
+		{
+			input:    "200 вчера",
+			expected: expenseArguments{amount: 200, category: "вчера", datetime: now},
+		},
+		{
+			input:    "350;Кафе;;Карта",
+			expected: expenseArguments{amount: 350, category: "Кафе", account: "Карта", datetime: now},
+		},
+	}
+
+	for _, tt := range tests {
+		args, err := parser.parse(tt.input)
+		assert.NoError(t, err, tt.input)
+		assert.Equal(t, tt.expected, args, tt.input)
+	}
+}
+
+func TestExpenseInputParserShouldReturnErrors(t *testing.T) {
+	parser := newExpenseInputParser(currencies, time.Now())
+
+	tests := map[string]string{
+		"":                        "неверное количество параметров.\nОжидается: Сумма;Категория;Дата;Счет или Сумма Категория \nНапример: 120.50;Дом;2022-10-01 13:25:23, 350 кофе вчера",
+		"350":                     "неверное количество параметров.\nОжидается: Сумма;Категория;Дата;Счет или Сумма Категория \nНапример: 120.50;Дом;2022-10-01 13:25:23, 350 кофе вчера",
+		"кофе 350":                "неверное значение суммы: кофе",
+		"12 GBP;Книги":            "неизвестная валюта GBP",
+		"-10;Дом":                 "неверное значение суммы: -10",
+		"10;Дом;2022-13-01 10:00": "неверный формат даты и времени: 2022-13-01 10:00. Ожидается 2022-01-28 15:10:11",
+	}
+
+	for input, expected := range tests {
+		_, err := parser.parse(input)
+		assert.EqualError(t, err, expected, input)
+	}
+}
@@ -0,0 +1,63 @@
+package servicemessages
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/model"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/service/expense_exporter"
+)
+
+// requestExport запрашивает выгрузку трат в файл: /export csv month, /export xlsx 2022-01-01..2022-12-31
+func (m *Model) requestExport(ctx context.Context, msg Message) (string, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "requestExport")
+	defer span.Finish()
+
+	rawFormat, rawPeriod, _ := strings.Cut(strings.Trim(msg.CommandArguments, " "), " ")
+	format, ok := model.ParseExportFormat(rawFormat)
+	if !ok {
+		return "", errors.New(errExportInvalidParameterMessage)
+	}
+
+	settings, err := m.getUserSettings(ctx, msg.UserID)
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now().In(settings.Location())
+	period, dateRange, err := parseReportPeriod(rawPeriod, now, settings.WeekStart)
+	if err != nil {
+		return "", err
+	}
+
+	if period != model.Custom {
+		dateRange = getPeriodRange(period, settings, now)
+	}
+
+	err = m.reportRequester.SendRequestExport(ctx, msg.UserID, replyChatID(msg.ChatID, msg.UserID), msg.BudgetID, format, dateRange, settings.Currency)
+	if err != nil {
+		return "", err
+	}
+
+	return msgExportRequested, nil
+}
+
+// SendExport отправляет файл выгрузки трат получателю
+func (m *Model) SendExport(ctx context.Context, export *expense_exporter.ExpenseExport) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "Messaging_SendExport")
+	defer span.Finish()
+
+	settings, err := m.getUserSettings(ctx, export.UserID)
+	if err != nil {
+		return err
+	}
+
+	// диапазон приходит в UTC, в подписи показываем его в часовом поясе пользователя
+	caption := fmt.Sprintf(msgExport, export.Range.In(settings.Location()).String(), export.Count)
+
+	return m.tgClient.SendDocument(caption, replyChatID(export.ChatID, export.UserID), export.File)
+}
@@ -2,9 +2,10 @@ package servicemessages
 
 import (
 	"context"
+	"strings"
+	"time"
 
 	"github.com/opentracing/opentracing-go"
-	"github.com/pkg/errors"
 	"gitlab.ozon.dev/cranky4/tg-bot/internal/model"
 )
 
@@ -16,23 +17,30 @@ func (m *Model) getExpenses(ctx context.Context, msg Message) (string, error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "getExpenses")
 	defer span.Finish()
 
-	var expPeriod model.ExpensePeriod
-
-	switch msg.CommandArguments {
-	case "week":
-		expPeriod = model.Week
-	case "month":
-		expPeriod = model.Month
-	case "year":
-		expPeriod = model.Year
-	default:
-		if msg.CommandArguments != "" {
-			return "", errors.New(errGetExpensesInvalidPeriodMessage)
-		}
-		expPeriod = model.Week
+	settings, err := m.getUserSettings(ctx, msg.UserID)
+	if err != nil {
+		return "", err
+	}
+
+	// отчет по одному счету: /getExpenses month;Карта
+	rawPeriod, rawAccount, _ := strings.Cut(msg.CommandArguments, expenseArgumentsSeparator)
+
+	now := time.Now().In(settings.Location())
+	expPeriod, dateRange, err := parseReportPeriod(rawPeriod, now, settings.WeekStart)
+	if err != nil {
+		return "", err
+	}
+
+	account, err := m.findAccount(ctx, msg.BudgetID, rawAccount)
+	if err != nil {
+		return "", err
+	}
+
+	if expPeriod != model.Custom {
+		dateRange = getPeriodRange(expPeriod, settings, now)
 	}
 
-	err := m.reportRequester.SendRequestReport(ctx, msg.UserID, expPeriod, m.currency)
+	err = m.reportRequester.SendRequestReport(ctx, msg.UserID, replyChatID(msg.ChatID, msg.UserID), msg.BudgetID, expPeriod, dateRange, settings.Currency, account)
 	if err != nil {
 		return "", err
 	}
@@ -0,0 +1,203 @@
+package servicemessages
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/model"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/service/expense_importer"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/service/logger"
+)
+
+const (
+	importDryRunArgument = "dry"
+	importErrorsLimit    = 20
+	importTokenBytes     = 24
+)
+
+// ErrInvalidImportToken токен импорта не выдавался или заменен новым
+var ErrInvalidImportToken = errors.New("неверный токен импорта, получите новый в личном чате с ботом: /importToken")
+
+// importExpenses загружает траты из csv документа с подписью /import [dry] [колонки]
+func (m *Model) importExpenses(ctx context.Context, msg Message) (string, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "importExpenses")
+	defer span.Finish()
+
+	if msg.Attachment == nil {
+		return "", errors.New(errImportFileMissing)
+	}
+
+	dryRun, mapping, err := parseImportArguments(msg.CommandArguments)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := m.tgClient.DownloadFile(msg.Attachment.FileID)
+	if err != nil {
+		logger.Error(err.Error())
+		return "", errors.New(errImportDownloadMessage)
+	}
+
+	result, err := m.expenseImporter.Import(ctx, data, expense_importer.ImportOptions{
+		UserID:   msg.UserID,
+		BudgetID: msg.BudgetID,
+		Mapping:  mapping,
+		DryRun:   dryRun,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return formatImportResult(result), nil
+}
+
+// issueImportToken выдает токен для импорта трат по HTTP: /importToken. Токен показывается один раз, хранится его хеш
+func (m *Model) issueImportToken(ctx context.Context, msg Message) (string, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "issueImportToken")
+	defer span.Finish()
+
+	raw := make([]byte, importTokenBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(raw)
+
+	if err := m.importTokensRepo.SaveToken(ctx, msg.UserID, hashImportToken(token)); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf(msgImportToken, token), nil
+}
+
+// ImportExpenses импортирует траты из csv, загруженного через HTTP, от имени владельца токена
+// в его активный бюджет или бюджет группового чата chatID, в котором он состоит
+func (m *Model) ImportExpenses(
+	ctx context.Context,
+	token string,
+	chatID int64,
+	data []byte,
+	rawMapping string,
+	dryRun bool,
+) (*expense_importer.ImportResult, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "Messaging_ImportExpenses")
+	defer span.Finish()
+
+	if token == "" {
+		return nil, ErrInvalidImportToken
+	}
+
+	userID, found, err := m.importTokensRepo.GetTokenUser(ctx, hashImportToken(token))
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, ErrInvalidImportToken
+	}
+
+	member, err := m.resolveImportBudget(ctx, chatID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !member.Role.CanEdit() {
+		return nil, errors.New(errBudgetReadOnly)
+	}
+
+	mapping, err := expense_importer.ParseColumnMapping(rawMapping)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.expenseImporter.Import(ctx, data, expense_importer.ImportOptions{
+		UserID:   userID,
+		BudgetID: member.BudgetID,
+		Mapping:  mapping,
+		DryRun:   dryRun,
+	})
+}
+
+// resolveImportBudget возвращает бюджет импорта по HTTP. Запрос приходит не из чата,
+// поэтому участие в группе проверяется по участникам, которых бот запомнил по их командам в группе
+func (m *Model) resolveImportBudget(ctx context.Context, chatID, userID int64) (model.BudgetMember, error) {
+	if !isGroupChat(chatID, userID) {
+		return m.resolveBudget(ctx, chatID, userID)
+	}
+
+	member, found, err := m.findBudgetMember(ctx, chatID, userID)
+	if err != nil {
+		return model.BudgetMember{}, err
+	}
+	if !found {
+		return model.BudgetMember{}, fmt.Errorf(errImportNotGroupMember, chatID)
+	}
+
+	return member, nil
+}
+
+func hashImportToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+
+	return hex.EncodeToString(hash[:])
+}
+
+// parseImportArguments разбирает аргументы: dry date=Дата операции;amount=Сумма, оба необязательны
+func parseImportArguments(arguments string) (bool, expense_importer.ColumnMapping, error) {
+	arguments = strings.Trim(arguments, " ")
+
+	dryRun := false
+	if first, rest, _ := strings.Cut(arguments, " "); strings.EqualFold(first, importDryRunArgument) {
+		dryRun, arguments = true, rest
+	}
+
+	mapping, err := expense_importer.ParseColumnMapping(arguments)
+	if err != nil {
+		return false, expense_importer.ColumnMapping{}, err
+	}
+
+	return dryRun, mapping, nil
+}
+
+func formatImportResult(result *expense_importer.ImportResult) string {
+	var response strings.Builder
+
+	if result.DryRun {
+		response.WriteString(fmt.Sprintf(msgImportDryRun, result.Rows, result.Added, result.Duplicates, len(result.Errors)))
+
+		if len(result.Preview) > 0 {
+			response.WriteString(msgImportPreview)
+		}
+		for _, row := range result.Preview {
+			duplicate := ""
+			if row.Duplicate {
+				duplicate = msgImportDuplicate
+			}
+
+			response.WriteString(fmt.Sprintf(
+				msgImportPreviewRow,
+				row.Row, row.Amount, row.Currency, row.Category, row.Datetime.Format(datetimeFormat), duplicate,
+			))
+		}
+	} else {
+		response.WriteString(fmt.Sprintf(msgImportDone, result.Rows, result.Added, result.Duplicates, len(result.Errors)))
+	}
+
+	if len(result.Errors) > 0 {
+		response.WriteString(msgImportErrors)
+	}
+	for i, rowErr := range result.Errors {
+		if i == importErrorsLimit {
+			response.WriteString(fmt.Sprintf(msgMoreExpenses, len(result.Errors)-importErrorsLimit))
+			break
+		}
+
+		response.WriteString(fmt.Sprintf(msgImportRowError, rowErr.Row, rowErr.Message))
+	}
+
+	return response.String()
+}
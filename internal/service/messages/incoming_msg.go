@@ -7,30 +7,181 @@ import (
 	"time"
 
 	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/uber/jaeger-client-go"
-	serviceconverter "gitlab.ozon.dev/cranky4/tg-bot/internal/service/converter"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/model"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/repository"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/service/cache"
+	expenseattachments "gitlab.ozon.dev/cranky4/tg-bot/internal/service/expense_attachments"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/service/expense_importer"
 	"gitlab.ozon.dev/cranky4/tg-bot/internal/service/expense_processor"
 	"gitlab.ozon.dev/cranky4/tg-bot/internal/service/expense_reporter"
 	"gitlab.ozon.dev/cranky4/tg-bot/internal/service/logger"
+	recurringexpenses "gitlab.ozon.dev/cranky4/tg-bot/internal/service/recurring_expenses"
 	reportrequester "gitlab.ozon.dev/cranky4/tg-bot/internal/service/report_requester"
 )
 
 const (
-	errAddExpenseInvalidParameterMessage = "неверное количество параметров.\nОжидается: Сумма;Категория;Дата \n" +
-		"Например: 120.50;Дом;2022-10-01 13:25:23"
+	errAddExpenseInvalidParameterMessage = "неверное количество параметров.\nОжидается: Сумма;Категория;Дата;Счет или Сумма Категория \n" +
+		"Например: 120.50;Дом;2022-10-01 13:25:23, 350 кофе вчера"
 	errInvalidAmountParameterMessage             = "неверное значение суммы: %v"
 	errAddExpenseInvalidDatetimeParameterMessage = "неверный формат даты и времени: %v. Ожидается 2022-01-28 15:10:11"
 
 	errUnknownCurrency                 = "неизвестная валюта %s"
-	errGetExpensesInvalidPeriodMessage = "неверный период. Ожидается: year, month, week. По-умолчанию week"
-	errSetLimitInvalidParameterMessage = "неверное количество параметров.\nОжидается: Категория;Сумма \n" +
-		"Например: Дом;12000.50"
-	msgExpenseAdded = "Трата %.02f %s добавлена в категорию %s с датой %s"
-	msgCurrencySet  = "Установлена валюта в %s"
-	msgFreeLimit    = "Свободный месячный лимит %.02f %s"
-	msgLimitReached = "Достигнут месячный лимит (%.02f %s)"
-	msgSetLimit     = "Установлен месячный лимит %.02f %s для категории %s"
+	errGetExpensesInvalidPeriodMessage = "неверный период. Ожидается: year, month, week, диапазон дат 2022-09-01..2022-09-30 " +
+		"или today, yesterday, this week, previous week, this month, previous month, this year, previous year. По-умолчанию week"
+	errInvalidDateRangeMessage         = "неверный диапазон дат: %s. Ожидается 2022-09-01..2022-09-30"
+	errUnknownPeriodMode               = "неизвестный режим периодов %s. Ожидается: rolling, calendar"
+	errUnknownTimezone                 = "неизвестный часовой пояс %s. Например: Europe/Moscow"
+	errNoCategoriesToPick              = "нет категорий для выбора. Укажите категорию: /addExpense 350 кофе"
+	errUnknownCallback                 = "неизвестная кнопка"
+	errPickedCategoryNotFound          = "выбранной категории больше нет. Укажите категорию: /addExpense 350 кофе"
+	errEmptyCategory                   = "категория не может быть пустой"
+	errNothingToCancel                 = "нечего отменять"
+	errUnknownWeekday                  = "неизвестный день недели %s. Ожидается: monday, tuesday, wednesday, thursday, friday, saturday, sunday"
+	errSetLimitInvalidParameterMessage = "неверное количество параметров.\nОжидается: Категория;Сумма;Период \n" +
+		"Например: Дом;12000.50, Дом;3000;week, *;50000;month для общего лимита"
+	errSetLimitAlertsInvalidParameterMessage = "неверное количество параметров.\nОжидается: Категория;Пороги в процентах;Период \n" +
+		"Например: Дом;50,80,100, *;90;week, Дом;off для отключения"
+	errInvalidThresholds                  = "неверные пороги уведомлений: %s. Ожидается список процентов, например 50,80,100"
+	errLimitNotFound                      = "%s лимит для %s не установлен: /setLimit"
+	errUnknownLimitPeriod                 = "неизвестный период лимита %s. Ожидается: week, month, quarter, year"
+	errEditExpenseInvalidParameterMessage = "неверное количество параметров.\nОжидается: ИД Сумма;Категория;Дата \n" +
+		"Например: 1b4e28ba-2fa1-11d2-883f-0016d3cca427 120.50;Дом;2022-10-01 13:25:23"
+	errDeleteExpenseInvalidParameterMessage  = "не указан ИД траты"
+	errRenameCategoryInvalidParameterMessage = "неверное количество параметров.\nОжидается: Категория;Новое название \n" +
+		"Например: Еда;Продукты"
+	errMergeCategoriesInvalidParameterMessage = "неверное количество параметров.\nОжидается: Категория;Категория, в которую перенести траты \n" +
+		"Например: Еда;Продукты"
+	errDeleteCategoryInvalidParameterMessage = "не указана категория"
+	errMergeSameCategory                     = "нельзя объединить категорию с самой собой"
+	errCategoryRuleInvalidParameterMessage   = "неверный формат правила.\nОжидается: Текст => Категория \n" +
+		"Например: /alias еда => Продукты, /keyword старбакс => Кофе"
+	errDeleteCategoryRuleInvalidParameterMessage = "не указан шаблон правила"
+	errCategoryRuleNotFound                      = "правило %s не найдено"
+	errCategoryNotFound                          = "категория %s не найдена"
+	errCategoriesNotFound                        = "категория %s или %s не найдена"
+	errExpenseNotFound                           = "трата %s не найдена"
+	errInvalidScheduleMessage                    = "неверное расписание.\nОжидается: weekly День недели Время или monthly День месяца Время, время необязательно \n" +
+		"Например: weekly monday 09:00, monthly 1"
+	errUnknownSchedulePeriod               = "неизвестный период расписания %s. Ожидается: weekly, monthly"
+	errInvalidScheduleTime                 = "неверное время: %s. Ожидается 09:00"
+	errInvalidScheduleDay                  = "неверный день месяца: %s. Ожидается число от 1 до 31"
+	errAddRecurringInvalidParameterMessage = "неверное количество параметров.\nОжидается: Сумма;Категория;Расписание \n" +
+		"Например: 25000;Аренда;monthly 5, 599 RUB;Подписки;weekly friday 10:00"
+	errAddIncomeInvalidParameterMessage = "неверное количество параметров.\nОжидается: Сумма;Источник;Дата, дата необязательна \n" +
+		"Например: 150000;Зарплата;2022-10-05, 500 USD;Фриланс"
+	errAddAccountInvalidParameterMessage = "неверное количество параметров.\nОжидается: Название;Валюта;Начальный баланс, валюта и баланс необязательны \n" +
+		"Например: Карта;RUB;15000, Наличные"
+	errTransferInvalidParameterMessage = "неверное количество параметров.\nОжидается: Сумма;Со счета;На счет;Дата, дата необязательна \n" +
+		"Например: 5000;Карта;Накопления, 100 USD;Карта;Наличные;вчера"
+	errTransferSameAccount                  = "нельзя перевести деньги на тот же счет"
+	errBudgetReadOnly                       = "недостаточно прав: в активном бюджете у вас роль viewer, доступны только отчеты"
+	errBudgetManageForbidden                = "управлять участниками может только владелец бюджета"
+	errPersonalBudgetActive                 = "активен личный бюджет. Создайте общий: /createBudget Семья или вступите в него: /join КОД"
+	errJoinCodeMissingMessage               = "не указан код приглашения.\nНапример: /join ABCD2345"
+	errJoinCodeNotFound                     = "бюджет с кодом приглашения %s не найден"
+	errUnknownBudgetRole                    = "неизвестная роль %s. Ожидается: owner, editor, viewer"
+	errBudgetMemberNotFound                 = "участник %s не найден, список участников: /members"
+	errOwnBudgetRoleChange                  = "нельзя изменить свою роль"
+	errOwnerCannotLeaveBudget               = "единственный владелец не может выйти из бюджета, сначала назначьте владельцем другого участника: /setRole"
+	errCreateBudgetInvalidParameterMessage  = "не указано название бюджета.\nНапример: /createBudget Семья"
+	errSwitchBudgetInvalidParameterMessage  = "не указан бюджет.\nОжидается: ИД бюджета из /budgets или personal для личного бюджета"
+	errSetBudgetRoleInvalidParameterMessage = "неверное количество параметров.\nОжидается: Участник;Роль, участник - имя или ИД из /members, " +
+		"роль - owner, editor или viewer \nНапример: bob;viewer"
+	errPrivateChatCommand           = "команда доступна только в личном чате с ботом"
+	errSplitInvalidParameterMessage = "неверное количество параметров.\nОжидается: Сумма;Категория;Участники;Дата, дата необязательна \n" +
+		"Например: 3000;Ресторан;@alice,@bob"
+	errSettleDebtInvalidParameterMessage = "неверное количество параметров.\nОжидается: Участник;Сумма;Дата, дата необязательна \n" +
+		"Например: @alice;1000"
+	errSharedBudgetRequired          = "делить траты можно в общем бюджете или групповом чате. Создайте бюджет: /createBudget Семья"
+	errSplitMemberNotFound           = "участник %s не найден: он должен вступить в бюджет, а в группе - отправить боту любую команду"
+	errSplitNoParticipants           = "укажите хотя бы одного участника кроме себя"
+	errSettleDebtToYourself          = "нельзя вернуть долг самому себе"
+	errReceiptCaptionMessage         = "укажите в подписи к чеку сумму и категорию.\nНапример: 350;Кафе"
+	errReceiptDownloadMessage        = "не удалось загрузить чек"
+	errShowExpenseIdMissing          = "не указан ИД траты.\nНапример: /expense 1b4e28ba-2fa1-11d2-883f-0016d3cca427"
+	errReceiptNotFound               = "к трате %s чек не прикреплен"
+	errExportInvalidParameterMessage = "неверный формат выгрузки.\nОжидается: csv или xlsx и период, как в /getExpenses \n" +
+		"Например: /export csv month, /export xlsx 2022-01-01..2022-12-31"
+	errTrendInvalidParameterMessage = "неверные параметры.\nОжидается: период, как в /getExpenses, интервал day, week или month " +
+		"и ;categories для разбивки по категориям\nНапример: /trend month day, /trend year month;categories"
+	errTrendTooManyBuckets = "слишком много интервалов, больше %d. Выберите интервал крупнее или период короче"
+	errImportFileMissing   = "прикрепите к команде csv файл: документ с подписью /import.\nКолонки по-умолчанию как в /export, " +
+		"свои задаются так: /import date=Дата операции;amount=Сумма;category=Описание"
+	errImportDownloadMessage     = "не удалось загрузить файл"
+	errImportNotGroupMember      = "вы не участник группы %d: отправьте боту команду в группе, чтобы он вас запомнил"
+	errRecurringIdMissingMessage = "не указан ИД регулярной траты"
+	errRecurringNotFound         = "регулярная трата %s не найдена"
+	errSubscriptionNotFound      = "подписки на %s отчет нет"
+	msgExpenseAdded              = "Трата %.02f %s добавлена в категорию %s с датой %s"
+	msgCurrencySet               = "Установлена валюта в %s"
+	msgPeriodModeSet             = "Установлен режим периодов %s"
+	msgTimezoneSet               = "Установлен часовой пояс %s"
+	msgWeekStartSet              = "Неделя начинается с %s"
+	msgPickCategory              = "Выберите категорию для траты %.02f %s"
+	msgDialogCancelled           = "Отменено"
+	msgFreeLimit                 = "Свободный %s лимит %.02f %s"
+	msgLimitReached              = "Достигнут %s лимит (%.02f %s)"
+	msgSetLimit                  = "Установлен %s лимит %.02f %s для категории %s"
+	msgLimitAlertsSet            = "%s лимит для %s: уведомления при расходе %s%%"
+	msgLimitAlertsDisabled       = "%s лимит для %s: уведомления отключены"
+	msgSetTotalLimit             = "Установлен общий %s лимит %.02f %s"
+	msgExpenseUpdated            = "Трата %s изменена: %.02f %s в категории %s с датой %s"
+	msgExpenseDeleted            = "Трата %s удалена"
+	msgNoCategories              = "Категорий нет"
+	msgCategoryRenamed           = "Категория %s переименована в %s"
+	msgCategoriesMerged          = "Траты и лимит категории %s перенесены в %s"
+	msgCategoryDeleted           = "Категория %s удалена"
+	msgCategoryRuleSaved         = "Правило сохранено: %s => %s"
+	msgCategoryRuleDeleted       = "Правило %s удалено"
+	msgNoCategoryRules           = "Правил нет. Добавьте: /alias еда => Продукты или /keyword старбакс => Кофе"
+	msgNoExpenses                = "Трат за период нет"
+	msgSubscribed                = "%s отчет будет приходить по расписанию %s, следующий %s"
+	msgUnsubscribed              = "Подписка на %s отчет отменена"
+	msgNoSubscriptions           = "Подписок нет. Оформите: /subscribe weekly monday 09:00"
+	msgRecurringAdded            = "Регулярная трата %.02f %s в категорию %s добавлена: %s, первая %s\nИД: %s"
+	msgRecurringPaused           = "Регулярная трата %s приостановлена"
+	msgRecurringResumed          = "Регулярная трата %s возобновлена"
+	msgNoRecurring               = "Регулярных трат нет. Добавьте: /addRecurring 25000;Аренда;monthly 5"
+	msgIncomeAdded               = "Доход %.02f %s (%s) добавлен с датой %s"
+	msgAccountAdded              = "Счет %s добавлен, начальный баланс %.02f %s"
+	msgTransferDone              = "Переведено %.02f %s со счета %s на счет %s"
+	msgNoAccounts                = "Счетов нет. Добавьте: /addAccount Карта;RUB;15000"
+	msgExpenseAccount            = " со счета %s"
+	msgMoreExpenses              = "...и еще %d\n"
+	msgBudgetCreated             = "Бюджет %s создан и выбран активным.\n%s"
+	msgBudgetJoined              = "Вы участник бюджета %s с ролью %s. Траты, лимиты и отчеты теперь общие, " +
+		"вернуться к личному бюджету: /switchBudget personal"
+	msgBudgetActive         = "Активен бюджет %s"
+	msgPersonalBudgetActive = "Активен личный бюджет"
+	msgBudgetRoleSet        = "Участнику %s назначена роль %s"
+	msgBudgetLeft           = "Вы вышли из бюджета %s, активен личный бюджет"
+	msgExpenseSplit         = "Трата %.02f %s в категорию %s разделена поровну, платил %s:\n%s"
+	msgDebtSettled          = "Записан возврат долга %.02f %s участнику %s"
+	msgNoDebts              = "Долгов нет"
+	msgReceiptSaved         = "\nЧек сохранен: /expense %s"
+	msgReceiptNotSaved      = "\nЧек не сохранен: %s"
+	msgReceipt              = "Чек траты %s"
+	msgExportRequested      = "Запрос на выгрузку трат отправлен, файл придет отдельным сообщением"
+	msgExport               = "Траты за %s: %d"
+	msgImportDone           = "Импорт: строк %d, добавлено трат %d, пропущено дублей %d, строк с ошибками %d\n"
+	msgImportDryRun         = "Проверка без сохранения: строк %d, будет добавлено трат %d, дублей %d, строк с ошибками %d\n"
+	msgImportPreview        = "Первые строки:\n"
+	msgImportPreviewRow     = "%d: %.02f %s - %s - %s%s\n"
+	msgImportDuplicate      = " (дубль)"
+	msgImportErrors         = "Ошибки:\n"
+	msgImportRowError       = "строка %d: %s\n"
+	msgTrendHeader          = "Динамика расходов за %s %s:\n"
+	msgTrendRow             = "%s - %.02f %s\n"
+	msgTrendBucket          = "%s: %s %.02f"
+	msgTrendCategory        = ", %s %.02f"
+	msgTrendTotal           = "Итого: %.02f %s\n"
+	msgTrendAverage         = "В среднем за интервал: %.02f %s\n"
+	msgTrendEmpty           = "пусто\n"
+	msgImportToken          = "Токен для импорта трат по HTTP: %s\nПрежний токен больше не действует. Не показывайте токен другим: " +
+		"с ним можно добавлять траты в ваши бюджеты"
 
 	datetimeFormat = "2006-01-02 15:04:05"
 
@@ -40,8 +191,53 @@ const (
 	requestCurrencyChangeCommand = "requestCurrencyChange"
 	setCurrencyCommand           = "setCurrency"
 	setLimitCommand              = "setLimit"
+	setLimitAlertsCommand        = "setLimitAlerts"
+	listExpensesCommand          = "listExpenses"
+	editExpenseCommand           = "editExpense"
+	deleteExpenseCommand         = "deleteExpense"
+	setPeriodModeCommand         = "setPeriodMode"
+	setWeekStartCommand          = "setWeekStart"
+	setTimezoneCommand           = "setTimezone"
+	cancelCommand                = "cancel"
+	categoriesCommand            = "categories"
+	renameCategoryCommand        = "renameCategory"
+	mergeCategoriesCommand       = "mergeCategories"
+	deleteCategoryCommand        = "deleteCategory"
+	aliasCommand                 = "alias"
+	keywordCommand               = "keyword"
+	categoryRulesCommand         = "rules"
+	deleteCategoryRuleCommand    = "deleteRule"
+	subscribeCommand             = "subscribe"
+	unsubscribeCommand           = "unsubscribe"
+	subscriptionsCommand         = "subscriptions"
+	addRecurringCommand          = "addRecurring"
+	recurringCommand             = "recurring"
+	pauseRecurringCommand        = "pauseRecurring"
+	resumeRecurringCommand       = "resumeRecurring"
+	addIncomeCommand             = "addIncome"
+	addAccountCommand            = "addAccount"
+	transferCommand              = "transfer"
+	balancesCommand              = "balances"
+	createBudgetCommand          = "createBudget"
+	joinBudgetCommand            = "join"
+	budgetsCommand               = "budgets"
+	switchBudgetCommand          = "switchBudget"
+	budgetMembersCommand         = "members"
+	setBudgetRoleCommand         = "setRole"
+	leaveBudgetCommand           = "leaveBudget"
+	splitExpenseCommand          = "split"
+	settleDebtCommand            = "settle"
+	debtsCommand                 = "debts"
+	showExpenseCommand           = "expense"
+	exportCommand                = "export"
+	importCommand                = "import"
+	importTokenCommand           = "importToken"
+	trendCommand                 = "trend"
 )
 
+// errSkipMessage сообщение не требует ответа
+var errSkipMessage = errors.New("сообщение пропущено")
+
 var mainMenu = []string{
 	strings.Join([]string{"/", getExpensesCommand}, ""),
 	strings.Join([]string{"/", requestCurrencyChangeCommand}, ""),
@@ -49,6 +245,10 @@ var mainMenu = []string{
 
 type MessageSender interface {
 	SendMessage(text string, userID int64, buttons []string) error
+	SendInlineKeyboard(text string, userID int64, buttons []model.InlineButton) error
+	SendDocument(caption string, userID int64, file model.File) error
+	SendPhoto(caption string, userID int64, file model.File) error
+	DownloadFile(fileID string) ([]byte, error)
 }
 
 type Model struct {
@@ -56,27 +256,52 @@ type Model struct {
 	currencies           map[string]struct{}
 	expenseProcessor     expense_processor.ExpenseProcessor
 	reportRequester      reportrequester.ReportRequester
-	currency             string
+	settingsRepo         repository.UserSettingsRepository
+	subscriptionsRepo    repository.ReportSubscriptionsRepository
+	budgetsRepo          repository.BudgetsRepository
+	recurringExpenses    recurringexpenses.RecurringExpenses
+	expenseAttachments   expenseattachments.ExpenseAttachments
+	expenseImporter      expense_importer.ExpenseImporter
+	importTokensRepo     repository.ImportTokensRepository
+	dialogCache          cache.Cache
 	totalRequestsCounter *prometheus.CounterVec
 	responseTimeSummary  *prometheus.SummaryVec
 }
 
-func New(
-	tgClient MessageSender,
-	currencies map[string]struct{},
-	expenseProcessor expense_processor.ExpenseProcessor,
-	reportRequester reportrequester.ReportRequester,
-	totalRequestsCounter *prometheus.CounterVec,
-	responseTimeSummary *prometheus.SummaryVec,
-) *Model {
+// Deps зависимости обработчика сообщений, незаданные метрики не собираются
+type Deps struct {
+	TgClient             MessageSender
+	Currencies           map[string]struct{}
+	ExpenseProcessor     expense_processor.ExpenseProcessor
+	ReportRequester      reportrequester.ReportRequester
+	SettingsRepo         repository.UserSettingsRepository
+	SubscriptionsRepo    repository.ReportSubscriptionsRepository
+	BudgetsRepo          repository.BudgetsRepository
+	RecurringExpenses    recurringexpenses.RecurringExpenses
+	ExpenseAttachments   expenseattachments.ExpenseAttachments
+	ExpenseImporter      expense_importer.ExpenseImporter
+	ImportTokensRepo     repository.ImportTokensRepository
+	DialogCache          cache.Cache
+	TotalRequestsCounter *prometheus.CounterVec
+	ResponseTimeSummary  *prometheus.SummaryVec
+}
+
+func New(deps Deps) *Model {
 	return &Model{
-		tgClient:             tgClient,
-		currencies:           currencies,
-		currency:             serviceconverter.RUB,
-		expenseProcessor:     expenseProcessor,
-		reportRequester:      reportRequester,
-		totalRequestsCounter: totalRequestsCounter,
-		responseTimeSummary:  responseTimeSummary,
+		tgClient:             deps.TgClient,
+		currencies:           deps.Currencies,
+		expenseProcessor:     deps.ExpenseProcessor,
+		reportRequester:      deps.ReportRequester,
+		settingsRepo:         deps.SettingsRepo,
+		subscriptionsRepo:    deps.SubscriptionsRepo,
+		budgetsRepo:          deps.BudgetsRepo,
+		recurringExpenses:    deps.RecurringExpenses,
+		expenseAttachments:   deps.ExpenseAttachments,
+		expenseImporter:      deps.ExpenseImporter,
+		importTokensRepo:     deps.ImportTokensRepo,
+		dialogCache:          deps.DialogCache,
+		totalRequestsCounter: deps.TotalRequestsCounter,
+		responseTimeSummary:  deps.ResponseTimeSummary,
 	}
 }
 
@@ -84,7 +309,44 @@ type Message struct {
 	Command          string
 	CommandArguments string
 	Text             string
-	UserID           int64
+	UserID           int64  // автор сообщения
+	ChatID           int64  // чат для ответа: личный или групповой, 0 - личный чат UserID
+	UserName         string // имя пользователя в Telegram
+	BotName          string // имя бота для ссылок-приглашений
+	// BudgetID и BudgetRole заполняются по активному бюджету пользователя или групповому чату при обработке сообщения
+	BudgetID   int64
+	BudgetRole model.BudgetRole
+	// Attachment фото или документ, Text - подпись к нему
+	Attachment *Attachment
+}
+
+// Attachment файл сообщения, скачивается только при сохранении
+type Attachment struct {
+	FileID   string
+	FileName string
+	MimeType string
+}
+
+// Callback нажатие на кнопку под сообщением
+type Callback struct {
+	Data     string
+	UserID   int64  // нажавший на кнопку
+	ChatID   int64  // чат сообщения с кнопкой, 0 - личный чат UserID
+	UserName string // имя пользователя в Telegram
+}
+
+// replyChatID чат, в который отправляется ответ
+func replyChatID(chatID, userID int64) int64 {
+	if chatID == 0 {
+		return userID
+	}
+
+	return chatID
+}
+
+// isGroupChat сообщение пришло из группового чата, а не из личного чата с ботом
+func isGroupChat(chatID, userID int64) bool {
+	return replyChatID(chatID, userID) != userID
 }
 
 func (m *Model) IncomingMessage(ctx context.Context, msg Message) error {
@@ -112,19 +374,86 @@ func (m *Model) IncomingMessage(ctx context.Context, msg Message) error {
 	logger.Debug(
 		"получена команда",
 		logger.LogDataItem{Key: "userId", Value: msg.UserID},
+		logger.LogDataItem{Key: "chatId", Value: msg.ChatID},
 		logger.LogDataItem{Key: "command", Value: msg.Command},
 		logger.LogDataItem{Key: "arguments", Value: msg.CommandArguments},
 	)
 
+	response, btns, inlineBtns, err := m.handleMessage(ctx, msg)
+	if errors.Is(err, errSkipMessage) {
+		return nil
+	}
+
+	if err != nil {
+		response = err.Error()
+
+		logger.Error(response)
+	}
+
+	if len(inlineBtns) > 0 {
+		return m.tgClient.SendInlineKeyboard(response, replyChatID(msg.ChatID, msg.UserID), inlineBtns)
+	}
+
+	return m.tgClient.SendMessage(response, replyChatID(msg.ChatID, msg.UserID), btns)
+}
+
+// handleMessage определяет активный бюджет пользователя и обрабатывает сообщение в диалоге или как команду
+func (m *Model) handleMessage(ctx context.Context, msg Message) (string, []string, []model.InlineButton, error) {
+	// фото или документ с подписью в личном чате - трата с чеком: 350;Кафе
+	if msg.Attachment != nil && msg.Command == "" && !isGroupChat(msg.ChatID, msg.UserID) {
+		msg.Command, msg.CommandArguments = addExpenseCommand, msg.Text
+	}
+
+	member, err := m.resolveBudget(ctx, msg.ChatID, msg.UserID)
+	if err != nil {
+		return "", mainMenu, nil, err
+	}
+	msg.BudgetID, msg.BudgetRole = member.BudgetID, member.Role
+
+	if err = checkBudgetAccess(msg); err != nil {
+		return "", mainMenu, nil, err
+	}
+
+	if err = m.saveGroupChatMember(ctx, msg); err != nil {
+		return "", mainMenu, nil, err
+	}
+
+	// диалог и выбор категории кнопками потеряли бы файл
+	if msg.Attachment != nil && msg.Command == addExpenseCommand {
+		response, err := m.addReceiptExpense(ctx, msg)
+		return response, mainMenu, nil, err
+	}
+
+	response, btns, inlineBtns, handled, err := m.handleDialog(ctx, msg)
+	if !handled {
+		// в группе бот видит всю переписку, отвечает только на команды
+		if msg.Command == "" && isGroupChat(msg.ChatID, msg.UserID) {
+			return "", nil, nil, errSkipMessage
+		}
+
+		response, btns, inlineBtns, err = m.handleCommand(ctx, msg)
+	}
+
+	return response, btns, inlineBtns, err
+}
+
+// handleCommand выполняет команду и возвращает ответ с кнопками
+func (m *Model) handleCommand(ctx context.Context, msg Message) (string, []string, []model.InlineButton, error) {
 	response := "не знаю эту команду"
 	var err error
 	btns := mainMenu
+	var inlineBtns []model.InlineButton
 
 	switch msg.Command {
 	case startCommand:
+		// ссылка-приглашение t.me/bot?start=join_КОД
+		if strings.HasPrefix(msg.CommandArguments, joinLinkPrefix) {
+			response, err = m.joinBudget(ctx, msg, strings.TrimPrefix(msg.CommandArguments, joinLinkPrefix))
+			break
+		}
 		response = m.showInfo(ctx)
 	case addExpenseCommand:
-		response, err = m.addExpense(ctx, msg)
+		response, inlineBtns, err = m.addExpense(ctx, msg)
 	case getExpensesCommand:
 		response, err = m.getExpenses(ctx, msg)
 	case requestCurrencyChangeCommand:
@@ -133,36 +462,180 @@ func (m *Model) IncomingMessage(ctx context.Context, msg Message) error {
 		response, err = m.setCurrency(ctx, msg)
 	case setLimitCommand:
 		response, err = m.setLimit(ctx, msg)
+	case setLimitAlertsCommand:
+		response, err = m.setLimitAlerts(ctx, msg)
+	case listExpensesCommand:
+		response, err = m.listExpenses(ctx, msg)
+	case editExpenseCommand:
+		response, err = m.editExpense(ctx, msg)
+	case deleteExpenseCommand:
+		response, err = m.deleteExpense(ctx, msg)
+	case setPeriodModeCommand:
+		response, err = m.setPeriodMode(ctx, msg)
+	case setWeekStartCommand:
+		response, err = m.setWeekStart(ctx, msg)
+	case setTimezoneCommand:
+		response, err = m.setTimezone(ctx, msg)
+	case categoriesCommand:
+		response, err = m.listCategories(ctx, msg)
+	case renameCategoryCommand:
+		response, err = m.renameCategory(ctx, msg)
+	case mergeCategoriesCommand:
+		response, err = m.mergeCategories(ctx, msg)
+	case deleteCategoryCommand:
+		response, err = m.deleteCategory(ctx, msg)
+	case aliasCommand:
+		response, err = m.saveCategoryRule(ctx, msg, model.AliasCategoryRule)
+	case keywordCommand:
+		response, err = m.saveCategoryRule(ctx, msg, model.KeywordCategoryRule)
+	case categoryRulesCommand:
+		response, err = m.listCategoryRules(ctx, msg)
+	case deleteCategoryRuleCommand:
+		response, err = m.deleteCategoryRule(ctx, msg)
+	case subscribeCommand:
+		response, err = m.subscribe(ctx, msg)
+	case unsubscribeCommand:
+		response, err = m.unsubscribe(ctx, msg)
+	case subscriptionsCommand:
+		response, err = m.listSubscriptions(ctx, msg)
+	case addRecurringCommand:
+		response, err = m.addRecurringExpense(ctx, msg)
+	case recurringCommand:
+		response, err = m.listRecurringExpenses(ctx, msg)
+	case pauseRecurringCommand:
+		response, err = m.pauseRecurringExpense(ctx, msg)
+	case resumeRecurringCommand:
+		response, err = m.resumeRecurringExpense(ctx, msg)
+	case addIncomeCommand:
+		response, err = m.addIncome(ctx, msg)
+	case addAccountCommand:
+		response, err = m.addAccount(ctx, msg)
+	case transferCommand:
+		response, err = m.transfer(ctx, msg)
+	case balancesCommand:
+		response, err = m.balances(ctx, msg)
+	case createBudgetCommand:
+		response, err = m.createBudget(ctx, msg)
+	case joinBudgetCommand:
+		response, err = m.joinBudget(ctx, msg, msg.CommandArguments)
+	case budgetsCommand:
+		response, err = m.listBudgets(ctx, msg)
+	case switchBudgetCommand:
+		response, err = m.switchBudget(ctx, msg)
+	case budgetMembersCommand:
+		response, err = m.listBudgetMembers(ctx, msg)
+	case setBudgetRoleCommand:
+		response, err = m.setBudgetRole(ctx, msg)
+	case leaveBudgetCommand:
+		response, err = m.leaveBudget(ctx, msg)
+	case splitExpenseCommand:
+		response, err = m.splitExpense(ctx, msg)
+	case settleDebtCommand:
+		response, err = m.settleDebt(ctx, msg)
+	case debtsCommand:
+		response, err = m.listDebts(ctx, msg)
+	case showExpenseCommand:
+		response, err = m.showExpense(ctx, msg)
+	case exportCommand:
+		response, err = m.requestExport(ctx, msg)
+	case importCommand:
+		response, err = m.importExpenses(ctx, msg)
+	case importTokenCommand:
+		response, err = m.issueImportToken(ctx, msg)
+	case trendCommand:
+		response, err = m.requestTrend(ctx, msg)
 	}
 
-	if err != nil {
-		response = err.Error()
-
-		logger.Error(response)
-	}
-
-	return m.tgClient.SendMessage(response, msg.UserID, btns)
+	return response, btns, inlineBtns, err
 }
 
 func (m *Model) SendReport(ctx context.Context, report *expense_reporter.ExpenseReport) error {
-	span, _ := opentracing.StartSpanFromContext(ctx, "Messaging_SendReport")
+	span, ctx := opentracing.StartSpanFromContext(ctx, "Messaging_SendReport")
 	defer span.Finish()
 
+	settings, err := m.getUserSettings(ctx, report.UserID)
+	if err != nil {
+		return err
+	}
+
+	// диапазон приходит в UTC, заголовок показываем в часовом поясе пользователя
+	dateRange := report.Range.In(settings.Location())
+
 	var reporter strings.Builder
-	reporter.WriteString(
-		fmt.Sprintf("%s бюджет:\n", report.Period.String()),
-	)
+	switch {
+	case report.Period == model.Custom:
+		reporter.WriteString(fmt.Sprintf("Бюджет за %s:\n", dateRange.String()))
+	case !report.Range.IsEmpty():
+		reporter.WriteString(fmt.Sprintf("%s бюджет (%s):\n", report.Period.String(), dateRange.String()))
+	default:
+		reporter.WriteString(fmt.Sprintf("%s бюджет:\n", report.Period.String()))
+	}
+	if report.Account != "" {
+		reporter.WriteString(fmt.Sprintf("Счет %s\n", report.Account))
+	}
 	defer reporter.Reset()
 
 	if report.IsEmpty() {
 		reporter.WriteString("пусто\n")
 	}
 
-	for category, amount := range report.Rows {
-		if _, err := reporter.WriteString(fmt.Sprintf("%s - %.02f %s\n", category, amount, m.currency)); err != nil {
+	// изменение расходов показываем, только если в предыдущем периоде были траты
+	compare := !report.PreviousRange.IsEmpty() && report.PreviousTotalExpenses > 0
+
+	rows := markReportRows(report.Rows)
+	for _, row := range rows {
+		if _, err := reporter.WriteString(formatReportRow(row, settings.Currency, compare)); err != nil {
+			return err
+		}
+	}
+
+	if len(rows) > 0 {
+		reporter.WriteString(fmt.Sprintf("Итого: %.02f %s", report.TotalExpenses, settings.Currency))
+		if compare {
+			reporter.WriteString(fmt.Sprintf(
+				" (%+.02f)\nВ скобках - изменение к %s\n",
+				report.TotalExpenses-report.PreviousTotalExpenses,
+				report.PreviousRange.In(settings.Location()).String(),
+			))
+		} else {
+			reporter.WriteString("\n")
+		}
+	}
+
+	if !report.IsEmpty() {
+		reporter.WriteString(fmt.Sprintf(
+			"\nДоходы: %.02f %s\nРасходы: %.02f %s\nБаланс: %+.02f %s\n",
+			report.TotalIncome, settings.Currency,
+			report.TotalExpenses, settings.Currency,
+			report.Balance, settings.Currency,
+		))
+	}
+
+	if len(report.Members) > 0 {
+		breakdown, err := m.formatMembersBreakdown(ctx, report, settings.Currency)
+		if err != nil {
 			return err
 		}
+		reporter.WriteString(breakdown)
+	}
+
+	chatID := replyChatID(report.ChatID, report.UserID)
+	if len(rows) == 0 || report.TotalExpenses <= 0 {
+		return m.tgClient.SendMessage(reporter.String(), chatID, mainMenu)
+	}
+
+	return m.sendChart(reporter.String(), chatID, reportChart(report, rows, settings.Location()))
+}
+
+// formatReportRow возвращает строку категории: сумма, доля в расходах, изменение к предыдущему периоду и расход лимита
+func formatReportRow(row reportRow, currency string, compare bool) string {
+	line := fmt.Sprintf("%s %s - %.02f %s, %.1f%%", row.Mark, row.Category, row.Amount, currency, row.Share)
+	if compare {
+		line += fmt.Sprintf(" (%+.02f)", row.Amount-row.Previous)
+	}
+	if row.Limit > 0 {
+		line += fmt.Sprintf(", лимит %.0f%% из %.02f", row.LimitUsage, row.Limit)
 	}
 
-	return m.tgClient.SendMessage(reporter.String(), report.UserID, mainMenu)
+	return line + "\n"
 }
@@ -2,6 +2,8 @@ package servicemessages
 
 import (
 	"context"
+	"fmt"
+	"regexp"
 	"testing"
 	"time"
 
@@ -9,8 +11,19 @@ import (
 	"github.com/opentracing/opentracing-go"
 	"github.com/stretchr/testify/assert"
 	"gitlab.ozon.dev/cranky4/tg-bot/internal/model"
+	memoryrepo "gitlab.ozon.dev/cranky4/tg-bot/internal/repository/memory"
+	repomocks "gitlab.ozon.dev/cranky4/tg-bot/internal/repository/mocks"
+	servicecache "gitlab.ozon.dev/cranky4/tg-bot/internal/service/cache"
+	cachememory "gitlab.ozon.dev/cranky4/tg-bot/internal/service/cache/memory"
+	exp_attachments_mock "gitlab.ozon.dev/cranky4/tg-bot/internal/service/expense_attachments/mocks"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/service/expense_exporter"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/service/expense_importer"
+	exp_importer_mock "gitlab.ozon.dev/cranky4/tg-bot/internal/service/expense_importer/mocks"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/service/expense_processor"
 	exp_processor_mock "gitlab.ozon.dev/cranky4/tg-bot/internal/service/expense_processor/mocks"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/service/expense_reporter"
 	msgmocks "gitlab.ozon.dev/cranky4/tg-bot/internal/service/messages/mocks"
+	recurring_expenses_mock "gitlab.ozon.dev/cranky4/tg-bot/internal/service/recurring_expenses/mocks"
 	report_requester_mock "gitlab.ozon.dev/cranky4/tg-bot/internal/service/report_requester/mocks"
 )
 
@@ -21,25 +34,119 @@ var currencies = map[string]struct{}{
 	"CNY": {},
 }
 
+// newTestModel создает обработчик сообщений, незаданные валюты, бюджеты и кеш диалогов берутся общие для тестов
+func newTestModel(deps Deps) *Model {
+	if deps.Currencies == nil {
+		deps.Currencies = currencies
+	}
+	if deps.BudgetsRepo == nil {
+		deps.BudgetsRepo = memoryrepo.NewBudgetsRepository()
+	}
+	if deps.DialogCache == nil {
+		deps.DialogCache = cachememory.NewLRUCache(10)
+	}
+
+	return New(deps)
+}
+
 func TestOnStartCommandShouldAnswerWithIntroMessage(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	sender := msgmocks.NewMockMessageSender(ctrl)
 	processor := exp_processor_mock.NewMockExpenseProcessor(ctrl)
 	reportRequester := report_requester_mock.NewMockReportRequester(ctrl)
-	model := New(sender, currencies, processor, reportRequester, nil, nil)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+	model := newTestModel(Deps{
+		TgClient:         sender,
+		ExpenseProcessor: processor,
+		ReportRequester:  reportRequester,
+		SettingsRepo:     settingsRepo,
+	})
 	ctx := context.Background()
 	userId := int64(100)
 
 	msg := "Привет, я буду считать твои деньги. Вот что я умею:\n" +
-		"addExpense- добавить трату\n" +
-		"Пример: /addExpense 10;Дом;2022-10-04 10:00:00\n" +
-		"getExpenses - получить список трат за неделю, месяц и год\n" +
-		"Пример: /getExpenses week\n" +
+		"addExpense- добавить трату. Дата необязательна: полная, только дата, сегодня или вчера. Сумма может быть с валютой. Если указать только сумму, бот предложит выбрать категорию\n" +
+		"Пример: /addExpense 10;Дом;2022-10-04 10:00:00, /addExpense 350 кофе вчера, /addExpense 12,50 USD;Книги, /addExpense 350\n" +
+		"getExpenses - получить список трат за неделю, месяц, год или диапазон дат\n" +
+		"Пример: /getExpenses week, /getExpenses previous month, /getExpenses 2022-09-01..2022-09-30\n" +
+		"trend - динамика расходов за период, как в getExpenses, по дням, неделям или месяцам. ;categories - с разбивкой по категориям\n" +
+		"Пример: /trend month day, /trend year month;categories\n" +
+		"export - выгрузить траты в файл csv или xlsx за период, как в getExpenses\n" +
+		"Пример: /export csv month, /export xlsx 2022-01-01..2022-12-31\n" +
+		"import - загрузить траты из csv: документ с подписью-командой. dry - только проверить файл. Колонки по-умолчанию как в export, " +
+		"дубли по дате, сумме и категории пропускаются\nПример: /import, /import dry date=Дата операции;amount=Сумма;category=Описание\n" +
+		"importToken - получить токен для импорта трат по HTTP, прежний токен перестает действовать\n" +
 		"requestCurrencyChange - вызвать менюсмены валюты\n" +
 		"setCurrency - установить валюту ввода и отображения отчетов.\n" +
 		"Пример: /setCurrency EUR\n" +
-		"setLimit - установить лимит трат на категорию.\n" +
-		"Пример: /setLimit Ремонт 1200.50\n"
+		"setLimit - установить лимит трат на категорию или общий лимит (*) на неделю, месяц, квартал или год. По-умолчанию на месяц\n" +
+		"Пример: /setLimit Ремонт;1200.50, /setLimit Дом;12000;week, /setLimit *;50000;quarter\n" +
+		"setLimitAlerts - пороги уведомлений о расходе лимита в процентах, по-умолчанию 50,80,100. off - отключить\n" +
+		"Пример: /setLimitAlerts Дом;80,100, /setLimitAlerts *;90;week\n" +
+		"listExpenses - список трат с их ИД за неделю, месяц или год\n" +
+		"Пример: /listExpenses month\n" +
+		"editExpense - изменить трату\n" +
+		"Пример: /editExpense ИД 10;Дом;2022-10-04 10:00:00\n" +
+		"deleteExpense - удалить трату\n" +
+		"Пример: /deleteExpense ИД\n" +
+		"expense - получить чек траты. Чек - фото или документ с подписью-тратой: 350;Кафе\n" +
+		"Пример: /expense ИД\n" +
+		"setPeriodMode - режим периодов отчетов и лимитов: rolling (неделя, месяц, год назад) или calendar (календарные)\n" +
+		"Пример: /setPeriodMode calendar\n" +
+		"setWeekStart - день начала календарной недели\n" +
+		"Пример: /setWeekStart monday\n" +
+		"setTimezone - часовой пояс для дат трат, периодов и отчетов\n" +
+		"Пример: /setTimezone Europe/Moscow\n" +
+		"cancel - отменить пошаговый ввод. Команды addExpense и setLimit без аргументов спрашивают аргументы по очереди\n" +
+		"categories - список ваших категорий\n" +
+		"renameCategory - переименовать категорию\n" +
+		"Пример: /renameCategory Еда;Продукты\n" +
+		"mergeCategories - перенести траты и лимит категории в другую и удалить ее\n" +
+		"Пример: /mergeCategories Еда;Продукты\n" +
+		"deleteCategory - удалить категорию без трат\n" +
+		"Пример: /deleteCategory Еда\n" +
+		"alias - синоним категории: трата с таким названием попадет в указанную категорию\n" +
+		"Пример: /alias еда => Продукты\n" +
+		"keyword - ключевое слово: трата, в описании которой оно встречается, попадет в указанную категорию\n" +
+		"Пример: /keyword старбакс => Кофе\n" +
+		"rules - список синонимов и ключевых слов\n" +
+		"deleteRule - удалить синоним или ключевое слово\n" +
+		"Пример: /deleteRule еда\n" +
+		"subscribe - получать отчет по расписанию в вашем часовом поясе: weekly день недели или monthly день месяца\n" +
+		"Пример: /subscribe weekly monday 09:00, /subscribe monthly 1 09:00\n" +
+		"subscriptions - список подписок на отчеты\n" +
+		"unsubscribe - отменить подписку на отчет\n" +
+		"Пример: /unsubscribe weekly\n" +
+		"addRecurring - регулярная трата, которая добавляется сама по расписанию. Пропущенные траты добавятся позже\n" +
+		"Пример: /addRecurring 25000;Аренда;monthly 5, /addRecurring 599;Подписки;weekly friday 10:00\n" +
+		"recurring - список регулярных трат с их ИД\n" +
+		"pauseRecurring - приостановить регулярную трату\n" +
+		"Пример: /pauseRecurring ИД\n" +
+		"resumeRecurring - возобновить регулярную трату\n" +
+		"Пример: /resumeRecurring ИД\n" +
+		"addIncome - добавить доход. Доходы, расходы и баланс показываются в отчетах\n" +
+		"Пример: /addIncome 150000;Зарплата;2022-10-05, /addIncome 500 USD;Фриланс\n" +
+		"addAccount - добавить счет: карта, наличные, накопления. Трата со счета: /addExpense 350;Кафе;;Карта\n" +
+		"Пример: /addAccount Карта;RUB;15000, /addAccount Наличные\n" +
+		"transfer - перевести деньги между счетами\n" +
+		"Пример: /transfer 5000;Карта;Накопления\n" +
+		"balances - остатки на счетах. Отчет по счету: /getExpenses month;Карта\n" +
+		"createBudget - создать общий бюджет и получить код приглашения. Траты, лимиты и категории общего бюджета видят все участники\n" +
+		"Пример: /createBudget Семья\n" +
+		"join - вступить в общий бюджет по коду приглашения\n" +
+		"Пример: /join ABCD1234\n" +
+		"budgets - список ваших бюджетов\n" +
+		"switchBudget - выбрать активный бюджет\n" +
+		"Пример: /switchBudget ИД, /switchBudget personal\n" +
+		"members - участники активного бюджета\n" +
+		"setRole - назначить роль участнику: owner, editor или viewer (только отчеты)\n" +
+		"Пример: /setRole alice;viewer\n" +
+		"leaveBudget - выйти из активного бюджета\n" +
+		"split - разделить трату, которую оплатили вы, поровну между вами и участниками бюджета или группы. Дата необязательна\n" +
+		"Пример: /split 3000;Ресторан;@alice,@bob\n" +
+		"debts - кто кому сколько должен, минимальным числом переводов\n" +
+		"settle - записать возврат долга участнику. Дата необязательна\n" +
+		"Пример: /settle @alice;1000\n"
 
 	sender.EXPECT().SendMessage(msg, userId, mainMenu)
 
@@ -56,10 +163,16 @@ func TestOnUnknownCommandShouldAnswerWithHelpMessage(t *testing.T) {
 	ctx := context.Background()
 	processor := exp_processor_mock.NewMockExpenseProcessor(ctrl)
 	reportRequester := report_requester_mock.NewMockReportRequester(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
 
 	sender := msgmocks.NewMockMessageSender(ctrl)
 	sender.EXPECT().SendMessage("не знаю эту команду", int64(123), mainMenu)
-	model := New(sender, currencies, processor, reportRequester, nil, nil)
+	model := newTestModel(Deps{
+		TgClient:         sender,
+		ExpenseProcessor: processor,
+		ReportRequester:  reportRequester,
+		SettingsRepo:     settingsRepo,
+	})
 
 	err := model.IncomingMessage(ctx, Message{
 		Text:   "some text",
@@ -79,17 +192,26 @@ func TestOnAddExpenseShouldAnswerWithSuccessMessage(t *testing.T) {
 	_, wrapedCtx := opentracing.StartSpanFromContext(ctx, "wrap1")
 	_, wrapedCtx = opentracing.StartSpanFromContext(wrapedCtx, "wrap2")
 
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+	settingsRepo.EXPECT().GetSettings(wrapedCtx, userId).Return(model.UserSettings{}, false, nil)
+
 	sender := msgmocks.NewMockMessageSender(ctrl)
 	sender.EXPECT().SendMessage("Трата 125.50 RUB добавлена в категорию Кофе с датой 2022-10-01 12:56:00",
 		userId, mainMenu)
 
-	date, err := time.Parse("2006-01-02 15:04:05", "2022-10-01 12:56:00")
+	date, err := time.ParseInLocation("2006-01-02 15:04:05", "2022-10-01 12:56:00", time.Local)
 	assert.NoError(t, err)
 
-	processor.EXPECT().AddExpense(wrapedCtx, 125.5, "RUB", "Кофе", date, userId)
-	processor.EXPECT().GetFreeLimit(wrapedCtx, "Кофе", "RUB", userId)
+	processor.EXPECT().AddExpense(wrapedCtx, 125.5, "RUB", "Кофе", "", date, userId, userId).
+		Return(&model.Expense{Category: "Кофе"}, nil)
+	processor.EXPECT().GetFreeLimits(wrapedCtx, "Кофе", userId, gomock.Any(), gomock.Any())
 
-	model := New(sender, currencies, processor, reportRequester, nil, nil)
+	model := newTestModel(Deps{
+		TgClient:         sender,
+		ExpenseProcessor: processor,
+		ReportRequester:  reportRequester,
+		SettingsRepo:     settingsRepo,
+	})
 
 	err = model.IncomingMessage(ctx, Message{
 		Command:          addExpenseCommand,
@@ -113,15 +235,25 @@ func TestOnAddExpenseWithLimitSetShouldAnswerWithSuccessMessage(t *testing.T) {
 		"Свободный месячный лимит 10.00 RUB",
 		userId, mainMenu)
 
-	date, err := time.Parse("2006-01-02 15:04:05", "2022-10-01 12:56:00")
+	date, err := time.ParseInLocation("2006-01-02 15:04:05", "2022-10-01 12:56:00", time.Local)
 	assert.NoError(t, err)
 
 	processor := exp_processor_mock.NewMockExpenseProcessor(ctrl)
-	processor.EXPECT().AddExpense(wrapedCtx, 125.50, "RUB", "Кофе", date, userId)
-	processor.EXPECT().GetFreeLimit(wrapedCtx, "Кофе", "RUB", userId).Return(10.00, true, nil)
+	processor.EXPECT().AddExpense(wrapedCtx, 125.50, "RUB", "Кофе", "", date, userId, userId).
+		Return(&model.Expense{Category: "Кофе"}, nil)
+	processor.EXPECT().GetFreeLimits(wrapedCtx, "Кофе", userId, gomock.Any(), gomock.Any()).Return([]expense_processor.FreeLimit{
+		{Scope: model.CategoryLimitScope, Period: model.Month, Category: "Кофе", Free: 10.00},
+	}, nil)
 
 	reportRequester := report_requester_mock.NewMockReportRequester(ctrl)
-	model := New(sender, currencies, processor, reportRequester, nil, nil)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+	settingsRepo.EXPECT().GetSettings(wrapedCtx, userId).Return(model.UserSettings{}, false, nil)
+	model := newTestModel(Deps{
+		TgClient:         sender,
+		ExpenseProcessor: processor,
+		ReportRequester:  reportRequester,
+		SettingsRepo:     settingsRepo,
+	})
 
 	err = model.IncomingMessage(ctx, Message{
 		Command:          addExpenseCommand,
@@ -142,18 +274,30 @@ func TestOnAddExpenseWithLimitReachedShouldAnswerWithSuccessMessage(t *testing.T
 
 	sender := msgmocks.NewMockMessageSender(ctrl)
 	sender.EXPECT().SendMessage("Трата 125.50 RUB добавлена в категорию Кофе с датой 2022-10-01 12:56:00.\n"+
-		"Достигнут месячный лимит (-12.00 RUB)",
+		"Достигнут месячный лимит (-12.00 RUB).\n"+
+		"Свободный общий недельный лимит 100.00 RUB",
 		userId, mainMenu)
 
-	date, err := time.Parse("2006-01-02 15:04:05", "2022-10-01 12:56:00")
+	date, err := time.ParseInLocation("2006-01-02 15:04:05", "2022-10-01 12:56:00", time.Local)
 	assert.NoError(t, err)
 
 	processor := exp_processor_mock.NewMockExpenseProcessor(ctrl)
-	processor.EXPECT().AddExpense(wrapedCtx, 125.50, "RUB", "Кофе", date, userId)
-	processor.EXPECT().GetFreeLimit(wrapedCtx, "Кофе", "RUB", userId).Return(-12.00, true, nil)
+	processor.EXPECT().AddExpense(wrapedCtx, 125.50, "RUB", "Кофе", "", date, userId, userId).
+		Return(&model.Expense{Category: "Кофе"}, nil)
+	processor.EXPECT().GetFreeLimits(wrapedCtx, "Кофе", userId, gomock.Any(), gomock.Any()).Return([]expense_processor.FreeLimit{
+		{Scope: model.CategoryLimitScope, Period: model.Month, Category: "Кофе", Free: -12.00},
+		{Scope: model.TotalLimitScope, Period: model.Week, Free: 100.00},
+	}, nil)
 
 	reportRequester := report_requester_mock.NewMockReportRequester(ctrl)
-	model := New(sender, currencies, processor, reportRequester, nil, nil)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+	settingsRepo.EXPECT().GetSettings(wrapedCtx, userId).Return(model.UserSettings{}, false, nil)
+	model := newTestModel(Deps{
+		TgClient:         sender,
+		ExpenseProcessor: processor,
+		ReportRequester:  reportRequester,
+		SettingsRepo:     settingsRepo,
+	})
 
 	err = model.IncomingMessage(ctx, Message{
 		Command:          addExpenseCommand,
@@ -171,16 +315,24 @@ func TestOnAddExpenseShouldAnswerWithFailMessage(t *testing.T) {
 
 	sender := msgmocks.NewMockMessageSender(ctrl)
 	sender.EXPECT().SendMessage("неверное количество параметров.\n"+
-		"Ожидается: Сумма;Категория;Дата \n"+
-		"Например: 120.50;Дом;2022-10-01 13:25:23", userId, mainMenu)
+		"Ожидается: Сумма;Категория;Дата;Счет или Сумма Категория \n"+
+		"Например: 120.50;Дом;2022-10-01 13:25:23, 350 кофе вчера", userId, mainMenu)
 
 	processor := exp_processor_mock.NewMockExpenseProcessor(ctrl)
 	reportRequester := report_requester_mock.NewMockReportRequester(ctrl)
-	model := New(sender, currencies, processor, reportRequester, nil, nil)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+	settingsRepo.EXPECT().GetSettings(gomock.Any(), userId).Return(model.UserSettings{}, false, nil)
+	model := newTestModel(Deps{
+		TgClient:         sender,
+		ExpenseProcessor: processor,
+		ReportRequester:  reportRequester,
+		SettingsRepo:     settingsRepo,
+	})
 
 	err := model.IncomingMessage(ctx, Message{
-		Command: addExpenseCommand,
-		UserID:  userId,
+		Command:          addExpenseCommand,
+		CommandArguments: "10;Дом;2022-10-01;Карта;лишнее",
+		UserID:           userId,
 	})
 
 	assert.NoError(t, err)
@@ -198,10 +350,17 @@ func TestOnGetWeekExpenseShouldAnswerWithEmptyMessage(t *testing.T) {
 	sender.EXPECT().SendMessage("Запрос на формирование отчета отправлен", userId, mainMenu)
 
 	reportRequester := report_requester_mock.NewMockReportRequester(ctrl)
-	reportRequester.EXPECT().SendRequestReport(wrapedCtx, userId, model.Week, "RUB")
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+	settingsRepo.EXPECT().GetSettings(wrapedCtx, userId).Return(model.UserSettings{}, false, nil)
+	reportRequester.EXPECT().SendRequestReport(wrapedCtx, userId, userId, userId, model.Week, gomock.Any(), "RUB", "")
 
 	processor := exp_processor_mock.NewMockExpenseProcessor(ctrl)
-	model := New(sender, currencies, processor, reportRequester, nil, nil)
+	model := newTestModel(Deps{
+		TgClient:         sender,
+		ExpenseProcessor: processor,
+		ReportRequester:  reportRequester,
+		SettingsRepo:     settingsRepo,
+	})
 
 	err := model.IncomingMessage(ctx, Message{
 		Command: getExpensesCommand,
@@ -225,9 +384,16 @@ func TestOnGetMonthExpenseShouldAnswerWithEmptyMessage(t *testing.T) {
 	processor := exp_processor_mock.NewMockExpenseProcessor(ctrl)
 
 	reportRequester := report_requester_mock.NewMockReportRequester(ctrl)
-	reportRequester.EXPECT().SendRequestReport(wrapedCtx, userId, model.Month, "RUB")
-
-	model := New(sender, currencies, processor, reportRequester, nil, nil)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+	settingsRepo.EXPECT().GetSettings(wrapedCtx, userId).Return(model.UserSettings{}, false, nil)
+	reportRequester.EXPECT().SendRequestReport(wrapedCtx, userId, userId, userId, model.Month, gomock.Any(), "RUB", "")
+
+	model := newTestModel(Deps{
+		TgClient:         sender,
+		ExpenseProcessor: processor,
+		ReportRequester:  reportRequester,
+		SettingsRepo:     settingsRepo,
+	})
 
 	err := model.IncomingMessage(ctx, Message{
 		Command:          getExpensesCommand,
@@ -252,9 +418,16 @@ func TestOnGetYearExpenseShouldAnswerWithEmptyMessage(t *testing.T) {
 	processor := exp_processor_mock.NewMockExpenseProcessor(ctrl)
 
 	reportRequester := report_requester_mock.NewMockReportRequester(ctrl)
-	reportRequester.EXPECT().SendRequestReport(wrapedCtx, userId, model.Year, "RUB")
-
-	model := New(sender, currencies, processor, reportRequester, nil, nil)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+	settingsRepo.EXPECT().GetSettings(wrapedCtx, userId).Return(model.UserSettings{}, false, nil)
+	reportRequester.EXPECT().SendRequestReport(wrapedCtx, userId, userId, userId, model.Year, gomock.Any(), "RUB", "")
+
+	model := newTestModel(Deps{
+		TgClient:         sender,
+		ExpenseProcessor: processor,
+		ReportRequester:  reportRequester,
+		SettingsRepo:     settingsRepo,
+	})
 
 	err := model.IncomingMessage(ctx, Message{
 		Command:          getExpensesCommand,
@@ -270,11 +443,20 @@ func TestOnGetExpenseShouldAnswerWithFailMessage(t *testing.T) {
 	ctx := context.Background()
 
 	sender := msgmocks.NewMockMessageSender(ctrl)
-	sender.EXPECT().SendMessage("неверный период. Ожидается: year, month, week. По-умолчанию week", int64(123), mainMenu)
+	sender.EXPECT().SendMessage("неверный период. Ожидается: year, month, week, диапазон дат 2022-09-01..2022-09-30 "+
+		"или today, yesterday, this week, previous week, this month, previous month, this year, previous year. По-умолчанию week",
+		int64(123), mainMenu)
 	processor := exp_processor_mock.NewMockExpenseProcessor(ctrl)
 	reportRequester := report_requester_mock.NewMockReportRequester(ctrl)
-
-	model := New(sender, currencies, processor, reportRequester, nil, nil)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+	settingsRepo.EXPECT().GetSettings(gomock.Any(), int64(123)).Return(model.UserSettings{}, false, nil)
+
+	model := newTestModel(Deps{
+		TgClient:         sender,
+		ExpenseProcessor: processor,
+		ReportRequester:  reportRequester,
+		SettingsRepo:     settingsRepo,
+	})
 
 	err := model.IncomingMessage(ctx, Message{
 		Command:          getExpensesCommand,
@@ -297,8 +479,14 @@ func TestOnRequestCurrencyChangeShouldAnswerWithSuccessMessage(t *testing.T) {
 	)
 	processor := exp_processor_mock.NewMockExpenseProcessor(ctrl)
 	reportRequester := report_requester_mock.NewMockReportRequester(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
 
-	model := New(sender, currencies, processor, reportRequester, nil, nil)
+	model := newTestModel(Deps{
+		TgClient:         sender,
+		ExpenseProcessor: processor,
+		ReportRequester:  reportRequester,
+		SettingsRepo:     settingsRepo,
+	})
 
 	err := model.IncomingMessage(ctx, Message{
 		Command:          requestCurrencyChangeCommand,
@@ -321,8 +509,21 @@ func TestOnSetCurrenctShouldAnswerWithSuccessMessage(t *testing.T) {
 	)
 	processor := exp_processor_mock.NewMockExpenseProcessor(ctrl)
 	reportRequester := report_requester_mock.NewMockReportRequester(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+	settingsRepo.EXPECT().GetSettings(gomock.Any(), int64(123)).Return(model.UserSettings{}, false, nil)
+	settingsRepo.EXPECT().SaveSettings(gomock.Any(), model.UserSettings{
+		UserID:     123,
+		Currency:   "USD",
+		PeriodMode: model.RollingPeriodMode,
+		WeekStart:  time.Monday,
+	})
 
-	model := New(sender, currencies, processor, reportRequester, nil, nil)
+	model := newTestModel(Deps{
+		TgClient:         sender,
+		ExpenseProcessor: processor,
+		ReportRequester:  reportRequester,
+		SettingsRepo:     settingsRepo,
+	})
 
 	err := model.IncomingMessage(ctx, Message{
 		Command:          setCurrencyCommand,
@@ -343,8 +544,14 @@ func TestOnSetCurrenctShouldAnswerWithFailMessage(t *testing.T) {
 
 	processor := exp_processor_mock.NewMockExpenseProcessor(ctrl)
 	reportRequester := report_requester_mock.NewMockReportRequester(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
 
-	model := New(sender, currencies, processor, reportRequester, nil, nil)
+	model := newTestModel(Deps{
+		TgClient:         sender,
+		ExpenseProcessor: processor,
+		ReportRequester:  reportRequester,
+		SettingsRepo:     settingsRepo,
+	})
 
 	err := model.IncomingMessage(ctx, Message{
 		Command:          setCurrencyCommand,
@@ -361,12 +568,18 @@ func TestOnSetLimitShouldAnswerWithFailMessage(t *testing.T) {
 	userId := int64(100)
 
 	sender := msgmocks.NewMockMessageSender(ctrl)
-	sender.EXPECT().SendMessage("неверное количество параметров.\nОжидается: Категория;Сумма \nНапример: Дом;12000.50", userId, mainMenu)
+	sender.EXPECT().SendMessage(errSetLimitInvalidParameterMessage, userId, mainMenu)
 
 	processor := exp_processor_mock.NewMockExpenseProcessor(ctrl)
 	reportRequester := report_requester_mock.NewMockReportRequester(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
 
-	model := New(sender, currencies, processor, reportRequester, nil, nil)
+	model := newTestModel(Deps{
+		TgClient:         sender,
+		ExpenseProcessor: processor,
+		ReportRequester:  reportRequester,
+		SettingsRepo:     settingsRepo,
+	})
 
 	err := model.IncomingMessage(ctx, Message{
 		Command:          setLimitCommand,
@@ -377,6 +590,64 @@ func TestOnSetLimitShouldAnswerWithFailMessage(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestOnSetLimitWithUnknownPeriodShouldAnswerWithFailMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	userId := int64(100)
+
+	sender := msgmocks.NewMockMessageSender(ctrl)
+	sender.EXPECT().SendMessage("неизвестный период лимита day. Ожидается: week, month, quarter, year", userId, mainMenu)
+
+	processor := exp_processor_mock.NewMockExpenseProcessor(ctrl)
+	reportRequester := report_requester_mock.NewMockReportRequester(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+
+	model := newTestModel(Deps{
+		TgClient:         sender,
+		ExpenseProcessor: processor,
+		ReportRequester:  reportRequester,
+		SettingsRepo:     settingsRepo,
+	})
+
+	err := model.IncomingMessage(ctx, Message{
+		Command:          setLimitCommand,
+		CommandArguments: "Дом;12000;day",
+		UserID:           userId,
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestOnSetTotalLimitShouldAnswerWithSuccessMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	userId := int64(100)
+
+	sender := msgmocks.NewMockMessageSender(ctrl)
+	sender.EXPECT().SendMessage("Установлен общий квартальный лимит 50000.00 RUB", userId, mainMenu)
+
+	processor := exp_processor_mock.NewMockExpenseProcessor(ctrl)
+	processor.EXPECT().SetLimit(gomock.Any(), "", model.Quarter, userId, 50000.0, "RUB").Return(50000.0, nil)
+	reportRequester := report_requester_mock.NewMockReportRequester(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+	settingsRepo.EXPECT().GetSettings(gomock.Any(), userId).Return(model.UserSettings{}, false, nil)
+
+	model := newTestModel(Deps{
+		TgClient:         sender,
+		ExpenseProcessor: processor,
+		ReportRequester:  reportRequester,
+		SettingsRepo:     settingsRepo,
+	})
+
+	err := model.IncomingMessage(ctx, Message{
+		Command:          setLimitCommand,
+		CommandArguments: "*; 50000; Quarter",
+		UserID:           userId,
+	})
+
+	assert.NoError(t, err)
+}
+
 func TestOnSetLimitShouldAnswerWithSuccessMessage(t *testing.T) {
 	ctx := context.Background()
 	_, wrapedCtx := opentracing.StartSpanFromContext(ctx, "wrap1")
@@ -390,10 +661,17 @@ func TestOnSetLimitShouldAnswerWithSuccessMessage(t *testing.T) {
 
 	processor := exp_processor_mock.NewMockExpenseProcessor(ctrl)
 	reportRequester := report_requester_mock.NewMockReportRequester(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+	settingsRepo.EXPECT().GetSettings(wrapedCtx, userId).Return(model.UserSettings{}, false, nil)
 
-	processor.EXPECT().SetLimit(wrapedCtx, "Дом", userId, 12500.50, "RUB").Return(12500.50, nil)
+	processor.EXPECT().SetLimit(wrapedCtx, "Дом", model.Month, userId, 12500.50, "RUB").Return(12500.50, nil)
 
-	model := New(sender, currencies, processor, reportRequester, nil, nil)
+	model := newTestModel(Deps{
+		TgClient:         sender,
+		ExpenseProcessor: processor,
+		ReportRequester:  reportRequester,
+		SettingsRepo:     settingsRepo,
+	})
 
 	err := model.IncomingMessage(ctx, Message{
 		Command:          setLimitCommand,
@@ -403,3 +681,2649 @@ func TestOnSetLimitShouldAnswerWithSuccessMessage(t *testing.T) {
 
 	assert.NoError(t, err)
 }
+
+func TestOnAddExpenseShouldUseUserCurrency(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	userId := int64(100)
+
+	ctx := context.Background()
+	_, wrapedCtx := opentracing.StartSpanFromContext(ctx, "wrap1")
+	_, wrapedCtx = opentracing.StartSpanFromContext(wrapedCtx, "wrap2")
+
+	sender := msgmocks.NewMockMessageSender(ctrl)
+	sender.EXPECT().SendMessage("Трата 125.50 USD добавлена в категорию Кофе с датой 2022-10-01 12:56:00",
+		userId, mainMenu)
+
+	date, err := time.ParseInLocation("2006-01-02 15:04:05", "2022-10-01 12:56:00", time.Local)
+	assert.NoError(t, err)
+
+	processor := exp_processor_mock.NewMockExpenseProcessor(ctrl)
+	processor.EXPECT().AddExpense(wrapedCtx, 125.5, "USD", "Кофе", "", date, userId, userId).
+		Return(&model.Expense{Category: "Кофе"}, nil)
+	processor.EXPECT().GetFreeLimits(wrapedCtx, "Кофе", userId, gomock.Any(), gomock.Any())
+
+	reportRequester := report_requester_mock.NewMockReportRequester(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+	settingsRepo.EXPECT().GetSettings(wrapedCtx, userId).Return(model.UserSettings{UserID: userId, Currency: "USD"}, true, nil)
+
+	model := newTestModel(Deps{
+		TgClient:         sender,
+		ExpenseProcessor: processor,
+		ReportRequester:  reportRequester,
+		SettingsRepo:     settingsRepo,
+	})
+
+	err = model.IncomingMessage(ctx, Message{
+		Command:          addExpenseCommand,
+		CommandArguments: "125.50; Кофе; 2022-10-01 12:56:00",
+		UserID:           userId,
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestOnEditExpenseShouldAnswerWithSuccessMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	userId := int64(100)
+	id := "1b4e28ba-2fa1-11d2-883f-0016d3cca427"
+
+	ctx := context.Background()
+	_, wrapedCtx := opentracing.StartSpanFromContext(ctx, "wrap1")
+	_, wrapedCtx = opentracing.StartSpanFromContext(wrapedCtx, "wrap2")
+
+	sender := msgmocks.NewMockMessageSender(ctrl)
+	sender.EXPECT().SendMessage("Трата "+id+" изменена: 125.50 RUB в категории Кофе с датой 2022-10-01 12:56:00",
+		userId, mainMenu)
+
+	date, err := time.ParseInLocation("2006-01-02 15:04:05", "2022-10-01 12:56:00", time.Local)
+	assert.NoError(t, err)
+
+	processor := exp_processor_mock.NewMockExpenseProcessor(ctrl)
+	processor.EXPECT().UpdateExpense(wrapedCtx, id, 125.5, "RUB", "Кофе", date, userId).Return(nil, true, nil)
+
+	reportRequester := report_requester_mock.NewMockReportRequester(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+	settingsRepo.EXPECT().GetSettings(wrapedCtx, userId).Return(model.UserSettings{}, false, nil)
+
+	model := newTestModel(Deps{
+		TgClient:         sender,
+		ExpenseProcessor: processor,
+		ReportRequester:  reportRequester,
+		SettingsRepo:     settingsRepo,
+	})
+
+	err = model.IncomingMessage(ctx, Message{
+		Command:          editExpenseCommand,
+		CommandArguments: id + " 125.50; Кофе; 2022-10-01 12:56:00",
+		UserID:           userId,
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestOnDeleteExpenseShouldAnswerWithSuccessMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	userId := int64(100)
+	id := "1b4e28ba-2fa1-11d2-883f-0016d3cca427"
+
+	ctx := context.Background()
+	_, wrapedCtx := opentracing.StartSpanFromContext(ctx, "wrap1")
+	_, wrapedCtx = opentracing.StartSpanFromContext(wrapedCtx, "wrap2")
+
+	sender := msgmocks.NewMockMessageSender(ctrl)
+	sender.EXPECT().SendMessage("Трата "+id+" удалена", userId, mainMenu)
+
+	processor := exp_processor_mock.NewMockExpenseProcessor(ctrl)
+	processor.EXPECT().DeleteExpense(wrapedCtx, id, userId).Return(true, nil)
+
+	reportRequester := report_requester_mock.NewMockReportRequester(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+
+	model := newTestModel(Deps{
+		TgClient:         sender,
+		ExpenseProcessor: processor,
+		ReportRequester:  reportRequester,
+		SettingsRepo:     settingsRepo,
+	})
+
+	err := model.IncomingMessage(ctx, Message{
+		Command:          deleteExpenseCommand,
+		CommandArguments: id,
+		UserID:           userId,
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestOnDeleteExpenseShouldAnswerWithNotFoundMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	userId := int64(100)
+
+	sender := msgmocks.NewMockMessageSender(ctrl)
+	sender.EXPECT().SendMessage("трата unknown не найдена", userId, mainMenu)
+
+	processor := exp_processor_mock.NewMockExpenseProcessor(ctrl)
+	processor.EXPECT().DeleteExpense(gomock.Any(), "unknown", userId).Return(false, nil)
+
+	reportRequester := report_requester_mock.NewMockReportRequester(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+
+	model := newTestModel(Deps{
+		TgClient:         sender,
+		ExpenseProcessor: processor,
+		ReportRequester:  reportRequester,
+		SettingsRepo:     settingsRepo,
+	})
+
+	err := model.IncomingMessage(context.Background(), Message{
+		Command:          deleteExpenseCommand,
+		CommandArguments: "unknown",
+		UserID:           userId,
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestOnGetExpenseWithDateRangeShouldRequestCustomReport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	userId := int64(100)
+	ctx := context.Background()
+	_, wrapedCtx := opentracing.StartSpanFromContext(ctx, "wrap1")
+	_, wrapedCtx = opentracing.StartSpanFromContext(wrapedCtx, "wrap2")
+
+	from := time.Date(2022, time.September, 1, 0, 0, 0, 0, time.Local)
+	to := time.Date(2022, time.October, 1, 0, 0, 0, 0, time.Local)
+
+	sender := msgmocks.NewMockMessageSender(ctrl)
+	sender.EXPECT().SendMessage("Запрос на формирование отчета отправлен", userId, mainMenu)
+
+	processor := exp_processor_mock.NewMockExpenseProcessor(ctrl)
+	reportRequester := report_requester_mock.NewMockReportRequester(ctrl)
+	reportRequester.EXPECT().SendRequestReport(wrapedCtx, userId, userId, userId, model.Custom, model.NewDateRange(from, to), "RUB", "")
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+	settingsRepo.EXPECT().GetSettings(wrapedCtx, userId).Return(model.UserSettings{}, false, nil)
+
+	model := newTestModel(Deps{
+		TgClient:         sender,
+		ExpenseProcessor: processor,
+		ReportRequester:  reportRequester,
+		SettingsRepo:     settingsRepo,
+	})
+
+	err := model.IncomingMessage(ctx, Message{
+		Command:          getExpensesCommand,
+		CommandArguments: "2022-09-01..2022-09-30",
+		UserID:           userId,
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestOnGetExpenseWithInvalidDateRangeShouldAnswerWithFailMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	sender := msgmocks.NewMockMessageSender(ctrl)
+	sender.EXPECT().SendMessage("неверный диапазон дат: 2022-09-30..2022-09-01. Ожидается 2022-09-01..2022-09-30", int64(123), mainMenu)
+
+	processor := exp_processor_mock.NewMockExpenseProcessor(ctrl)
+	reportRequester := report_requester_mock.NewMockReportRequester(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+	settingsRepo.EXPECT().GetSettings(gomock.Any(), int64(123)).Return(model.UserSettings{}, false, nil)
+
+	model := newTestModel(Deps{
+		TgClient:         sender,
+		ExpenseProcessor: processor,
+		ReportRequester:  reportRequester,
+		SettingsRepo:     settingsRepo,
+	})
+
+	err := model.IncomingMessage(ctx, Message{
+		Command:          getExpensesCommand,
+		CommandArguments: "2022-09-30..2022-09-01",
+		UserID:           123,
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestParseReportPeriodShouldReturnNamedRanges(t *testing.T) {
+	now := time.Date(2022, time.October, 12, 15, 30, 0, 0, time.UTC) // среда
+
+	period, dateRange, err := parseReportPeriod("previous month", now, time.Monday)
+	assert.NoError(t, err)
+	assert.Equal(t, model.Custom, period)
+	assert.Equal(t, "2022-09-01..2022-09-30", dateRange.String())
+
+	_, dateRange, err = parseReportPeriod("this month", now, time.Monday)
+	assert.NoError(t, err)
+	assert.Equal(t, "2022-10-01..2022-10-31", dateRange.String())
+
+	_, dateRange, err = parseReportPeriod("this week", now, time.Monday)
+	assert.NoError(t, err)
+	assert.Equal(t, "2022-10-10..2022-10-16", dateRange.String())
+
+	_, dateRange, err = parseReportPeriod("previous year", now, time.Monday)
+	assert.NoError(t, err)
+	assert.Equal(t, "2021-01-01..2021-12-31", dateRange.String())
+
+	period, _, err = parseReportPeriod("month", now, time.Monday)
+	assert.NoError(t, err)
+	assert.Equal(t, model.Month, period)
+}
+
+func TestOnSetPeriodModeShouldSaveSettings(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	sender := msgmocks.NewMockMessageSender(ctrl)
+	sender.EXPECT().SendMessage("Установлен режим периодов calendar", int64(123), mainMenu)
+	processor := exp_processor_mock.NewMockExpenseProcessor(ctrl)
+	reportRequester := report_requester_mock.NewMockReportRequester(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+	settingsRepo.EXPECT().GetSettings(gomock.Any(), int64(123)).Return(model.UserSettings{
+		UserID:     123,
+		Currency:   "RUB",
+		PeriodMode: model.RollingPeriodMode,
+		WeekStart:  time.Monday,
+	}, true, nil)
+	settingsRepo.EXPECT().SaveSettings(gomock.Any(), model.UserSettings{
+		UserID:     123,
+		Currency:   "RUB",
+		PeriodMode: model.CalendarPeriodMode,
+		WeekStart:  time.Monday,
+	})
+
+	model := newTestModel(Deps{
+		TgClient:         sender,
+		ExpenseProcessor: processor,
+		ReportRequester:  reportRequester,
+		SettingsRepo:     settingsRepo,
+	})
+
+	err := model.IncomingMessage(ctx, Message{
+		Command:          setPeriodModeCommand,
+		CommandArguments: "calendar",
+		UserID:           123,
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestOnSetWeekStartShouldSaveSettings(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	sender := msgmocks.NewMockMessageSender(ctrl)
+	sender.EXPECT().SendMessage("Неделя начинается с sunday", int64(123), mainMenu)
+	processor := exp_processor_mock.NewMockExpenseProcessor(ctrl)
+	reportRequester := report_requester_mock.NewMockReportRequester(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+	settingsRepo.EXPECT().GetSettings(gomock.Any(), int64(123)).Return(model.UserSettings{
+		UserID:     123,
+		Currency:   "RUB",
+		PeriodMode: model.RollingPeriodMode,
+		WeekStart:  time.Monday,
+	}, true, nil)
+	settingsRepo.EXPECT().SaveSettings(gomock.Any(), model.UserSettings{
+		UserID:     123,
+		Currency:   "RUB",
+		PeriodMode: model.RollingPeriodMode,
+		WeekStart:  time.Sunday,
+	})
+
+	model := newTestModel(Deps{
+		TgClient:         sender,
+		ExpenseProcessor: processor,
+		ReportRequester:  reportRequester,
+		SettingsRepo:     settingsRepo,
+	})
+
+	err := model.IncomingMessage(ctx, Message{
+		Command:          setWeekStartCommand,
+		CommandArguments: "Sunday",
+		UserID:           123,
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestOnSetPeriodModeWithUnknownModeShouldAnswerWithFailMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	sender := msgmocks.NewMockMessageSender(ctrl)
+	sender.EXPECT().SendMessage("неизвестный режим периодов fiscal. Ожидается: rolling, calendar", int64(123), mainMenu)
+	processor := exp_processor_mock.NewMockExpenseProcessor(ctrl)
+	reportRequester := report_requester_mock.NewMockReportRequester(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+
+	model := newTestModel(Deps{
+		TgClient:         sender,
+		ExpenseProcessor: processor,
+		ReportRequester:  reportRequester,
+		SettingsRepo:     settingsRepo,
+	})
+
+	err := model.IncomingMessage(ctx, Message{
+		Command:          setPeriodModeCommand,
+		CommandArguments: "fiscal",
+		UserID:           123,
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestOnAddExpenseShouldParseDateInUserTimezone(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	_, wrapedCtx := opentracing.StartSpanFromContext(ctx, "wrap1")
+	_, wrapedCtx = opentracing.StartSpanFromContext(wrapedCtx, "wrap2")
+	userId := int64(100)
+	loc, err := time.LoadLocation("Asia/Vladivostok")
+	assert.NoError(t, err)
+	date := time.Date(2022, time.October, 1, 0, 30, 0, 0, loc)
+
+	sender := msgmocks.NewMockMessageSender(ctrl)
+	sender.EXPECT().SendMessage("Трата 125.50 RUB добавлена в категорию Кофе с датой 2022-10-01 00:30:00", userId, mainMenu)
+
+	processor := exp_processor_mock.NewMockExpenseProcessor(ctrl)
+	processor.EXPECT().AddExpense(wrapedCtx, 125.50, "RUB", "Кофе", "", date, userId, userId).
+		Return(&model.Expense{Category: "Кофе"}, nil)
+	processor.EXPECT().GetFreeLimits(wrapedCtx, "Кофе", userId, gomock.Any(), gomock.Any())
+	reportRequester := report_requester_mock.NewMockReportRequester(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+	settingsRepo.EXPECT().GetSettings(wrapedCtx, userId).Return(model.UserSettings{
+		UserID:   userId,
+		Currency: "RUB",
+		Timezone: "Asia/Vladivostok",
+	}, true, nil)
+
+	model := newTestModel(Deps{
+		TgClient:         sender,
+		ExpenseProcessor: processor,
+		ReportRequester:  reportRequester,
+		SettingsRepo:     settingsRepo,
+	})
+
+	err = model.IncomingMessage(ctx, Message{
+		Command:          addExpenseCommand,
+		CommandArguments: "125.50;Кофе;2022-10-01 00:30:00",
+		UserID:           userId,
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestOnListExpensesShouldShowStoredWallClock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	userId := int64(100)
+
+	processor := exp_processor_mock.NewMockExpenseProcessor(ctrl)
+	// трата в 12:00 по Москве, из базы время читается с меткой UTC
+	processor.EXPECT().ListExpenses(gomock.Any(), gomock.Any(), "RUB", userId).Return([]expense_processor.ExpenseItem{
+		{ID: "1", Amount: 350, Category: "Кофе", Datetime: time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC)},
+	}, nil)
+	reportRequester := report_requester_mock.NewMockReportRequester(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+	settingsRepo.EXPECT().GetSettings(gomock.Any(), userId).Return(model.UserSettings{
+		UserID:   userId,
+		Currency: "RUB",
+		Timezone: "Europe/Moscow",
+	}, true, nil)
+
+	sender := msgmocks.NewMockMessageSender(ctrl)
+	sender.EXPECT().SendMessage(
+		"Траты за 2022-10-01..2022-10-02:\n1\n350.00 RUB - Кофе - 2022-10-01 12:00:00\n",
+		userId,
+		mainMenu,
+	)
+
+	model := newTestModel(Deps{
+		TgClient:         sender,
+		ExpenseProcessor: processor,
+		ReportRequester:  reportRequester,
+		SettingsRepo:     settingsRepo,
+	})
+
+	err := model.IncomingMessage(ctx, Message{
+		Command:          listExpensesCommand,
+		CommandArguments: "2022-10-01..2022-10-02",
+		UserID:           userId,
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestOnSetTimezoneShouldSaveSettings(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	sender := msgmocks.NewMockMessageSender(ctrl)
+	sender.EXPECT().SendMessage("Установлен часовой пояс Europe/Moscow", int64(123), mainMenu)
+	processor := exp_processor_mock.NewMockExpenseProcessor(ctrl)
+	reportRequester := report_requester_mock.NewMockReportRequester(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+	settingsRepo.EXPECT().GetSettings(gomock.Any(), int64(123)).Return(model.UserSettings{}, false, nil)
+	settingsRepo.EXPECT().SaveSettings(gomock.Any(), model.UserSettings{
+		UserID:     123,
+		Currency:   "RUB",
+		PeriodMode: model.RollingPeriodMode,
+		WeekStart:  time.Monday,
+		Timezone:   "Europe/Moscow",
+	})
+	subscriptionsRepo := repomocks.NewMockReportSubscriptionsRepository(ctrl)
+	subscriptionsRepo.EXPECT().GetSubscriptions(gomock.Any(), int64(123)).Return([]model.ReportSubscription{
+		{
+			UserId:    123,
+			Schedule:  model.Schedule{Period: model.Week, Weekday: time.Monday, Hour: 9},
+			NextRunAt: time.Now().Add(time.Hour),
+		},
+	}, nil)
+	subscriptionsRepo.EXPECT().SaveSubscription(gomock.Any(), gomock.Any()).Do(
+		func(ctx context.Context, subscription model.ReportSubscription) {
+			moscow, err := time.LoadLocation("Europe/Moscow")
+			assert.NoError(t, err)
+
+			nextRunAt := subscription.NextRunAt.In(moscow)
+			assert.Equal(t, time.Monday, nextRunAt.Weekday())
+			assert.Equal(t, 9, nextRunAt.Hour())
+		},
+	)
+
+	cache := cachememory.NewLRUCache(10)
+	assert.NoError(t, cache.Set(ctx, servicecache.ReportsVersionKey(123), "1", 0))
+
+	model := newTestModel(Deps{
+		TgClient:          sender,
+		ExpenseProcessor:  processor,
+		ReportRequester:   reportRequester,
+		SettingsRepo:      settingsRepo,
+		SubscriptionsRepo: subscriptionsRepo,
+		DialogCache:       cache,
+	})
+
+	err := model.IncomingMessage(ctx, Message{
+		Command:          setTimezoneCommand,
+		CommandArguments: "Europe/Moscow",
+		UserID:           123,
+	})
+
+	assert.NoError(t, err)
+
+	// границы периодов в закешированных отчетах считались в прежнем часовом поясе
+	_, found, err := cache.Get(ctx, servicecache.ReportsVersionKey(123))
+	assert.NoError(t, err)
+	assert.False(t, found)
+}
+
+func TestOnSetTimezoneWithUnknownZoneShouldAnswerWithFailMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	sender := msgmocks.NewMockMessageSender(ctrl)
+	sender.EXPECT().SendMessage("неизвестный часовой пояс Mars/Olympus. Например: Europe/Moscow", int64(123), mainMenu)
+	processor := exp_processor_mock.NewMockExpenseProcessor(ctrl)
+	reportRequester := report_requester_mock.NewMockReportRequester(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+
+	model := newTestModel(Deps{
+		TgClient:         sender,
+		ExpenseProcessor: processor,
+		ReportRequester:  reportRequester,
+		SettingsRepo:     settingsRepo,
+	})
+
+	err := model.IncomingMessage(ctx, Message{
+		Command:          setTimezoneCommand,
+		CommandArguments: "Mars/Olympus",
+		UserID:           123,
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestOnAddExpenseWithAmountOnlyShouldOfferTopCategories(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	_, wrapedCtx := opentracing.StartSpanFromContext(ctx, "wrap1")
+	_, wrapedCtx = opentracing.StartSpanFromContext(wrapedCtx, "wrap2")
+	_, pickerCtx := opentracing.StartSpanFromContext(wrapedCtx, "wrap3")
+	userId := int64(100)
+
+	processor := exp_processor_mock.NewMockExpenseProcessor(ctrl)
+	processor.EXPECT().GetTopCategories(pickerCtx, userId, categoryPickerLimit).
+		Return([]string{"Кофе", "Дом", "Продукты и бытовая химия"}, nil)
+	reportRequester := report_requester_mock.NewMockReportRequester(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+	settingsRepo.EXPECT().GetSettings(wrapedCtx, userId).Return(model.UserSettings{}, false, nil)
+
+	sender := msgmocks.NewMockMessageSender(ctrl)
+	sender.EXPECT().SendInlineKeyboard("Выберите категорию для траты 350.00 RUB", userId, gomock.Any()).
+		DoAndReturn(func(text string, userID int64, buttons []model.InlineButton) error {
+			assert.Len(t, buttons, 3)
+			assert.Equal(t, "Кофе", buttons[0].Text)
+			assert.Regexp(t, `^ce\|350\|RUB\|\d+\|[0-9a-f]{8}$`, buttons[0].Data)
+			assert.Equal(t, categoryCallbackKey("Кофе"), buttons[0].Data[len(buttons[0].Data)-8:])
+			assert.Equal(t, "Дом", buttons[1].Text)
+			// длинное название на кириллице не занимает место в callback_data
+			assert.Equal(t, "Продукты и бытовая химия", buttons[2].Text)
+			assert.LessOrEqual(t, len(buttons[2].Data), 64)
+			return nil
+		})
+
+	model := newTestModel(Deps{
+		TgClient:         sender,
+		ExpenseProcessor: processor,
+		ReportRequester:  reportRequester,
+		SettingsRepo:     settingsRepo,
+	})
+
+	err := model.IncomingMessage(ctx, Message{
+		Command:          addExpenseCommand,
+		CommandArguments: "350",
+		UserID:           userId,
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestOnAddExpenseWithAmountOnlyAndNoCategoriesShouldAnswerWithFailMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	userId := int64(100)
+
+	processor := exp_processor_mock.NewMockExpenseProcessor(ctrl)
+	processor.EXPECT().GetTopCategories(gomock.Any(), userId, categoryPickerLimit).Return([]string{}, nil)
+	reportRequester := report_requester_mock.NewMockReportRequester(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+	settingsRepo.EXPECT().GetSettings(gomock.Any(), userId).Return(model.UserSettings{}, false, nil)
+
+	sender := msgmocks.NewMockMessageSender(ctrl)
+	sender.EXPECT().SendMessage("нет категорий для выбора. Укажите категорию: /addExpense 350 кофе", userId, mainMenu)
+
+	model := newTestModel(Deps{
+		TgClient:         sender,
+		ExpenseProcessor: processor,
+		ReportRequester:  reportRequester,
+		SettingsRepo:     settingsRepo,
+	})
+
+	err := model.IncomingMessage(ctx, Message{
+		Command:          addExpenseCommand,
+		CommandArguments: "350",
+		UserID:           userId,
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestOnCategoryCallbackShouldAddExpense(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	userId := int64(100)
+	date := time.Unix(1664628960, 0).In(time.Local)
+
+	processor := exp_processor_mock.NewMockExpenseProcessor(ctrl)
+	processor.EXPECT().GetCategories(gomock.Any(), userId).Return([]string{"Дом", "Кофе"}, nil)
+	processor.EXPECT().AddExpense(gomock.Any(), 12.5, "USD", "Кофе", "", date, userId, userId).
+		Return(&model.Expense{Category: "Кофе"}, nil)
+	processor.EXPECT().GetFreeLimits(gomock.Any(), "Кофе", userId, gomock.Any(), gomock.Any())
+	reportRequester := report_requester_mock.NewMockReportRequester(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+	settingsRepo.EXPECT().GetSettings(gomock.Any(), userId).Return(model.UserSettings{}, false, nil)
+
+	sender := msgmocks.NewMockMessageSender(ctrl)
+	sender.EXPECT().SendMessage(
+		fmt.Sprintf("Трата 12.50 USD добавлена в категорию Кофе с датой %s", date.Format(datetimeFormat)),
+		userId,
+		mainMenu,
+	)
+
+	model := newTestModel(Deps{
+		TgClient:         sender,
+		ExpenseProcessor: processor,
+		ReportRequester:  reportRequester,
+		SettingsRepo:     settingsRepo,
+	})
+
+	err := model.IncomingCallback(ctx, Callback{
+		Data:   "ce|12.5|USD|1664628960|" + categoryCallbackKey("кофе"),
+		UserID: userId,
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestOnCategoryCallbackWithDeletedCategoryShouldAnswerWithFailMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	userId := int64(100)
+
+	processor := exp_processor_mock.NewMockExpenseProcessor(ctrl)
+	processor.EXPECT().GetCategories(gomock.Any(), userId).Return([]string{"Дом"}, nil)
+	reportRequester := report_requester_mock.NewMockReportRequester(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+
+	sender := msgmocks.NewMockMessageSender(ctrl)
+	sender.EXPECT().SendMessage("выбранной категории больше нет. Укажите категорию: /addExpense 350 кофе", userId, mainMenu)
+
+	model := newTestModel(Deps{
+		TgClient:         sender,
+		ExpenseProcessor: processor,
+		ReportRequester:  reportRequester,
+		SettingsRepo:     settingsRepo,
+	})
+
+	err := model.IncomingCallback(ctx, Callback{
+		Data:   "ce|12.5|USD|1664628960|" + categoryCallbackKey("Кофе"),
+		UserID: userId,
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestOnUnknownCallbackShouldAnswerWithFailMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	processor := exp_processor_mock.NewMockExpenseProcessor(ctrl)
+	reportRequester := report_requester_mock.NewMockReportRequester(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+
+	sender := msgmocks.NewMockMessageSender(ctrl)
+	sender.EXPECT().SendMessage("неизвестная кнопка", int64(100), mainMenu)
+
+	model := newTestModel(Deps{
+		TgClient:         sender,
+		ExpenseProcessor: processor,
+		ReportRequester:  reportRequester,
+		SettingsRepo:     settingsRepo,
+	})
+
+	err := model.IncomingCallback(ctx, Callback{Data: "unknown", UserID: 100})
+
+	assert.NoError(t, err)
+}
+
+func TestOnCategoriesShouldListUserCategories(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	userId := int64(100)
+
+	processor := exp_processor_mock.NewMockExpenseProcessor(ctrl)
+	processor.EXPECT().GetCategories(gomock.Any(), userId).Return([]string{"Дом", "Кофе"}, nil)
+	reportRequester := report_requester_mock.NewMockReportRequester(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+
+	sender := msgmocks.NewMockMessageSender(ctrl)
+	sender.EXPECT().SendMessage("Ваши категории:\n- Дом\n- Кофе\n", userId, mainMenu)
+
+	model := newTestModel(Deps{
+		TgClient:         sender,
+		ExpenseProcessor: processor,
+		ReportRequester:  reportRequester,
+		SettingsRepo:     settingsRepo,
+	})
+
+	err := model.IncomingMessage(ctx, Message{
+		Command: categoriesCommand,
+		UserID:  userId,
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestOnRenameCategoryShouldAnswerWithSuccessMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	userId := int64(100)
+
+	processor := exp_processor_mock.NewMockExpenseProcessor(ctrl)
+	processor.EXPECT().RenameCategory(gomock.Any(), userId, "Еда", "Продукты").Return(true, nil)
+	reportRequester := report_requester_mock.NewMockReportRequester(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+
+	sender := msgmocks.NewMockMessageSender(ctrl)
+	sender.EXPECT().SendMessage("Категория Еда переименована в Продукты", userId, mainMenu)
+
+	model := newTestModel(Deps{
+		TgClient:         sender,
+		ExpenseProcessor: processor,
+		ReportRequester:  reportRequester,
+		SettingsRepo:     settingsRepo,
+	})
+
+	err := model.IncomingMessage(ctx, Message{
+		Command:          renameCategoryCommand,
+		CommandArguments: "Еда; Продукты",
+		UserID:           userId,
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestOnMergeCategoriesShouldAnswerWithNotFoundMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	userId := int64(100)
+
+	processor := exp_processor_mock.NewMockExpenseProcessor(ctrl)
+	processor.EXPECT().MergeCategories(gomock.Any(), userId, "Еда", "Продукты").Return(false, nil)
+	reportRequester := report_requester_mock.NewMockReportRequester(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+
+	sender := msgmocks.NewMockMessageSender(ctrl)
+	sender.EXPECT().SendMessage("категория Еда или Продукты не найдена", userId, mainMenu)
+
+	model := newTestModel(Deps{
+		TgClient:         sender,
+		ExpenseProcessor: processor,
+		ReportRequester:  reportRequester,
+		SettingsRepo:     settingsRepo,
+	})
+
+	err := model.IncomingMessage(ctx, Message{
+		Command:          mergeCategoriesCommand,
+		CommandArguments: "Еда;Продукты",
+		UserID:           userId,
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestOnMergeSameCategoryShouldAnswerWithFailMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	userId := int64(100)
+
+	processor := exp_processor_mock.NewMockExpenseProcessor(ctrl)
+	reportRequester := report_requester_mock.NewMockReportRequester(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+
+	sender := msgmocks.NewMockMessageSender(ctrl)
+	sender.EXPECT().SendMessage("нельзя объединить категорию с самой собой", userId, mainMenu)
+
+	model := newTestModel(Deps{
+		TgClient:         sender,
+		ExpenseProcessor: processor,
+		ReportRequester:  reportRequester,
+		SettingsRepo:     settingsRepo,
+	})
+
+	err := model.IncomingMessage(ctx, Message{
+		Command:          mergeCategoriesCommand,
+		CommandArguments: "Еда;еда",
+		UserID:           userId,
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestOnDeleteCategoryShouldAnswerWithSuccessMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	userId := int64(100)
+
+	processor := exp_processor_mock.NewMockExpenseProcessor(ctrl)
+	processor.EXPECT().DeleteCategory(gomock.Any(), userId, "Еда").Return(true, nil)
+	reportRequester := report_requester_mock.NewMockReportRequester(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+
+	sender := msgmocks.NewMockMessageSender(ctrl)
+	sender.EXPECT().SendMessage("Категория Еда удалена", userId, mainMenu)
+
+	model := newTestModel(Deps{
+		TgClient:         sender,
+		ExpenseProcessor: processor,
+		ReportRequester:  reportRequester,
+		SettingsRepo:     settingsRepo,
+	})
+
+	err := model.IncomingMessage(ctx, Message{
+		Command:          deleteCategoryCommand,
+		CommandArguments: "Еда",
+		UserID:           userId,
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestOnAliasShouldSaveCategoryRule(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	userId := int64(100)
+
+	processor := exp_processor_mock.NewMockExpenseProcessor(ctrl)
+	processor.EXPECT().SaveCategoryRule(gomock.Any(), model.CategoryRule{
+		Type:     model.AliasCategoryRule,
+		Pattern:  "еда",
+		Category: "Продукты",
+		UserId:   userId,
+	})
+	reportRequester := report_requester_mock.NewMockReportRequester(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+
+	sender := msgmocks.NewMockMessageSender(ctrl)
+	sender.EXPECT().SendMessage("Правило сохранено: еда => Продукты", userId, mainMenu)
+
+	model := newTestModel(Deps{
+		TgClient:         sender,
+		ExpenseProcessor: processor,
+		ReportRequester:  reportRequester,
+		SettingsRepo:     settingsRepo,
+	})
+
+	err := model.IncomingMessage(ctx, Message{
+		Command:          aliasCommand,
+		CommandArguments: "еда => Продукты",
+		UserID:           userId,
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestOnKeywordWithoutCategoryShouldAnswerWithFailMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	userId := int64(100)
+
+	processor := exp_processor_mock.NewMockExpenseProcessor(ctrl)
+	reportRequester := report_requester_mock.NewMockReportRequester(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+
+	sender := msgmocks.NewMockMessageSender(ctrl)
+	sender.EXPECT().SendMessage(errCategoryRuleInvalidParameterMessage, userId, mainMenu)
+
+	model := newTestModel(Deps{
+		TgClient:         sender,
+		ExpenseProcessor: processor,
+		ReportRequester:  reportRequester,
+		SettingsRepo:     settingsRepo,
+	})
+
+	err := model.IncomingMessage(ctx, Message{
+		Command:          keywordCommand,
+		CommandArguments: "старбакс =>",
+		UserID:           userId,
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestOnRulesShouldListCategoryRules(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	userId := int64(100)
+
+	processor := exp_processor_mock.NewMockExpenseProcessor(ctrl)
+	processor.EXPECT().GetCategoryRules(gomock.Any(), userId).Return([]model.CategoryRule{
+		{Type: model.AliasCategoryRule, Pattern: "еда", Category: "Продукты", UserId: userId},
+		{Type: model.KeywordCategoryRule, Pattern: "старбакс", Category: "Кофе", UserId: userId},
+	}, nil)
+	reportRequester := report_requester_mock.NewMockReportRequester(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+
+	sender := msgmocks.NewMockMessageSender(ctrl)
+	sender.EXPECT().SendMessage("Правила категорий:\n"+
+		"- еда => Продукты (синоним)\n"+
+		"- старбакс => Кофе (ключевое слово)\n"+
+		"Удалить правило: /deleteRule Текст", userId, mainMenu)
+
+	model := newTestModel(Deps{
+		TgClient:         sender,
+		ExpenseProcessor: processor,
+		ReportRequester:  reportRequester,
+		SettingsRepo:     settingsRepo,
+	})
+
+	err := model.IncomingMessage(ctx, Message{
+		Command: categoryRulesCommand,
+		UserID:  userId,
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestOnDeleteRuleShouldAnswerWithNotFoundMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	userId := int64(100)
+
+	processor := exp_processor_mock.NewMockExpenseProcessor(ctrl)
+	processor.EXPECT().DeleteCategoryRule(gomock.Any(), userId, "еда").Return(false, nil)
+	reportRequester := report_requester_mock.NewMockReportRequester(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+
+	sender := msgmocks.NewMockMessageSender(ctrl)
+	sender.EXPECT().SendMessage("правило еда не найдено", userId, mainMenu)
+
+	model := newTestModel(Deps{
+		TgClient:         sender,
+		ExpenseProcessor: processor,
+		ReportRequester:  reportRequester,
+		SettingsRepo:     settingsRepo,
+	})
+
+	err := model.IncomingMessage(ctx, Message{
+		Command:          deleteCategoryRuleCommand,
+		CommandArguments: "еда",
+		UserID:           userId,
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestOnSetLimitAlertsShouldAnswerWithSuccessMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	userId := int64(100)
+
+	processor := exp_processor_mock.NewMockExpenseProcessor(ctrl)
+	processor.EXPECT().SetLimitThresholds(gomock.Any(), "", model.Week, userId, []int{80, 100}).Return(true, nil)
+	reportRequester := report_requester_mock.NewMockReportRequester(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+
+	sender := msgmocks.NewMockMessageSender(ctrl)
+	sender.EXPECT().SendMessage("Недельный лимит для всех категорий: уведомления при расходе 80,100%", userId, mainMenu)
+
+	model := newTestModel(Deps{
+		TgClient:         sender,
+		ExpenseProcessor: processor,
+		ReportRequester:  reportRequester,
+		SettingsRepo:     settingsRepo,
+	})
+
+	err := model.IncomingMessage(ctx, Message{
+		Command:          setLimitAlertsCommand,
+		CommandArguments: "*;100, 80%;week",
+		UserID:           userId,
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestOnSetLimitAlertsForMissingLimitShouldAnswerWithFailMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	userId := int64(100)
+
+	processor := exp_processor_mock.NewMockExpenseProcessor(ctrl)
+	processor.EXPECT().SetLimitThresholds(gomock.Any(), "Дом", model.Month, userId, nil).Return(false, nil)
+	reportRequester := report_requester_mock.NewMockReportRequester(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+
+	sender := msgmocks.NewMockMessageSender(ctrl)
+	sender.EXPECT().SendMessage("месячный лимит для Дом не установлен: /setLimit", userId, mainMenu)
+
+	model := newTestModel(Deps{
+		TgClient:         sender,
+		ExpenseProcessor: processor,
+		ReportRequester:  reportRequester,
+		SettingsRepo:     settingsRepo,
+	})
+
+	err := model.IncomingMessage(ctx, Message{
+		Command:          setLimitAlertsCommand,
+		CommandArguments: "Дом;off",
+		UserID:           userId,
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestOnSetLimitAlertsWithInvalidThresholdsShouldAnswerWithFailMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	userId := int64(100)
+
+	processor := exp_processor_mock.NewMockExpenseProcessor(ctrl)
+	reportRequester := report_requester_mock.NewMockReportRequester(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+
+	sender := msgmocks.NewMockMessageSender(ctrl)
+	sender.EXPECT().SendMessage("неверные пороги уведомлений: 50,много. Ожидается список процентов, например 50,80,100", userId, mainMenu)
+
+	model := newTestModel(Deps{
+		TgClient:         sender,
+		ExpenseProcessor: processor,
+		ReportRequester:  reportRequester,
+		SettingsRepo:     settingsRepo,
+	})
+
+	err := model.IncomingMessage(ctx, Message{
+		Command:          setLimitAlertsCommand,
+		CommandArguments: "Дом;50,много",
+		UserID:           userId,
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestOnSubscribeShouldSaveSubscription(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	sender := msgmocks.NewMockMessageSender(ctrl)
+	sender.EXPECT().SendMessage(gomock.Any(), int64(123), mainMenu).Do(func(text string, userID int64, buttons []string) {
+		assert.Contains(t, text, "Недельный отчет будет приходить по расписанию weekly monday 09:00, следующий ")
+	})
+	processor := exp_processor_mock.NewMockExpenseProcessor(ctrl)
+	reportRequester := report_requester_mock.NewMockReportRequester(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+	settingsRepo.EXPECT().GetSettings(gomock.Any(), int64(123)).Return(model.UserSettings{}, false, nil)
+	subscriptionsRepo := repomocks.NewMockReportSubscriptionsRepository(ctrl)
+	subscriptionsRepo.EXPECT().SaveSubscription(gomock.Any(), gomock.Any()).Do(
+		func(ctx context.Context, subscription model.ReportSubscription) {
+			assert.Equal(t, int64(123), subscription.UserId)
+			assert.Equal(t, model.Schedule{Period: model.Week, Weekday: time.Monday, Hour: 9}, subscription.Schedule)
+			assert.True(t, subscription.NextRunAt.After(time.Now()))
+		},
+	)
+
+	model := newTestModel(Deps{
+		TgClient:          sender,
+		ExpenseProcessor:  processor,
+		ReportRequester:   reportRequester,
+		SettingsRepo:      settingsRepo,
+		SubscriptionsRepo: subscriptionsRepo,
+	})
+
+	err := model.IncomingMessage(ctx, Message{
+		Command:          subscribeCommand,
+		CommandArguments: "weekly Monday 09:00",
+		UserID:           123,
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestOnSubscribeWithInvalidTimeShouldAnswerWithFailMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	sender := msgmocks.NewMockMessageSender(ctrl)
+	sender.EXPECT().SendMessage("неверное время: 25:00. Ожидается 09:00", int64(123), mainMenu)
+	processor := exp_processor_mock.NewMockExpenseProcessor(ctrl)
+	reportRequester := report_requester_mock.NewMockReportRequester(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+	subscriptionsRepo := repomocks.NewMockReportSubscriptionsRepository(ctrl)
+
+	model := newTestModel(Deps{
+		TgClient:          sender,
+		ExpenseProcessor:  processor,
+		ReportRequester:   reportRequester,
+		SettingsRepo:      settingsRepo,
+		SubscriptionsRepo: subscriptionsRepo,
+	})
+
+	err := model.IncomingMessage(ctx, Message{
+		Command:          subscribeCommand,
+		CommandArguments: "monthly 1 25:00",
+		UserID:           123,
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestOnSubscriptionsShouldListSubscriptions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	sender := msgmocks.NewMockMessageSender(ctrl)
+	sender.EXPECT().SendMessage(
+		"Подписки на отчеты:\n"+
+			"- Месячный: monthly 31 18:30, следующий 2022-11-30 18:30\n"+
+			"Отписаться: /unsubscribe weekly",
+		int64(123),
+		mainMenu,
+	)
+	processor := exp_processor_mock.NewMockExpenseProcessor(ctrl)
+	reportRequester := report_requester_mock.NewMockReportRequester(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+	settingsRepo.EXPECT().GetSettings(gomock.Any(), int64(123)).Return(model.UserSettings{
+		UserID:   123,
+		Currency: "RUB",
+		Timezone: "Europe/Moscow",
+	}, true, nil)
+	subscriptionsRepo := repomocks.NewMockReportSubscriptionsRepository(ctrl)
+	subscriptionsRepo.EXPECT().GetSubscriptions(gomock.Any(), int64(123)).Return([]model.ReportSubscription{
+		{
+			UserId:    123,
+			Schedule:  model.Schedule{Period: model.Month, Day: 31, Hour: 18, Minute: 30},
+			NextRunAt: time.Date(2022, 11, 30, 15, 30, 0, 0, time.UTC),
+		},
+	}, nil)
+
+	model := newTestModel(Deps{
+		TgClient:          sender,
+		ExpenseProcessor:  processor,
+		ReportRequester:   reportRequester,
+		SettingsRepo:      settingsRepo,
+		SubscriptionsRepo: subscriptionsRepo,
+	})
+
+	err := model.IncomingMessage(ctx, Message{
+		Command: subscriptionsCommand,
+		UserID:  123,
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestOnUnsubscribeShouldAnswerWithNotFoundMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	sender := msgmocks.NewMockMessageSender(ctrl)
+	sender.EXPECT().SendMessage("подписки на месячный отчет нет", int64(123), mainMenu)
+	processor := exp_processor_mock.NewMockExpenseProcessor(ctrl)
+	reportRequester := report_requester_mock.NewMockReportRequester(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+	subscriptionsRepo := repomocks.NewMockReportSubscriptionsRepository(ctrl)
+	subscriptionsRepo.EXPECT().DeleteSubscription(gomock.Any(), int64(123), model.Month).Return(false, nil)
+
+	model := newTestModel(Deps{
+		TgClient:          sender,
+		ExpenseProcessor:  processor,
+		ReportRequester:   reportRequester,
+		SettingsRepo:      settingsRepo,
+		SubscriptionsRepo: subscriptionsRepo,
+	})
+
+	err := model.IncomingMessage(ctx, Message{
+		Command:          unsubscribeCommand,
+		CommandArguments: "monthly",
+		UserID:           123,
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestOnAddRecurringShouldAnswerWithSuccessMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	sender := msgmocks.NewMockMessageSender(ctrl)
+	sender.EXPECT().SendMessage(
+		"Регулярная трата 25000.50 USD в категорию Аренда добавлена: monthly 5 00:00, первая 2022-12-05 00:00\nИД: 1",
+		int64(123),
+		mainMenu,
+	)
+	processor := exp_processor_mock.NewMockExpenseProcessor(ctrl)
+	reportRequester := report_requester_mock.NewMockReportRequester(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+	settingsRepo.EXPECT().GetSettings(gomock.Any(), int64(123)).Return(model.UserSettings{
+		UserID:   123,
+		Currency: "USD",
+		Timezone: "UTC",
+	}, true, nil)
+	recurringExpenses := recurring_expenses_mock.NewMockRecurringExpenses(ctrl)
+	recurringExpenses.EXPECT().AddRecurring(
+		gomock.Any(),
+		int64(123),
+		25000.50,
+		"USD",
+		"Аренда",
+		model.Schedule{Period: model.Month, Day: 5},
+	).Return(model.RecurringExpense{ID: "1", NextRunAt: time.Date(2022, 12, 5, 0, 0, 0, 0, time.UTC)}, nil)
+
+	model := newTestModel(Deps{
+		TgClient:          sender,
+		ExpenseProcessor:  processor,
+		ReportRequester:   reportRequester,
+		SettingsRepo:      settingsRepo,
+		RecurringExpenses: recurringExpenses,
+	})
+
+	err := model.IncomingMessage(ctx, Message{
+		Command:          addRecurringCommand,
+		CommandArguments: "25000,50;Аренда;monthly 5",
+		UserID:           123,
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestOnAddRecurringWithoutScheduleShouldAnswerWithFailMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	sender := msgmocks.NewMockMessageSender(ctrl)
+	sender.EXPECT().SendMessage(
+		"неверное количество параметров.\nОжидается: Сумма;Категория;Расписание \n"+
+			"Например: 25000;Аренда;monthly 5, 599 RUB;Подписки;weekly friday 10:00",
+		int64(123),
+		mainMenu,
+	)
+	processor := exp_processor_mock.NewMockExpenseProcessor(ctrl)
+	reportRequester := report_requester_mock.NewMockReportRequester(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+	recurringExpenses := recurring_expenses_mock.NewMockRecurringExpenses(ctrl)
+
+	model := newTestModel(Deps{
+		TgClient:          sender,
+		ExpenseProcessor:  processor,
+		ReportRequester:   reportRequester,
+		SettingsRepo:      settingsRepo,
+		RecurringExpenses: recurringExpenses,
+	})
+
+	err := model.IncomingMessage(ctx, Message{
+		Command:          addRecurringCommand,
+		CommandArguments: "25000;Аренда",
+		UserID:           123,
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestOnRecurringShouldListRecurringExpenses(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	sender := msgmocks.NewMockMessageSender(ctrl)
+	sender.EXPECT().SendMessage(
+		"Регулярные траты:\n"+
+			"1\n25000.00 RUB - Аренда - monthly 5 00:00, следующая 2022-12-05 00:00\n"+
+			"2\n599.00 RUB - Подписки - weekly friday 10:00, приостановлена\n",
+		int64(123),
+		mainMenu,
+	)
+	processor := exp_processor_mock.NewMockExpenseProcessor(ctrl)
+	reportRequester := report_requester_mock.NewMockReportRequester(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+	settingsRepo.EXPECT().GetSettings(gomock.Any(), int64(123)).Return(model.UserSettings{
+		UserID:   123,
+		Currency: "RUB",
+		Timezone: "UTC",
+	}, true, nil)
+	recurringExpenses := recurring_expenses_mock.NewMockRecurringExpenses(ctrl)
+	recurringExpenses.EXPECT().GetRecurring(gomock.Any(), int64(123)).Return([]model.RecurringExpense{
+		{
+			ID:        "1",
+			Amount:    2500000,
+			Currency:  "RUB",
+			Category:  "Аренда",
+			Schedule:  model.Schedule{Period: model.Month, Day: 5},
+			NextRunAt: time.Date(2022, 12, 5, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			ID:       "2",
+			Amount:   59900,
+			Currency: "RUB",
+			Category: "Подписки",
+			Schedule: model.Schedule{Period: model.Week, Weekday: time.Friday, Hour: 10},
+			Paused:   true,
+		},
+	}, nil)
+
+	model := newTestModel(Deps{
+		TgClient:          sender,
+		ExpenseProcessor:  processor,
+		ReportRequester:   reportRequester,
+		SettingsRepo:      settingsRepo,
+		RecurringExpenses: recurringExpenses,
+	})
+
+	err := model.IncomingMessage(ctx, Message{
+		Command: recurringCommand,
+		UserID:  123,
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestOnPauseRecurringShouldAnswerWithNotFoundMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	sender := msgmocks.NewMockMessageSender(ctrl)
+	sender.EXPECT().SendMessage("регулярная трата 1 не найдена", int64(123), mainMenu)
+	processor := exp_processor_mock.NewMockExpenseProcessor(ctrl)
+	reportRequester := report_requester_mock.NewMockReportRequester(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+	recurringExpenses := recurring_expenses_mock.NewMockRecurringExpenses(ctrl)
+	recurringExpenses.EXPECT().PauseRecurring(gomock.Any(), int64(123), "1").Return(false, nil)
+
+	model := newTestModel(Deps{
+		TgClient:          sender,
+		ExpenseProcessor:  processor,
+		ReportRequester:   reportRequester,
+		SettingsRepo:      settingsRepo,
+		RecurringExpenses: recurringExpenses,
+	})
+
+	err := model.IncomingMessage(ctx, Message{
+		Command:          pauseRecurringCommand,
+		CommandArguments: "1",
+		UserID:           123,
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestOnAddIncomeShouldAnswerWithSuccessMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	date := time.Date(2022, 10, 5, 0, 0, 0, 0, time.UTC)
+
+	sender := msgmocks.NewMockMessageSender(ctrl)
+	sender.EXPECT().SendMessage("Доход 150000.00 RUB (Зарплата) добавлен с датой 2022-10-05 00:00:00", int64(123), mainMenu)
+	processor := exp_processor_mock.NewMockExpenseProcessor(ctrl)
+	processor.EXPECT().AddIncome(gomock.Any(), 150000.0, "RUB", "Зарплата", date, int64(123)).
+		Return(&model.Income{Amount: 15000000, Source: "Зарплата", Datetime: date, UserId: 123}, nil)
+	reportRequester := report_requester_mock.NewMockReportRequester(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+	settingsRepo.EXPECT().GetSettings(gomock.Any(), int64(123)).Return(model.UserSettings{
+		UserID:   123,
+		Currency: "RUB",
+		Timezone: "UTC",
+	}, true, nil)
+
+	model := newTestModel(Deps{
+		TgClient:         sender,
+		ExpenseProcessor: processor,
+		ReportRequester:  reportRequester,
+		SettingsRepo:     settingsRepo,
+	})
+
+	err := model.IncomingMessage(ctx, Message{
+		Command:          addIncomeCommand,
+		CommandArguments: "150000;Зарплата;2022-10-05",
+		UserID:           123,
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestOnAddIncomeWithoutSourceShouldAnswerWithFailMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	sender := msgmocks.NewMockMessageSender(ctrl)
+	sender.EXPECT().SendMessage(
+		"неверное количество параметров.\nОжидается: Сумма;Источник;Дата, дата необязательна \n"+
+			"Например: 150000;Зарплата;2022-10-05, 500 USD;Фриланс",
+		int64(123),
+		mainMenu,
+	)
+	processor := exp_processor_mock.NewMockExpenseProcessor(ctrl)
+	reportRequester := report_requester_mock.NewMockReportRequester(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+
+	model := newTestModel(Deps{
+		TgClient:         sender,
+		ExpenseProcessor: processor,
+		ReportRequester:  reportRequester,
+		SettingsRepo:     settingsRepo,
+	})
+
+	err := model.IncomingMessage(ctx, Message{
+		Command:          addIncomeCommand,
+		CommandArguments: "150000",
+		UserID:           123,
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestSendReportShouldShowIncomeAndBalance(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	sender := msgmocks.NewMockMessageSender(ctrl)
+	sender.EXPECT().SendPhoto(
+		"Бюджет за 2022-10-01..2022-10-31:\n"+
+			"🟥 Дом - 1200.50 RUB, 100.0%\n"+
+			"Итого: 1200.50 RUB\n"+
+			"\nДоходы: 150000.00 RUB\nРасходы: 1200.50 RUB\nБаланс: +148799.50 RUB\n",
+		int64(123),
+		gomock.Any(),
+	)
+	processor := exp_processor_mock.NewMockExpenseProcessor(ctrl)
+	reportRequester := report_requester_mock.NewMockReportRequester(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+	settingsRepo.EXPECT().GetSettings(gomock.Any(), int64(123)).Return(model.UserSettings{
+		UserID:   123,
+		Currency: "RUB",
+		Timezone: "UTC",
+	}, true, nil)
+
+	messages := newTestModel(Deps{
+		TgClient:         sender,
+		ExpenseProcessor: processor,
+		ReportRequester:  reportRequester,
+		SettingsRepo:     settingsRepo,
+	})
+
+	from := time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC)
+	err := messages.SendReport(ctx, &expense_reporter.ExpenseReport{
+		Rows:          []expense_reporter.ReportRow{{Category: "Дом", Amount: 1200.50, Share: 100}},
+		UserID:        123,
+		Period:        model.Custom,
+		Range:         model.NewDateRange(from, from.AddDate(0, 1, 0)),
+		TotalIncome:   150000,
+		TotalExpenses: 1200.50,
+		Balance:       148799.50,
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestSendReportShouldCompareWithPreviousPeriodAndAttachChart(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	sender := msgmocks.NewMockMessageSender(ctrl)
+	sender.EXPECT().SendPhoto(
+		"Бюджет за 2022-10-01..2022-10-03:\n"+
+			"🟥 Дом - 900.00 RUB, 29.0% (+150.00), лимит 90% из 1000.00\n"+
+			"🟧 Кафе - 500.00 RUB, 16.1% (-100.00)\n"+
+			"🟨 Такси - 500.00 RUB, 16.1% (+500.00)\n"+
+			"🟩 Аптека - 400.00 RUB, 12.9% (+0.00)\n"+
+			"🟦 Кино - 300.00 RUB, 9.7% (+0.00)\n"+
+			"🟪 Книги - 200.00 RUB, 6.5% (+0.00)\n"+
+			"🟫 Связь - 150.00 RUB, 4.8% (+0.00)\n"+
+			"⬛ Спорт - 100.00 RUB, 3.2% (+0.00)\n"+
+			"⬛ Цветы - 50.00 RUB, 1.6% (+0.00), лимит 125% из 40.00\n"+
+			"Итого: 3100.00 RUB (+550.00)\n"+
+			"В скобках - изменение к 2022-09-28..2022-09-30\n"+
+			"\nДоходы: 0.00 RUB\nРасходы: 3100.00 RUB\nБаланс: -3100.00 RUB\n",
+		int64(-100),
+		gomock.Any(),
+	).DoAndReturn(func(caption string, chatID int64, file model.File) error {
+		assert.Equal(t, "report.png", file.Name)
+		assert.NotEmpty(t, file.Data)
+		return nil
+	})
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+	settingsRepo.EXPECT().GetSettings(gomock.Any(), int64(123)).Return(model.UserSettings{
+		UserID:   123,
+		Currency: "RUB",
+		Timezone: "UTC",
+	}, true, nil)
+
+	messages := newTestModel(Deps{
+		TgClient:     sender,
+		SettingsRepo: settingsRepo,
+	})
+
+	from := time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC)
+	err := messages.SendReport(ctx, &expense_reporter.ExpenseReport{
+		Rows: []expense_reporter.ReportRow{
+			{Category: "Дом", Amount: 900, Share: 29.03, Previous: 750, Limit: 1000, LimitUsage: 90},
+			{Category: "Кафе", Amount: 500, Share: 16.13, Previous: 600},
+			{Category: "Такси", Amount: 500, Share: 16.13},
+			{Category: "Аптека", Amount: 400, Share: 12.9, Previous: 400},
+			{Category: "Кино", Amount: 300, Share: 9.68, Previous: 300},
+			{Category: "Книги", Amount: 200, Share: 6.45, Previous: 200},
+			{Category: "Связь", Amount: 150, Share: 4.84, Previous: 150},
+			{Category: "Спорт", Amount: 100, Share: 3.23, Previous: 100},
+			{Category: "Цветы", Amount: 50, Share: 1.61, Previous: 50, Limit: 40, LimitUsage: 125},
+		},
+		UserID:                123,
+		ChatID:                -100,
+		Period:                model.Custom,
+		Range:                 model.NewDateRange(from, from.AddDate(0, 0, 3)),
+		TotalExpenses:         3100,
+		Balance:               -3100,
+		PreviousRange:         model.NewDateRange(from.AddDate(0, 0, -3), from),
+		PreviousTotalExpenses: 2550,
+		Timeline: []expense_reporter.TimelinePoint{
+			{From: from, Amount: 1000},
+			{From: from.AddDate(0, 0, 1), Amount: 0},
+			{From: from.AddDate(0, 0, 2), Amount: 2100},
+		},
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestSendReportShouldSendTextWithoutExpenses(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	sender := msgmocks.NewMockMessageSender(ctrl)
+	sender.EXPECT().SendMessage(
+		"Бюджет за 2022-10-01..2022-10-31:\n"+
+			"\nДоходы: 1000.00 RUB\nРасходы: 0.00 RUB\nБаланс: +1000.00 RUB\n",
+		int64(123),
+		mainMenu,
+	)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+	settingsRepo.EXPECT().GetSettings(gomock.Any(), int64(123)).Return(model.UserSettings{
+		UserID:   123,
+		Currency: "RUB",
+		Timezone: "UTC",
+	}, true, nil)
+
+	messages := newTestModel(Deps{
+		TgClient:     sender,
+		SettingsRepo: settingsRepo,
+	})
+
+	from := time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC)
+	err := messages.SendReport(ctx, &expense_reporter.ExpenseReport{
+		UserID:      123,
+		Period:      model.Custom,
+		Range:       model.NewDateRange(from, from.AddDate(0, 1, 0)),
+		TotalIncome: 1000,
+		Balance:     1000,
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestOnGetExpensesForAccountShouldRequestAccountReport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	sender := msgmocks.NewMockMessageSender(ctrl)
+	sender.EXPECT().SendMessage("Запрос на формирование отчета отправлен", int64(123), mainMenu)
+	processor := exp_processor_mock.NewMockExpenseProcessor(ctrl)
+	processor.EXPECT().GetBalances(gomock.Any(), int64(123)).Return([]expense_processor.AccountBalance{
+		{Name: "Карта", Currency: "RUB", Balance: 9650},
+	}, nil)
+	reportRequester := report_requester_mock.NewMockReportRequester(ctrl)
+	reportRequester.EXPECT().SendRequestReport(gomock.Any(), int64(123), int64(123), int64(123), model.Month, gomock.Any(), "RUB", "Карта")
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+	settingsRepo.EXPECT().GetSettings(gomock.Any(), int64(123)).Return(model.UserSettings{}, false, nil)
+
+	model := newTestModel(Deps{
+		TgClient:         sender,
+		ExpenseProcessor: processor,
+		ReportRequester:  reportRequester,
+		SettingsRepo:     settingsRepo,
+	})
+
+	err := model.IncomingMessage(ctx, Message{
+		Command:          getExpensesCommand,
+		CommandArguments: "month;карта",
+		UserID:           123,
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestOnTransferShouldAnswerWithSuccessMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	date := time.Date(2022, 10, 5, 0, 0, 0, 0, time.UTC)
+
+	sender := msgmocks.NewMockMessageSender(ctrl)
+	sender.EXPECT().SendMessage("Переведено 5000.00 RUB со счета Карта на счет Накопления", int64(123), mainMenu)
+	processor := exp_processor_mock.NewMockExpenseProcessor(ctrl)
+	processor.EXPECT().Transfer(gomock.Any(), "Карта", "Накопления", 5000.0, "RUB", date, int64(123))
+	reportRequester := report_requester_mock.NewMockReportRequester(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+	settingsRepo.EXPECT().GetSettings(gomock.Any(), int64(123)).Return(model.UserSettings{
+		UserID:   123,
+		Currency: "RUB",
+		Timezone: "UTC",
+	}, true, nil)
+
+	model := newTestModel(Deps{
+		TgClient:         sender,
+		ExpenseProcessor: processor,
+		ReportRequester:  reportRequester,
+		SettingsRepo:     settingsRepo,
+	})
+
+	err := model.IncomingMessage(ctx, Message{
+		Command:          transferCommand,
+		CommandArguments: "5000;Карта;Накопления;2022-10-05",
+		UserID:           123,
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestOnBalancesShouldListAccountBalances(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	sender := msgmocks.NewMockMessageSender(ctrl)
+	sender.EXPECT().SendMessage(
+		"Остатки на счетах:\nКарта - 9650.00 RUB\nНаличные - 120.50 USD\n",
+		int64(123),
+		mainMenu,
+	)
+	processor := exp_processor_mock.NewMockExpenseProcessor(ctrl)
+	processor.EXPECT().GetBalances(gomock.Any(), int64(123)).Return([]expense_processor.AccountBalance{
+		{Name: "Карта", Currency: "RUB", Balance: 9650},
+		{Name: "Наличные", Currency: "USD", Balance: 120.50},
+	}, nil)
+	reportRequester := report_requester_mock.NewMockReportRequester(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+
+	model := newTestModel(Deps{
+		TgClient:         sender,
+		ExpenseProcessor: processor,
+		ReportRequester:  reportRequester,
+		SettingsRepo:     settingsRepo,
+	})
+
+	err := model.IncomingMessage(ctx, Message{
+		Command: balancesCommand,
+		UserID:  123,
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestOnJoinShouldAddExpenseToSharedBudget(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	budgetsRepo := memoryrepo.NewBudgetsRepository()
+	budget, err := budgetsRepo.AddBudget(
+		ctx,
+		model.Budget{Name: "Семья", JoinCode: "ABCD1234", OwnerId: 100},
+		model.BudgetMember{UserID: 100, Name: "alice", Role: model.OwnerBudgetRole},
+	)
+	assert.NoError(t, err)
+
+	date := time.Date(2022, 10, 1, 12, 56, 0, 0, time.UTC)
+
+	sender := msgmocks.NewMockMessageSender(ctrl)
+	sender.EXPECT().SendMessage(
+		"Вы участник бюджета Семья с ролью editor. Траты, лимиты и отчеты теперь общие, "+
+			"вернуться к личному бюджету: /switchBudget personal",
+		int64(200),
+		mainMenu,
+	)
+	sender.EXPECT().SendMessage("Трата 125.50 RUB добавлена в категорию Кофе с датой 2022-10-01 12:56:00", int64(200), mainMenu)
+	processor := exp_processor_mock.NewMockExpenseProcessor(ctrl)
+	processor.EXPECT().AddExpense(gomock.Any(), 125.50, "RUB", "Кофе", "", date, budget.ID, int64(200)).
+		Return(&model.Expense{Category: "Кофе"}, nil)
+	processor.EXPECT().GetFreeLimits(gomock.Any(), "Кофе", budget.ID, gomock.Any(), gomock.Any())
+	reportRequester := report_requester_mock.NewMockReportRequester(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+	settingsRepo.EXPECT().GetSettings(gomock.Any(), int64(200)).Return(model.UserSettings{
+		UserID:   200,
+		Currency: "RUB",
+		Timezone: "UTC",
+	}, true, nil)
+
+	model := newTestModel(Deps{
+		TgClient:         sender,
+		ExpenseProcessor: processor,
+		ReportRequester:  reportRequester,
+		SettingsRepo:     settingsRepo,
+		BudgetsRepo:      budgetsRepo,
+	})
+
+	err = model.IncomingMessage(ctx, Message{
+		Command:          joinBudgetCommand,
+		CommandArguments: "abcd1234",
+		UserID:           200,
+		UserName:         "bob",
+	})
+	assert.NoError(t, err)
+
+	err = model.IncomingMessage(ctx, Message{
+		Command:          addExpenseCommand,
+		CommandArguments: "125.50;Кофе;2022-10-01 12:56:00",
+		UserID:           200,
+		UserName:         "bob",
+	})
+	assert.NoError(t, err)
+}
+
+func TestViewerShouldNotAddExpenseToSharedBudget(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	budgetsRepo := memoryrepo.NewBudgetsRepository()
+	budget, err := budgetsRepo.AddBudget(
+		ctx,
+		model.Budget{Name: "Семья", JoinCode: "ABCD1234", OwnerId: 100},
+		model.BudgetMember{UserID: 100, Name: "alice", Role: model.OwnerBudgetRole},
+	)
+	assert.NoError(t, err)
+	assert.NoError(t, budgetsRepo.SaveMember(ctx, model.BudgetMember{BudgetID: budget.ID, UserID: 200, Name: "bob", Role: model.ViewerBudgetRole}))
+	assert.NoError(t, budgetsRepo.SetActiveBudget(ctx, 200, budget.ID))
+
+	sender := msgmocks.NewMockMessageSender(ctrl)
+	sender.EXPECT().SendMessage(
+		"недостаточно прав: в активном бюджете у вас роль viewer, доступны только отчеты",
+		int64(200),
+		mainMenu,
+	)
+	processor := exp_processor_mock.NewMockExpenseProcessor(ctrl)
+	reportRequester := report_requester_mock.NewMockReportRequester(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+
+	model := newTestModel(Deps{
+		TgClient:         sender,
+		ExpenseProcessor: processor,
+		ReportRequester:  reportRequester,
+		SettingsRepo:     settingsRepo,
+		BudgetsRepo:      budgetsRepo,
+	})
+
+	err = model.IncomingMessage(ctx, Message{
+		Command:          addExpenseCommand,
+		CommandArguments: "125.50;Кофе",
+		UserID:           200,
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestOnSetRoleByEditorShouldAnswerWithFailMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	budgetsRepo := memoryrepo.NewBudgetsRepository()
+	budget, err := budgetsRepo.AddBudget(
+		ctx,
+		model.Budget{Name: "Семья", JoinCode: "ABCD1234", OwnerId: 100},
+		model.BudgetMember{UserID: 100, Name: "alice", Role: model.OwnerBudgetRole},
+	)
+	assert.NoError(t, err)
+	assert.NoError(t, budgetsRepo.SaveMember(ctx, model.BudgetMember{BudgetID: budget.ID, UserID: 200, Name: "bob", Role: model.EditorBudgetRole}))
+	assert.NoError(t, budgetsRepo.SetActiveBudget(ctx, 200, budget.ID))
+
+	sender := msgmocks.NewMockMessageSender(ctrl)
+	sender.EXPECT().SendMessage("управлять участниками может только владелец бюджета", int64(200), mainMenu)
+	processor := exp_processor_mock.NewMockExpenseProcessor(ctrl)
+	reportRequester := report_requester_mock.NewMockReportRequester(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+
+	model := newTestModel(Deps{
+		TgClient:         sender,
+		ExpenseProcessor: processor,
+		ReportRequester:  reportRequester,
+		SettingsRepo:     settingsRepo,
+		BudgetsRepo:      budgetsRepo,
+	})
+
+	err = model.IncomingMessage(ctx, Message{
+		Command:          setBudgetRoleCommand,
+		CommandArguments: "@alice;viewer",
+		UserID:           200,
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestInGroupChatShouldAddExpenseToChatBudgetAndAnswerToChat(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	chatId := int64(-500)
+
+	date := time.Date(2022, 10, 1, 12, 56, 0, 0, time.UTC)
+
+	sender := msgmocks.NewMockMessageSender(ctrl)
+	sender.EXPECT().SendMessage("Трата 125.50 RUB добавлена в категорию Кофе с датой 2022-10-01 12:56:00", chatId, mainMenu)
+	processor := exp_processor_mock.NewMockExpenseProcessor(ctrl)
+	processor.EXPECT().AddExpense(gomock.Any(), 125.50, "RUB", "Кофе", "", date, chatId, int64(100)).
+		Return(&model.Expense{Category: "Кофе"}, nil)
+	processor.EXPECT().GetFreeLimits(gomock.Any(), "Кофе", chatId, gomock.Any(), gomock.Any())
+	reportRequester := report_requester_mock.NewMockReportRequester(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+	settingsRepo.EXPECT().GetSettings(gomock.Any(), int64(100)).Return(model.UserSettings{
+		UserID:   100,
+		Currency: "RUB",
+		Timezone: "UTC",
+	}, true, nil)
+	budgetsRepo := memoryrepo.NewBudgetsRepository()
+
+	model := newTestModel(Deps{
+		TgClient:         sender,
+		ExpenseProcessor: processor,
+		ReportRequester:  reportRequester,
+		SettingsRepo:     settingsRepo,
+		BudgetsRepo:      budgetsRepo,
+	})
+
+	err := model.IncomingMessage(ctx, Message{
+		Command:          addExpenseCommand,
+		CommandArguments: "125.50;Кофе;2022-10-01 12:56:00",
+		UserID:           100,
+		ChatID:           chatId,
+		UserName:         "alice",
+	})
+	assert.NoError(t, err)
+
+	members, err := budgetsRepo.GetMembers(ctx, chatId)
+	assert.NoError(t, err)
+	assert.Len(t, members, 1)
+	assert.Equal(t, "alice", members[0].Name)
+}
+
+func TestInGroupChatShouldIgnoreTextWithoutCommand(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	sender := msgmocks.NewMockMessageSender(ctrl)
+	processor := exp_processor_mock.NewMockExpenseProcessor(ctrl)
+	reportRequester := report_requester_mock.NewMockReportRequester(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+
+	model := newTestModel(Deps{
+		TgClient:         sender,
+		ExpenseProcessor: processor,
+		ReportRequester:  reportRequester,
+		SettingsRepo:     settingsRepo,
+	})
+
+	err := model.IncomingMessage(ctx, Message{
+		Text:   "всем привет",
+		UserID: 100,
+		ChatID: -500,
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestInGroupChatBudgetCommandShouldAnswerWithFailMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	sender := msgmocks.NewMockMessageSender(ctrl)
+	sender.EXPECT().SendMessage("команда доступна только в личном чате с ботом", int64(-500), mainMenu)
+	processor := exp_processor_mock.NewMockExpenseProcessor(ctrl)
+	reportRequester := report_requester_mock.NewMockReportRequester(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+
+	model := newTestModel(Deps{
+		TgClient:         sender,
+		ExpenseProcessor: processor,
+		ReportRequester:  reportRequester,
+		SettingsRepo:     settingsRepo,
+	})
+
+	err := model.IncomingMessage(ctx, Message{
+		Command:          createBudgetCommand,
+		CommandArguments: "Семья",
+		UserID:           100,
+		ChatID:           -500,
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestOnSplitShouldSplitExpenseBetweenMembers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	budgetsRepo := memoryrepo.NewBudgetsRepository()
+	budget, err := budgetsRepo.AddBudget(
+		ctx,
+		model.Budget{Name: "Семья", JoinCode: "ABCD1234", OwnerId: 100},
+		model.BudgetMember{UserID: 100, Name: "alice", Role: model.OwnerBudgetRole},
+	)
+	assert.NoError(t, err)
+	assert.NoError(t, budgetsRepo.SaveMember(ctx, model.BudgetMember{BudgetID: budget.ID, UserID: 200, Name: "bob", Role: model.EditorBudgetRole}))
+	assert.NoError(t, budgetsRepo.SaveMember(ctx, model.BudgetMember{BudgetID: budget.ID, UserID: 300, Name: "carol", Role: model.ViewerBudgetRole}))
+	assert.NoError(t, budgetsRepo.SetActiveBudget(ctx, 100, budget.ID))
+
+	date := time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	sender := msgmocks.NewMockMessageSender(ctrl)
+	sender.EXPECT().SendMessage(
+		"Трата 3000.00 RUB в категорию Ресторан разделена поровну, платил alice:\n"+
+			"alice - 1000.00 RUB\nbob - 1000.00 RUB\ncarol - 1000.00 RUB\n",
+		int64(100),
+		mainMenu,
+	)
+	processor := exp_processor_mock.NewMockExpenseProcessor(ctrl)
+	processor.EXPECT().SplitExpense(gomock.Any(), 3000.0, "RUB", "Ресторан", date, budget.ID, int64(100), []int64{100, 200, 300}).
+		Return(&model.Expense{Category: "Ресторан"}, []expense_processor.Share{
+			{UserID: 100, Amount: 1000},
+			{UserID: 200, Amount: 1000},
+			{UserID: 300, Amount: 1000},
+		}, nil)
+	reportRequester := report_requester_mock.NewMockReportRequester(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+	settingsRepo.EXPECT().GetSettings(gomock.Any(), int64(100)).Return(model.UserSettings{
+		UserID:   100,
+		Currency: "RUB",
+		Timezone: "UTC",
+	}, true, nil)
+
+	model := newTestModel(Deps{
+		TgClient:         sender,
+		ExpenseProcessor: processor,
+		ReportRequester:  reportRequester,
+		SettingsRepo:     settingsRepo,
+		BudgetsRepo:      budgetsRepo,
+	})
+
+	err = model.IncomingMessage(ctx, Message{
+		Command:          splitExpenseCommand,
+		CommandArguments: "3000;Ресторан;@bob, @carol, @alice;2022-10-01",
+		UserID:           100,
+		UserName:         "alice",
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestOnSplitInPersonalBudgetShouldAnswerWithFailMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	sender := msgmocks.NewMockMessageSender(ctrl)
+	sender.EXPECT().SendMessage(
+		"делить траты можно в общем бюджете или групповом чате. Создайте бюджет: /createBudget Семья",
+		int64(100),
+		mainMenu,
+	)
+	processor := exp_processor_mock.NewMockExpenseProcessor(ctrl)
+	reportRequester := report_requester_mock.NewMockReportRequester(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+
+	model := newTestModel(Deps{
+		TgClient:         sender,
+		ExpenseProcessor: processor,
+		ReportRequester:  reportRequester,
+		SettingsRepo:     settingsRepo,
+	})
+
+	err := model.IncomingMessage(ctx, Message{
+		Command:          splitExpenseCommand,
+		CommandArguments: "3000;Ресторан;@bob",
+		UserID:           100,
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestOnDebtsInGroupChatShouldListDebtsWithMemberNames(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	chatId := int64(-500)
+
+	budgetsRepo := memoryrepo.NewBudgetsRepository()
+	assert.NoError(t, budgetsRepo.SaveMember(ctx, model.BudgetMember{BudgetID: chatId, UserID: 100, Name: "alice", Role: model.EditorBudgetRole}))
+
+	sender := msgmocks.NewMockMessageSender(ctrl)
+	sender.EXPECT().SendMessage(
+		"Долги:\nbob -> alice: 1000.00 RUB\n300 -> alice: 500.00 RUB\nВернуть долг: /settle Участник;Сумма\n",
+		chatId,
+		mainMenu,
+	)
+	processor := exp_processor_mock.NewMockExpenseProcessor(ctrl)
+	processor.EXPECT().GetDebts(gomock.Any(), "RUB", chatId).Return([]expense_processor.Debt{
+		{FromUserID: 200, ToUserID: 100, Amount: 1000},
+		{FromUserID: 300, ToUserID: 100, Amount: 500},
+	}, nil)
+	reportRequester := report_requester_mock.NewMockReportRequester(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+	settingsRepo.EXPECT().GetSettings(gomock.Any(), int64(200)).Return(model.UserSettings{}, false, nil)
+
+	model := newTestModel(Deps{
+		TgClient:         sender,
+		ExpenseProcessor: processor,
+		ReportRequester:  reportRequester,
+		SettingsRepo:     settingsRepo,
+		BudgetsRepo:      budgetsRepo,
+	})
+
+	err := model.IncomingMessage(ctx, Message{
+		Command:  debtsCommand,
+		UserID:   200,
+		ChatID:   chatId,
+		UserName: "bob",
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestOnPhotoWithCaptionShouldAddExpenseWithReceipt(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	userId := int64(100)
+
+	sender := msgmocks.NewMockMessageSender(ctrl)
+	sender.EXPECT().DownloadFile("photo-file-id").Return([]byte("receipt"), nil)
+	sender.EXPECT().SendMessage(
+		"Трата 350.00 RUB добавлена в категорию Кафе с датой 2022-10-01 12:56:00\n"+
+			"Чек сохранен: /expense 1b4e28ba-2fa1-11d2-883f-0016d3cca427",
+		userId,
+		mainMenu,
+	)
+	processor := exp_processor_mock.NewMockExpenseProcessor(ctrl)
+	processor.EXPECT().AddExpense(gomock.Any(), 350.0, "RUB", "Кафе", "", gomock.Any(), userId, userId).
+		Return(&model.Expense{ID: "1b4e28ba-2fa1-11d2-883f-0016d3cca427", Category: "Кафе"}, nil)
+	processor.EXPECT().GetFreeLimits(gomock.Any(), "Кафе", userId, gomock.Any(), gomock.Any())
+	attachments := exp_attachments_mock.NewMockExpenseAttachments(ctrl)
+	attachments.EXPECT().Attach(gomock.Any(), "1b4e28ba-2fa1-11d2-883f-0016d3cca427", userId, model.File{
+		Name: "photo_unique.jpg", MimeType: "image/jpeg", Data: []byte("receipt"),
+	})
+	reportRequester := report_requester_mock.NewMockReportRequester(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+	settingsRepo.EXPECT().GetSettings(gomock.Any(), userId).Return(model.UserSettings{}, false, nil)
+
+	model := newTestModel(Deps{
+		TgClient:           sender,
+		ExpenseProcessor:   processor,
+		ReportRequester:    reportRequester,
+		SettingsRepo:       settingsRepo,
+		ExpenseAttachments: attachments,
+	})
+
+	err := model.IncomingMessage(ctx, Message{
+		Text:       "350;Кафе;2022-10-01 12:56:00",
+		UserID:     userId,
+		Attachment: &Attachment{FileID: "photo-file-id", FileName: "photo_unique.jpg", MimeType: "image/jpeg"},
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestOnPhotoWithAmountOnlyShouldAskForCategory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	userId := int64(100)
+
+	sender := msgmocks.NewMockMessageSender(ctrl)
+	sender.EXPECT().SendMessage("укажите в подписи к чеку сумму и категорию.\nНапример: 350;Кафе", userId, mainMenu)
+	processor := exp_processor_mock.NewMockExpenseProcessor(ctrl)
+	attachments := exp_attachments_mock.NewMockExpenseAttachments(ctrl)
+	reportRequester := report_requester_mock.NewMockReportRequester(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+	settingsRepo.EXPECT().GetSettings(gomock.Any(), userId).Return(model.UserSettings{}, false, nil)
+
+	model := newTestModel(Deps{
+		TgClient:           sender,
+		ExpenseProcessor:   processor,
+		ReportRequester:    reportRequester,
+		SettingsRepo:       settingsRepo,
+		ExpenseAttachments: attachments,
+	})
+
+	err := model.IncomingMessage(ctx, Message{
+		Text:       "350",
+		UserID:     userId,
+		Attachment: &Attachment{FileID: "photo-file-id", FileName: "photo_unique.jpg", MimeType: "image/jpeg"},
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestOnPhotoWithoutCommandInGroupChatShouldBeSkipped(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	sender := msgmocks.NewMockMessageSender(ctrl)
+	processor := exp_processor_mock.NewMockExpenseProcessor(ctrl)
+	attachments := exp_attachments_mock.NewMockExpenseAttachments(ctrl)
+	reportRequester := report_requester_mock.NewMockReportRequester(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+
+	model := newTestModel(Deps{
+		TgClient:           sender,
+		ExpenseProcessor:   processor,
+		ReportRequester:    reportRequester,
+		SettingsRepo:       settingsRepo,
+		ExpenseAttachments: attachments,
+	})
+
+	err := model.IncomingMessage(ctx, Message{
+		Text:       "350;Кафе",
+		UserID:     100,
+		ChatID:     -500,
+		Attachment: &Attachment{FileID: "photo-file-id", FileName: "photo_unique.jpg", MimeType: "image/jpeg"},
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestOnShowExpenseShouldSendReceipt(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	userId := int64(100)
+	receipt := model.File{Name: "receipt.pdf", MimeType: "application/pdf", Data: []byte("receipt")}
+
+	sender := msgmocks.NewMockMessageSender(ctrl)
+	sender.EXPECT().SendDocument("Чек траты 1b4e28ba-2fa1-11d2-883f-0016d3cca427", userId, receipt)
+	processor := exp_processor_mock.NewMockExpenseProcessor(ctrl)
+	attachments := exp_attachments_mock.NewMockExpenseAttachments(ctrl)
+	attachments.EXPECT().Get(gomock.Any(), "1b4e28ba-2fa1-11d2-883f-0016d3cca427", userId).Return(&receipt, true, nil)
+	reportRequester := report_requester_mock.NewMockReportRequester(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+
+	model := newTestModel(Deps{
+		TgClient:           sender,
+		ExpenseProcessor:   processor,
+		ReportRequester:    reportRequester,
+		SettingsRepo:       settingsRepo,
+		ExpenseAttachments: attachments,
+	})
+
+	err := model.IncomingMessage(ctx, Message{
+		Command:          showExpenseCommand,
+		CommandArguments: "1b4e28ba-2fa1-11d2-883f-0016d3cca427",
+		UserID:           userId,
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestOnShowExpenseWithoutReceiptShouldAnswerWithFailMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	userId := int64(100)
+
+	sender := msgmocks.NewMockMessageSender(ctrl)
+	sender.EXPECT().SendMessage("к трате unknown чек не прикреплен", userId, mainMenu)
+	processor := exp_processor_mock.NewMockExpenseProcessor(ctrl)
+	attachments := exp_attachments_mock.NewMockExpenseAttachments(ctrl)
+	attachments.EXPECT().Get(gomock.Any(), "unknown", userId).Return(nil, false, nil)
+	reportRequester := report_requester_mock.NewMockReportRequester(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+
+	model := newTestModel(Deps{
+		TgClient:           sender,
+		ExpenseProcessor:   processor,
+		ReportRequester:    reportRequester,
+		SettingsRepo:       settingsRepo,
+		ExpenseAttachments: attachments,
+	})
+
+	err := model.IncomingMessage(ctx, Message{
+		Command:          showExpenseCommand,
+		CommandArguments: "unknown",
+		UserID:           userId,
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestOnExportShouldRequestExportForPeriod(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	userId := int64(100)
+
+	sender := msgmocks.NewMockMessageSender(ctrl)
+	sender.EXPECT().SendMessage("Запрос на выгрузку трат отправлен, файл придет отдельным сообщением", userId, mainMenu)
+	processor := exp_processor_mock.NewMockExpenseProcessor(ctrl)
+	reportRequester := report_requester_mock.NewMockReportRequester(ctrl)
+	reportRequester.EXPECT().SendRequestExport(
+		gomock.Any(),
+		userId,
+		userId,
+		userId,
+		model.XLSXExportFormat,
+		model.NewDateRange(time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)),
+		"RUB",
+	)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+	settingsRepo.EXPECT().GetSettings(gomock.Any(), userId).Return(model.UserSettings{Currency: "RUB", Timezone: "UTC"}, true, nil)
+
+	model := newTestModel(Deps{
+		TgClient:         sender,
+		ExpenseProcessor: processor,
+		ReportRequester:  reportRequester,
+		SettingsRepo:     settingsRepo,
+	})
+
+	err := model.IncomingMessage(ctx, Message{
+		Command:          exportCommand,
+		CommandArguments: "XLSX 2022-01-01..2022-12-31",
+		UserID:           userId,
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestOnExportWithUnknownFormatShouldAnswerWithFailMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	userId := int64(100)
+
+	sender := msgmocks.NewMockMessageSender(ctrl)
+	sender.EXPECT().SendMessage("неверный формат выгрузки.\nОжидается: csv или xlsx и период, как в /getExpenses \n"+
+		"Например: /export csv month, /export xlsx 2022-01-01..2022-12-31", userId, mainMenu)
+	processor := exp_processor_mock.NewMockExpenseProcessor(ctrl)
+	reportRequester := report_requester_mock.NewMockReportRequester(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+
+	model := newTestModel(Deps{
+		TgClient:         sender,
+		ExpenseProcessor: processor,
+		ReportRequester:  reportRequester,
+		SettingsRepo:     settingsRepo,
+	})
+
+	err := model.IncomingMessage(ctx, Message{
+		Command:          exportCommand,
+		CommandArguments: "pdf month",
+		UserID:           userId,
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestSendExportShouldSendFileToChat(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	userId := int64(100)
+	chatId := int64(-500)
+	file := model.File{Name: "expenses_2022-10-01_2022-10-31.csv", MimeType: "text/csv", Data: []byte("Дата,Категория")}
+
+	sender := msgmocks.NewMockMessageSender(ctrl)
+	sender.EXPECT().SendDocument("Траты за 2022-10-01..2022-10-31: 12", chatId, file)
+	processor := exp_processor_mock.NewMockExpenseProcessor(ctrl)
+	reportRequester := report_requester_mock.NewMockReportRequester(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+	settingsRepo.EXPECT().GetSettings(gomock.Any(), userId).Return(model.UserSettings{Timezone: "Europe/Moscow"}, true, nil)
+
+	messages := newTestModel(Deps{
+		TgClient:         sender,
+		ExpenseProcessor: processor,
+		ReportRequester:  reportRequester,
+		SettingsRepo:     settingsRepo,
+	})
+
+	// диапазон приходит в UTC: начало октября по Москве - 21:00 30 сентября
+	err := messages.SendExport(ctx, &expense_exporter.ExpenseExport{
+		UserID:   userId,
+		ChatID:   chatId,
+		BudgetID: chatId,
+		Format:   "csv",
+		Range:    model.NewDateRange(time.Date(2022, 9, 30, 21, 0, 0, 0, time.UTC), time.Date(2022, 10, 31, 21, 0, 0, 0, time.UTC)),
+		Count:    12,
+		File:     file,
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestOnImportDryRunShouldAnswerWithPreviewAndRowErrors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	userId := int64(100)
+	data := []byte("Дата операции;Описание;Сумма")
+
+	sender := msgmocks.NewMockMessageSender(ctrl)
+	sender.EXPECT().DownloadFile("file-1").Return(data, nil)
+	sender.EXPECT().SendMessage("Проверка без сохранения: строк 3, будет добавлено трат 1, дублей 1, строк с ошибками 1\n"+
+		"Первые строки:\n"+
+		"2: 350.00 RUB - Кафе - 2022-10-01 12:30:00\n"+
+		"3: 120.50 RUB - Продукты - 2022-10-02 00:00:00 (дубль)\n"+
+		"Ошибки:\n"+
+		"строка 4: не удалось распознать дату вчера\n", userId, mainMenu)
+	processor := exp_processor_mock.NewMockExpenseProcessor(ctrl)
+	reportRequester := report_requester_mock.NewMockReportRequester(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+	importer := exp_importer_mock.NewMockExpenseImporter(ctrl)
+	importer.EXPECT().Import(gomock.Any(), data, expense_importer.ImportOptions{
+		UserID:   userId,
+		BudgetID: userId,
+		Mapping: expense_importer.ColumnMapping{
+			Date:     "Дата операции",
+			Amount:   "Сумма",
+			Category: "Описание",
+			Currency: "Валюта",
+		},
+		DryRun: true,
+	}).Return(&expense_importer.ImportResult{
+		DryRun:     true,
+		Rows:       3,
+		Added:      1,
+		Duplicates: 1,
+		Errors:     []expense_importer.RowError{{Row: 4, Message: "не удалось распознать дату вчера"}},
+		Preview: []expense_importer.ImportedRow{
+			{Row: 2, Datetime: time.Date(2022, 10, 1, 12, 30, 0, 0, time.UTC), Amount: 350, Currency: "RUB", Category: "Кафе"},
+			{Row: 3, Datetime: time.Date(2022, 10, 2, 0, 0, 0, 0, time.UTC), Amount: 120.5, Currency: "RUB", Category: "Продукты", Duplicate: true},
+		},
+	}, nil)
+
+	messages := newTestModel(Deps{
+		TgClient:         sender,
+		ExpenseProcessor: processor,
+		ReportRequester:  reportRequester,
+		SettingsRepo:     settingsRepo,
+		ExpenseImporter:  importer,
+	})
+
+	err := messages.IncomingMessage(ctx, Message{
+		Command:          importCommand,
+		CommandArguments: "dry date=Дата операции;category=Описание",
+		UserID:           userId,
+		Attachment:       &Attachment{FileID: "file-1", FileName: "statement.csv", MimeType: "text/csv"},
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestOnImportWithoutFileShouldAnswerWithFailMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	userId := int64(100)
+
+	sender := msgmocks.NewMockMessageSender(ctrl)
+	sender.EXPECT().SendMessage("прикрепите к команде csv файл: документ с подписью /import.\nКолонки по-умолчанию как в /export, "+
+		"свои задаются так: /import date=Дата операции;amount=Сумма;category=Описание", userId, mainMenu)
+	processor := exp_processor_mock.NewMockExpenseProcessor(ctrl)
+	reportRequester := report_requester_mock.NewMockReportRequester(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+	importer := exp_importer_mock.NewMockExpenseImporter(ctrl)
+
+	messages := newTestModel(Deps{
+		TgClient:         sender,
+		ExpenseProcessor: processor,
+		ReportRequester:  reportRequester,
+		SettingsRepo:     settingsRepo,
+		ExpenseImporter:  importer,
+	})
+
+	err := messages.IncomingMessage(ctx, Message{
+		Command: importCommand,
+		UserID:  userId,
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestImportExpensesShouldRejectViewerOfActiveBudget(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	budgetsRepo := memoryrepo.NewBudgetsRepository()
+	budget, err := budgetsRepo.AddBudget(
+		ctx,
+		model.Budget{Name: "Семья", JoinCode: "ABCD1234", OwnerId: 100},
+		model.BudgetMember{UserID: 100, Name: "alice", Role: model.OwnerBudgetRole},
+	)
+	assert.NoError(t, err)
+	assert.NoError(t, budgetsRepo.SaveMember(ctx, model.BudgetMember{BudgetID: budget.ID, UserID: 200, Name: "bob", Role: model.ViewerBudgetRole}))
+	assert.NoError(t, budgetsRepo.SetActiveBudget(ctx, 200, budget.ID))
+
+	sender := msgmocks.NewMockMessageSender(ctrl)
+	processor := exp_processor_mock.NewMockExpenseProcessor(ctrl)
+	reportRequester := report_requester_mock.NewMockReportRequester(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+	importer := exp_importer_mock.NewMockExpenseImporter(ctrl)
+
+	tokensRepo := memoryrepo.NewImportTokensRepository()
+	assert.NoError(t, tokensRepo.SaveToken(ctx, 200, hashImportToken("token-200")))
+
+	messages := newTestModel(Deps{
+		TgClient:         sender,
+		ExpenseProcessor: processor,
+		ReportRequester:  reportRequester,
+		SettingsRepo:     settingsRepo,
+		BudgetsRepo:      budgetsRepo,
+		ExpenseImporter:  importer,
+		ImportTokensRepo: tokensRepo,
+	})
+
+	_, err = messages.ImportExpenses(ctx, "token-200", 0, []byte("Дата,Категория,Сумма"), "", false)
+	assert.EqualError(t, err, "недостаточно прав: в активном бюджете у вас роль viewer, доступны только отчеты")
+}
+
+func TestOnImportTokenShouldIssueTokenForHTTPImport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	userId := int64(100)
+	data := []byte("Дата,Категория,Сумма")
+
+	var answer string
+	sender := msgmocks.NewMockMessageSender(ctrl)
+	sender.EXPECT().SendMessage(gomock.Any(), userId, mainMenu).DoAndReturn(func(text string, _ int64, _ []string) error {
+		answer = text
+		return nil
+	})
+	processor := exp_processor_mock.NewMockExpenseProcessor(ctrl)
+	reportRequester := report_requester_mock.NewMockReportRequester(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+	importer := exp_importer_mock.NewMockExpenseImporter(ctrl)
+	importer.EXPECT().Import(gomock.Any(), data, expense_importer.ImportOptions{
+		UserID:   userId,
+		BudgetID: userId,
+		Mapping:  expense_importer.DefaultColumnMapping,
+	}).Return(&expense_importer.ImportResult{}, nil)
+
+	messages := newTestModel(Deps{
+		TgClient:         sender,
+		ExpenseProcessor: processor,
+		ReportRequester:  reportRequester,
+		SettingsRepo:     settingsRepo,
+		ExpenseImporter:  importer,
+		ImportTokensRepo: memoryrepo.NewImportTokensRepository(),
+	})
+
+	err := messages.IncomingMessage(ctx, Message{Command: importTokenCommand, UserID: userId})
+	assert.NoError(t, err)
+
+	token := regexp.MustCompile(`[0-9a-f]{48}`).FindString(answer)
+	assert.NotEmpty(t, token)
+
+	_, err = messages.ImportExpenses(ctx, token, 0, data, "", false)
+	assert.NoError(t, err)
+
+	_, err = messages.ImportExpenses(ctx, "", 0, data, "", false)
+	assert.ErrorIs(t, err, ErrInvalidImportToken)
+
+	_, err = messages.ImportExpenses(ctx, "unknown", 0, data, "", false)
+	assert.ErrorIs(t, err, ErrInvalidImportToken)
+}
+
+func TestImportExpensesShouldRejectUserOutsideGroupChat(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	chatId := int64(-100)
+	data := []byte("Дата,Категория,Сумма")
+
+	budgetsRepo := memoryrepo.NewBudgetsRepository()
+	assert.NoError(t, budgetsRepo.SaveMember(ctx, model.BudgetMember{BudgetID: chatId, UserID: 200, Name: "bob", Role: model.EditorBudgetRole}))
+
+	tokensRepo := memoryrepo.NewImportTokensRepository()
+	assert.NoError(t, tokensRepo.SaveToken(ctx, 200, hashImportToken("token-200")))
+	assert.NoError(t, tokensRepo.SaveToken(ctx, 300, hashImportToken("token-300")))
+
+	sender := msgmocks.NewMockMessageSender(ctrl)
+	processor := exp_processor_mock.NewMockExpenseProcessor(ctrl)
+	reportRequester := report_requester_mock.NewMockReportRequester(ctrl)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+	importer := exp_importer_mock.NewMockExpenseImporter(ctrl)
+	importer.EXPECT().Import(gomock.Any(), data, expense_importer.ImportOptions{
+		UserID:   200,
+		BudgetID: chatId,
+		Mapping:  expense_importer.DefaultColumnMapping,
+	}).Return(&expense_importer.ImportResult{}, nil)
+
+	messages := newTestModel(Deps{
+		TgClient:         sender,
+		ExpenseProcessor: processor,
+		ReportRequester:  reportRequester,
+		SettingsRepo:     settingsRepo,
+		BudgetsRepo:      budgetsRepo,
+		ExpenseImporter:  importer,
+		ImportTokensRepo: tokensRepo,
+	})
+
+	// бот запомнил участника по команде в группе
+	_, err := messages.ImportExpenses(ctx, "token-200", chatId, data, "", false)
+	assert.NoError(t, err)
+
+	_, err = messages.ImportExpenses(ctx, "token-300", chatId, data, "", false)
+	assert.EqualError(t, err, "вы не участник группы -100: отправьте боту команду в группе, чтобы он вас запомнил")
+}
+
+func TestOnTrendShouldRequestTrendByCategories(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	userId := int64(100)
+	settings := model.UserSettings{Currency: "RUB", Timezone: "UTC", WeekStart: time.Sunday}
+
+	sender := msgmocks.NewMockMessageSender(ctrl)
+	sender.EXPECT().SendMessage("Запрос на формирование отчета отправлен", userId, mainMenu)
+	reportRequester := report_requester_mock.NewMockReportRequester(ctrl)
+	reportRequester.EXPECT().SendRequestTrend(
+		gomock.Any(),
+		userId,
+		userId,
+		userId,
+		model.WeekTrendBucket,
+		true,
+		model.NewDateRange(time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC), time.Date(2022, 11, 1, 0, 0, 0, 0, time.UTC)),
+		settings,
+	)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+	settingsRepo.EXPECT().GetSettings(gomock.Any(), userId).Return(settings, true, nil)
+
+	model := newTestModel(Deps{
+		TgClient:        sender,
+		ReportRequester: reportRequester,
+		SettingsRepo:    settingsRepo,
+	})
+
+	err := model.IncomingMessage(ctx, Message{
+		Command:          trendCommand,
+		CommandArguments: "2022-10-01..2022-10-31 Week; categories",
+		UserID:           userId,
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestOnTrendWithTooManyBucketsShouldAnswerWithFailMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	userId := int64(100)
+
+	sender := msgmocks.NewMockMessageSender(ctrl)
+	sender.EXPECT().SendMessage("слишком много интервалов, больше 62. Выберите интервал крупнее или период короче", userId, mainMenu)
+	sender.EXPECT().SendMessage("неверные параметры.\nОжидается: период, как в /getExpenses, интервал day, week или month "+
+		"и ;categories для разбивки по категориям\nНапример: /trend month day, /trend year month;categories", userId, mainMenu)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+	settingsRepo.EXPECT().GetSettings(gomock.Any(), userId).Return(model.UserSettings{Currency: "RUB", Timezone: "UTC"}, true, nil)
+
+	model := newTestModel(Deps{
+		TgClient:     sender,
+		SettingsRepo: settingsRepo,
+	})
+
+	err := model.IncomingMessage(ctx, Message{
+		Command:          trendCommand,
+		CommandArguments: "year day",
+		UserID:           userId,
+	})
+	assert.NoError(t, err)
+
+	err = model.IncomingMessage(ctx, Message{
+		Command:          trendCommand,
+		CommandArguments: "month hour",
+		UserID:           userId,
+	})
+	assert.NoError(t, err)
+}
+
+func TestSendTrendShouldSendChartWithBuckets(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	userId := int64(100)
+	chatId := int64(-500)
+
+	sender := msgmocks.NewMockMessageSender(ctrl)
+	sender.EXPECT().SendPhoto(
+		"Динамика расходов за 2022-10-01..2022-10-03 по дням:\n"+
+			"2022-10-01 - 100.00 RUB\n"+
+			"2022-10-02 - 0.00 RUB\n"+
+			"2022-10-03 - 50.50 RUB\n"+
+			"Итого: 150.50 RUB\n"+
+			"В среднем за интервал: 50.17 RUB\n",
+		chatId,
+		gomock.Any(),
+	)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+	settingsRepo.EXPECT().GetSettings(gomock.Any(), userId).Return(model.UserSettings{Currency: "RUB", Timezone: "Europe/Moscow"}, true, nil)
+
+	messages := newTestModel(Deps{
+		TgClient:     sender,
+		SettingsRepo: settingsRepo,
+	})
+
+	// даты приходят в UTC: начало суток по Москве - 21:00 предыдущего дня
+	from := time.Date(2022, 9, 30, 21, 0, 0, 0, time.UTC)
+	err := messages.SendTrend(ctx, &expense_reporter.TrendReport{
+		UserID:   userId,
+		ChatID:   chatId,
+		BudgetID: chatId,
+		Range:    model.NewDateRange(from, from.AddDate(0, 0, 3)),
+		Bucket:   model.DayTrendBucket,
+		Points: []expense_reporter.TrendReportPoint{
+			{From: from, Amount: 100},
+			{From: from.AddDate(0, 0, 1)},
+			{From: from.AddDate(0, 0, 2), Amount: 50.5},
+		},
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestSendTrendByCategoryShouldGroupCategoriesOfBucket(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	userId := int64(100)
+
+	sender := msgmocks.NewMockMessageSender(ctrl)
+	sender.EXPECT().SendPhoto(
+		"Динамика расходов за 2022-10-01..2022-12-31 по месяцам:\n"+
+			"2022-10: Дом 200.00, Кафе 100.00\n"+
+			"2022-12: Кафе 50.00\n"+
+			"Итого: 350.00 RUB\n",
+		userId,
+		gomock.Any(),
+	)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+	settingsRepo.EXPECT().GetSettings(gomock.Any(), userId).Return(model.UserSettings{Currency: "RUB", Timezone: "UTC"}, true, nil)
+
+	messages := newTestModel(Deps{
+		TgClient:     sender,
+		SettingsRepo: settingsRepo,
+	})
+
+	from := time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC)
+	err := messages.SendTrend(ctx, &expense_reporter.TrendReport{
+		UserID:     userId,
+		BudgetID:   userId,
+		Range:      model.NewDateRange(from, from.AddDate(0, 3, 0)),
+		Bucket:     model.MonthTrendBucket,
+		ByCategory: true,
+		Points: []expense_reporter.TrendReportPoint{
+			{From: from, Category: "Дом", Amount: 200},
+			{From: from, Category: "Кафе", Amount: 100},
+			{From: from.AddDate(0, 2, 0), Category: "Кафе", Amount: 50},
+		},
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestSendTrendWithoutExpensesShouldSendText(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	userId := int64(100)
+
+	sender := msgmocks.NewMockMessageSender(ctrl)
+	sender.EXPECT().SendMessage("Динамика расходов за 2022-10-01..2022-10-31 по неделям:\nпусто\n", userId, mainMenu)
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+	settingsRepo.EXPECT().GetSettings(gomock.Any(), userId).Return(model.UserSettings{Currency: "RUB", Timezone: "UTC"}, true, nil)
+
+	messages := newTestModel(Deps{
+		TgClient:     sender,
+		SettingsRepo: settingsRepo,
+	})
+
+	from := time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC)
+	err := messages.SendTrend(ctx, &expense_reporter.TrendReport{
+		UserID:     userId,
+		BudgetID:   userId,
+		Range:      model.NewDateRange(from, from.AddDate(0, 1, 0)),
+		Bucket:     model.WeekTrendBucket,
+		ByCategory: true,
+		Points:     []expense_reporter.TrendReportPoint{},
+	})
+
+	assert.NoError(t, err)
+}
@@ -0,0 +1,63 @@
+package servicemessages
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/model"
+)
+
+const (
+	listExpensesLimit = 20
+)
+
+func (m *Model) listExpenses(ctx context.Context, msg Message) (string, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "listExpenses")
+	defer span.Finish()
+
+	settings, err := m.getUserSettings(ctx, msg.UserID)
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now().In(settings.Location())
+	expPeriod, dateRange, err := parseReportPeriod(msg.CommandArguments, now, settings.WeekStart)
+	if err != nil {
+		return "", err
+	}
+
+	if expPeriod != model.Custom {
+		dateRange = getPeriodRange(expPeriod, settings, now)
+	}
+
+	items, err := m.expenseProcessor.ListExpenses(ctx, dateRange, settings.Currency, msg.BudgetID)
+	if err != nil {
+		return "", err
+	}
+
+	if len(items) == 0 {
+		return msgNoExpenses, nil
+	}
+
+	// время трат - показания часов пользователя, пояс у прочитанного из базы значения не его
+	location := settings.Location()
+	var list strings.Builder
+	list.WriteString(fmt.Sprintf("Траты за %s:\n", dateRange.String()))
+
+	for i, item := range items {
+		if i == listExpensesLimit {
+			list.WriteString(fmt.Sprintf(msgMoreExpenses, len(items)-listExpensesLimit))
+			break
+		}
+
+		list.WriteString(fmt.Sprintf(
+			"%s\n%.02f %s - %s - %s\n",
+			item.ID, item.Amount, settings.Currency, item.Category, model.WallClock(item.Datetime, location).Format(datetimeFormat),
+		))
+	}
+
+	return list.String(), nil
+}
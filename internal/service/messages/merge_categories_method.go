@@ -0,0 +1,35 @@
+package servicemessages
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+)
+
+func (m *Model) mergeCategories(ctx context.Context, msg Message) (string, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "mergeCategories")
+	defer span.Finish()
+
+	from, to, err := parseCategoryPair(msg.CommandArguments, errMergeCategoriesInvalidParameterMessage)
+	if err != nil {
+		return "", err
+	}
+
+	if strings.EqualFold(from, to) {
+		return "", errors.New(errMergeSameCategory)
+	}
+
+	found, err := m.expenseProcessor.MergeCategories(ctx, msg.BudgetID, from, to)
+	if err != nil {
+		return "", err
+	}
+
+	if !found {
+		return "", fmt.Errorf(errCategoriesNotFound, from, to)
+	}
+
+	return fmt.Sprintf(msgCategoriesMerged, from, to), nil
+}
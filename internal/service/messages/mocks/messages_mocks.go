@@ -8,6 +8,7 @@ import (
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
+	model "gitlab.ozon.dev/cranky4/tg-bot/internal/model"
 )
 
 // MockMessageSender is a mock of MessageSender interface.
@@ -33,6 +34,49 @@ func (m *MockMessageSender) EXPECT() *MockMessageSenderMockRecorder {
 	return m.recorder
 }
 
+// DownloadFile mocks base method.
+func (m *MockMessageSender) DownloadFile(fileID string) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DownloadFile", fileID)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DownloadFile indicates an expected call of DownloadFile.
+func (mr *MockMessageSenderMockRecorder) DownloadFile(fileID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DownloadFile", reflect.TypeOf((*MockMessageSender)(nil).DownloadFile), fileID)
+}
+
+// SendDocument mocks base method.
+func (m *MockMessageSender) SendDocument(caption string, userID int64, file model.File) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendDocument", caption, userID, file)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendDocument indicates an expected call of SendDocument.
+func (mr *MockMessageSenderMockRecorder) SendDocument(caption, userID, file interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendDocument", reflect.TypeOf((*MockMessageSender)(nil).SendDocument), caption, userID, file)
+}
+
+// SendInlineKeyboard mocks base method.
+func (m *MockMessageSender) SendInlineKeyboard(text string, userID int64, buttons []model.InlineButton) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendInlineKeyboard", text, userID, buttons)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendInlineKeyboard indicates an expected call of SendInlineKeyboard.
+func (mr *MockMessageSenderMockRecorder) SendInlineKeyboard(text, userID, buttons interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendInlineKeyboard", reflect.TypeOf((*MockMessageSender)(nil).SendInlineKeyboard), text, userID, buttons)
+}
+
 // SendMessage mocks base method.
 func (m *MockMessageSender) SendMessage(text string, userID int64, buttons []string) error {
 	m.ctrl.T.Helper()
@@ -46,3 +90,17 @@ func (mr *MockMessageSenderMockRecorder) SendMessage(text, userID, buttons inter
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMessage", reflect.TypeOf((*MockMessageSender)(nil).SendMessage), text, userID, buttons)
 }
+
+// SendPhoto mocks base method.
+func (m *MockMessageSender) SendPhoto(caption string, userID int64, file model.File) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendPhoto", caption, userID, file)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendPhoto indicates an expected call of SendPhoto.
+func (mr *MockMessageSenderMockRecorder) SendPhoto(caption, userID, file interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendPhoto", reflect.TypeOf((*MockMessageSender)(nil).SendPhoto), caption, userID, file)
+}
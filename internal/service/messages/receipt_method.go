@@ -0,0 +1,86 @@
+package servicemessages
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/model"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/service/logger"
+)
+
+// addReceiptExpense добавляет трату из подписи к фото или документу и прикрепляет файл к трате
+func (m *Model) addReceiptExpense(ctx context.Context, msg Message) (string, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "addReceiptExpense")
+	defer span.Finish()
+
+	settings, err := m.getUserSettings(ctx, msg.UserID)
+	if err != nil {
+		return "", err
+	}
+
+	parser := newExpenseInputParser(m.currencies, time.Now().In(settings.Location()))
+	args, err := parser.parse(msg.CommandArguments)
+	if err != nil {
+		// без категории трату не сохранить, а кнопки выбора категории не знают о файле
+		if _, _, amountErr := parser.parseAmount(msg.CommandArguments); amountErr == nil || strings.Trim(msg.CommandArguments, " ") == "" {
+			return "", errors.New(errReceiptCaptionMessage)
+		}
+		return "", err
+	}
+
+	// файл скачиваем до сохранения траты, чтобы не добавить трату без чека
+	data, err := m.tgClient.DownloadFile(msg.Attachment.FileID)
+	if err != nil {
+		logger.Error(err.Error())
+		return "", errors.New(errReceiptDownloadMessage)
+	}
+
+	ex, response, err := m.saveExpense(ctx, settings, args, msg.BudgetID, msg.UserID)
+	if err != nil {
+		return "", err
+	}
+
+	// трата уже сохранена, ошибка хранилища не должна ее отменять
+	err = m.expenseAttachments.Attach(ctx, ex.ID, msg.BudgetID, model.File{
+		Name:     msg.Attachment.FileName,
+		MimeType: msg.Attachment.MimeType,
+		Data:     data,
+	})
+	if err != nil {
+		logger.Error(err.Error())
+		return response + fmt.Sprintf(msgReceiptNotSaved, err.Error()), nil
+	}
+
+	return response + fmt.Sprintf(msgReceiptSaved, ex.ID), nil
+}
+
+// showExpense отправляет файл, прикрепленный к трате
+func (m *Model) showExpense(ctx context.Context, msg Message) (string, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "showExpense")
+	defer span.Finish()
+
+	id := strings.Trim(msg.CommandArguments, " ")
+	if id == "" {
+		return "", errors.New(errShowExpenseIdMissing)
+	}
+
+	file, found, err := m.expenseAttachments.Get(ctx, id, msg.BudgetID)
+	if err != nil {
+		return "", err
+	}
+
+	if !found {
+		return "", fmt.Errorf(errReceiptNotFound, id)
+	}
+
+	if err = m.tgClient.SendDocument(fmt.Sprintf(msgReceipt, id), replyChatID(msg.ChatID, msg.UserID), *file); err != nil {
+		return "", err
+	}
+
+	// ответом служит сам файл
+	return "", errSkipMessage
+}
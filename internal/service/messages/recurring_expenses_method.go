@@ -0,0 +1,145 @@
+package servicemessages
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+)
+
+// recurringAmountMultiplier сумма регулярной траты хранится в копейках
+const recurringAmountMultiplier = 100
+
+func (m *Model) addRecurringExpense(ctx context.Context, msg Message) (string, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "addRecurringExpense")
+	defer span.Finish()
+
+	parts := strings.Split(msg.CommandArguments, expenseArgumentsSeparator)
+	if len(parts) != 3 {
+		return "", errors.New(errAddRecurringInvalidParameterMessage)
+	}
+
+	settings, err := m.getUserSettings(ctx, msg.UserID)
+	if err != nil {
+		return "", err
+	}
+
+	parser := newExpenseInputParser(m.currencies, time.Now().In(settings.Location()))
+	amount, currency, err := parser.parseAmount(parts[0])
+	if err != nil {
+		return "", err
+	}
+	if currency == "" {
+		currency = settings.Currency
+	}
+
+	category := strings.Trim(parts[1], " ")
+	if category == "" {
+		return "", errors.New(errAddRecurringInvalidParameterMessage)
+	}
+
+	schedule, err := parseSchedule(parts[2])
+	if err != nil {
+		return "", err
+	}
+
+	rec, err := m.recurringExpenses.AddRecurring(ctx, msg.BudgetID, amount, currency, category, schedule)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf(
+		msgRecurringAdded,
+		amount,
+		currency,
+		category,
+		schedule.String(),
+		rec.NextRunAt.In(settings.Location()).Format(scheduleNextRunFormat),
+		rec.ID,
+	), nil
+}
+
+func (m *Model) listRecurringExpenses(ctx context.Context, msg Message) (string, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "listRecurringExpenses")
+	defer span.Finish()
+
+	recurring, err := m.recurringExpenses.GetRecurring(ctx, msg.BudgetID)
+	if err != nil {
+		return "", err
+	}
+
+	if len(recurring) == 0 {
+		return msgNoRecurring, nil
+	}
+
+	settings, err := m.getUserSettings(ctx, msg.UserID)
+	if err != nil {
+		return "", err
+	}
+
+	var list strings.Builder
+	list.WriteString("Регулярные траты:\n")
+	for _, rec := range recurring {
+		next := "приостановлена"
+		if !rec.Paused {
+			next = fmt.Sprintf("следующая %s", rec.NextRunAt.In(settings.Location()).Format(scheduleNextRunFormat))
+		}
+
+		list.WriteString(fmt.Sprintf(
+			"%s\n%.02f %s - %s - %s, %s\n",
+			rec.ID,
+			float64(rec.Amount)/recurringAmountMultiplier,
+			rec.Currency,
+			rec.Category,
+			rec.Schedule.String(),
+			next,
+		))
+	}
+
+	return list.String(), nil
+}
+
+func (m *Model) pauseRecurringExpense(ctx context.Context, msg Message) (string, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "pauseRecurringExpense")
+	defer span.Finish()
+
+	id := strings.Trim(msg.CommandArguments, " ")
+	if id == "" {
+		return "", errors.New(errRecurringIdMissingMessage)
+	}
+
+	found, err := m.recurringExpenses.PauseRecurring(ctx, msg.BudgetID, id)
+	if err != nil {
+		return "", err
+	}
+
+	if !found {
+		return "", fmt.Errorf(errRecurringNotFound, id)
+	}
+
+	return fmt.Sprintf(msgRecurringPaused, id), nil
+}
+
+func (m *Model) resumeRecurringExpense(ctx context.Context, msg Message) (string, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "resumeRecurringExpense")
+	defer span.Finish()
+
+	id := strings.Trim(msg.CommandArguments, " ")
+	if id == "" {
+		return "", errors.New(errRecurringIdMissingMessage)
+	}
+
+	found, err := m.recurringExpenses.ResumeRecurring(ctx, msg.BudgetID, id)
+	if err != nil {
+		return "", err
+	}
+
+	if !found {
+		return "", fmt.Errorf(errRecurringNotFound, id)
+	}
+
+	return fmt.Sprintf(msgRecurringResumed, id), nil
+}
@@ -0,0 +1,29 @@
+package servicemessages
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/opentracing/opentracing-go"
+)
+
+func (m *Model) renameCategory(ctx context.Context, msg Message) (string, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "renameCategory")
+	defer span.Finish()
+
+	name, newName, err := parseCategoryPair(msg.CommandArguments, errRenameCategoryInvalidParameterMessage)
+	if err != nil {
+		return "", err
+	}
+
+	found, err := m.expenseProcessor.RenameCategory(ctx, msg.BudgetID, name, newName)
+	if err != nil {
+		return "", err
+	}
+
+	if !found {
+		return "", fmt.Errorf(errCategoryNotFound, name)
+	}
+
+	return fmt.Sprintf(msgCategoryRenamed, name, newName), nil
+}
@@ -0,0 +1,88 @@
+package servicemessages
+
+import (
+	"time"
+	"unicode/utf8"
+
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/model"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/service/expense_reporter"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/service/logger"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/utils/chart"
+)
+
+const (
+	reportChartFileName   = "report.png"
+	reportChartDateFormat = "02.01"
+	// photoCaptionLimit ограничение telegram на длину подписи к картинке
+	photoCaptionLimit = 1024
+)
+
+// reportRow строка отчета с цветом доли на круговой диаграмме
+type reportRow struct {
+	expense_reporter.ReportRow
+	Mark string
+}
+
+// markReportRows раскрашивает отсортированные по убыванию суммы категории цветами диаграммы.
+// Категории, которым не хватило цвета, попадают в последнюю долю "прочее"
+func markReportRows(rows []expense_reporter.ReportRow) []reportRow {
+	marked := make([]reportRow, 0, len(rows))
+	for i, row := range rows {
+		marked = append(marked, reportRow{ReportRow: row, Mark: chart.Palette[reportColorIndex(i, len(rows))].Mark})
+	}
+
+	return marked
+}
+
+// reportColorIndex индекс цвета строки i из count строк отчета
+func reportColorIndex(i, count int) int {
+	last := len(chart.Palette) - 1
+	if count > len(chart.Palette) && i >= last {
+		return last
+	}
+	return i
+}
+
+// reportChart возвращает доли категорий и расходы по времени, rows должны быть раскрашены markReportRows
+func reportChart(report *expense_reporter.ExpenseReport, rows []reportRow, location *time.Location) chart.Chart {
+	shares := make([]float64, 0, len(chart.Palette))
+	for i, row := range rows {
+		index := reportColorIndex(i, len(rows))
+		if index == len(shares) {
+			shares = append(shares, 0)
+		}
+		if row.Amount > 0 {
+			shares[index] += row.Amount
+		}
+	}
+
+	bars := make([]chart.Bar, 0, len(report.Timeline))
+	for _, point := range report.Timeline {
+		bars = append(bars, chart.Bar{
+			Label: point.From.In(location).Format(reportChartDateFormat),
+			Value: point.Amount,
+		})
+	}
+
+	return chart.Chart{Shares: shares, Bars: bars}
+}
+
+// sendChart отправляет картинку с текстом в подписи. Длинный текст уходит отдельным сообщением,
+// а если картинку нарисовать не удалось, отчет все равно доходит до пользователя текстом
+func (m *Model) sendChart(text string, chatID int64, data chart.Chart) error {
+	image, err := chart.Render(data)
+	if err != nil {
+		logger.Error(err.Error())
+		return m.tgClient.SendMessage(text, chatID, mainMenu)
+	}
+
+	file := model.File{Name: reportChartFileName, MimeType: "image/png", Data: image}
+	if utf8.RuneCountInString(text) > photoCaptionLimit {
+		if err := m.tgClient.SendPhoto("", chatID, file); err != nil {
+			return err
+		}
+		return m.tgClient.SendMessage(text, chatID, mainMenu)
+	}
+
+	return m.tgClient.SendPhoto(text, chatID, file)
+}
@@ -0,0 +1,49 @@
+package servicemessages
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/model"
+)
+
+const scheduleNextRunFormat = "2006-01-02 15:04"
+
+// parseSchedule разбирает расписание: weekly monday 09:00 или monthly 1 09:00, время необязательно
+func parseSchedule(argument string) (model.Schedule, error) {
+	parts := strings.Fields(argument)
+	if len(parts) < 2 || len(parts) > 3 {
+		return model.Schedule{}, errors.New(errInvalidScheduleMessage)
+	}
+
+	period, ok := model.ParseSchedulePeriod(parts[0])
+	if !ok {
+		return model.Schedule{}, fmt.Errorf(errUnknownSchedulePeriod, parts[0])
+	}
+
+	schedule := model.Schedule{Period: period}
+
+	if len(parts) == 3 {
+		if schedule.Hour, schedule.Minute, ok = model.ParseScheduleTime(parts[2]); !ok {
+			return model.Schedule{}, fmt.Errorf(errInvalidScheduleTime, parts[2])
+		}
+	}
+
+	if period == model.Month {
+		day, err := strconv.Atoi(parts[1])
+		if err != nil || day < 1 || day > 31 {
+			return model.Schedule{}, fmt.Errorf(errInvalidScheduleDay, parts[1])
+		}
+		schedule.Day = day
+
+		return schedule, nil
+	}
+
+	if schedule.Weekday, ok = parseWeekday(parts[1]); !ok {
+		return model.Schedule{}, fmt.Errorf(errUnknownWeekday, parts[1])
+	}
+
+	return schedule, nil
+}
@@ -8,14 +8,22 @@ import (
 )
 
 func (m *Model) setCurrency(ctx context.Context, msg Message) (string, error) {
-	span, _ := opentracing.StartSpanFromContext(ctx, "setCurrency")
+	span, ctx := opentracing.StartSpanFromContext(ctx, "setCurrency")
 	defer span.Finish()
 
 	if _, found := m.currencies[msg.CommandArguments]; !found {
 		return "", fmt.Errorf(errUnknownCurrency, msg.CommandArguments)
 	}
 
-	m.currency = msg.CommandArguments
+	settings, err := m.getUserSettings(ctx, msg.UserID)
+	if err != nil {
+		return "", err
+	}
+
+	settings.Currency = msg.CommandArguments
+	if err = m.settingsRepo.SaveSettings(ctx, settings); err != nil {
+		return "", err
+	}
 
 	return fmt.Sprintf(msgCurrencySet, msg.CommandArguments), nil
 }
@@ -0,0 +1,70 @@
+package servicemessages
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/model"
+)
+
+const (
+	disableLimitAlerts = "off"            // значение порогов, отключающее уведомления
+	totalLimitName     = "всех категорий" // название общего лимита в ответах
+)
+
+func (m *Model) setLimitAlerts(ctx context.Context, msg Message) (string, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "setLimitAlerts")
+	defer span.Finish()
+
+	parts := strings.Split(msg.CommandArguments, ";")
+
+	if len(parts) != 2 && len(parts) != 3 {
+		return "", errors.New(errSetLimitAlertsInvalidParameterMessage)
+	}
+
+	category := strings.Trim(parts[0], " ")
+	if category == "" {
+		return "", errors.New(errSetLimitAlertsInvalidParameterMessage)
+	}
+
+	trimmedThresholds := strings.Trim(parts[1], " ")
+
+	var thresholds []int
+	if !strings.EqualFold(trimmedThresholds, disableLimitAlerts) {
+		var ok bool
+		if thresholds, ok = model.ParseThresholds(trimmedThresholds); !ok || len(thresholds) == 0 {
+			return "", fmt.Errorf(errInvalidThresholds, trimmedThresholds)
+		}
+	}
+
+	period := model.Month
+	if len(parts) == 3 {
+		var err error
+		if period, err = parseLimitPeriod(parts[2]); err != nil {
+			return "", err
+		}
+	}
+
+	limitCategory, limitName := category, category
+	if category == totalLimitCategory {
+		limitCategory, limitName = "", totalLimitName
+	}
+
+	found, err := m.expenseProcessor.SetLimitThresholds(ctx, limitCategory, period, msg.BudgetID, thresholds)
+	if err != nil {
+		return "", err
+	}
+
+	if !found {
+		return "", fmt.Errorf(errLimitNotFound, strings.ToLower(period.String()), limitName)
+	}
+
+	if len(thresholds) == 0 {
+		return fmt.Sprintf(msgLimitAlertsDisabled, period.String(), limitName), nil
+	}
+
+	return fmt.Sprintf(msgLimitAlertsSet, period.String(), limitName, model.FormatThresholds(thresholds)), nil
+}
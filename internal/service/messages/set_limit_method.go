@@ -8,15 +8,19 @@ import (
 
 	"github.com/opentracing/opentracing-go"
 	"github.com/pkg/errors"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/model"
 )
 
+// totalLimitCategory категория общего лимита на все траты
+const totalLimitCategory = "*"
+
 func (m *Model) setLimit(ctx context.Context, msg Message) (string, error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "setLimit")
 	defer span.Finish()
 
 	parts := strings.Split(msg.CommandArguments, ";")
 
-	if len(parts) != 2 {
+	if len(parts) != 2 && len(parts) != 3 {
 		return "", errors.New(errSetLimitInvalidParameterMessage)
 	}
 
@@ -28,9 +32,41 @@ func (m *Model) setLimit(ctx context.Context, msg Message) (string, error) {
 		return "", fmt.Errorf(errInvalidAmountParameterMessage, trimmedAmount)
 	}
 
-	convertedAmount, err := m.expenseProcessor.SetLimit(ctx, trimmedCategory, msg.UserID, amount, m.currency)
+	period := model.Month
+	if len(parts) == 3 {
+		if period, err = parseLimitPeriod(parts[2]); err != nil {
+			return "", err
+		}
+	}
+
+	settings, err := m.getUserSettings(ctx, msg.UserID)
+	if err != nil {
+		return "", err
+	}
+
+	if trimmedCategory == totalLimitCategory {
+		convertedAmount, err := m.expenseProcessor.SetLimit(ctx, "", period, msg.BudgetID, amount, settings.Currency)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf(msgSetTotalLimit, strings.ToLower(period.String()), convertedAmount, settings.Currency), nil
+	}
+
+	convertedAmount, err := m.expenseProcessor.SetLimit(ctx, trimmedCategory, period, msg.BudgetID, amount, settings.Currency)
 	if err != nil {
 		return "", err
 	}
-	return fmt.Sprintf(msgSetLimit, convertedAmount, m.currency, trimmedCategory), nil
+	return fmt.Sprintf(msgSetLimit, strings.ToLower(period.String()), convertedAmount, settings.Currency, trimmedCategory), nil
+}
+
+// parseLimitPeriod разбирает период лимита: week, month, quarter, year
+func parseLimitPeriod(argument string) (model.ExpensePeriod, error) {
+	trimmedPeriod := strings.ToLower(strings.Trim(argument, " "))
+
+	period, ok := model.ParseLimitPeriod(trimmedPeriod)
+	if !ok {
+		return period, fmt.Errorf(errUnknownLimitPeriod, trimmedPeriod)
+	}
+
+	return period, nil
 }
@@ -0,0 +1,32 @@
+package servicemessages
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/opentracing/opentracing-go"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/model"
+)
+
+func (m *Model) setPeriodMode(ctx context.Context, msg Message) (string, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "setPeriodMode")
+	defer span.Finish()
+
+	mode := model.PeriodMode(strings.ToLower(strings.Trim(msg.CommandArguments, " ")))
+	if mode != model.RollingPeriodMode && mode != model.CalendarPeriodMode {
+		return "", fmt.Errorf(errUnknownPeriodMode, msg.CommandArguments)
+	}
+
+	settings, err := m.getUserSettings(ctx, msg.UserID)
+	if err != nil {
+		return "", err
+	}
+
+	settings.PeriodMode = mode
+	if err = m.saveUserSettings(ctx, settings); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf(msgPeriodModeSet, mode), nil
+}
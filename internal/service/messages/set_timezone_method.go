@@ -0,0 +1,41 @@
+package servicemessages
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+)
+
+func (m *Model) setTimezone(ctx context.Context, msg Message) (string, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "setTimezone")
+	defer span.Finish()
+
+	timezone := strings.Trim(msg.CommandArguments, " ")
+	if timezone == "" {
+		return "", fmt.Errorf(errUnknownTimezone, timezone)
+	}
+
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return "", fmt.Errorf(errUnknownTimezone, timezone)
+	}
+
+	settings, err := m.getUserSettings(ctx, msg.UserID)
+	if err != nil {
+		return "", err
+	}
+
+	settings.Timezone = loc.String()
+	if err = m.saveUserSettings(ctx, settings); err != nil {
+		return "", err
+	}
+
+	if err = m.rescheduleSubscriptions(ctx, settings); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf(msgTimezoneSet, settings.Timezone), nil
+}
@@ -0,0 +1,44 @@
+package servicemessages
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+)
+
+func (m *Model) setWeekStart(ctx context.Context, msg Message) (string, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "setWeekStart")
+	defer span.Finish()
+
+	weekStart, ok := parseWeekday(msg.CommandArguments)
+	if !ok {
+		return "", fmt.Errorf(errUnknownWeekday, msg.CommandArguments)
+	}
+
+	settings, err := m.getUserSettings(ctx, msg.UserID)
+	if err != nil {
+		return "", err
+	}
+
+	settings.WeekStart = weekStart
+	if err = m.saveUserSettings(ctx, settings); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf(msgWeekStartSet, strings.ToLower(weekStart.String())), nil
+}
+
+func parseWeekday(value string) (time.Weekday, bool) {
+	value = strings.ToLower(strings.Trim(value, " "))
+
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if strings.ToLower(d.String()) == value {
+			return d, true
+		}
+	}
+
+	return time.Sunday, false
+}
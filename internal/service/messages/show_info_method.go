@@ -14,14 +14,111 @@ func (m *Model) showInfo(ctx context.Context) string {
 	return strings.Join([]string{
 		"Привет, я буду считать твои деньги. Вот что я умею:\n",
 		addExpenseCommand,
-		"- добавить трату\nПример: /addExpense 10;Дом;2022-10-04 10:00:00\n",
+		"- добавить трату. Дата необязательна: полная, только дата, сегодня или вчера. Сумма может быть с валютой. Если указать только сумму, бот предложит выбрать категорию\n" +
+			"Пример: /addExpense 10;Дом;2022-10-04 10:00:00, /addExpense 350 кофе вчера, /addExpense 12,50 USD;Книги, /addExpense 350\n",
 		getExpensesCommand,
-		" - получить список трат за неделю, месяц и год\nПример: /getExpenses week\n",
+		" - получить список трат за неделю, месяц, год или диапазон дат\nПример: /getExpenses week, /getExpenses previous month, /getExpenses 2022-09-01..2022-09-30\n",
+		trendCommand,
+		" - динамика расходов за период, как в getExpenses, по дням, неделям или месяцам. ;categories - с разбивкой по категориям\n" +
+			"Пример: /trend month day, /trend year month;categories\n",
+		exportCommand,
+		" - выгрузить траты в файл csv или xlsx за период, как в getExpenses\n" +
+			"Пример: /export csv month, /export xlsx 2022-01-01..2022-12-31\n",
+		importCommand,
+		" - загрузить траты из csv: документ с подписью-командой. dry - только проверить файл. Колонки по-умолчанию как в export, " +
+			"дубли по дате, сумме и категории пропускаются\nПример: /import, /import dry date=Дата операции;amount=Сумма;category=Описание\n",
+		importTokenCommand,
+		" - получить токен для импорта трат по HTTP, прежний токен перестает действовать\n",
 		requestCurrencyChangeCommand,
 		" - вызвать менюсмены валюты\n",
 		setCurrencyCommand,
 		" - установить валюту ввода и отображения отчетов.\nПример: /setCurrency EUR\n",
 		setLimitCommand,
-		" - установить лимит трат на категорию.\nПример: /setLimit Ремонт 1200.50\n",
+		" - установить лимит трат на категорию или общий лимит (*) на неделю, месяц, квартал или год. По-умолчанию на месяц\n" +
+			"Пример: /setLimit Ремонт;1200.50, /setLimit Дом;12000;week, /setLimit *;50000;quarter\n",
+		setLimitAlertsCommand,
+		" - пороги уведомлений о расходе лимита в процентах, по-умолчанию 50,80,100. off - отключить\n" +
+			"Пример: /setLimitAlerts Дом;80,100, /setLimitAlerts *;90;week\n",
+		listExpensesCommand,
+		" - список трат с их ИД за неделю, месяц или год\nПример: /listExpenses month\n",
+		editExpenseCommand,
+		" - изменить трату\nПример: /editExpense ИД 10;Дом;2022-10-04 10:00:00\n",
+		deleteExpenseCommand,
+		" - удалить трату\nПример: /deleteExpense ИД\n",
+		showExpenseCommand,
+		" - получить чек траты. Чек - фото или документ с подписью-тратой: 350;Кафе\nПример: /expense ИД\n",
+		setPeriodModeCommand,
+		" - режим периодов отчетов и лимитов: rolling (неделя, месяц, год назад) или calendar (календарные)\n" +
+			"Пример: /setPeriodMode calendar\n",
+		setWeekStartCommand,
+		" - день начала календарной недели\nПример: /setWeekStart monday\n",
+		setTimezoneCommand,
+		" - часовой пояс для дат трат, периодов и отчетов\nПример: /setTimezone Europe/Moscow\n",
+		cancelCommand,
+		" - отменить пошаговый ввод. Команды addExpense и setLimit без аргументов спрашивают аргументы по очереди\n",
+		categoriesCommand,
+		" - список ваших категорий\n",
+		renameCategoryCommand,
+		" - переименовать категорию\nПример: /renameCategory Еда;Продукты\n",
+		mergeCategoriesCommand,
+		" - перенести траты и лимит категории в другую и удалить ее\nПример: /mergeCategories Еда;Продукты\n",
+		deleteCategoryCommand,
+		" - удалить категорию без трат\nПример: /deleteCategory Еда\n",
+		aliasCommand,
+		" - синоним категории: трата с таким названием попадет в указанную категорию\nПример: /alias еда => Продукты\n",
+		keywordCommand,
+		" - ключевое слово: трата, в описании которой оно встречается, попадет в указанную категорию\nПример: /keyword старбакс => Кофе\n",
+		categoryRulesCommand,
+		" - список синонимов и ключевых слов\n",
+		deleteCategoryRuleCommand,
+		" - удалить синоним или ключевое слово\nПример: /deleteRule еда\n",
+		subscribeCommand,
+		" - получать отчет по расписанию в вашем часовом поясе: weekly день недели или monthly день месяца\n" +
+			"Пример: /subscribe weekly monday 09:00, /subscribe monthly 1 09:00\n",
+		subscriptionsCommand,
+		" - список подписок на отчеты\n",
+		unsubscribeCommand,
+		" - отменить подписку на отчет\nПример: /unsubscribe weekly\n",
+		addRecurringCommand,
+		" - регулярная трата, которая добавляется сама по расписанию. Пропущенные траты добавятся позже\n" +
+			"Пример: /addRecurring 25000;Аренда;monthly 5, /addRecurring 599;Подписки;weekly friday 10:00\n",
+		recurringCommand,
+		" - список регулярных трат с их ИД\n",
+		pauseRecurringCommand,
+		" - приостановить регулярную трату\nПример: /pauseRecurring ИД\n",
+		resumeRecurringCommand,
+		" - возобновить регулярную трату\nПример: /resumeRecurring ИД\n",
+		addIncomeCommand,
+		" - добавить доход. Доходы, расходы и баланс показываются в отчетах\n" +
+			"Пример: /addIncome 150000;Зарплата;2022-10-05, /addIncome 500 USD;Фриланс\n",
+		addAccountCommand,
+		" - добавить счет: карта, наличные, накопления. Трата со счета: /addExpense 350;Кафе;;Карта\n" +
+			"Пример: /addAccount Карта;RUB;15000, /addAccount Наличные\n",
+		transferCommand,
+		" - перевести деньги между счетами\nПример: /transfer 5000;Карта;Накопления\n",
+		balancesCommand,
+		" - остатки на счетах. Отчет по счету: /getExpenses month;Карта\n",
+		createBudgetCommand,
+		" - создать общий бюджет и получить код приглашения. Траты, лимиты и категории общего бюджета видят все участники\n" +
+			"Пример: /createBudget Семья\n",
+		joinBudgetCommand,
+		" - вступить в общий бюджет по коду приглашения\nПример: /join ABCD1234\n",
+		budgetsCommand,
+		" - список ваших бюджетов\n",
+		switchBudgetCommand,
+		" - выбрать активный бюджет\nПример: /switchBudget ИД, /switchBudget personal\n",
+		budgetMembersCommand,
+		" - участники активного бюджета\n",
+		setBudgetRoleCommand,
+		" - назначить роль участнику: owner, editor или viewer (только отчеты)\nПример: /setRole alice;viewer\n",
+		leaveBudgetCommand,
+		" - выйти из активного бюджета\n",
+		splitExpenseCommand,
+		" - разделить трату, которую оплатили вы, поровну между вами и участниками бюджета или группы. Дата необязательна\n" +
+			"Пример: /split 3000;Ресторан;@alice,@bob\n",
+		debtsCommand,
+		" - кто кому сколько должен, минимальным числом переводов\n",
+		settleDebtCommand,
+		" - записать возврат долга участнику. Дата необязательна\nПример: /settle @alice;1000\n",
 	}, "")
 }
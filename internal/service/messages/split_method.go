@@ -0,0 +1,228 @@
+package servicemessages
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/model"
+)
+
+const participantsSeparator = ","
+
+// splitExpense добавляет трату, которую оплатил автор сообщения, и делит ее поровну между ним и указанными участниками
+func (m *Model) splitExpense(ctx context.Context, msg Message) (string, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "splitExpense")
+	defer span.Finish()
+
+	parts := strings.Split(msg.CommandArguments, expenseArgumentsSeparator)
+	if len(parts) < 3 || len(parts) > 4 {
+		return "", errors.New(errSplitInvalidParameterMessage)
+	}
+
+	category := strings.Trim(parts[1], " ")
+	if category == "" {
+		return "", errors.New(errSplitInvalidParameterMessage)
+	}
+
+	if !model.IsSharedBudget(msg.BudgetID) {
+		return "", errors.New(errSharedBudgetRequired)
+	}
+
+	settings, err := m.getUserSettings(ctx, msg.UserID)
+	if err != nil {
+		return "", err
+	}
+
+	parser := newExpenseInputParser(m.currencies, time.Now().In(settings.Location()))
+	amount, currency, err := parser.parseAmount(parts[0])
+	if err != nil {
+		return "", err
+	}
+	if currency == "" {
+		currency = settings.Currency
+	}
+
+	datetime, err := parseOptionalDatetime(parser, parts, 3)
+	if err != nil {
+		return "", err
+	}
+
+	members, err := m.budgetsRepo.GetMembers(ctx, msg.BudgetID)
+	if err != nil {
+		return "", err
+	}
+
+	participants := []int64{msg.UserID}
+	for _, name := range strings.Split(parts[2], participantsSeparator) {
+		if strings.Trim(name, " ") == "" {
+			continue
+		}
+
+		member, found := matchBudgetMember(members, name)
+		if !found {
+			return "", fmt.Errorf(errSplitMemberNotFound, strings.Trim(name, " "))
+		}
+
+		if !containsUser(participants, member.UserID) {
+			participants = append(participants, member.UserID)
+		}
+	}
+
+	if len(participants) < 2 {
+		return "", errors.New(errSplitNoParticipants)
+	}
+
+	expense, shares, err := m.expenseProcessor.SplitExpense(ctx, amount, currency, category, datetime, msg.BudgetID, msg.UserID, participants)
+	if err != nil {
+		return "", err
+	}
+
+	names := memberNames(members)
+	var list strings.Builder
+	for _, share := range shares {
+		list.WriteString(fmt.Sprintf("%s - %.02f %s\n", participantName(names, share.UserID), share.Amount, currency))
+	}
+
+	return fmt.Sprintf(msgExpenseSplit, amount, currency, expense.Category, memberName(msg), list.String()), nil
+}
+
+// settleDebt записывает, что автор сообщения вернул долг участнику
+func (m *Model) settleDebt(ctx context.Context, msg Message) (string, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "settleDebt")
+	defer span.Finish()
+
+	parts := strings.Split(msg.CommandArguments, expenseArgumentsSeparator)
+	if len(parts) < 2 || len(parts) > 3 {
+		return "", errors.New(errSettleDebtInvalidParameterMessage)
+	}
+
+	if !model.IsSharedBudget(msg.BudgetID) {
+		return "", errors.New(errSharedBudgetRequired)
+	}
+
+	members, err := m.budgetsRepo.GetMembers(ctx, msg.BudgetID)
+	if err != nil {
+		return "", err
+	}
+
+	member, found := matchBudgetMember(members, parts[0])
+	if !found {
+		return "", fmt.Errorf(errSplitMemberNotFound, strings.Trim(parts[0], " "))
+	}
+
+	if member.UserID == msg.UserID {
+		return "", errors.New(errSettleDebtToYourself)
+	}
+
+	settings, err := m.getUserSettings(ctx, msg.UserID)
+	if err != nil {
+		return "", err
+	}
+
+	parser := newExpenseInputParser(m.currencies, time.Now().In(settings.Location()))
+	amount, currency, err := parser.parseAmount(parts[1])
+	if err != nil {
+		return "", err
+	}
+	if currency == "" {
+		currency = settings.Currency
+	}
+
+	datetime, err := parseOptionalDatetime(parser, parts, 2)
+	if err != nil {
+		return "", err
+	}
+
+	if err = m.expenseProcessor.SettleDebt(ctx, amount, currency, datetime, msg.BudgetID, msg.UserID, member.UserID); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf(msgDebtSettled, amount, currency, member.Name), nil
+}
+
+// listDebts показывает, кто кому сколько должен, минимальным числом переводов
+func (m *Model) listDebts(ctx context.Context, msg Message) (string, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "listDebts")
+	defer span.Finish()
+
+	if !model.IsSharedBudget(msg.BudgetID) {
+		return "", errors.New(errSharedBudgetRequired)
+	}
+
+	settings, err := m.getUserSettings(ctx, msg.UserID)
+	if err != nil {
+		return "", err
+	}
+
+	debts, err := m.expenseProcessor.GetDebts(ctx, settings.Currency, msg.BudgetID)
+	if err != nil {
+		return "", err
+	}
+
+	if len(debts) == 0 {
+		return msgNoDebts, nil
+	}
+
+	members, err := m.budgetsRepo.GetMembers(ctx, msg.BudgetID)
+	if err != nil {
+		return "", err
+	}
+	names := memberNames(members)
+
+	var list strings.Builder
+	list.WriteString("Долги:\n")
+	for _, debt := range debts {
+		list.WriteString(fmt.Sprintf(
+			"%s -> %s: %.02f %s\n",
+			participantName(names, debt.FromUserID),
+			participantName(names, debt.ToUserID),
+			debt.Amount,
+			settings.Currency,
+		))
+	}
+	list.WriteString(fmt.Sprintf("Вернуть долг: /%s Участник;Сумма\n", settleDebtCommand))
+
+	return list.String(), nil
+}
+
+// parseOptionalDatetime возвращает дату из аргумента index, если он указан, иначе текущее время
+func parseOptionalDatetime(parser *expenseInputParser, parts []string, index int) (time.Time, error) {
+	if len(parts) <= index {
+		return parser.now, nil
+	}
+
+	rawDatetime := strings.Trim(parts[index], " ")
+	if rawDatetime == "" {
+		return parser.now, nil
+	}
+
+	datetime, ok := parser.parseDatetime(rawDatetime)
+	if !ok {
+		return time.Time{}, fmt.Errorf(errAddExpenseInvalidDatetimeParameterMessage, rawDatetime)
+	}
+
+	return datetime, nil
+}
+
+// participantName имя участника, вышедшего из бюджета, неизвестно - показываем ИД
+func participantName(names map[int64]string, userId int64) string {
+	if name, ok := names[userId]; ok && name != "" {
+		return name
+	}
+
+	return fmt.Sprint(userId)
+}
+
+func containsUser(userIds []int64, userId int64) bool {
+	for _, id := range userIds {
+		if id == userId {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,111 @@
+package servicemessages
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/model"
+)
+
+func (m *Model) subscribe(ctx context.Context, msg Message) (string, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "subscribe")
+	defer span.Finish()
+
+	schedule, err := parseSchedule(msg.CommandArguments)
+	if err != nil {
+		return "", err
+	}
+	subscription := model.ReportSubscription{UserId: msg.UserID, Schedule: schedule}
+
+	settings, err := m.getUserSettings(ctx, msg.UserID)
+	if err != nil {
+		return "", err
+	}
+
+	subscription.NextRunAt = schedule.NextRun(time.Now(), settings.Location())
+	if err = m.subscriptionsRepo.SaveSubscription(ctx, subscription); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf(
+		msgSubscribed,
+		schedule.Period.String(),
+		schedule.String(),
+		subscription.NextRunAt.In(settings.Location()).Format(scheduleNextRunFormat),
+	), nil
+}
+
+func (m *Model) listSubscriptions(ctx context.Context, msg Message) (string, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "listSubscriptions")
+	defer span.Finish()
+
+	subscriptions, err := m.subscriptionsRepo.GetSubscriptions(ctx, msg.UserID)
+	if err != nil {
+		return "", err
+	}
+
+	if len(subscriptions) == 0 {
+		return msgNoSubscriptions, nil
+	}
+
+	settings, err := m.getUserSettings(ctx, msg.UserID)
+	if err != nil {
+		return "", err
+	}
+
+	var list strings.Builder
+	list.WriteString("Подписки на отчеты:\n")
+	for _, s := range subscriptions {
+		list.WriteString(fmt.Sprintf(
+			"- %s: %s, следующий %s\n",
+			s.Schedule.Period.String(),
+			s.Schedule.String(),
+			s.NextRunAt.In(settings.Location()).Format(scheduleNextRunFormat),
+		))
+	}
+	list.WriteString(fmt.Sprintf("Отписаться: /%s weekly", unsubscribeCommand))
+
+	return list.String(), nil
+}
+
+func (m *Model) unsubscribe(ctx context.Context, msg Message) (string, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "unsubscribe")
+	defer span.Finish()
+
+	period, ok := model.ParseSchedulePeriod(msg.CommandArguments)
+	if !ok {
+		return "", fmt.Errorf(errUnknownSchedulePeriod, msg.CommandArguments)
+	}
+
+	found, err := m.subscriptionsRepo.DeleteSubscription(ctx, msg.UserID, period)
+	if err != nil {
+		return "", err
+	}
+
+	if !found {
+		return "", fmt.Errorf(errSubscriptionNotFound, strings.ToLower(period.String()))
+	}
+
+	return fmt.Sprintf(msgUnsubscribed, strings.ToLower(period.String())), nil
+}
+
+// rescheduleSubscriptions пересчитывает время отправки отчетов после смены часового пояса
+func (m *Model) rescheduleSubscriptions(ctx context.Context, settings model.UserSettings) error {
+	subscriptions, err := m.subscriptionsRepo.GetSubscriptions(ctx, settings.UserID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, subscription := range subscriptions {
+		subscription.NextRunAt = subscription.Schedule.NextRun(now, settings.Location())
+		if err = m.subscriptionsRepo.SaveSubscription(ctx, subscription); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
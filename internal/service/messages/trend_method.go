@@ -0,0 +1,141 @@
+package servicemessages
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/model"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/service/expense_reporter"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/utils/chart"
+)
+
+const (
+	trendByCategoryArgument = "categories"
+	// maxTrendBuckets интервалов в отчете, больше не помещается в сообщение и на график
+	maxTrendBuckets = 62
+)
+
+// trendBucketNames интервалы в заголовке отчета
+var trendBucketNames = map[model.TrendBucket]string{
+	model.DayTrendBucket:   "по дням",
+	model.WeekTrendBucket:  "по неделям",
+	model.MonthTrendBucket: "по месяцам",
+}
+
+// trendBucketFormats форматы начала интервала в тексте и на графике
+var trendBucketFormats = map[model.TrendBucket][2]string{
+	model.DayTrendBucket:   {"2006-01-02", "02.01"},
+	model.WeekTrendBucket:  {"с 2006-01-02", "02.01"},
+	model.MonthTrendBucket: {"2006-01", "01.06"},
+}
+
+// requestTrend запрашивает отчет о динамике трат: /trend month day, /trend year month;categories
+func (m *Model) requestTrend(ctx context.Context, msg Message) (string, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "requestTrend")
+	defer span.Finish()
+
+	rawArguments, rawGrouping, _ := strings.Cut(msg.CommandArguments, expenseArgumentsSeparator)
+
+	byCategory := false
+	if rawGrouping = strings.Trim(rawGrouping, " "); rawGrouping != "" {
+		if !strings.EqualFold(rawGrouping, trendByCategoryArgument) {
+			return "", errors.New(errTrendInvalidParameterMessage)
+		}
+		byCategory = true
+	}
+
+	// интервал - последнее слово, период перед ним может состоять из нескольких слов: previous month day
+	rawArguments = strings.Trim(rawArguments, " ")
+	separator := strings.LastIndex(rawArguments, " ")
+	bucket, ok := model.ParseTrendBucket(rawArguments[separator+1:])
+	if !ok {
+		return "", errors.New(errTrendInvalidParameterMessage)
+	}
+
+	settings, err := m.getUserSettings(ctx, msg.UserID)
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now().In(settings.Location())
+	period, dateRange, err := parseReportPeriod(rawArguments[:separator+1], now, settings.WeekStart)
+	if err != nil {
+		return "", err
+	}
+
+	if period != model.Custom {
+		dateRange = getPeriodRange(period, settings, now)
+	}
+
+	if len(bucket.Buckets(dateRange, settings.WeekStart)) > maxTrendBuckets {
+		return "", fmt.Errorf(errTrendTooManyBuckets, maxTrendBuckets)
+	}
+
+	err = m.reportRequester.SendRequestTrend(ctx, msg.UserID, replyChatID(msg.ChatID, msg.UserID), msg.BudgetID, bucket, byCategory, dateRange, settings)
+	if err != nil {
+		return "", err
+	}
+
+	return reportRequestedMsg, nil
+}
+
+// SendTrend отправляет отчет о динамике трат: график расходов по интервалам, в подписи - суммы
+func (m *Model) SendTrend(ctx context.Context, trend *expense_reporter.TrendReport) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "Messaging_SendTrend")
+	defer span.Finish()
+
+	settings, err := m.getUserSettings(ctx, trend.UserID)
+	if err != nil {
+		return err
+	}
+
+	// даты приходят в UTC, интервалы показываем в часовом поясе пользователя
+	location := settings.Location()
+	formats := trendBucketFormats[trend.Bucket]
+
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf(msgTrendHeader, trend.Range.In(location).String(), trendBucketNames[trend.Bucket]))
+
+	// суммы интервалов для графика, в разбивке по категориям интервал занимает несколько точек подряд
+	bars := make([]chart.Bar, 0, len(trend.Points))
+	var total float64
+	for i, point := range trend.Points {
+		from := point.From.In(location)
+		total += point.Amount
+
+		if i > 0 && point.From.Equal(trend.Points[i-1].From) {
+			bars[len(bars)-1].Value += point.Amount
+			text.WriteString(fmt.Sprintf(msgTrendCategory, point.Category, point.Amount))
+			continue
+		}
+
+		bars = append(bars, chart.Bar{Label: from.Format(formats[1]), Value: point.Amount})
+		if trend.ByCategory {
+			if i > 0 {
+				text.WriteString("\n")
+			}
+			text.WriteString(fmt.Sprintf(msgTrendBucket, from.Format(formats[0]), point.Category, point.Amount))
+			continue
+		}
+		text.WriteString(fmt.Sprintf(msgTrendRow, from.Format(formats[0]), point.Amount, settings.Currency))
+	}
+
+	chatID := replyChatID(trend.ChatID, trend.UserID)
+	if total <= 0 {
+		return m.tgClient.SendMessage(text.String()+msgTrendEmpty, chatID, mainMenu)
+	}
+
+	if trend.ByCategory {
+		text.WriteString("\n")
+	}
+	text.WriteString(fmt.Sprintf(msgTrendTotal, total, settings.Currency))
+	if !trend.ByCategory {
+		text.WriteString(fmt.Sprintf(msgTrendAverage, total/float64(len(bars)), settings.Currency))
+	}
+
+	return m.sendChart(text.String(), chatID, chart.Chart{Bars: bars})
+}
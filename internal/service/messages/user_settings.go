@@ -0,0 +1,39 @@
+package servicemessages
+
+import (
+	"context"
+	"time"
+
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/model"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/service/cache"
+)
+
+// getUserSettings возвращает настройки пользователя, либо настройки по-умолчанию
+func (m *Model) getUserSettings(ctx context.Context, userID int64) (model.UserSettings, error) {
+	settings, found, err := m.settingsRepo.GetSettings(ctx, userID)
+	if err != nil {
+		return model.UserSettings{}, err
+	}
+
+	if !found {
+		return model.DefaultUserSettings(userID), nil
+	}
+
+	return settings, nil
+}
+
+// saveUserSettings сохраняет настройки и сбрасывает закешированные отчеты, границы периодов в них зависят от настроек
+func (m *Model) saveUserSettings(ctx context.Context, settings model.UserSettings) error {
+	if err := m.settingsRepo.SaveSettings(ctx, settings); err != nil {
+		return err
+	}
+
+	_, err := m.dialogCache.Del(ctx, cache.ReportsVersionKey(settings.UserID))
+
+	return err
+}
+
+// getPeriodRange возвращает диапазон периода с учетом настроек пользователя
+func getPeriodRange(period model.ExpensePeriod, settings model.UserSettings, now time.Time) model.DateRange {
+	return period.GetPeriodRange(now, settings.PeriodMode, settings.WeekStart)
+}
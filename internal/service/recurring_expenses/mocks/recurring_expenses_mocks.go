@@ -0,0 +1,125 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/service/recurring_expenses/recurring_expenses.go
+
+// Package mock_recurringexpenses is a generated GoMock package.
+package mock_recurringexpenses
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	model "gitlab.ozon.dev/cranky4/tg-bot/internal/model"
+)
+
+// MockRecurringExpenses is a mock of RecurringExpenses interface.
+type MockRecurringExpenses struct {
+	ctrl     *gomock.Controller
+	recorder *MockRecurringExpensesMockRecorder
+}
+
+// MockRecurringExpensesMockRecorder is the mock recorder for MockRecurringExpenses.
+type MockRecurringExpensesMockRecorder struct {
+	mock *MockRecurringExpenses
+}
+
+// NewMockRecurringExpenses creates a new mock instance.
+func NewMockRecurringExpenses(ctrl *gomock.Controller) *MockRecurringExpenses {
+	mock := &MockRecurringExpenses{ctrl: ctrl}
+	mock.recorder = &MockRecurringExpensesMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRecurringExpenses) EXPECT() *MockRecurringExpensesMockRecorder {
+	return m.recorder
+}
+
+// AddRecurring mocks base method.
+func (m *MockRecurringExpenses) AddRecurring(ctx context.Context, userId int64, amount float64, currency, category string, schedule model.Schedule) (model.RecurringExpense, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddRecurring", ctx, userId, amount, currency, category, schedule)
+	ret0, _ := ret[0].(model.RecurringExpense)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddRecurring indicates an expected call of AddRecurring.
+func (mr *MockRecurringExpensesMockRecorder) AddRecurring(ctx, userId, amount, currency, category, schedule interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddRecurring", reflect.TypeOf((*MockRecurringExpenses)(nil).AddRecurring), ctx, userId, amount, currency, category, schedule)
+}
+
+// GetRecurring mocks base method.
+func (m *MockRecurringExpenses) GetRecurring(ctx context.Context, userId int64) ([]model.RecurringExpense, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRecurring", ctx, userId)
+	ret0, _ := ret[0].([]model.RecurringExpense)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRecurring indicates an expected call of GetRecurring.
+func (mr *MockRecurringExpensesMockRecorder) GetRecurring(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecurring", reflect.TypeOf((*MockRecurringExpenses)(nil).GetRecurring), ctx, userId)
+}
+
+// PauseRecurring mocks base method.
+func (m *MockRecurringExpenses) PauseRecurring(ctx context.Context, userId int64, id string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PauseRecurring", ctx, userId, id)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PauseRecurring indicates an expected call of PauseRecurring.
+func (mr *MockRecurringExpensesMockRecorder) PauseRecurring(ctx, userId, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PauseRecurring", reflect.TypeOf((*MockRecurringExpenses)(nil).PauseRecurring), ctx, userId, id)
+}
+
+// ResumeRecurring mocks base method.
+func (m *MockRecurringExpenses) ResumeRecurring(ctx context.Context, userId int64, id string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResumeRecurring", ctx, userId, id)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResumeRecurring indicates an expected call of ResumeRecurring.
+func (mr *MockRecurringExpensesMockRecorder) ResumeRecurring(ctx, userId, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResumeRecurring", reflect.TypeOf((*MockRecurringExpenses)(nil).ResumeRecurring), ctx, userId, id)
+}
+
+// RunDue mocks base method.
+func (m *MockRecurringExpenses) RunDue(ctx context.Context, now time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RunDue", ctx, now)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RunDue indicates an expected call of RunDue.
+func (mr *MockRecurringExpensesMockRecorder) RunDue(ctx, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunDue", reflect.TypeOf((*MockRecurringExpenses)(nil).RunDue), ctx, now)
+}
+
+// Start mocks base method.
+func (m *MockRecurringExpenses) Start(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Start", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Start indicates an expected call of Start.
+func (mr *MockRecurringExpensesMockRecorder) Start(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Start", reflect.TypeOf((*MockRecurringExpenses)(nil).Start), ctx)
+}
@@ -0,0 +1,249 @@
+package recurringexpenses
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/model"
+	repo "gitlab.ozon.dev/cranky4/tg-bot/internal/repository"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/service/expense_processor"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/service/logger"
+)
+
+const (
+	primitiveCurrencyMultiplier = 100
+
+	defaultInterval  = time.Minute
+	defaultBatchSize = 100
+
+	errAddRecurringMessage    = "ошибка создания регулярной траты"
+	errGetRecurringMessage    = "ошибка получения регулярных трат"
+	errPauseRecurringMessage  = "ошибка приостановки регулярной траты"
+	errResumeRecurringMessage = "ошибка возобновления регулярной траты"
+	errRunDueMessage          = "ошибка добавления регулярных трат"
+)
+
+type RecurringExpenses interface {
+	AddRecurring(
+		ctx context.Context,
+		userId int64,
+		amount float64,
+		currency string,
+		category string,
+		schedule model.Schedule,
+	) (model.RecurringExpense, error)
+	GetRecurring(ctx context.Context, userId int64) ([]model.RecurringExpense, error)
+	PauseRecurring(ctx context.Context, userId int64, id string) (bool, error)
+	// ResumeRecurring возобновляет трату, пропущенные за время паузы траты не добавляются
+	ResumeRecurring(ctx context.Context, userId int64, id string) (bool, error)
+	Start(ctx context.Context) error
+	// RunDue добавляет траты, время которых наступило к now, в том числе пропущенные
+	RunDue(ctx context.Context, now time.Time) error
+}
+
+type recurringExpenses struct {
+	repo             repo.RecurringExpensesRepository
+	settingsRepo     repo.UserSettingsRepository
+	expenseProcessor expense_processor.ExpenseProcessor
+	interval         time.Duration
+	batchSize        int
+}
+
+func NewRecurringExpenses(
+	repo repo.RecurringExpensesRepository,
+	settingsRepo repo.UserSettingsRepository,
+	expenseProcessor expense_processor.ExpenseProcessor,
+	interval time.Duration,
+	batchSize int,
+) RecurringExpenses {
+	if interval <= 0 {
+		interval = defaultInterval
+	}
+	if batchSize <= 0 {
+		batchSize = defaultBatchSize
+	}
+
+	return &recurringExpenses{
+		repo:             repo,
+		settingsRepo:     settingsRepo,
+		expenseProcessor: expenseProcessor,
+		interval:         interval,
+		batchSize:        batchSize,
+	}
+}
+
+func (s *recurringExpenses) AddRecurring(
+	ctx context.Context,
+	userId int64,
+	amount float64,
+	currency string,
+	category string,
+	schedule model.Schedule,
+) (model.RecurringExpense, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "AddRecurring")
+	defer span.Finish()
+
+	settings, err := s.getUserSettings(ctx, userId)
+	if err != nil {
+		return model.RecurringExpense{}, errors.Wrap(err, errAddRecurringMessage)
+	}
+
+	recurring, err := s.repo.AddRecurring(ctx, model.RecurringExpense{
+		UserId:    userId,
+		Amount:    int64(math.Round(amount * primitiveCurrencyMultiplier)),
+		Currency:  currency,
+		Category:  category,
+		Schedule:  schedule,
+		NextRunAt: schedule.NextRun(time.Now(), settings.Location()),
+	})
+	if err != nil {
+		return model.RecurringExpense{}, errors.Wrap(err, errAddRecurringMessage)
+	}
+
+	return recurring, nil
+}
+
+func (s *recurringExpenses) GetRecurring(ctx context.Context, userId int64) ([]model.RecurringExpense, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "GetRecurring")
+	defer span.Finish()
+
+	recurring, err := s.repo.GetRecurring(ctx, userId)
+	if err != nil {
+		return nil, errors.Wrap(err, errGetRecurringMessage)
+	}
+
+	return recurring, nil
+}
+
+func (s *recurringExpenses) PauseRecurring(ctx context.Context, userId int64, id string) (bool, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "PauseRecurring")
+	defer span.Finish()
+
+	found, err := s.repo.SetPaused(ctx, userId, id, true, time.Time{})
+	if err != nil {
+		return false, errors.Wrap(err, errPauseRecurringMessage)
+	}
+
+	return found, nil
+}
+
+func (s *recurringExpenses) ResumeRecurring(ctx context.Context, userId int64, id string) (bool, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "ResumeRecurring")
+	defer span.Finish()
+
+	recurring, err := s.repo.GetRecurring(ctx, userId)
+	if err != nil {
+		return false, errors.Wrap(err, errResumeRecurringMessage)
+	}
+
+	settings, err := s.getUserSettings(ctx, userId)
+	if err != nil {
+		return false, errors.Wrap(err, errResumeRecurringMessage)
+	}
+
+	for _, rec := range recurring {
+		if rec.ID != id {
+			continue
+		}
+
+		found, err := s.repo.SetPaused(ctx, userId, id, false, rec.Schedule.NextRun(time.Now(), settings.Location()))
+		if err != nil {
+			return false, errors.Wrap(err, errResumeRecurringMessage)
+		}
+
+		return found, nil
+	}
+
+	return false, nil
+}
+
+func (s *recurringExpenses) Start(ctx context.Context) error {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case now := <-ticker.C:
+			if err := s.RunDue(ctx, now); err != nil {
+				logger.Error(err.Error(), logger.LogDataItem{
+					Key: "service", Value: "RECURRING_EXPENSES",
+				})
+			}
+		}
+	}
+}
+
+func (s *recurringExpenses) RunDue(ctx context.Context, now time.Time) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "RecurringExpenses_RunDue")
+	defer span.Finish()
+
+	for {
+		recurring, err := s.repo.GetDueRecurring(ctx, now, s.batchSize)
+		if err != nil {
+			return errors.Wrap(err, errRunDueMessage)
+		}
+
+		failed := 0
+		for _, rec := range recurring {
+			if err = s.materialize(ctx, rec, now); err != nil {
+				failed++
+				logger.Error(
+					err.Error(),
+					logger.LogDataItem{Key: "service", Value: "RECURRING_EXPENSES"},
+					logger.LogDataItem{Key: "recurringId", Value: rec.ID},
+				)
+			}
+		}
+
+		// неудачные траты остаются в очереди, повторим их на следующем тике
+		if len(recurring) < s.batchSize || failed == len(recurring) {
+			return nil
+		}
+	}
+}
+
+// materialize добавляет все наступившие к now траты по очереди. Трата сохраняется вместе с отметкой о своем времени,
+// поэтому после сбоя или на другой реплике повторное добавление ничего не меняет. Следующее время переносится
+// только после сохранения траты, и ни одна трата не теряется
+func (s *recurringExpenses) materialize(ctx context.Context, rec model.RecurringExpense, now time.Time) error {
+	settings, err := s.getUserSettings(ctx, rec.UserId)
+	if err != nil {
+		return err
+	}
+	loc := settings.Location()
+
+	for !rec.NextRunAt.After(now) {
+		if _, _, err = s.expenseProcessor.AddRecurringExpense(ctx, rec, rec.NextRunAt.In(loc)); err != nil {
+			return err
+		}
+
+		nextRunAt := rec.Schedule.NextRun(rec.NextRunAt, loc)
+		moved, err := s.repo.Reschedule(ctx, rec, nextRunAt)
+		if err != nil || !moved {
+			return err
+		}
+
+		rec.NextRunAt = nextRunAt
+	}
+
+	return nil
+}
+
+// getUserSettings возвращает настройки пользователя, либо настройки по-умолчанию
+func (s *recurringExpenses) getUserSettings(ctx context.Context, userId int64) (model.UserSettings, error) {
+	settings, found, err := s.settingsRepo.GetSettings(ctx, userId)
+	if err != nil {
+		return model.UserSettings{}, err
+	}
+
+	if !found {
+		return model.DefaultUserSettings(userId), nil
+	}
+
+	return settings, nil
+}
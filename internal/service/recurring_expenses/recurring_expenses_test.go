@@ -0,0 +1,172 @@
+package recurringexpenses
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/opentracing/opentracing-go"
+	"github.com/stretchr/testify/assert"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/model"
+	memoryrepo "gitlab.ozon.dev/cranky4/tg-bot/internal/repository/memory"
+	repomocks "gitlab.ozon.dev/cranky4/tg-bot/internal/repository/mocks"
+	exp_processor_mock "gitlab.ozon.dev/cranky4/tg-bot/internal/service/expense_processor/mocks"
+)
+
+func TestRunDueShouldBackfillMissedExpensesOnce(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	_, wrapedCtx := opentracing.StartSpanFromContext(ctx, "RecurringExpenses_RunDue")
+
+	repo := memoryrepo.NewRecurringExpensesRepository()
+	rec, err := repo.AddRecurring(ctx, model.RecurringExpense{
+		UserId:    123,
+		Amount:    2500050,
+		Currency:  "RUB",
+		Category:  "Аренда",
+		Schedule:  model.Schedule{Period: model.Month, Day: 5},
+		NextRunAt: time.Date(2022, 9, 5, 0, 0, 0, 0, time.UTC),
+	})
+	assert.NoError(t, err)
+
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+	settingsRepo.EXPECT().GetSettings(gomock.Any(), int64(123)).Return(model.UserSettings{
+		UserID:   123,
+		Currency: "RUB",
+		Timezone: "UTC",
+	}, true, nil).AnyTimes()
+
+	processor := exp_processor_mock.NewMockExpenseProcessor(ctrl)
+	for _, month := range []time.Month{time.September, time.October, time.November} {
+		processor.EXPECT().AddRecurringExpense(gomock.Any(), recurringWithID(rec.ID), time.Date(2022, month, 5, 0, 0, 0, 0, time.UTC)).
+			Return(&model.Expense{}, true, nil)
+	}
+
+	replica1 := NewRecurringExpenses(repo, settingsRepo, processor, time.Minute, 10)
+	replica2 := NewRecurringExpenses(repo, settingsRepo, processor, time.Minute, 10)
+
+	now := time.Date(2022, 11, 10, 12, 0, 0, 0, time.UTC)
+	assert.NoError(t, replica1.RunDue(wrapedCtx, now))
+	assert.NoError(t, replica2.RunDue(wrapedCtx, now))
+
+	recurring, err := repo.GetRecurring(ctx, 123)
+	assert.NoError(t, err)
+	assert.Equal(t, rec.ID, recurring[0].ID)
+	assert.Equal(t, time.Date(2022, 12, 5, 0, 0, 0, 0, time.UTC), recurring[0].NextRunAt.UTC())
+}
+
+func TestRunDueShouldRetryExpenseAfterFail(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	_, wrapedCtx := opentracing.StartSpanFromContext(ctx, "RecurringExpenses_RunDue")
+
+	nextRunAt := time.Date(2022, 11, 7, 0, 0, 0, 0, time.UTC)
+	repo := memoryrepo.NewRecurringExpensesRepository()
+	_, err := repo.AddRecurring(ctx, model.RecurringExpense{
+		UserId:    123,
+		Amount:    59900,
+		Currency:  "RUB",
+		Category:  "Подписки",
+		Schedule:  model.Schedule{Period: model.Week, Weekday: time.Monday},
+		NextRunAt: nextRunAt,
+	})
+	assert.NoError(t, err)
+
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+	settingsRepo.EXPECT().GetSettings(gomock.Any(), int64(123)).Return(model.UserSettings{}, false, nil)
+
+	processor := exp_processor_mock.NewMockExpenseProcessor(ctrl)
+	processor.EXPECT().AddRecurringExpense(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(nil, false, errors.New("ошибка сохранения траты"))
+
+	worker := NewRecurringExpenses(repo, settingsRepo, processor, time.Minute, 10)
+	assert.NoError(t, worker.RunDue(wrapedCtx, nextRunAt.Add(time.Hour)))
+
+	due, err := repo.GetDueRecurring(ctx, nextRunAt.Add(time.Hour), 10)
+	assert.NoError(t, err)
+	assert.Len(t, due, 1)
+	assert.True(t, nextRunAt.Equal(due[0].NextRunAt))
+}
+
+func TestRunDueShouldRescheduleAlreadyAddedExpense(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	_, wrapedCtx := opentracing.StartSpanFromContext(ctx, "RecurringExpenses_RunDue")
+
+	nextRunAt := time.Date(2022, 11, 7, 0, 0, 0, 0, time.UTC)
+	repo := memoryrepo.NewRecurringExpensesRepository()
+	_, err := repo.AddRecurring(ctx, model.RecurringExpense{
+		UserId:    123,
+		Amount:    59900,
+		Currency:  "RUB",
+		Category:  "Подписки",
+		Schedule:  model.Schedule{Period: model.Week, Weekday: time.Monday},
+		NextRunAt: nextRunAt,
+	})
+	assert.NoError(t, err)
+
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+	settingsRepo.EXPECT().GetSettings(gomock.Any(), int64(123)).Return(model.UserSettings{}, false, nil)
+
+	// трата добавлена до сбоя, но время следующей траты не перенесено
+	processor := exp_processor_mock.NewMockExpenseProcessor(ctrl)
+	processor.EXPECT().AddRecurringExpense(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, false, nil)
+
+	worker := NewRecurringExpenses(repo, settingsRepo, processor, time.Minute, 10)
+	assert.NoError(t, worker.RunDue(wrapedCtx, nextRunAt.Add(time.Hour)))
+
+	due, err := repo.GetDueRecurring(ctx, nextRunAt.Add(time.Hour), 10)
+	assert.NoError(t, err)
+	assert.Empty(t, due)
+}
+
+func TestPausedExpenseShouldNotBeAdded(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	_, wrapedCtx := opentracing.StartSpanFromContext(ctx, "RecurringExpenses_RunDue")
+
+	repo := memoryrepo.NewRecurringExpensesRepository()
+	rec, err := repo.AddRecurring(ctx, model.RecurringExpense{
+		UserId:    123,
+		Amount:    59900,
+		Currency:  "RUB",
+		Category:  "Подписки",
+		Schedule:  model.Schedule{Period: model.Week, Weekday: time.Monday},
+		NextRunAt: time.Date(2022, 11, 7, 0, 0, 0, 0, time.UTC),
+	})
+	assert.NoError(t, err)
+
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+	processor := exp_processor_mock.NewMockExpenseProcessor(ctrl)
+	worker := NewRecurringExpenses(repo, settingsRepo, processor, time.Minute, 10)
+
+	found, err := worker.PauseRecurring(wrapedCtx, 123, rec.ID)
+	assert.NoError(t, err)
+	assert.True(t, found)
+
+	found, err = worker.PauseRecurring(wrapedCtx, 456, rec.ID)
+	assert.NoError(t, err)
+	assert.False(t, found)
+
+	assert.NoError(t, worker.RunDue(wrapedCtx, time.Date(2022, 11, 20, 0, 0, 0, 0, time.UTC)))
+}
+
+// recurringIDMatcher сравнивает регулярную трату только по ИД, время следующей траты меняется при переносе
+type recurringIDMatcher struct {
+	id string
+}
+
+func recurringWithID(id string) gomock.Matcher {
+	return recurringIDMatcher{id: id}
+}
+
+func (m recurringIDMatcher) Matches(x interface{}) bool {
+	rec, ok := x.(model.RecurringExpense)
+	return ok && rec.ID == m.id
+}
+
+func (m recurringIDMatcher) String() string {
+	return "is recurring expense " + m.id
+}
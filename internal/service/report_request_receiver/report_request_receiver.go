@@ -10,6 +10,8 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/uber/jaeger-client-go"
 	messagebroker "gitlab.ozon.dev/cranky4/tg-bot/internal/clients/message_broker"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/model"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/service/expense_exporter"
 	"gitlab.ozon.dev/cranky4/tg-bot/internal/service/expense_reporter"
 	"gitlab.ozon.dev/cranky4/tg-bot/internal/service/logger"
 	reportrequester "gitlab.ozon.dev/cranky4/tg-bot/internal/service/report_requester"
@@ -25,6 +27,7 @@ type reportRequestReceiver struct {
 	broker                      messagebroker.MessageBroker
 	queue                       string
 	expenseReporter             expense_reporter.ExpenseReporter
+	expenseExporter             expense_exporter.ExpenseExporter
 	reportSender                reportsender.ReportSender
 	totalMessageConsumedCounter *prometheus.CounterVec
 }
@@ -33,6 +36,7 @@ func NewReportRequestReceiver(
 	broker messagebroker.MessageBroker,
 	queue string,
 	expenseReporter expense_reporter.ExpenseReporter,
+	expenseExporter expense_exporter.ExpenseExporter,
 	reportSender reportsender.ReportSender,
 	totalMessageConsumedCounter *prometheus.CounterVec,
 ) ReportRequestReceiver {
@@ -40,6 +44,7 @@ func NewReportRequestReceiver(
 		broker:                      broker,
 		queue:                       queue,
 		expenseReporter:             expenseReporter,
+		expenseExporter:             expenseExporter,
 		reportSender:                reportSender,
 		totalMessageConsumedCounter: totalMessageConsumedCounter,
 	}
@@ -93,15 +98,38 @@ func (r *reportRequestReceiver) Start(ctx context.Context) error {
 				return err
 			}
 
+			budgetID := reportRequest.BudgetID
+			if budgetID == 0 {
+				budgetID = reportRequest.UserID
+			}
+
+			if reportRequest.Format != "" {
+				if err := r.sendExport(wrapedCtx, reportRequest, budgetID); err != nil {
+					return err
+				}
+				continue
+			}
+
+			if reportRequest.Trend != "" {
+				if err := r.sendTrend(wrapedCtx, reportRequest, budgetID); err != nil {
+					return err
+				}
+				continue
+			}
+
 			report, err := r.expenseReporter.GetReport(
 				wrapedCtx,
 				reportRequest.Period,
+				reportRequest.Range,
 				reportRequest.Currency,
-				reportRequest.UserID,
+				reportRequest.Account,
+				budgetID,
 			)
 			if err != nil {
 				return err
 			}
+			report.UserID = reportRequest.UserID
+			report.ChatID = reportRequest.ChatID
 
 			if err := r.reportSender.Send(wrapedCtx, report); err != nil {
 				return err
@@ -111,6 +139,36 @@ func (r *reportRequestReceiver) Start(ctx context.Context) error {
 	}
 }
 
+// sendExport выгружает траты бюджета в файл и отправляет его получателю
+func (r *reportRequestReceiver) sendExport(ctx context.Context, request *reportrequester.ReportRequest, budgetID int64) error {
+	export, err := r.expenseExporter.Export(ctx, request.Format, request.Range, request.Currency, budgetID)
+	if err != nil {
+		return err
+	}
+	export.UserID = request.UserID
+	export.ChatID = request.ChatID
+
+	return r.reportSender.SendExport(ctx, export)
+}
+
+// sendTrend группирует траты бюджета по интервалам и отправляет отчет получателю
+func (r *reportRequestReceiver) sendTrend(ctx context.Context, request *reportrequester.ReportRequest, budgetID int64) error {
+	trend, err := r.expenseReporter.GetTrend(ctx, model.TrendQuery{
+		Range:      request.Range,
+		Bucket:     request.Trend,
+		ByCategory: request.ByCategory,
+		Location:   model.LoadLocation(request.Timezone),
+		WeekStart:  request.WeekStart,
+	}, request.Currency, budgetID)
+	if err != nil {
+		return err
+	}
+	trend.UserID = request.UserID
+	trend.ChatID = request.ChatID
+
+	return r.reportSender.SendTrend(ctx, trend)
+}
+
 func extractTraceFromMeta(ctx context.Context, span opentracing.Span, meta []messagebroker.MetaItem) (opentracing.Span, context.Context, error) {
 	for _, v := range meta {
 		if v.Key == "trace" {
@@ -35,16 +35,44 @@ func (m *MockReportRequester) EXPECT() *MockReportRequesterMockRecorder {
 	return m.recorder
 }
 
+// SendRequestExport mocks base method.
+func (m *MockReportRequester) SendRequestExport(ctx context.Context, userID, chatID, budgetID int64, format model.ExportFormat, dateRange model.DateRange, currency string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendRequestExport", ctx, userID, chatID, budgetID, format, dateRange, currency)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendRequestExport indicates an expected call of SendRequestExport.
+func (mr *MockReportRequesterMockRecorder) SendRequestExport(ctx, userID, chatID, budgetID, format, dateRange, currency interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendRequestExport", reflect.TypeOf((*MockReportRequester)(nil).SendRequestExport), ctx, userID, chatID, budgetID, format, dateRange, currency)
+}
+
 // SendRequestReport mocks base method.
-func (m *MockReportRequester) SendRequestReport(ctx context.Context, userID int64, period model.ExpensePeriod, currency string) error {
+func (m *MockReportRequester) SendRequestReport(ctx context.Context, userID, chatID, budgetID int64, period model.ExpensePeriod, dateRange model.DateRange, currency, account string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SendRequestReport", ctx, userID, period, currency)
+	ret := m.ctrl.Call(m, "SendRequestReport", ctx, userID, chatID, budgetID, period, dateRange, currency, account)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // SendRequestReport indicates an expected call of SendRequestReport.
-func (mr *MockReportRequesterMockRecorder) SendRequestReport(ctx, userID, period, currency interface{}) *gomock.Call {
+func (mr *MockReportRequesterMockRecorder) SendRequestReport(ctx, userID, chatID, budgetID, period, dateRange, currency, account interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendRequestReport", reflect.TypeOf((*MockReportRequester)(nil).SendRequestReport), ctx, userID, chatID, budgetID, period, dateRange, currency, account)
+}
+
+// SendRequestTrend mocks base method.
+func (m *MockReportRequester) SendRequestTrend(ctx context.Context, userID, chatID, budgetID int64, bucket model.TrendBucket, byCategory bool, dateRange model.DateRange, settings model.UserSettings) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendRequestTrend", ctx, userID, chatID, budgetID, bucket, byCategory, dateRange, settings)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendRequestTrend indicates an expected call of SendRequestTrend.
+func (mr *MockReportRequesterMockRecorder) SendRequestTrend(ctx, userID, chatID, budgetID, bucket, byCategory, dateRange, settings interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendRequestReport", reflect.TypeOf((*MockReportRequester)(nil).SendRequestReport), ctx, userID, period, currency)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendRequestTrend", reflect.TypeOf((*MockReportRequester)(nil).SendRequestTrend), ctx, userID, chatID, budgetID, bucket, byCategory, dateRange, settings)
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/opentracing/opentracing-go"
 	"github.com/opentracing/opentracing-go/ext"
@@ -14,13 +15,47 @@ import (
 )
 
 type ReportRequest struct {
-	UserID   int64
+	UserID   int64 // получатель отчета
+	ChatID   int64 // чат, в который отправляется отчет, 0 - личный чат UserID
+	BudgetID int64 // бюджет, по которому строится отчет, 0 - личный бюджет UserID
 	Period   model.ExpensePeriod
+	Range    model.DateRange // только для model.Custom
 	Currency string
+	Account  string // пусто - траты по всем счетам
+	// Format пусто - текстовый отчет, иначе выгрузка трат за Range в файл
+	Format model.ExportFormat
+	// Trend не пусто - отчет о динамике трат за Range по интервалам
+	Trend      model.TrendBucket
+	ByCategory bool
+	Timezone   string // часовой пояс пользователя для границ интервалов
+	WeekStart  time.Weekday
 }
 
 type ReportRequester interface {
-	SendRequestReport(ctx context.Context, userID int64, period model.ExpensePeriod, currency string) error
+	SendRequestReport(
+		ctx context.Context,
+		userID, chatID, budgetID int64,
+		period model.ExpensePeriod,
+		dateRange model.DateRange,
+		currency, account string,
+	) error
+	// SendRequestExport запрашивает выгрузку трат бюджета budgetID за диапазон дат в файл
+	SendRequestExport(
+		ctx context.Context,
+		userID, chatID, budgetID int64,
+		format model.ExportFormat,
+		dateRange model.DateRange,
+		currency string,
+	) error
+	// SendRequestTrend запрашивает отчет о динамике трат бюджета budgetID за диапазон дат
+	SendRequestTrend(
+		ctx context.Context,
+		userID, chatID, budgetID int64,
+		bucket model.TrendBucket,
+		byCategory bool,
+		dateRange model.DateRange,
+		settings model.UserSettings,
+	) error
 }
 
 type reportRequester struct {
@@ -37,14 +72,81 @@ func NewReportRequester(broker messagebroker.MessageBroker, queueName string, to
 	}
 }
 
-func (r *reportRequester) SendRequestReport(ctx context.Context, userID int64, period model.ExpensePeriod, currency string) error {
+func (r *reportRequester) SendRequestReport(
+	ctx context.Context,
+	userID, chatID, budgetID int64,
+	period model.ExpensePeriod,
+	dateRange model.DateRange,
+	currency, account string,
+) error {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "SendRequestReport")
 	ext.SpanKindRPCClient.Set(span)
 	defer span.Finish()
 
-	UID := fmt.Sprintf("%d", userID)
+	return r.produce(ctx, span, ReportRequest{
+		UserID:   userID,
+		ChatID:   chatID,
+		BudgetID: budgetID,
+		Currency: currency,
+		Period:   period,
+		Range:    dateRange,
+		Account:  account,
+	})
+}
+
+func (r *reportRequester) SendRequestExport(
+	ctx context.Context,
+	userID, chatID, budgetID int64,
+	format model.ExportFormat,
+	dateRange model.DateRange,
+	currency string,
+) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "SendRequestExport")
+	ext.SpanKindRPCClient.Set(span)
+	defer span.Finish()
+
+	// выгрузка идет тем же топиком, что и отчеты: большой файл не блокирует обработку сообщений
+	return r.produce(ctx, span, ReportRequest{
+		UserID:   userID,
+		ChatID:   chatID,
+		BudgetID: budgetID,
+		Currency: currency,
+		Period:   model.Custom,
+		Range:    dateRange,
+		Format:   format,
+	})
+}
+
+func (r *reportRequester) SendRequestTrend(
+	ctx context.Context,
+	userID, chatID, budgetID int64,
+	bucket model.TrendBucket,
+	byCategory bool,
+	dateRange model.DateRange,
+	settings model.UserSettings,
+) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "SendRequestTrend")
+	ext.SpanKindRPCClient.Set(span)
+	defer span.Finish()
+
+	return r.produce(ctx, span, ReportRequest{
+		UserID:     userID,
+		ChatID:     chatID,
+		BudgetID:   budgetID,
+		Currency:   settings.Currency,
+		Period:     model.Custom,
+		Range:      dateRange,
+		Trend:      bucket,
+		ByCategory: byCategory,
+		Timezone:   settings.Timezone,
+		WeekStart:  settings.WeekStart,
+	})
+}
+
+// produce отправляет запрос в брокер с контекстом трейса
+func (r *reportRequester) produce(ctx context.Context, span opentracing.Span, request ReportRequest) error {
+	UID := fmt.Sprintf("%d", request.UserID)
 
-	request := ReportRequest{UserID: userID, Currency: currency, Period: period}
 	value, err := json.Marshal(request)
 	if err != nil {
 		return err
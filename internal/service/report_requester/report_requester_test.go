@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/opentracing/opentracing-go"
@@ -25,6 +26,8 @@ func TestSendRequestReport(t *testing.T) {
 	value, err := json.Marshal(ReportRequest{
 		Period:   model.Week,
 		UserID:   123,
+		ChatID:   123,
+		BudgetID: 123,
 		Currency: "RUB",
 	})
 
@@ -50,6 +53,104 @@ func TestSendRequestReport(t *testing.T) {
 
 	requester := NewReportRequester(client, "queue", nil)
 
-	err = requester.SendRequestReport(ctx, 123, model.Week, "RUB")
+	err = requester.SendRequestReport(ctx, 123, 123, 123, model.Week, model.DateRange{}, "RUB", "")
+	assert.Nil(t, err)
+}
+
+func TestSendRequestExport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	span, wrapedCtx := opentracing.StartSpanFromContext(ctx, "wrap1")
+
+	client := clientmocks.NewMockMessageBroker(ctrl)
+
+	dateRange := model.NewDateRange(time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+	value, err := json.Marshal(ReportRequest{
+		Period:   model.Custom,
+		Range:    dateRange,
+		UserID:   123,
+		ChatID:   -500,
+		BudgetID: -500,
+		Currency: "RUB",
+		Format:   model.XLSXExportFormat,
+	})
+
+	assert.Nil(t, err)
+
+	metaValue, err := tracer.InjectTracerContext(span)
+
+	assert.Nil(t, err)
+
+	client.EXPECT().Produce(
+		wrapedCtx,
+		"queue",
+		messagebroker.Message{
+			Key:   "123",
+			Value: value,
+			Meta: []messagebroker.MetaItem{
+				{
+					Key:   "trace",
+					Value: metaValue,
+				},
+			},
+		})
+
+	requester := NewReportRequester(client, "queue", nil)
+
+	err = requester.SendRequestExport(ctx, 123, -500, -500, model.XLSXExportFormat, dateRange, "RUB")
+	assert.Nil(t, err)
+}
+
+func TestSendRequestTrend(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	span, wrapedCtx := opentracing.StartSpanFromContext(ctx, "wrap1")
+
+	client := clientmocks.NewMockMessageBroker(ctrl)
+
+	dateRange := model.NewDateRange(time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC), time.Date(2022, 11, 1, 0, 0, 0, 0, time.UTC))
+	value, err := json.Marshal(ReportRequest{
+		Period:     model.Custom,
+		Range:      dateRange,
+		UserID:     123,
+		ChatID:     123,
+		BudgetID:   123,
+		Currency:   "RUB",
+		Trend:      model.WeekTrendBucket,
+		ByCategory: true,
+		Timezone:   "Europe/Moscow",
+		WeekStart:  time.Sunday,
+	})
+
+	assert.Nil(t, err)
+
+	metaValue, err := tracer.InjectTracerContext(span)
+
+	assert.Nil(t, err)
+
+	client.EXPECT().Produce(
+		wrapedCtx,
+		"queue",
+		messagebroker.Message{
+			Key:   "123",
+			Value: value,
+			Meta: []messagebroker.MetaItem{
+				{
+					Key:   "trace",
+					Value: metaValue,
+				},
+			},
+		})
+
+	requester := NewReportRequester(client, "queue", nil)
+
+	err = requester.SendRequestTrend(ctx, 123, 123, 123, model.WeekTrendBucket, true, dateRange, model.UserSettings{
+		UserID:    123,
+		Currency:  "RUB",
+		Timezone:  "Europe/Moscow",
+		WeekStart: time.Sunday,
+	})
 	assert.Nil(t, err)
 }
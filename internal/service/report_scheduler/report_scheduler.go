@@ -0,0 +1,174 @@
+package reportscheduler
+
+import (
+	"context"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/model"
+	repo "gitlab.ozon.dev/cranky4/tg-bot/internal/repository"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/service/logger"
+	reportrequester "gitlab.ozon.dev/cranky4/tg-bot/internal/service/report_requester"
+)
+
+const (
+	defaultInterval  = time.Minute
+	defaultBatchSize = 100
+
+	runDueErrMsg = "ошибка в методе runDue"
+)
+
+type ReportScheduler interface {
+	Start(ctx context.Context) error
+	// RunDue запрашивает отчеты по подпискам, время отправки которых наступило к now
+	RunDue(ctx context.Context, now time.Time) error
+}
+
+type reportScheduler struct {
+	subscriptionsRepo repo.ReportSubscriptionsRepository
+	settingsRepo      repo.UserSettingsRepository
+	budgetsRepo       repo.BudgetsRepository
+	reportRequester   reportrequester.ReportRequester
+	interval          time.Duration
+	batchSize         int
+}
+
+func NewReportScheduler(
+	subscriptionsRepo repo.ReportSubscriptionsRepository,
+	settingsRepo repo.UserSettingsRepository,
+	budgetsRepo repo.BudgetsRepository,
+	reportRequester reportrequester.ReportRequester,
+	interval time.Duration,
+	batchSize int,
+) ReportScheduler {
+	if interval <= 0 {
+		interval = defaultInterval
+	}
+	if batchSize <= 0 {
+		batchSize = defaultBatchSize
+	}
+
+	return &reportScheduler{
+		subscriptionsRepo: subscriptionsRepo,
+		settingsRepo:      settingsRepo,
+		budgetsRepo:       budgetsRepo,
+		reportRequester:   reportRequester,
+		interval:          interval,
+		batchSize:         batchSize,
+	}
+}
+
+func (s *reportScheduler) Start(ctx context.Context) error {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case now := <-ticker.C:
+			if err := s.RunDue(ctx, now); err != nil {
+				logger.Error(err.Error(), logger.LogDataItem{
+					Key: "service", Value: "REPORT_SCHEDULER",
+				})
+			}
+		}
+	}
+}
+
+func (s *reportScheduler) RunDue(ctx context.Context, now time.Time) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "ReportScheduler_RunDue")
+	defer span.Finish()
+
+	for {
+		subscriptions, err := s.subscriptionsRepo.GetDueSubscriptions(ctx, now, s.batchSize)
+		if err != nil {
+			return errors.Wrap(err, runDueErrMsg)
+		}
+
+		failed := 0
+		for _, subscription := range subscriptions {
+			if err = s.requestReport(ctx, subscription, now); err != nil {
+				failed++
+				logger.Error(
+					err.Error(),
+					logger.LogDataItem{Key: "service", Value: "REPORT_SCHEDULER"},
+					logger.LogDataItem{Key: "userId", Value: subscription.UserId},
+				)
+			}
+		}
+
+		// неудачные подписки остаются в очереди, повторим их на следующем тике
+		if len(subscriptions) < s.batchSize || failed == len(subscriptions) {
+			return nil
+		}
+	}
+}
+
+// requestReport переносит подписку на следующий запуск и запрашивает отчет.
+// Отчет запрашивает только реплика, которая успела перенести подписку,
+// если запросить отчет не удалось, подписка возвращается на прежнее время
+func (s *reportScheduler) requestReport(ctx context.Context, subscription model.ReportSubscription, now time.Time) error {
+	settings, found, err := s.settingsRepo.GetSettings(ctx, subscription.UserId)
+	if err != nil {
+		return err
+	}
+	if !found {
+		settings = model.DefaultUserSettings(subscription.UserId)
+	}
+
+	// пропущенные запуски не догоняем, следующий считаем от текущего момента
+	nextRunAt := subscription.Schedule.NextRun(now, settings.Location())
+	rescheduled, err := s.subscriptionsRepo.Reschedule(ctx, subscription, nextRunAt)
+	if err != nil || !rescheduled {
+		return err
+	}
+
+	if err = s.sendReportRequest(ctx, subscription, settings, now); err != nil {
+		rescheduledSubscription := subscription
+		rescheduledSubscription.NextRunAt = nextRunAt
+		if _, releaseErr := s.subscriptionsRepo.Reschedule(ctx, rescheduledSubscription, subscription.NextRunAt); releaseErr != nil {
+			logger.Error(
+				releaseErr.Error(),
+				logger.LogDataItem{Key: "service", Value: "REPORT_SCHEDULER"},
+				logger.LogDataItem{Key: "userId", Value: subscription.UserId},
+			)
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// sendReportRequest запрашивает отчет подписки за период, закончившийся к now
+func (s *reportScheduler) sendReportRequest(
+	ctx context.Context,
+	subscription model.ReportSubscription,
+	settings model.UserSettings,
+	now time.Time,
+) error {
+	// отчет по бюджету, активному у пользователя в момент отправки
+	budgetID := subscription.UserId
+	member, found, err := s.budgetsRepo.GetActiveMember(ctx, subscription.UserId)
+	if err != nil {
+		return err
+	}
+	if found {
+		budgetID = member.BudgetID
+	}
+
+	dateRange := subscription.ReportRange(now.In(settings.Location()), settings)
+
+	return s.reportRequester.SendRequestReport(
+		ctx,
+		subscription.UserId,
+		subscription.UserId,
+		budgetID,
+		subscription.Schedule.Period,
+		dateRange,
+		settings.Currency,
+		"",
+	)
+}
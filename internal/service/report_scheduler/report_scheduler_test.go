@@ -0,0 +1,116 @@
+package reportscheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/model"
+	memoryrepo "gitlab.ozon.dev/cranky4/tg-bot/internal/repository/memory"
+	repomocks "gitlab.ozon.dev/cranky4/tg-bot/internal/repository/mocks"
+	report_requester_mock "gitlab.ozon.dev/cranky4/tg-bot/internal/service/report_requester/mocks"
+)
+
+func TestRunDueShouldRequestReportOnceForSeveralReplicas(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+	_, wrapedCtx := opentracing.StartSpanFromContext(ctx, "ReportScheduler_RunDue")
+
+	loc := time.FixedZone("UTC+3", 3*60*60)
+	now := time.Date(2022, 11, 7, 6, 0, 30, 0, time.UTC)
+
+	subscriptionsRepo := memoryrepo.NewReportSubscriptionsRepository()
+	subscription := model.ReportSubscription{
+		UserId:    123,
+		Schedule:  model.Schedule{Period: model.Week, Weekday: time.Monday, Hour: 9},
+		NextRunAt: time.Date(2022, 11, 7, 6, 0, 0, 0, time.UTC),
+	}
+	assert.NoError(t, subscriptionsRepo.SaveSubscription(ctx, subscription))
+
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+	settingsRepo.EXPECT().GetSettings(gomock.Any(), int64(123)).Return(model.UserSettings{
+		UserID:     123,
+		Currency:   "USD",
+		PeriodMode: model.CalendarPeriodMode,
+		WeekStart:  time.Monday,
+		Timezone:   "Europe/Moscow",
+	}, true, nil).AnyTimes()
+
+	moscow, err := time.LoadLocation("Europe/Moscow")
+	assert.NoError(t, err)
+	weekStart := time.Date(2022, 10, 31, 0, 0, 0, 0, moscow)
+
+	budgetsRepo := memoryrepo.NewBudgetsRepository()
+
+	reportRequester := report_requester_mock.NewMockReportRequester(ctrl)
+	reportRequester.EXPECT().SendRequestReport(
+		gomock.Any(),
+		int64(123),
+		int64(123),
+		int64(123),
+		model.Week,
+		model.NewDateRange(weekStart, weekStart.AddDate(0, 0, 7)),
+		"USD",
+		"",
+	).Times(1)
+
+	replica1 := NewReportScheduler(subscriptionsRepo, settingsRepo, budgetsRepo, reportRequester, time.Minute, 10)
+	replica2 := NewReportScheduler(subscriptionsRepo, settingsRepo, budgetsRepo, reportRequester, time.Minute, 10)
+
+	// вторая реплика получила подписку до того, как первая ее перенесла
+	due, err := subscriptionsRepo.GetDueSubscriptions(ctx, now, 10)
+	assert.NoError(t, err)
+	assert.Len(t, due, 1)
+
+	assert.NoError(t, replica1.RunDue(wrapedCtx, now))
+	assert.NoError(t, replica2.(*reportScheduler).requestReport(wrapedCtx, due[0], now))
+
+	subscriptions, err := subscriptionsRepo.GetSubscriptions(ctx, 123)
+	assert.NoError(t, err)
+	assert.True(t, time.Date(2022, 11, 14, 9, 0, 0, 0, loc).Equal(subscriptions[0].NextRunAt))
+}
+
+func TestRunDueShouldKeepSubscriptionDueWhenRequestFails(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	now := time.Date(2022, 11, 7, 6, 0, 30, 0, time.UTC)
+	nextRunAt := time.Date(2022, 11, 7, 6, 0, 0, 0, time.UTC)
+
+	subscriptionsRepo := memoryrepo.NewReportSubscriptionsRepository()
+	assert.NoError(t, subscriptionsRepo.SaveSubscription(ctx, model.ReportSubscription{
+		UserId:    123,
+		Schedule:  model.Schedule{Period: model.Week, Weekday: time.Monday, Hour: 9},
+		NextRunAt: nextRunAt,
+	}))
+
+	settingsRepo := repomocks.NewMockUserSettingsRepository(ctrl)
+	settingsRepo.EXPECT().GetSettings(gomock.Any(), int64(123)).Return(model.UserSettings{}, false, nil).Times(2)
+
+	reportRequester := report_requester_mock.NewMockReportRequester(ctrl)
+	gomock.InOrder(
+		reportRequester.EXPECT().SendRequestReport(gomock.Any(), int64(123), int64(123), int64(123), model.Week, gomock.Any(), "RUB", "").
+			Return(errors.New("kafka недоступна")),
+		reportRequester.EXPECT().SendRequestReport(gomock.Any(), int64(123), int64(123), int64(123), model.Week, gomock.Any(), "RUB", ""),
+	)
+
+	scheduler := NewReportScheduler(subscriptionsRepo, settingsRepo, memoryrepo.NewBudgetsRepository(), reportRequester, time.Minute, 10)
+
+	assert.NoError(t, scheduler.RunDue(ctx, now))
+
+	// отчет за период не потерян: подписка осталась в очереди и отправится на следующем тике
+	due, err := subscriptionsRepo.GetDueSubscriptions(ctx, now, 10)
+	assert.NoError(t, err)
+	assert.Len(t, due, 1)
+	assert.True(t, nextRunAt.Equal(due[0].NextRunAt))
+
+	assert.NoError(t, scheduler.RunDue(ctx, now.Add(time.Minute)))
+
+	due, err = subscriptionsRepo.GetDueSubscriptions(ctx, now.Add(time.Minute), 10)
+	assert.NoError(t, err)
+	assert.Empty(t, due)
+}
@@ -9,6 +9,7 @@ import (
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
+	expense_exporter "gitlab.ozon.dev/cranky4/tg-bot/internal/service/expense_exporter"
 	expense_reporter "gitlab.ozon.dev/cranky4/tg-bot/internal/service/expense_reporter"
 )
 
@@ -48,3 +49,31 @@ func (mr *MockReportSenderMockRecorder) Send(ctx, report interface{}) *gomock.Ca
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockReportSender)(nil).Send), ctx, report)
 }
+
+// SendExport mocks base method.
+func (m *MockReportSender) SendExport(ctx context.Context, export *expense_exporter.ExpenseExport) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendExport", ctx, export)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendExport indicates an expected call of SendExport.
+func (mr *MockReportSenderMockRecorder) SendExport(ctx, export interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendExport", reflect.TypeOf((*MockReportSender)(nil).SendExport), ctx, export)
+}
+
+// SendTrend mocks base method.
+func (m *MockReportSender) SendTrend(ctx context.Context, trend *expense_reporter.TrendReport) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendTrend", ctx, trend)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendTrend indicates an expected call of SendTrend.
+func (mr *MockReportSenderMockRecorder) SendTrend(ctx, trend interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendTrend", reflect.TypeOf((*MockReportSender)(nil).SendTrend), ctx, trend)
+}
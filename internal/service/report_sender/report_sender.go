@@ -7,16 +7,23 @@ import (
 
 	"github.com/opentracing/opentracing-go"
 	"gitlab.ozon.dev/cranky4/tg-bot/internal/config"
+	"gitlab.ozon.dev/cranky4/tg-bot/internal/service/expense_exporter"
 	"gitlab.ozon.dev/cranky4/tg-bot/internal/service/expense_reporter"
 	"gitlab.ozon.dev/cranky4/tg-bot/internal/utils/tracer"
 	api "gitlab.ozon.dev/cranky4/tg-bot/pkg/reporter_v1"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
+// maxMessageSize файл выгрузки передается одним сообщением, Telegram принимает от бота файлы до 50 МБ
+const maxMessageSize = 50 << 20
+
 type ReportSender interface {
 	Send(ctx context.Context, report *expense_reporter.ExpenseReport) error
+	SendExport(ctx context.Context, export *expense_exporter.ExpenseExport) error
+	SendTrend(ctx context.Context, trend *expense_reporter.TrendReport) error
 }
 
 type reportSender struct {
@@ -33,9 +40,111 @@ func (s *reportSender) Send(ctx context.Context, report *expense_reporter.Expens
 	span, ctx := opentracing.StartSpanFromContext(ctx, "ReportSender_Send")
 	defer span.Finish()
 
+	timeline := make([]*api.TimelinePoint, 0, len(report.Timeline))
+	for _, point := range report.Timeline {
+		timeline = append(timeline, &api.TimelinePoint{
+			From:   timestamppb.New(point.From),
+			Amount: point.Amount,
+		})
+	}
+
+	rows := make([]*api.ReportRow, 0, len(report.Rows))
+	for _, row := range report.Rows {
+		rows = append(rows, &api.ReportRow{
+			Category:   row.Category,
+			Amount:     row.Amount,
+			Share:      row.Share,
+			Previous:   row.Previous,
+			Limit:      row.Limit,
+			LimitUsage: row.LimitUsage,
+		})
+	}
+
+	return s.call(ctx, span, func(ctx context.Context, c api.ReporterV1Client) error {
+		_, err := c.SendReport(ctx, &api.SendReportRequest{
+			Rows:                  rows,
+			UserId:                report.UserID,
+			ChatId:                report.ChatID,
+			Period:                int64(report.Period),
+			RangeFrom:             timestamppb.New(report.Range.From),
+			RangeTo:               timestamppb.New(report.Range.To),
+			TotalIncome:           report.TotalIncome,
+			TotalExpenses:         report.TotalExpenses,
+			Balance:               report.Balance,
+			Account:               report.Account,
+			BudgetId:              report.BudgetID,
+			Members:               report.Members,
+			Timeline:              timeline,
+			PreviousFrom:          timestamppb.New(report.PreviousRange.From),
+			PreviousTo:            timestamppb.New(report.PreviousRange.To),
+			PreviousTotalExpenses: report.PreviousTotalExpenses,
+		})
+
+		return err
+	})
+}
+
+func (s *reportSender) SendTrend(ctx context.Context, trend *expense_reporter.TrendReport) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "ReportSender_SendTrend")
+	defer span.Finish()
+
+	points := make([]*api.TrendPoint, 0, len(trend.Points))
+	for _, point := range trend.Points {
+		points = append(points, &api.TrendPoint{
+			From:     timestamppb.New(point.From),
+			Category: point.Category,
+			Amount:   point.Amount,
+		})
+	}
+
+	return s.call(ctx, span, func(ctx context.Context, c api.ReporterV1Client) error {
+		_, err := c.SendTrend(ctx, &api.SendTrendRequest{
+			UserId:     trend.UserID,
+			ChatId:     trend.ChatID,
+			BudgetId:   trend.BudgetID,
+			Bucket:     string(trend.Bucket),
+			ByCategory: trend.ByCategory,
+			RangeFrom:  timestamppb.New(trend.Range.From),
+			RangeTo:    timestamppb.New(trend.Range.To),
+			Points:     points,
+		})
+
+		return err
+	})
+}
+
+func (s *reportSender) SendExport(ctx context.Context, export *expense_exporter.ExpenseExport) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "ReportSender_SendExport")
+	defer span.Finish()
+
+	return s.call(ctx, span, func(ctx context.Context, c api.ReporterV1Client) error {
+		_, err := c.SendExport(ctx, &api.SendExportRequest{
+			UserId:    export.UserID,
+			ChatId:    export.ChatID,
+			BudgetId:  export.BudgetID,
+			Format:    string(export.Format),
+			RangeFrom: timestamppb.New(export.Range.From),
+			RangeTo:   timestamppb.New(export.Range.To),
+			Count:     int64(export.Count),
+			FileName:  export.File.Name,
+			MimeType:  export.File.MimeType,
+			Data:      export.File.Data,
+		})
+
+		return err
+	})
+}
+
+// call подключается к боту и вызывает метод с контекстом трейса
+func (s *reportSender) call(ctx context.Context, span opentracing.Span, method func(ctx context.Context, c api.ReporterV1Client) error) error {
 	addr := fmt.Sprintf(":%d", s.grpcConfig.Port)
 
-	conn, err := grpc.DialContext(ctx, addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := grpc.DialContext(
+		ctx,
+		addr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultCallOptions(grpc.MaxCallSendMsgSize(maxMessageSize)),
+	)
 	if err != nil {
 		return err
 	}
@@ -55,10 +164,5 @@ func (s *reportSender) Send(ctx context.Context, report *expense_reporter.Expens
 
 	ctx = metadata.AppendToOutgoingContext(ctx, "trace", string(encodedTraceContext))
 
-	_, err = c.SendReport(ctx, &api.SendReportRequest{
-		Rows:   report.Rows,
-		UserId: report.UserID,
-	})
-
-	return err
+	return method(ctx, c)
 }
@@ -0,0 +1,183 @@
+package chart
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	width  = 800
+	height = 900
+
+	pieCenterX = width / 2
+	pieCenterY = 220
+	pieRadius  = 200
+
+	plotLeft   = 90
+	plotRight  = width - 20
+	plotTop    = 480
+	plotBottom = height - 50
+
+	labelGap = 12 // промежуток между подписями столбцов
+
+	errRenderMessage = "ошибка отрисовки графика"
+)
+
+var (
+	background = color.RGBA{R: 255, G: 255, B: 255, A: 255}
+	axisColor  = color.RGBA{R: 120, G: 120, B: 120, A: 255}
+	gridColor  = color.RGBA{R: 225, G: 225, B: 225, A: 255}
+	barColor   = color.RGBA{R: 85, G: 172, B: 238, A: 255}
+	textColor  = color.RGBA{R: 60, G: 60, B: 60, A: 255}
+)
+
+// Color цвет доли круговой диаграммы, Mark - эмодзи того же цвета для легенды в подписи к картинке
+type Color struct {
+	RGBA color.RGBA
+	Mark string
+}
+
+// Palette цвета долей по порядку, долей не больше, чем цветов
+var Palette = []Color{
+	{RGBA: color.RGBA{R: 221, G: 46, B: 68, A: 255}, Mark: "🟥"},
+	{RGBA: color.RGBA{R: 244, G: 144, B: 12, A: 255}, Mark: "🟧"},
+	{RGBA: color.RGBA{R: 253, G: 203, B: 88, A: 255}, Mark: "🟨"},
+	{RGBA: color.RGBA{R: 120, G: 177, B: 89, A: 255}, Mark: "🟩"},
+	{RGBA: color.RGBA{R: 85, G: 172, B: 238, A: 255}, Mark: "🟦"},
+	{RGBA: color.RGBA{R: 170, G: 142, B: 214, A: 255}, Mark: "🟪"},
+	{RGBA: color.RGBA{R: 193, G: 105, B: 79, A: 255}, Mark: "🟫"},
+	{RGBA: color.RGBA{R: 49, G: 55, B: 61, A: 255}, Mark: "⬛"},
+}
+
+// Bar столбец графика трат по времени
+type Bar struct {
+	Label string
+	Value float64
+}
+
+// Chart данные картинки отчета
+type Chart struct {
+	Shares []float64 // доли круговой диаграммы, цвет доли i - Palette[i]
+	Bars   []Bar
+}
+
+// Render рисует в PNG круговую диаграмму долей и под ней столбцы трат по времени
+func Render(chart Chart) ([]byte, error) {
+	if len(chart.Shares) > len(Palette) {
+		return nil, errors.Errorf("%s: долей больше, чем цветов", errRenderMessage)
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), &image.Uniform{C: background}, image.Point{}, draw.Src)
+
+	drawPie(img, chart.Shares)
+	drawBars(img, chart.Bars)
+
+	var data bytes.Buffer
+	if err := png.Encode(&data, img); err != nil {
+		return nil, errors.Wrap(err, errRenderMessage)
+	}
+
+	return data.Bytes(), nil
+}
+
+// drawPie рисует доли по часовой стрелке от верхней точки круга
+func drawPie(img *image.RGBA, shares []float64) {
+	var total float64
+	for _, share := range shares {
+		if share > 0 {
+			total += share
+		}
+	}
+	if total == 0 {
+		return
+	}
+
+	// границы долей в долях круга
+	bounds := make([]float64, len(shares))
+	var cumulative float64
+	for i, share := range shares {
+		if share > 0 {
+			cumulative += share
+		}
+		bounds[i] = cumulative / total
+	}
+
+	for y := pieCenterY - pieRadius; y <= pieCenterY+pieRadius; y++ {
+		for x := pieCenterX - pieRadius; x <= pieCenterX+pieRadius; x++ {
+			dx, dy := float64(x-pieCenterX), float64(y-pieCenterY)
+			if dx*dx+dy*dy > pieRadius*pieRadius {
+				continue
+			}
+
+			angle := math.Atan2(dx, -dy)
+			if angle < 0 {
+				angle += 2 * math.Pi
+			}
+			position := angle / (2 * math.Pi)
+
+			for i, bound := range bounds {
+				if position < bound || i == len(bounds)-1 {
+					img.SetRGBA(x, y, Palette[i].RGBA)
+					break
+				}
+			}
+		}
+	}
+}
+
+func drawBars(img *image.RGBA, bars []Bar) {
+	if len(bars) == 0 {
+		return
+	}
+
+	var max float64
+	for _, bar := range bars {
+		if bar.Value > max {
+			max = bar.Value
+		}
+	}
+
+	fillRect(img, image.Rect(plotLeft, plotTop, plotRight, plotTop+1), gridColor)
+	fillRect(img, image.Rect(plotLeft, plotTop, plotLeft+1, plotBottom+1), axisColor)
+	fillRect(img, image.Rect(plotLeft, plotBottom, plotRight, plotBottom+1), axisColor)
+
+	maxLabel := strconv.FormatFloat(math.Ceil(max), 'f', 0, 64)
+	drawText(img, plotLeft-textWidth(maxLabel)-8, plotTop-glyphHeight/2, maxLabel)
+	drawText(img, plotLeft-textWidth("0")-8, plotBottom-glyphHeight/2, "0")
+
+	slot := float64(plotRight-plotLeft) / float64(len(bars))
+	barWidth := int(math.Max(1, slot*0.7))
+
+	// подписываем каждый step-й столбец, чтобы подписи не наезжали друг на друга
+	step := 1
+	for _, bar := range bars {
+		if labelSlots := int(math.Ceil(float64(textWidth(bar.Label)+labelGap) / slot)); labelSlots > step {
+			step = labelSlots
+		}
+	}
+
+	for i, bar := range bars {
+		center := plotLeft + int(slot*(float64(i)+0.5))
+
+		if max > 0 && bar.Value > 0 {
+			barHeight := int(math.Max(1, bar.Value/max*float64(plotBottom-plotTop)))
+			fillRect(img, image.Rect(center-barWidth/2, plotBottom-barHeight, center-barWidth/2+barWidth, plotBottom), barColor)
+		}
+
+		if i%step == 0 {
+			drawText(img, center-textWidth(bar.Label)/2, plotBottom+10, bar.Label)
+		}
+	}
+}
+
+func fillRect(img *image.RGBA, rect image.Rectangle, c color.RGBA) {
+	draw.Draw(img, rect, &image.Uniform{C: c}, image.Point{}, draw.Src)
+}
@@ -0,0 +1,37 @@
+package chart
+
+import (
+	"bytes"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderShouldDrawPieClockwiseFromTop(t *testing.T) {
+	data, err := Render(Chart{
+		Shares: []float64{3, 1},
+		Bars:   []Bar{{Label: "01.10", Value: 100}, {Label: "02.10", Value: 0}, {Label: "03.10", Value: 50}},
+	})
+	assert.NoError(t, err)
+
+	img, err := png.Decode(bytes.NewReader(data))
+	assert.NoError(t, err)
+	assert.Equal(t, width, img.Bounds().Dx())
+	assert.Equal(t, height, img.Bounds().Dy())
+
+	// первая доля - три четверти круга от 12 до 9 часов, вторая - последняя четверть
+	assert.Equal(t, Palette[0].RGBA, img.At(pieCenterX+pieRadius/2, pieCenterY-10))
+	assert.Equal(t, Palette[0].RGBA, img.At(pieCenterX-10, pieCenterY+pieRadius/2))
+	assert.Equal(t, Palette[1].RGBA, img.At(pieCenterX-pieRadius/2, pieCenterY-pieRadius/2))
+	assert.Equal(t, background, img.At(0, 0))
+
+	// самый высокий столбец доходит до верха графика
+	slot := (plotRight - plotLeft) / 3
+	assert.Equal(t, barColor, img.At(plotLeft+slot/2, plotTop+1))
+}
+
+func TestRenderShouldRejectSharesOverPalette(t *testing.T) {
+	_, err := Render(Chart{Shares: make([]float64, len(Palette)+1)})
+	assert.Error(t, err)
+}
@@ -0,0 +1,63 @@
+package chart
+
+import "image"
+
+const (
+	// glyphScale размер пикселя шрифта 3x5 на картинке
+	glyphScale   = 3
+	glyphColumns = 3
+	glyphRows    = 5
+	glyphWidth   = (glyphColumns + 1) * glyphScale // с промежутком до следующего символа
+	glyphHeight  = glyphRows * glyphScale
+)
+
+// glyphs пиксельный шрифт для сумм и дат, другие символы рисуются пробелом
+var glyphs = map[rune][glyphRows]string{
+	'0': {"###", "#.#", "#.#", "#.#", "###"},
+	'1': {".#.", "##.", ".#.", ".#.", "###"},
+	'2': {"###", "..#", "###", "#..", "###"},
+	'3': {"###", "..#", "###", "..#", "###"},
+	'4': {"#.#", "#.#", "###", "..#", "..#"},
+	'5': {"###", "#..", "###", "..#", "###"},
+	'6': {"###", "#..", "###", "#.#", "###"},
+	'7': {"###", "..#", "..#", "..#", "..#"},
+	'8': {"###", "#.#", "###", "#.#", "###"},
+	'9': {"###", "#.#", "###", "..#", "###"},
+	'.': {"...", "...", "...", "...", ".#."},
+	'-': {"...", "...", "###", "...", "..."},
+	':': {"...", ".#.", "...", ".#.", "..."},
+}
+
+func textWidth(text string) int {
+	count := len([]rune(text))
+	if count == 0 {
+		return 0
+	}
+
+	return count*glyphWidth - glyphScale
+}
+
+// drawText рисует текст, x и y - левый верхний угол
+func drawText(img *image.RGBA, x, y int, text string) {
+	for _, r := range text {
+		glyph, ok := glyphs[r]
+		if ok {
+			for row, line := range glyph {
+				for column, pixel := range line {
+					if pixel != '#' {
+						continue
+					}
+
+					fillRect(img, image.Rect(
+						x+column*glyphScale,
+						y+row*glyphScale,
+						x+(column+1)*glyphScale,
+						y+(row+1)*glyphScale,
+					), textColor)
+				}
+			}
+		}
+
+		x += glyphWidth
+	}
+}
@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE users_settings (
+    user_id bigint not null primary key,
+    currency varchar(3) not null,
+    created_at timestamp not null default now(),
+    updated_at timestamp not null default now()
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE users_settings;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users_settings ADD COLUMN period_mode varchar(16) not null default 'rolling';
+ALTER TABLE users_settings ADD COLUMN week_start smallint not null default 1; -- 0 - воскресенье, 1 - понедельник
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users_settings DROP COLUMN week_start;
+ALTER TABLE users_settings DROP COLUMN period_mode;
+-- +goose StatementEnd
@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users_settings ADD COLUMN timezone varchar(64) not null default ''; -- пусто - часовой пояс сервера
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users_settings DROP COLUMN timezone;
+-- +goose StatementEnd
@@ -0,0 +1,34 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE expense_categories ADD COLUMN user_id bigint;
+
+-- общие категории копируются каждому пользователю, у которого есть траты или лимиты в них
+INSERT INTO expense_categories (id, name, user_id, created_at)
+SELECT gen_random_uuid(), c.name, u.user_id, c.created_at
+FROM expense_categories c
+    INNER JOIN (
+        SELECT category_id, user_id FROM expenses
+        UNION
+        SELECT category_id, user_id FROM expenses_limits
+    ) u ON u.category_id = c.id
+WHERE c.user_id IS NULL;
+
+UPDATE expenses e SET category_id = uc.id
+FROM expense_categories c, expense_categories uc
+WHERE e.category_id = c.id AND c.user_id IS NULL AND uc.user_id = e.user_id AND uc.name = c.name;
+
+UPDATE expenses_limits el SET category_id = uc.id
+FROM expense_categories c, expense_categories uc
+WHERE el.category_id = c.id AND c.user_id IS NULL AND uc.user_id = el.user_id AND uc.name = c.name;
+
+DELETE FROM expense_categories WHERE user_id IS NULL;
+
+ALTER TABLE expense_categories ALTER COLUMN user_id SET NOT NULL;
+CREATE UNIQUE INDEX idx_expense_categories_user_id_name on expense_categories (user_id, lower(name));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX idx_expense_categories_user_id_name;
+ALTER TABLE expense_categories DROP COLUMN user_id;
+-- +goose StatementEnd
@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE category_rules (
+    user_id bigint not null,
+    type varchar(16) not null,
+    pattern varchar(255) not null,
+    category varchar(255) not null,
+    created_at timestamp not null default now(),
+    updated_at timestamp not null default now()
+);
+
+CREATE UNIQUE INDEX idx_category_rules_user_id_pattern ON category_rules (user_id, lower(pattern));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE category_rules;
+-- +goose StatementEnd
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE expenses_limits DROP CONSTRAINT pr_expense_category_id_user_id;
+ALTER TABLE expenses_limits ALTER COLUMN category_id DROP NOT NULL;
+ALTER TABLE expenses_limits ADD COLUMN period varchar(16) not null default 'month';
+ALTER TABLE expenses_limits ADD COLUMN scope varchar(16) not null default 'category';
+
+CREATE UNIQUE INDEX idx_expenses_limits_category ON expenses_limits (user_id, category_id, period) WHERE scope = 'category';
+CREATE UNIQUE INDEX idx_expenses_limits_total ON expenses_limits (user_id, period) WHERE scope = 'total';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM expenses_limits WHERE scope <> 'category' OR period <> 'month';
+
+DROP INDEX idx_expenses_limits_total;
+DROP INDEX idx_expenses_limits_category;
+
+ALTER TABLE expenses_limits DROP COLUMN scope;
+ALTER TABLE expenses_limits DROP COLUMN period;
+ALTER TABLE expenses_limits ALTER COLUMN category_id SET NOT NULL;
+ALTER TABLE expenses_limits ADD CONSTRAINT pr_expense_category_id_user_id PRIMARY KEY (category_id, user_id);
+-- +goose StatementEnd
@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE expenses_limits ADD COLUMN thresholds varchar(64) not null default '50,80,100';
+
+CREATE TABLE limit_notifications (
+    user_id bigint not null,
+    limit_scope varchar(16) not null,
+    limit_period varchar(16) not null,
+    category_id uuid
+        constraint fk_limit_notifications_category_id_expense_category_id
+            references expense_categories
+            on delete cascade,
+    period_start timestamp not null,
+    threshold smallint not null,
+    created_at timestamp not null default now()
+);
+
+CREATE UNIQUE INDEX idx_limit_notifications_unique ON limit_notifications (
+    user_id, limit_scope, limit_period,
+    COALESCE(category_id, '00000000-0000-0000-0000-000000000000'::uuid),
+    period_start, threshold
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE limit_notifications;
+ALTER TABLE expenses_limits DROP COLUMN thresholds;
+-- +goose StatementEnd
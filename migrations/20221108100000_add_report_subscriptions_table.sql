@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE report_subscriptions (
+    user_id bigint not null,
+    period varchar(16) not null,
+    weekday smallint not null default 0,
+    day smallint not null default 1,
+    hour smallint not null,
+    minute smallint not null,
+    next_run_at timestamptz not null,
+    created_at timestamp not null default now(),
+    updated_at timestamp not null default now(),
+    primary key (user_id, period)
+);
+
+CREATE INDEX idx_report_subscriptions_next_run_at ON report_subscriptions (next_run_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE report_subscriptions;
+-- +goose StatementEnd
@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE recurring_expenses (
+    id uuid primary key,
+    user_id bigint not null,
+    amount bigint not null,
+    currency varchar(3) not null,
+    category varchar(255) not null,
+    period varchar(16) not null,
+    weekday smallint not null default 0,
+    day smallint not null default 1,
+    hour smallint not null default 0,
+    minute smallint not null default 0,
+    next_run_at timestamptz not null,
+    paused boolean not null default false,
+    created_at timestamp not null default now(),
+    updated_at timestamp not null default now()
+);
+
+CREATE INDEX idx_recurring_expenses_user_id ON recurring_expenses (user_id);
+CREATE INDEX idx_recurring_expenses_next_run_at ON recurring_expenses (next_run_at) WHERE NOT paused;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE recurring_expenses;
+-- +goose StatementEnd
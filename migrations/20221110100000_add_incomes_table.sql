@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE incomes (
+    id uuid primary key,
+    user_id bigint not null,
+    amount bigint not null,
+    source varchar(255) not null,
+    datetime timestamp not null,
+    created_at timestamp not null default now()
+);
+
+CREATE INDEX idx_incomes_user_id_datetime ON incomes (user_id, datetime);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE incomes;
+-- +goose StatementEnd
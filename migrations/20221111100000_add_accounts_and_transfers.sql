@@ -0,0 +1,36 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE accounts (
+    id uuid primary key,
+    user_id bigint not null,
+    name varchar(255) not null,
+    currency varchar(3) not null,
+    opening_balance bigint not null default 0,
+    created_at timestamp not null default now()
+);
+
+CREATE UNIQUE INDEX idx_accounts_user_id_name ON accounts (user_id, lower(name));
+
+ALTER TABLE expenses ADD COLUMN account_id uuid null references accounts (id);
+CREATE INDEX idx_expenses_account_id ON expenses (account_id);
+
+CREATE TABLE transfers (
+    id uuid primary key,
+    user_id bigint not null,
+    from_account_id uuid not null references accounts (id),
+    to_account_id uuid not null references accounts (id),
+    amount bigint not null,
+    datetime timestamp not null,
+    created_at timestamp not null default now()
+);
+
+CREATE INDEX idx_transfers_from_account_id ON transfers (from_account_id);
+CREATE INDEX idx_transfers_to_account_id ON transfers (to_account_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE transfers;
+ALTER TABLE expenses DROP COLUMN account_id;
+DROP TABLE accounts;
+-- +goose StatementEnd
@@ -0,0 +1,44 @@
+-- +goose Up
+-- +goose StatementBegin
+-- ИД общих бюджетов отрицательные, чтобы не пересекаться с ИД пользователей в user_id трат, лимитов и категорий
+CREATE SEQUENCE budgets_id_seq;
+
+CREATE TABLE budgets (
+    id bigint primary key default -nextval('budgets_id_seq'),
+    name varchar(255) not null,
+    join_code varchar(16) not null,
+    owner_id bigint not null,
+    created_at timestamp not null default now()
+);
+
+CREATE UNIQUE INDEX idx_budgets_join_code ON budgets (upper(join_code));
+
+CREATE TABLE budget_members (
+    budget_id bigint not null references budgets (id) on delete cascade,
+    user_id bigint not null,
+    name varchar(255) not null default '',
+    role varchar(16) not null,
+    created_at timestamp not null default now(),
+    updated_at timestamp not null default now(),
+    primary key (budget_id, user_id)
+);
+
+CREATE INDEX idx_budget_members_user_id ON budget_members (user_id);
+
+CREATE TABLE active_budgets (
+    user_id bigint primary key,
+    budget_id bigint not null references budgets (id) on delete cascade,
+    updated_at timestamp not null default now()
+);
+
+ALTER TABLE expenses ADD COLUMN author_id bigint null; -- null - трата добавлена владельцем user_id
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE expenses DROP COLUMN author_id;
+DROP TABLE active_budgets;
+DROP TABLE budget_members;
+DROP TABLE budgets;
+DROP SEQUENCE budgets_id_seq;
+-- +goose StatementEnd
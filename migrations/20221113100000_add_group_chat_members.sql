@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+-- участники групповых чатов: бюджет чата хранится с ИД чата без записи в budgets
+ALTER TABLE budget_members DROP CONSTRAINT budget_members_budget_id_fkey;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM budget_members WHERE budget_id NOT IN (SELECT id FROM budgets);
+ALTER TABLE budget_members ADD CONSTRAINT budget_members_budget_id_fkey
+    FOREIGN KEY (budget_id) REFERENCES budgets (id) ON DELETE CASCADE;
+-- +goose StatementEnd
@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE expense_shares (
+    expense_id uuid not null references expenses (id) on delete cascade,
+    budget_id bigint not null,
+    payer_id bigint not null,
+    user_id bigint not null,
+    amount bigint not null,
+    primary key (expense_id, user_id)
+);
+
+CREATE INDEX idx_expense_shares_budget_id ON expense_shares (budget_id);
+
+CREATE TABLE debt_settlements (
+    id uuid primary key,
+    budget_id bigint not null,
+    from_user_id bigint not null,
+    to_user_id bigint not null,
+    amount bigint not null,
+    datetime timestamp not null,
+    created_at timestamp not null default now()
+);
+
+CREATE INDEX idx_debt_settlements_budget_id ON debt_settlements (budget_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE debt_settlements;
+DROP TABLE expense_shares;
+-- +goose StatementEnd
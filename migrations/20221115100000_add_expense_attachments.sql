@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE expense_attachments (
+    expense_id uuid primary key references expenses (id) on delete cascade,
+    user_id bigint not null,
+    storage_key text not null,
+    file_name text not null,
+    mime_type text not null,
+    size bigint not null,
+    created_at timestamp not null default now()
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE expense_attachments;
+-- +goose StatementEnd
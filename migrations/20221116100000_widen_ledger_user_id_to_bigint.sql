@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+-- в user_id трат и лимитов хранится ИД бюджета: у супергрупп он вида -100xxxxxxxxxx и в int не помещается
+ALTER TABLE expenses ALTER COLUMN user_id TYPE bigint;
+ALTER TABLE expenses_limits ALTER COLUMN user_id TYPE bigint;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM expenses WHERE user_id NOT BETWEEN -2147483648 AND 2147483647;
+DELETE FROM expenses_limits WHERE user_id NOT BETWEEN -2147483648 AND 2147483647;
+ALTER TABLE expenses_limits ALTER COLUMN user_id TYPE int;
+ALTER TABLE expenses ALTER COLUMN user_id TYPE int;
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE import_tokens (
+    user_id bigint primary key,
+    token_hash text not null unique,
+    created_at timestamp not null default now()
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE import_tokens;
+-- +goose StatementEnd
@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+-- отметка о добавленной трате регулярной траты, остается и после удаления самой траты
+CREATE TABLE recurring_occurrences (
+    recurring_id uuid not null,
+    occurrence_at timestamptz not null,
+    expense_id uuid not null,
+    created_at timestamp not null default now(),
+    primary key (recurring_id, occurrence_at)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE recurring_occurrences;
+-- +goose StatementEnd
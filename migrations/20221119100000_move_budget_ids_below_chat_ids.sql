@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+-- ИД чатов Telegram по модулю меньше 2^52, новые общие бюджеты получают ИД от -(2^53 + 1) и ниже
+-- и не совпадают с ИД групповых чатов, в которых тоже ведутся траты
+SELECT setval('budgets_id_seq', 9007199254740993, false);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT setval('budgets_id_seq', COALESCE((SELECT max(-id) FROM budgets WHERE id > -9007199254740993), 0) + 1, false);
+-- +goose StatementEnd
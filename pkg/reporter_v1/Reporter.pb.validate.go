@@ -57,12 +57,210 @@ func (m *SendReportRequest) validate(all bool) error {
 
 	var errors []error
 
-	// no validation rules for Rows
-
 	// no validation rules for UserId
 
 	// no validation rules for Period
 
+	if all {
+		switch v := interface{}(m.GetRangeFrom()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, SendReportRequestValidationError{
+					field:  "RangeFrom",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, SendReportRequestValidationError{
+					field:  "RangeFrom",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetRangeFrom()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return SendReportRequestValidationError{
+				field:  "RangeFrom",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if all {
+		switch v := interface{}(m.GetRangeTo()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, SendReportRequestValidationError{
+					field:  "RangeTo",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, SendReportRequestValidationError{
+					field:  "RangeTo",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetRangeTo()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return SendReportRequestValidationError{
+				field:  "RangeTo",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	// no validation rules for TotalIncome
+
+	// no validation rules for TotalExpenses
+
+	// no validation rules for Balance
+
+	// no validation rules for Account
+
+	// no validation rules for BudgetId
+
+	// no validation rules for Members
+
+	// no validation rules for ChatId
+
+	for idx, item := range m.GetTimeline() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, SendReportRequestValidationError{
+						field:  fmt.Sprintf("Timeline[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, SendReportRequestValidationError{
+						field:  fmt.Sprintf("Timeline[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return SendReportRequestValidationError{
+					field:  fmt.Sprintf("Timeline[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	for idx, item := range m.GetRows() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, SendReportRequestValidationError{
+						field:  fmt.Sprintf("Rows[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, SendReportRequestValidationError{
+						field:  fmt.Sprintf("Rows[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return SendReportRequestValidationError{
+					field:  fmt.Sprintf("Rows[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	if all {
+		switch v := interface{}(m.GetPreviousFrom()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, SendReportRequestValidationError{
+					field:  "PreviousFrom",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, SendReportRequestValidationError{
+					field:  "PreviousFrom",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetPreviousFrom()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return SendReportRequestValidationError{
+				field:  "PreviousFrom",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if all {
+		switch v := interface{}(m.GetPreviousTo()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, SendReportRequestValidationError{
+					field:  "PreviousTo",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, SendReportRequestValidationError{
+					field:  "PreviousTo",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetPreviousTo()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return SendReportRequestValidationError{
+				field:  "PreviousTo",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	// no validation rules for PreviousTotalExpenses
+
 	if len(errors) > 0 {
 		return SendReportRequestMultiError(errors)
 	}
@@ -142,3 +340,1288 @@ var _ interface {
 	Cause() error
 	ErrorName() string
 } = SendReportRequestValidationError{}
+
+// Validate checks the field values on ReportRow with the rules defined in the
+// proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *ReportRow) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on ReportRow with the rules defined in
+// the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in ReportRowMultiError, or nil
+// if none found.
+func (m *ReportRow) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *ReportRow) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Category
+
+	// no validation rules for Amount
+
+	// no validation rules for Share
+
+	// no validation rules for Previous
+
+	// no validation rules for Limit
+
+	// no validation rules for LimitUsage
+
+	if len(errors) > 0 {
+		return ReportRowMultiError(errors)
+	}
+
+	return nil
+}
+
+// ReportRowMultiError is an error wrapping multiple validation errors returned
+// by ReportRow.ValidateAll() if the designated constraints aren't met.
+type ReportRowMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m ReportRowMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m ReportRowMultiError) AllErrors() []error { return m }
+
+// ReportRowValidationError is the validation error returned by
+// ReportRow.Validate if the designated constraints aren't met.
+type ReportRowValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e ReportRowValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e ReportRowValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e ReportRowValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e ReportRowValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e ReportRowValidationError) ErrorName() string { return "ReportRowValidationError" }
+
+// Error satisfies the builtin error interface
+func (e ReportRowValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sReportRow.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = ReportRowValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = ReportRowValidationError{}
+
+// Validate checks the field values on TimelinePoint with the rules defined in
+// the proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *TimelinePoint) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on TimelinePoint with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in TimelinePointMultiError, or
+// nil if none found.
+func (m *TimelinePoint) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *TimelinePoint) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if all {
+		switch v := interface{}(m.GetFrom()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, TimelinePointValidationError{
+					field:  "From",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, TimelinePointValidationError{
+					field:  "From",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetFrom()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return TimelinePointValidationError{
+				field:  "From",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	// no validation rules for Amount
+
+	if len(errors) > 0 {
+		return TimelinePointMultiError(errors)
+	}
+
+	return nil
+}
+
+// TimelinePointMultiError is an error wrapping multiple validation errors
+// returned by TimelinePoint.ValidateAll() if the designated constraints
+// aren't met.
+type TimelinePointMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m TimelinePointMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m TimelinePointMultiError) AllErrors() []error { return m }
+
+// TimelinePointValidationError is the validation error returned by
+// TimelinePoint.Validate if the designated constraints aren't met.
+type TimelinePointValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e TimelinePointValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e TimelinePointValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e TimelinePointValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e TimelinePointValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e TimelinePointValidationError) ErrorName() string { return "TimelinePointValidationError" }
+
+// Error satisfies the builtin error interface
+func (e TimelinePointValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sTimelinePoint.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = TimelinePointValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = TimelinePointValidationError{}
+
+// Validate checks the field values on SendTrendRequest with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// first error encountered is returned, or nil if there are no violations.
+func (m *SendTrendRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on SendTrendRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// SendTrendRequestMultiError, or nil if none found.
+func (m *SendTrendRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *SendTrendRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for UserId
+
+	// no validation rules for ChatId
+
+	// no validation rules for BudgetId
+
+	// no validation rules for Bucket
+
+	// no validation rules for ByCategory
+
+	if all {
+		switch v := interface{}(m.GetRangeFrom()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, SendTrendRequestValidationError{
+					field:  "RangeFrom",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, SendTrendRequestValidationError{
+					field:  "RangeFrom",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetRangeFrom()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return SendTrendRequestValidationError{
+				field:  "RangeFrom",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if all {
+		switch v := interface{}(m.GetRangeTo()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, SendTrendRequestValidationError{
+					field:  "RangeTo",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, SendTrendRequestValidationError{
+					field:  "RangeTo",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetRangeTo()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return SendTrendRequestValidationError{
+				field:  "RangeTo",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	for idx, item := range m.GetPoints() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, SendTrendRequestValidationError{
+						field:  fmt.Sprintf("Points[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, SendTrendRequestValidationError{
+						field:  fmt.Sprintf("Points[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return SendTrendRequestValidationError{
+					field:  fmt.Sprintf("Points[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	if len(errors) > 0 {
+		return SendTrendRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// SendTrendRequestMultiError is an error wrapping multiple validation errors
+// returned by SendTrendRequest.ValidateAll() if the designated constraints
+// aren't met.
+type SendTrendRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m SendTrendRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m SendTrendRequestMultiError) AllErrors() []error { return m }
+
+// SendTrendRequestValidationError is the validation error returned by
+// SendTrendRequest.Validate if the designated constraints aren't met.
+type SendTrendRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e SendTrendRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e SendTrendRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e SendTrendRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e SendTrendRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e SendTrendRequestValidationError) ErrorName() string { return "SendTrendRequestValidationError" }
+
+// Error satisfies the builtin error interface
+func (e SendTrendRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sSendTrendRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = SendTrendRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = SendTrendRequestValidationError{}
+
+// Validate checks the field values on TrendPoint with the rules defined in the
+// proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *TrendPoint) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on TrendPoint with the rules defined in
+// the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in TrendPointMultiError, or
+// nil if none found.
+func (m *TrendPoint) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *TrendPoint) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if all {
+		switch v := interface{}(m.GetFrom()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, TrendPointValidationError{
+					field:  "From",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, TrendPointValidationError{
+					field:  "From",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetFrom()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return TrendPointValidationError{
+				field:  "From",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	// no validation rules for Category
+
+	// no validation rules for Amount
+
+	if len(errors) > 0 {
+		return TrendPointMultiError(errors)
+	}
+
+	return nil
+}
+
+// TrendPointMultiError is an error wrapping multiple validation errors
+// returned by TrendPoint.ValidateAll() if the designated constraints aren't met.
+type TrendPointMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m TrendPointMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m TrendPointMultiError) AllErrors() []error { return m }
+
+// TrendPointValidationError is the validation error returned by
+// TrendPoint.Validate if the designated constraints aren't met.
+type TrendPointValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e TrendPointValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e TrendPointValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e TrendPointValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e TrendPointValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e TrendPointValidationError) ErrorName() string { return "TrendPointValidationError" }
+
+// Error satisfies the builtin error interface
+func (e TrendPointValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sTrendPoint.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = TrendPointValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = TrendPointValidationError{}
+
+// Validate checks the field values on SendExportRequest with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// first error encountered is returned, or nil if there are no violations.
+func (m *SendExportRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on SendExportRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// SendExportRequestMultiError, or nil if none found.
+func (m *SendExportRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *SendExportRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for UserId
+
+	// no validation rules for ChatId
+
+	// no validation rules for BudgetId
+
+	// no validation rules for Format
+
+	if all {
+		switch v := interface{}(m.GetRangeFrom()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, SendExportRequestValidationError{
+					field:  "RangeFrom",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, SendExportRequestValidationError{
+					field:  "RangeFrom",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetRangeFrom()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return SendExportRequestValidationError{
+				field:  "RangeFrom",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if all {
+		switch v := interface{}(m.GetRangeTo()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, SendExportRequestValidationError{
+					field:  "RangeTo",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, SendExportRequestValidationError{
+					field:  "RangeTo",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetRangeTo()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return SendExportRequestValidationError{
+				field:  "RangeTo",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	// no validation rules for Count
+
+	// no validation rules for FileName
+
+	// no validation rules for MimeType
+
+	// no validation rules for Data
+
+	if len(errors) > 0 {
+		return SendExportRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// SendExportRequestMultiError is an error wrapping multiple validation errors
+// returned by SendExportRequest.ValidateAll() if the designated constraints
+// aren't met.
+type SendExportRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m SendExportRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m SendExportRequestMultiError) AllErrors() []error { return m }
+
+// SendExportRequestValidationError is the validation error returned by
+// SendExportRequest.Validate if the designated constraints aren't met.
+type SendExportRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e SendExportRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e SendExportRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e SendExportRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e SendExportRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e SendExportRequestValidationError) ErrorName() string {
+	return "SendExportRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e SendExportRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sSendExportRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = SendExportRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = SendExportRequestValidationError{}
+
+// Validate checks the field values on ImportExpensesRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *ImportExpensesRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on ImportExpensesRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// ImportExpensesRequestMultiError, or nil if none found.
+func (m *ImportExpensesRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *ImportExpensesRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for ChatId
+
+	// no validation rules for Data
+
+	// no validation rules for Mapping
+
+	// no validation rules for DryRun
+
+	// no validation rules for Token
+
+	if len(errors) > 0 {
+		return ImportExpensesRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// ImportExpensesRequestMultiError is an error wrapping multiple validation
+// errors returned by ImportExpensesRequest.ValidateAll() if the designated
+// constraints aren't met.
+type ImportExpensesRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m ImportExpensesRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m ImportExpensesRequestMultiError) AllErrors() []error { return m }
+
+// ImportExpensesRequestValidationError is the validation error returned by
+// ImportExpensesRequest.Validate if the designated constraints aren't met.
+type ImportExpensesRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e ImportExpensesRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e ImportExpensesRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e ImportExpensesRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e ImportExpensesRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e ImportExpensesRequestValidationError) ErrorName() string {
+	return "ImportExpensesRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e ImportExpensesRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sImportExpensesRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = ImportExpensesRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = ImportExpensesRequestValidationError{}
+
+// Validate checks the field values on ImportExpensesResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *ImportExpensesResponse) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on ImportExpensesResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// ImportExpensesResponseMultiError, or nil if none found.
+func (m *ImportExpensesResponse) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *ImportExpensesResponse) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Rows
+
+	// no validation rules for Added
+
+	// no validation rules for Duplicates
+
+	for idx, item := range m.GetErrors() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, ImportExpensesResponseValidationError{
+						field:  fmt.Sprintf("Errors[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, ImportExpensesResponseValidationError{
+						field:  fmt.Sprintf("Errors[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return ImportExpensesResponseValidationError{
+					field:  fmt.Sprintf("Errors[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	for idx, item := range m.GetPreview() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, ImportExpensesResponseValidationError{
+						field:  fmt.Sprintf("Preview[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, ImportExpensesResponseValidationError{
+						field:  fmt.Sprintf("Preview[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return ImportExpensesResponseValidationError{
+					field:  fmt.Sprintf("Preview[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	// no validation rules for DryRun
+
+	if len(errors) > 0 {
+		return ImportExpensesResponseMultiError(errors)
+	}
+
+	return nil
+}
+
+// ImportExpensesResponseMultiError is an error wrapping multiple validation
+// errors returned by ImportExpensesResponse.ValidateAll() if the designated
+// constraints aren't met.
+type ImportExpensesResponseMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m ImportExpensesResponseMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m ImportExpensesResponseMultiError) AllErrors() []error { return m }
+
+// ImportExpensesResponseValidationError is the validation error returned by
+// ImportExpensesResponse.Validate if the designated constraints aren't met.
+type ImportExpensesResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e ImportExpensesResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e ImportExpensesResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e ImportExpensesResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e ImportExpensesResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e ImportExpensesResponseValidationError) ErrorName() string {
+	return "ImportExpensesResponseValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e ImportExpensesResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sImportExpensesResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = ImportExpensesResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = ImportExpensesResponseValidationError{}
+
+// Validate checks the field values on ImportRowError with the rules defined in
+// the proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *ImportRowError) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on ImportRowError with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in ImportRowErrorMultiError,
+// or nil if none found.
+func (m *ImportRowError) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *ImportRowError) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Row
+
+	// no validation rules for Message
+
+	if len(errors) > 0 {
+		return ImportRowErrorMultiError(errors)
+	}
+
+	return nil
+}
+
+// ImportRowErrorMultiError is an error wrapping multiple validation errors
+// returned by ImportRowError.ValidateAll() if the designated constraints
+// aren't met.
+type ImportRowErrorMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m ImportRowErrorMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m ImportRowErrorMultiError) AllErrors() []error { return m }
+
+// ImportRowErrorValidationError is the validation error returned by
+// ImportRowError.Validate if the designated constraints aren't met.
+type ImportRowErrorValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e ImportRowErrorValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e ImportRowErrorValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e ImportRowErrorValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e ImportRowErrorValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e ImportRowErrorValidationError) ErrorName() string { return "ImportRowErrorValidationError" }
+
+// Error satisfies the builtin error interface
+func (e ImportRowErrorValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sImportRowError.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = ImportRowErrorValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = ImportRowErrorValidationError{}
+
+// Validate checks the field values on ImportPreviewRow with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// first error encountered is returned, or nil if there are no violations.
+func (m *ImportPreviewRow) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on ImportPreviewRow with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// ImportPreviewRowMultiError, or nil if none found.
+func (m *ImportPreviewRow) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *ImportPreviewRow) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Row
+
+	if all {
+		switch v := interface{}(m.GetDatetime()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, ImportPreviewRowValidationError{
+					field:  "Datetime",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, ImportPreviewRowValidationError{
+					field:  "Datetime",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetDatetime()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return ImportPreviewRowValidationError{
+				field:  "Datetime",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	// no validation rules for Amount
+
+	// no validation rules for Currency
+
+	// no validation rules for Category
+
+	// no validation rules for Duplicate
+
+	if len(errors) > 0 {
+		return ImportPreviewRowMultiError(errors)
+	}
+
+	return nil
+}
+
+// ImportPreviewRowMultiError is an error wrapping multiple validation errors
+// returned by ImportPreviewRow.ValidateAll() if the designated constraints
+// aren't met.
+type ImportPreviewRowMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m ImportPreviewRowMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m ImportPreviewRowMultiError) AllErrors() []error { return m }
+
+// ImportPreviewRowValidationError is the validation error returned by
+// ImportPreviewRow.Validate if the designated constraints aren't met.
+type ImportPreviewRowValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e ImportPreviewRowValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e ImportPreviewRowValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e ImportPreviewRowValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e ImportPreviewRowValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e ImportPreviewRowValidationError) ErrorName() string { return "ImportPreviewRowValidationError" }
+
+// Error satisfies the builtin error interface
+func (e ImportPreviewRowValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sImportPreviewRow.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = ImportPreviewRowValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = ImportPreviewRowValidationError{}
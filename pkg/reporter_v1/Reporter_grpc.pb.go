@@ -25,6 +25,9 @@ const _ = grpc.SupportPackageIsVersion7
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type ReporterV1Client interface {
 	SendReport(ctx context.Context, in *SendReportRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	SendExport(ctx context.Context, in *SendExportRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	SendTrend(ctx context.Context, in *SendTrendRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	ImportExpenses(ctx context.Context, in *ImportExpensesRequest, opts ...grpc.CallOption) (*ImportExpensesResponse, error)
 }
 
 type reporterV1Client struct {
@@ -44,11 +47,41 @@ func (c *reporterV1Client) SendReport(ctx context.Context, in *SendReportRequest
 	return out, nil
 }
 
+func (c *reporterV1Client) SendExport(ctx context.Context, in *SendExportRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/ReporterV1.ReporterV1/SendExport", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reporterV1Client) SendTrend(ctx context.Context, in *SendTrendRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/ReporterV1.ReporterV1/SendTrend", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reporterV1Client) ImportExpenses(ctx context.Context, in *ImportExpensesRequest, opts ...grpc.CallOption) (*ImportExpensesResponse, error) {
+	out := new(ImportExpensesResponse)
+	err := c.cc.Invoke(ctx, "/ReporterV1.ReporterV1/ImportExpenses", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ReporterV1Server is the server API for ReporterV1 service.
 // All implementations must embed UnimplementedReporterV1Server
 // for forward compatibility
 type ReporterV1Server interface {
 	SendReport(context.Context, *SendReportRequest) (*emptypb.Empty, error)
+	SendExport(context.Context, *SendExportRequest) (*emptypb.Empty, error)
+	SendTrend(context.Context, *SendTrendRequest) (*emptypb.Empty, error)
+	ImportExpenses(context.Context, *ImportExpensesRequest) (*ImportExpensesResponse, error)
 	mustEmbedUnimplementedReporterV1Server()
 }
 
@@ -59,6 +92,15 @@ type UnimplementedReporterV1Server struct {
 func (UnimplementedReporterV1Server) SendReport(context.Context, *SendReportRequest) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SendReport not implemented")
 }
+func (UnimplementedReporterV1Server) SendExport(context.Context, *SendExportRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SendExport not implemented")
+}
+func (UnimplementedReporterV1Server) SendTrend(context.Context, *SendTrendRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SendTrend not implemented")
+}
+func (UnimplementedReporterV1Server) ImportExpenses(context.Context, *ImportExpensesRequest) (*ImportExpensesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ImportExpenses not implemented")
+}
 func (UnimplementedReporterV1Server) mustEmbedUnimplementedReporterV1Server() {}
 
 // UnsafeReporterV1Server may be embedded to opt out of forward compatibility for this service.
@@ -90,6 +132,60 @@ func _ReporterV1_SendReport_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ReporterV1_SendExport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendExportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReporterV1Server).SendExport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ReporterV1.ReporterV1/SendExport",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReporterV1Server).SendExport(ctx, req.(*SendExportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReporterV1_SendTrend_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendTrendRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReporterV1Server).SendTrend(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ReporterV1.ReporterV1/SendTrend",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReporterV1Server).SendTrend(ctx, req.(*SendTrendRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReporterV1_ImportExpenses_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportExpensesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReporterV1Server).ImportExpenses(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ReporterV1.ReporterV1/ImportExpenses",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReporterV1Server).ImportExpenses(ctx, req.(*ImportExpensesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // ReporterV1_ServiceDesc is the grpc.ServiceDesc for ReporterV1 service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -101,6 +197,18 @@ var ReporterV1_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "SendReport",
 			Handler:    _ReporterV1_SendReport_Handler,
 		},
+		{
+			MethodName: "SendExport",
+			Handler:    _ReporterV1_SendExport_Handler,
+		},
+		{
+			MethodName: "SendTrend",
+			Handler:    _ReporterV1_SendTrend_Handler,
+		},
+		{
+			MethodName: "ImportExpenses",
+			Handler:    _ReporterV1_ImportExpenses_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "Reporter.proto",